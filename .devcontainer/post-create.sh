@@ -12,6 +12,10 @@ cd api-gateway
 go mod download
 go mod tidy
 
+# generate a development key to sign the access tokens
+mkdir -p keys
+[ -f keys/dev.pem ] || openssl genpkey -algorithm ed25519 -out keys/dev.pem
+
 # install planner backend dependencies
 cd ../planner-backend
 go mod download
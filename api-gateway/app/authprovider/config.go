@@ -0,0 +1,143 @@
+package authprovider
+
+import (
+	"api-gateway/app/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+type Config struct {
+	Local LocalConfig  `json:"local"`
+	OIDC  []OIDCConfig `json:"oidc"`
+	LDAP  []LDAPConfig `json:"ldap"`
+}
+
+type LocalConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+// DefaultConfig only enables the local passwords
+var DefaultConfig = Config{Local: LocalConfig{Enabled: true}}
+
+func LoadConfig(path string) (*Config, error) {
+	/**
+	* Loads and validates the provider configuration from a JSON file
+	* @param path: The path of the file
+	* @return: The loaded configuration
+	**/
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := json.Unmarshal(file, &config); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func (c *Config) Validate() error {
+	/* Checks that the required fields are set and that every provider has a unique name */
+	names := map[string]bool{}
+	if c.Local.Enabled {
+		names[LocalProviderName] = true
+	}
+	unique := func(name string) error {
+		if name == "" {
+			return fmt.Errorf("provider without a name")
+		}
+		if names[name] {
+			return fmt.Errorf("provider %s: name is used twice", name)
+		}
+		names[name] = true
+		return nil
+	}
+
+	for _, oidc := range c.OIDC {
+		if err := unique(oidc.Name); err != nil {
+			return err
+		}
+		if oidc.Issuer == "" || oidc.ClientID == "" || oidc.RedirectURL == "" {
+			return fmt.Errorf("provider %s: issuer, client_id and redirect_url are required", oidc.Name)
+		}
+	}
+	for _, ldap := range c.LDAP {
+		if err := unique(ldap.Name); err != nil {
+			return err
+		}
+		if ldap.URL == "" || ldap.BaseDN == "" {
+			return fmt.Errorf("provider %s: url and base_dn are required", ldap.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("no provider is enabled")
+	}
+	return nil
+}
+
+func (c *Config) Registry(ctx context.Context, userRepository repository.UserRepository) (*Registry, error) {
+	/**
+	* Creates the enabled providers, the OIDC providers load the discovery document of their issuer
+	* @param ctx: The context of the discovery requests
+	* @param userRepository: The repository of the local provider
+	* @return: The registry of the providers
+	**/
+	providers := []Provider{}
+	if c.Local.Enabled {
+		providers = append(providers, LocalProvider{UserRepository: userRepository})
+	}
+
+	for _, config := range c.OIDC {
+		provider, err := NewOIDCProvider(ctx, config, os.Getenv(config.ClientSecretEnv))
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", config.Name, err)
+		}
+		providers = append(providers, provider)
+	}
+	for _, config := range c.LDAP {
+		providers = append(providers, NewLDAPProvider(config, os.Getenv(config.BindPasswordEnv)))
+	}
+
+	return NewRegistry(providers...), nil
+}
+
+func Load(path string, userRepository repository.UserRepository) (*Registry, error) {
+	/**
+	* Creates the providers of a provider file, see LoadConfig
+	* @param path: The path of the file, only the local provider is enabled if it is empty
+	* @param userRepository: The repository of the local provider
+	* @return: The registry of the providers
+	**/
+	config := DefaultConfig
+	if path == "" {
+		slog.Info("GATEWAY_AUTH_PROVIDERS_FILE is not set, using the local provider only")
+	} else {
+		loaded, err := LoadConfig(path)
+		if err != nil {
+			return nil, fmt.Errorf("auth providers %s: %w", path, err)
+		}
+		config = *loaded
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	registry, err := config.Registry(ctx, userRepository)
+	if err != nil {
+		return nil, fmt.Errorf("auth providers %s: %w", path, err)
+	}
+
+	slog.Info("Loaded auth providers", "providers", registry.Providers())
+	return registry, nil
+}
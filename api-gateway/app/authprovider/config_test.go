@@ -0,0 +1,58 @@
+package authprovider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testSteps := []struct {
+		content       string
+		errorExpected bool
+	}{
+		{
+			content: `{
+				"local": {"enabled": true},
+				"ldap": [{"name": "directory", "url": "ldap://localhost:389", "base_dn": "dc=example,dc=org"}]
+			}`,
+		},
+		{
+			// the provider names have to be unique
+			content: `{
+				"local": {"enabled": true},
+				"ldap": [{"name": "local", "url": "ldap://localhost:389", "base_dn": "dc=example,dc=org"}]
+			}`,
+			errorExpected: true,
+		},
+		{
+			content:       `{"oidc": [{"name": "keycloak", "issuer": "http://localhost:8081/realms/planner"}]}`,
+			errorExpected: true,
+		},
+		{
+			content:       `{"local": {"enabled": false}}`,
+			errorExpected: true,
+		},
+		{
+			content:       `{"local": `,
+			errorExpected: true,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		path := filepath.Join(t.TempDir(), "auth_providers.json")
+		if err := os.WriteFile(path, []byte(testStep.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := LoadConfig(path)
+		if testStep.errorExpected != (err != nil) {
+			t.Errorf("Test Step %d: expected error %v, got %v", i, testStep.errorExpected, err)
+		}
+	}
+
+	// the example shipped with the repository must stay valid
+	if _, err := LoadConfig("../../config/auth_providers.example.json"); err != nil {
+		t.Errorf("Expected the example config to be valid, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package authprovider
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+type LDAPConfig struct {
+	Name string `json:"name"`
+	// e.g. ldap://ldap.example.org:389 or ldaps://ldap.example.org:636
+	URL                string `json:"url"`
+	StartTLS           bool   `json:"start_tls"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+
+	// Service account used to search the users, anonymous search is used without a bind DN
+	BindDN string `json:"bind_dn"`
+	// Name of the environment variable holding the password of the service account
+	BindPasswordEnv string `json:"bind_password_env"`
+
+	BaseDN string `json:"base_dn"`
+	// Filter to find a user, %s is replaced with the escaped username
+	UserFilter string `json:"user_filter"`
+
+	// Attribute identifying the user, the DN is used if it is empty
+	SubjectAttribute    string `json:"subject_attribute"`
+	UsernameAttribute   string `json:"username_attribute"`
+	EmailAttribute      string `json:"email_attribute"`
+	DepartmentAttribute string `json:"department_attribute"`
+	GroupAttribute      string `json:"group_attribute"`
+
+	Mapping Mapping `json:"mapping"`
+}
+
+// ldapConn is the part of the LDAP connection used by the provider
+type ldapConn interface {
+	Bind(username string, password string) error
+	Search(request *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+type LDAPProvider struct {
+	config       LDAPConfig
+	bindPassword string
+	dial         func() (ldapConn, error)
+}
+
+func NewLDAPProvider(config LDAPConfig, bindPassword string) *LDAPProvider {
+	/* Creates an LDAP provider, which searches the user and binds with the password of the user */
+	if config.UserFilter == "" {
+		config.UserFilter = "(uid=%s)"
+	}
+	if config.UsernameAttribute == "" {
+		config.UsernameAttribute = "uid"
+	}
+	if config.EmailAttribute == "" {
+		config.EmailAttribute = "mail"
+	}
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = "memberOf"
+	}
+
+	provider := &LDAPProvider{config: config, bindPassword: bindPassword}
+	provider.dial = func() (ldapConn, error) {
+		tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify}
+		conn, err := ldap.DialURL(config.URL, ldap.DialWithTLSConfig(tlsConfig))
+		if err != nil {
+			return nil, err
+		}
+		if config.StartTLS {
+			if err := conn.StartTLS(tlsConfig); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		return conn, nil
+	}
+	return provider
+}
+
+func (p *LDAPProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *LDAPProvider) Authenticate(ctx context.Context, username string, password string) (*Identity, error) {
+	// an empty password would result in an unauthenticated bind, which most servers accept
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := p.dial()
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", p.config.URL, err)
+	}
+	defer conn.Close()
+
+	if p.config.BindDN != "" {
+		if err := conn.Bind(p.config.BindDN, p.bindPassword); err != nil {
+			return nil, fmt.Errorf("bind service account: %w", err)
+		}
+	}
+
+	attributes := []string{p.config.UsernameAttribute, p.config.EmailAttribute, p.config.GroupAttribute}
+	for _, attribute := range []string{p.config.SubjectAttribute, p.config.DepartmentAttribute} {
+		if attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		p.config.BaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(p.config.UserFilter, ldap.EscapeFilter(username)),
+		attributes,
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("search user: %w", err)
+	}
+	if len(result.Entries) != 1 {
+		// unknown and ambiguous usernames are treated the same way
+		return nil, ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("bind user: %w", err)
+	}
+
+	subject := entry.DN
+	if p.config.SubjectAttribute != "" {
+		subject = entry.GetAttributeValue(p.config.SubjectAttribute)
+		if subject == "" {
+			return nil, errors.New("user has no " + p.config.SubjectAttribute + " attribute")
+		}
+	}
+
+	identity := &Identity{
+		Provider: p.config.Name,
+		Subject:  subject,
+		Username: entry.GetAttributeValue(p.config.UsernameAttribute),
+		Email:    entry.GetAttributeValue(p.config.EmailAttribute),
+		Roles:    p.config.Mapping.RolesOf(entry.GetAttributeValues(p.config.GroupAttribute)),
+	}
+	if p.config.DepartmentAttribute != "" {
+		identity.Department = p.config.Mapping.Department(entry.GetAttributeValue(p.config.DepartmentAttribute))
+	} else {
+		identity.Department = p.config.Mapping.Department("")
+	}
+	if identity.Username == "" {
+		identity.Username = username
+	}
+
+	return identity, nil
+}
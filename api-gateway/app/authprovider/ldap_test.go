@@ -0,0 +1,140 @@
+package authprovider
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+type ldapConnMock struct {
+	// password per DN
+	passwords map[string]string
+	entries   []*ldap.Entry
+
+	binds   []string
+	filters []string
+}
+
+func (c *ldapConnMock) Bind(username string, password string) error {
+	c.binds = append(c.binds, username)
+	if expected, ok := c.passwords[username]; !ok || expected != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	return nil
+}
+
+func (c *ldapConnMock) Search(request *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	c.filters = append(c.filters, request.Filter)
+	return &ldap.SearchResult{Entries: c.entries}, nil
+}
+
+func (c *ldapConnMock) Close() error {
+	return nil
+}
+
+func TestLDAPAuthenticate(t *testing.T) {
+	serviceDN := "cn=gateway,dc=example,dc=org"
+	userDN := "uid=jdoe,ou=people,dc=example,dc=org"
+	entry := ldap.NewEntry(userDN, map[string][]string{
+		"uid":       {"jdoe"},
+		"mail":      {"jdoe@example.org"},
+		"ou":        {"CARD"},
+		"entryUUID": {"0b7c6c2e-5d4e-4a55-9e57-36f1c3c1f8a2"},
+		"memberOf":  {"cn=planners,ou=groups,dc=example,dc=org"},
+	})
+
+	config := LDAPConfig{
+		Name:                "directory",
+		URL:                 "ldap://localhost:389",
+		BindDN:              serviceDN,
+		BaseDN:              "dc=example,dc=org",
+		SubjectAttribute:    "entryUUID",
+		DepartmentAttribute: "ou",
+		Mapping: Mapping{
+			Departments: map[string]string{"CARD": "cardiology"},
+			Roles:       []RoleMapping{{Group: "cn=planners,ou=groups,dc=example,dc=org", Role: "planner", DepartmentScoped: true}},
+		},
+	}
+
+	testSteps := []struct {
+		username      string
+		password      string
+		entries       []*ldap.Entry
+		expected      *Identity
+		expectedError error
+	}{
+		{
+			username: "jdoe",
+			password: "secret",
+			entries:  []*ldap.Entry{entry},
+			expected: &Identity{
+				Provider:   "directory",
+				Subject:    "0b7c6c2e-5d4e-4a55-9e57-36f1c3c1f8a2",
+				Username:   "jdoe",
+				Email:      "jdoe@example.org",
+				Department: "cardiology",
+				Roles:      []MappedRole{{Name: "planner", DepartmentScoped: true}},
+			},
+		},
+		{
+			// wrong password
+			username:      "jdoe",
+			password:      "wrong",
+			entries:       []*ldap.Entry{entry},
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			// unknown user
+			username:      "unknown",
+			password:      "secret",
+			entries:       []*ldap.Entry{},
+			expectedError: ErrInvalidCredentials,
+		},
+		{
+			// an empty password would be an unauthenticated bind
+			username:      "jdoe",
+			password:      "",
+			entries:       []*ldap.Entry{entry},
+			expectedError: ErrInvalidCredentials,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		conn := &ldapConnMock{
+			passwords: map[string]string{serviceDN: "service-secret", userDN: "secret"},
+			entries:   testStep.entries,
+		}
+		provider := NewLDAPProvider(config, "service-secret")
+		provider.dial = func() (ldapConn, error) { return conn, nil }
+
+		identity, err := provider.Authenticate(context.Background(), testStep.username, testStep.password)
+		if !errors.Is(err, testStep.expectedError) {
+			t.Errorf("Test Step %d: expected error %v, got %v", i, testStep.expectedError, err)
+			continue
+		}
+		if !reflect.DeepEqual(identity, testStep.expected) {
+			t.Errorf("Test Step %d: expected identity %+v, got %+v", i, testStep.expected, identity)
+		}
+	}
+}
+
+func TestLDAPAuthenticateEscapesFilter(t *testing.T) {
+	conn := &ldapConnMock{passwords: map[string]string{}}
+	provider := NewLDAPProvider(LDAPConfig{Name: "directory", BaseDN: "dc=example,dc=org"}, "")
+	provider.dial = func() (ldapConn, error) { return conn, nil }
+
+	if _, err := provider.Authenticate(context.Background(), "*)(uid=*", "secret"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected invalid credentials, got %v", err)
+	}
+
+	// without a bind DN the search is anonymous
+	if len(conn.binds) != 0 {
+		t.Errorf("Expected no bind, got %v", conn.binds)
+	}
+	if expected := `(uid=\2a\29\28uid=\2a)`; len(conn.filters) != 1 || conn.filters[0] != expected {
+		t.Errorf("Expected filter %s, got %v", expected, conn.filters)
+	}
+}
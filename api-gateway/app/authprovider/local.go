@@ -0,0 +1,48 @@
+package authprovider
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/repository"
+	"context"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const LocalProviderName = dao.AuthProviderLocal
+
+type LocalProvider struct {
+	// Checks the bcrypt passwords stored in the gateway
+	UserRepository repository.UserRepository
+}
+
+func (p LocalProvider) Name() string {
+	return LocalProviderName
+}
+
+func (p LocalProvider) Authenticate(ctx context.Context, username string, password string) (*Identity, error) {
+	user, err := p.UserRepository.FindUserByUsername(username)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// users of external providers have no local password
+	if user.IsExternal() {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return &Identity{
+		Provider: LocalProviderName,
+		Subject:  user.ID.String(),
+		Username: user.Username,
+		Email:    user.Email,
+	}, nil
+}
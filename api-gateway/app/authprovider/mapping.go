@@ -0,0 +1,66 @@
+package authprovider
+
+import (
+	"strings"
+)
+
+type Mapping struct {
+	// Department of the users without a (known) department value
+	DefaultDepartment string `json:"default_department"`
+	// Maps the department values of the provider to department names, unmapped values are used as they are
+	Departments map[string]string `json:"departments"`
+	// Maps the groups of the provider to roles
+	Roles []RoleMapping `json:"roles"`
+}
+
+type RoleMapping struct {
+	// Groups are compared case-insensitively, since LDAP DNs are case-insensitive
+	Group string `json:"group"`
+	Role  string `json:"role"`
+	// Limits the role to the department of the user, otherwise the role applies to every department
+	DepartmentScoped bool `json:"department_scoped"`
+}
+
+type MappedRole struct {
+	Name             string
+	DepartmentScoped bool
+}
+
+func (m Mapping) Department(value string) string {
+	/**
+	* Maps the department value of the provider to the name of a department
+	* @param value: The department value of the provider, e.g. a claim or an attribute
+	* @return: The department name, the default department if the value is empty
+	**/
+	if value == "" {
+		return m.DefaultDepartment
+	}
+	if department, ok := m.Departments[value]; ok {
+		return department
+	}
+	return value
+}
+
+func (m Mapping) RolesOf(groups []string) []MappedRole {
+	/**
+	* Maps the groups of the provider to roles, every role is returned once
+	* @param groups: The groups of the user
+	* @return: The mapped roles
+	**/
+	roles := []MappedRole{}
+	seen := map[MappedRole]bool{}
+	for _, mapping := range m.Roles {
+		for _, group := range groups {
+			if !strings.EqualFold(mapping.Group, group) {
+				continue
+			}
+
+			role := MappedRole{Name: mapping.Role, DepartmentScoped: mapping.DepartmentScoped}
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+	}
+	return roles
+}
@@ -0,0 +1,65 @@
+package authprovider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMappingDepartment(t *testing.T) {
+	mapping := Mapping{
+		DefaultDepartment: "general",
+		Departments:       map[string]string{"CARD": "cardiology"},
+	}
+
+	testSteps := map[string]string{
+		"":          "general",
+		"CARD":      "cardiology",
+		"radiology": "radiology",
+	}
+
+	for value, expected := range testSteps {
+		if department := mapping.Department(value); department != expected {
+			t.Errorf("Value %q: expected %q, got %q", value, expected, department)
+		}
+	}
+}
+
+func TestMappingRolesOf(t *testing.T) {
+	mapping := Mapping{
+		Roles: []RoleMapping{
+			{Group: "cn=planners,ou=groups,dc=example,dc=org", Role: "planner", DepartmentScoped: true},
+			{Group: "cn=leads,ou=groups,dc=example,dc=org", Role: "planner", DepartmentScoped: true},
+			{Group: "gateway-admins", Role: "system-admin"},
+		},
+	}
+
+	testSteps := []struct {
+		groups   []string
+		expected []MappedRole
+	}{
+		{
+			groups:   nil,
+			expected: []MappedRole{},
+		},
+		{
+			// DNs are compared case-insensitively
+			groups:   []string{"CN=Planners,OU=Groups,DC=example,DC=org"},
+			expected: []MappedRole{{Name: "planner", DepartmentScoped: true}},
+		},
+		{
+			// roles granted by several groups are returned once
+			groups:   []string{"cn=planners,ou=groups,dc=example,dc=org", "cn=leads,ou=groups,dc=example,dc=org", "gateway-admins"},
+			expected: []MappedRole{{Name: "planner", DepartmentScoped: true}, {Name: "system-admin"}},
+		},
+		{
+			groups:   []string{"unknown"},
+			expected: []MappedRole{},
+		},
+	}
+
+	for i, testStep := range testSteps {
+		if roles := mapping.RolesOf(testStep.groups); !reflect.DeepEqual(roles, testStep.expected) {
+			t.Errorf("Test Step %d: expected %v, got %v", i, testStep.expected, roles)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package authprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+type OIDCConfig struct {
+	Name   string `json:"name"`
+	Issuer string `json:"issuer"`
+
+	ClientID string `json:"client_id"`
+	// Name of the environment variable holding the client secret, the secret is not stored in the file
+	ClientSecretEnv string `json:"client_secret_env"`
+	// The callback of the gateway, e.g. https://planner.example.org/auth/oidc/callback
+	RedirectURL string   `json:"redirect_url"`
+	Scopes      []string `json:"scopes"`
+
+	UsernameClaim   string `json:"username_claim"`
+	EmailClaim      string `json:"email_claim"`
+	DepartmentClaim string `json:"department_claim"`
+	GroupsClaim     string `json:"groups_claim"`
+
+	Mapping Mapping `json:"mapping"`
+}
+
+type OIDCProvider struct {
+	config   OIDCConfig
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewOIDCProvider(ctx context.Context, config OIDCConfig, clientSecret string) (*OIDCProvider, error) {
+	/**
+	* Creates an OIDC provider using the authorization code flow with PKCE.
+	* The endpoints and keys of the issuer are loaded from its discovery document.
+	**/
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discover issuer %s: %w", config.Issuer, err)
+	}
+
+	if config.UsernameClaim == "" {
+		config.UsernameClaim = "preferred_username"
+	}
+	if config.EmailClaim == "" {
+		config.EmailClaim = "email"
+	}
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+	scopes := config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"profile", "email"}
+	}
+
+	return &OIDCProvider{
+		config: config,
+		oauth2: oauth2.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  config.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID}, scopes...),
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.ClientID}),
+	}, nil
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *OIDCProvider) AuthCodeURL(state string, nonce string, codeVerifier string) string {
+	return p.oauth2.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier))
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, nonce string, codeVerifier string) (*Identity, error) {
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+
+	username, _ := claims[p.config.UsernameClaim].(string)
+	if username == "" {
+		return nil, fmt.Errorf("id_token has no %s claim", p.config.UsernameClaim)
+	}
+	email, _ := claims[p.config.EmailClaim].(string)
+	department, _ := claims[p.config.DepartmentClaim].(string)
+
+	return &Identity{
+		Provider:   p.config.Name,
+		Subject:    idToken.Subject,
+		Username:   username,
+		Email:      email,
+		Department: p.config.Mapping.Department(department),
+		Roles:      p.config.Mapping.RolesOf(stringValues(claims[p.config.GroupsClaim])),
+	}, nil
+}
+
+func stringValues(value interface{}) []string {
+	/* Claims may hold a single string or a list of strings */
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := []string{}
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
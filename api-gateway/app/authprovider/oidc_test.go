@@ -0,0 +1,221 @@
+package authprovider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockIdP is a minimal OIDC identity provider serving discovery, keys and the token endpoint
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	// claims of the next id_token
+	claims jwt.MapClaims
+	// the code challenge of the authorization request, checked against the code verifier
+	codeChallenge string
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &mockIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "valid-code" || base64.RawURLEncoding.EncodeToString(challenge[:]) != idp.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+		token.Header["kid"] = "test"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   300,
+			"id_token":     idToken,
+		})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func TestOIDCLogin(t *testing.T) {
+	idp := newMockIdP(t)
+
+	provider, err := NewOIDCProvider(context.Background(), OIDCConfig{
+		Name:            "keycloak",
+		Issuer:          idp.server.URL,
+		ClientID:        "gateway",
+		RedirectURL:     "http://localhost:8080/auth/keycloak/callback",
+		DepartmentClaim: "department",
+		Mapping: Mapping{
+			DefaultDepartment: "general",
+			Roles:             []RoleMapping{{Group: "planners", Role: "planner", DepartmentScoped: true}},
+		},
+	}, "client-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the login page receives the state, nonce and code challenge
+	authCodeURL, err := url.Parse(provider.AuthCodeURL("state", "nonce", "verifier-verifier-verifier-verifier-verifier"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := authCodeURL.Query()
+	if query.Get("state") != "state" || query.Get("nonce") != "nonce" || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("Unexpected auth code URL %s", authCodeURL)
+	}
+	idp.codeChallenge = query.Get("code_challenge")
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":                idp.server.URL,
+			"aud":                "gateway",
+			"sub":                "4f1c2a",
+			"exp":                time.Now().Add(time.Minute).Unix(),
+			"iat":                time.Now().Unix(),
+			"nonce":              "nonce",
+			"preferred_username": "jdoe",
+			"email":              "jdoe@example.org",
+			"groups":             []string{"planners", "nurses"},
+		}
+	}
+
+	testSteps := []struct {
+		claims        func() jwt.MapClaims
+		code          string
+		codeVerifier  string
+		expected      *Identity
+		errorExpected bool
+	}{
+		{
+			claims:       validClaims,
+			code:         "valid-code",
+			codeVerifier: "verifier-verifier-verifier-verifier-verifier",
+			expected: &Identity{
+				Provider:   "keycloak",
+				Subject:    "4f1c2a",
+				Username:   "jdoe",
+				Email:      "jdoe@example.org",
+				Department: "general",
+				Roles:      []MappedRole{{Name: "planner", DepartmentScoped: true}},
+			},
+		},
+		{
+			// the code verifier does not match the challenge
+			claims:        validClaims,
+			code:          "valid-code",
+			codeVerifier:  "another-verifier-another-verifier-another",
+			errorExpected: true,
+		},
+		{
+			// the token was issued for another login
+			claims: func() jwt.MapClaims {
+				claims := validClaims()
+				claims["nonce"] = "another-nonce"
+				return claims
+			},
+			code:          "valid-code",
+			codeVerifier:  "verifier-verifier-verifier-verifier-verifier",
+			errorExpected: true,
+		},
+		{
+			// the token was issued for another client
+			claims: func() jwt.MapClaims {
+				claims := validClaims()
+				claims["aud"] = "another-client"
+				return claims
+			},
+			code:          "valid-code",
+			codeVerifier:  "verifier-verifier-verifier-verifier-verifier",
+			errorExpected: true,
+		},
+		{
+			claims:        validClaims,
+			code:          "invalid-code",
+			codeVerifier:  "verifier-verifier-verifier-verifier-verifier",
+			errorExpected: true,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		idp.claims = testStep.claims()
+
+		identity, err := provider.Exchange(context.Background(), testStep.code, "nonce", testStep.codeVerifier)
+		if testStep.errorExpected {
+			if err == nil {
+				t.Errorf("Test Step %d: expected an error, got %+v", i, identity)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test Step %d: unexpected error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(identity, testStep.expected) {
+			t.Errorf("Test Step %d: expected identity %+v, got %+v", i, testStep.expected, identity)
+		}
+	}
+}
+
+func TestStringValues(t *testing.T) {
+	if values := stringValues("planners"); !reflect.DeepEqual(values, []string{"planners"}) {
+		t.Errorf("Expected a single value, got %v", values)
+	}
+	if values := stringValues([]interface{}{"planners", 1, "nurses"}); !reflect.DeepEqual(values, []string{"planners", "nurses"}) {
+		t.Errorf("Expected the string values, got %v", values)
+	}
+	if values := stringValues(nil); values != nil {
+		t.Errorf("Expected no values, got %v", values)
+	}
+}
@@ -0,0 +1,113 @@
+/**
+* This package authenticates users against identity providers.
+* Password providers check a username and password, e.g. the local passwords or an LDAP directory.
+* Redirect providers send the user to an external login page, e.g. an OIDC identity provider.
+* Users of external providers are provisioned by the gateway on their first login.
+**/
+package authprovider
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+const (
+	TypePassword = "password"
+	TypeRedirect = "redirect"
+)
+
+// ErrInvalidCredentials is returned if the user is unknown or the credentials are wrong
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+type Identity struct {
+	// Name of the provider which authenticated the user
+	Provider string
+	// Stable identifier of the user at the provider
+	Subject  string
+	Username string
+	Email    string
+
+	// Department and roles mapped from the claims or groups of the user
+	Department string
+	Roles      []MappedRole
+}
+
+type Provider interface {
+	Name() string
+}
+
+type PasswordProvider interface {
+	Provider
+	Authenticate(ctx context.Context, username string, password string) (*Identity, error)
+}
+
+type RedirectProvider interface {
+	Provider
+	// Returns the URL of the login page, the state, nonce and code verifier are checked on the callback
+	AuthCodeURL(state string, nonce string, codeVerifier string) string
+	Exchange(ctx context.Context, code string, nonce string, codeVerifier string) (*Identity, error)
+}
+
+type ProviderInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type Registry struct {
+	passwordProviders map[string]PasswordProvider
+	redirectProviders map[string]RedirectProvider
+	providers         []ProviderInfo
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	/**
+	* Creates a registry of the given providers, the order is kept for the login page
+	* @param providers: The enabled providers, each has to implement PasswordProvider or RedirectProvider
+	* @return: The registry
+	**/
+	registry := &Registry{
+		passwordProviders: map[string]PasswordProvider{},
+		redirectProviders: map[string]RedirectProvider{},
+		providers:         []ProviderInfo{},
+	}
+
+	for _, provider := range providers {
+		switch p := provider.(type) {
+		case PasswordProvider:
+			registry.passwordProviders[p.Name()] = p
+			registry.providers = append(registry.providers, ProviderInfo{Name: p.Name(), Type: TypePassword})
+		case RedirectProvider:
+			registry.redirectProviders[p.Name()] = p
+			registry.providers = append(registry.providers, ProviderInfo{Name: p.Name(), Type: TypeRedirect})
+		}
+	}
+
+	return registry
+}
+
+func (r *Registry) PasswordProvider(name string) (PasswordProvider, bool) {
+	/* Without a name the local provider is used, or the first password provider if it is disabled */
+	if name == "" {
+		if provider, ok := r.passwordProviders[LocalProviderName]; ok {
+			return provider, true
+		}
+		index := slices.IndexFunc(r.providers, func(info ProviderInfo) bool { return info.Type == TypePassword })
+		if index < 0 {
+			return nil, false
+		}
+		name = r.providers[index].Name
+	}
+
+	provider, ok := r.passwordProviders[name]
+	return provider, ok
+}
+
+func (r *Registry) RedirectProvider(name string) (RedirectProvider, bool) {
+	provider, ok := r.redirectProviders[name]
+	return provider, ok
+}
+
+func (r *Registry) Providers() []ProviderInfo {
+	return r.providers
+}
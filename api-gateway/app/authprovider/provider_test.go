@@ -0,0 +1,81 @@
+package authprovider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type passwordProviderMock struct {
+	name string
+}
+
+func (p passwordProviderMock) Name() string {
+	return p.name
+}
+
+func (p passwordProviderMock) Authenticate(ctx context.Context, username string, password string) (*Identity, error) {
+	return &Identity{Provider: p.name, Subject: username, Username: username}, nil
+}
+
+type redirectProviderMock struct {
+	name string
+}
+
+func (p redirectProviderMock) Name() string {
+	return p.name
+}
+
+func (p redirectProviderMock) AuthCodeURL(state string, nonce string, codeVerifier string) string {
+	return "https://idp.example.org/authorize?state=" + state
+}
+
+func (p redirectProviderMock) Exchange(ctx context.Context, code string, nonce string, codeVerifier string) (*Identity, error) {
+	return &Identity{Provider: p.name, Subject: code}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(
+		passwordProviderMock{name: "directory"},
+		redirectProviderMock{name: "keycloak"},
+		passwordProviderMock{name: LocalProviderName},
+	)
+
+	expected := []ProviderInfo{
+		{Name: "directory", Type: TypePassword},
+		{Name: "keycloak", Type: TypeRedirect},
+		{Name: LocalProviderName, Type: TypePassword},
+	}
+	if !reflect.DeepEqual(registry.Providers(), expected) {
+		t.Errorf("Expected providers %v, got %v", expected, registry.Providers())
+	}
+
+	// the local provider is the default, even if it is not the first one
+	if provider, ok := registry.PasswordProvider(""); !ok || provider.Name() != LocalProviderName {
+		t.Errorf("Expected the local provider as default, got %v", provider)
+	}
+	if provider, ok := registry.PasswordProvider("directory"); !ok || provider.Name() != "directory" {
+		t.Errorf("Expected the directory provider, got %v", provider)
+	}
+	if _, ok := registry.PasswordProvider("keycloak"); ok {
+		t.Errorf("Expected redirect provider not to be a password provider")
+	}
+	if provider, ok := registry.RedirectProvider("keycloak"); !ok || provider.Name() != "keycloak" {
+		t.Errorf("Expected the keycloak provider, got %v", provider)
+	}
+	if _, ok := registry.RedirectProvider("unknown"); ok {
+		t.Errorf("Expected unknown provider not to be found")
+	}
+}
+
+func TestRegistryWithoutLocalProvider(t *testing.T) {
+	registry := NewRegistry(redirectProviderMock{name: "keycloak"}, passwordProviderMock{name: "directory"})
+	if provider, ok := registry.PasswordProvider(""); !ok || provider.Name() != "directory" {
+		t.Errorf("Expected the first password provider as default, got %v", provider)
+	}
+
+	registry = NewRegistry(redirectProviderMock{name: "keycloak"})
+	if _, ok := registry.PasswordProvider(""); ok {
+		t.Errorf("Expected no default password provider")
+	}
+}
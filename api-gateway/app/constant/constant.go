@@ -1,5 +1,7 @@
 package constant
 
+import "net/http"
+
 type ResponseStatus int
 type Headers int
 type General int
@@ -12,6 +14,13 @@ const (
 	DataNotFound
 	Conflict
 	UnknownError
+	Forbidden
+	PasswordChangeRequired
+	TooManyRequests
+	TwoFactorRequired
+	BadGateway
+	GatewayTimeout
+	ServiceUnavailable
 )
 
 func (r ResponseStatus) GetResponseStatus() string {
@@ -22,6 +31,13 @@ func (r ResponseStatus) GetResponseStatus() string {
 		"Data Not Found",
 		"Conflict",
 		"Unknown Error",
+		"Forbidden",
+		"Password Change Required",
+		"Too Many Requests",
+		"Two Factor Required",
+		"Bad Gateway",
+		"Gateway Timeout",
+		"Service Unavailable",
 	}[r-1]
 }
 
@@ -33,5 +49,49 @@ func (r ResponseStatus) GetResponseMessage() string {
 		"Data Not Found: Data not found",
 		"Conflict: Data already exist",
 		"Unknown Error: Unknown error",
+		"Forbidden: Missing permission",
+		"Password Change Required: Please change your password",
+		"Too Many Requests: Please try again later",
+		"Two Factor Required: Please enable two-factor authentication",
+		"Bad Gateway: The planner-backend could not be reached",
+		"Gateway Timeout: The planner-backend did not respond in time",
+		"Service Unavailable: The planner-backend is unavailable, please try again later",
+	}[r-1]
+}
+
+func (r ResponseStatus) GetHTTPStatus() int {
+	return [...]int{
+		http.StatusOK,
+		http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusNotFound,
+		http.StatusConflict,
+		http.StatusInternalServerError,
+		http.StatusForbidden,
+		http.StatusForbidden,
+		http.StatusTooManyRequests,
+		http.StatusForbidden,
+		http.StatusBadGateway,
+		http.StatusGatewayTimeout,
+		http.StatusServiceUnavailable,
+	}[r-1]
+}
+
+// GetErrorCode returns the machine-readable code of the status, which clients can rely on
+func (r ResponseStatus) GetErrorCode() string {
+	return [...]string{
+		"success",
+		"invalid_request",
+		"unauthorized",
+		"not_found",
+		"conflict",
+		"unknown_error",
+		"forbidden",
+		"password_change_required",
+		"too_many_requests",
+		"two_factor_required",
+		"bad_gateway",
+		"gateway_timeout",
+		"service_unavailable",
 	}[r-1]
 }
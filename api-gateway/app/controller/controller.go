@@ -5,6 +5,8 @@ import "github.com/google/wire"
 var ControllerSet = wire.NewSet(
 	userControllerSet,
 	permissionControllerSet,
+	roleControllerSet,
 	departmentControllerSet,
 	systemControllerSet,
+	meControllerSet,
 )
@@ -13,6 +13,9 @@ type DepartmentController interface {
 	Create(ctx *gin.Context)
 	Update(ctx *gin.Context)
 	Delete(ctx *gin.Context)
+	Sync(ctx *gin.Context)
+	Remove(ctx *gin.Context)
+	Reconcile(ctx *gin.Context)
 }
 
 type DepartmentControllerImpl struct {
@@ -39,6 +42,18 @@ func (u DepartmentControllerImpl) Delete(ctx *gin.Context) {
 	u.DepartmentService.DeleteDepartment(ctx)
 }
 
+func (u DepartmentControllerImpl) Sync(ctx *gin.Context) {
+	u.DepartmentService.SyncDepartment(ctx)
+}
+
+func (u DepartmentControllerImpl) Remove(ctx *gin.Context) {
+	u.DepartmentService.RemoveDepartment(ctx)
+}
+
+func (u DepartmentControllerImpl) Reconcile(ctx *gin.Context) {
+	u.DepartmentService.ReconcileDepartments(ctx)
+}
+
 var departmentControllerSet = wire.NewSet(
 	wire.Struct(new(DepartmentControllerImpl), "*"),
 	wire.Bind(new(DepartmentController), new(*DepartmentControllerImpl)),
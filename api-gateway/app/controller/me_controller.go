@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"api-gateway/app/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+)
+
+type MeController interface {
+	Assignments(ctx *gin.Context)
+	Absences(ctx *gin.Context)
+	Hours(ctx *gin.Context)
+	LeaveRequests(ctx *gin.Context)
+	RequestLeave(ctx *gin.Context)
+}
+
+type MeControllerImpl struct {
+	MeService service.MeService
+}
+
+func (m MeControllerImpl) Assignments(ctx *gin.Context) {
+	m.MeService.Assignments(ctx)
+}
+
+func (m MeControllerImpl) Absences(ctx *gin.Context) {
+	m.MeService.Absences(ctx)
+}
+
+func (m MeControllerImpl) Hours(ctx *gin.Context) {
+	m.MeService.Hours(ctx)
+}
+
+func (m MeControllerImpl) LeaveRequests(ctx *gin.Context) {
+	m.MeService.LeaveRequests(ctx)
+}
+
+func (m MeControllerImpl) RequestLeave(ctx *gin.Context) {
+	m.MeService.RequestLeave(ctx)
+}
+
+var meControllerSet = wire.NewSet(
+	wire.Struct(new(MeControllerImpl), "*"),
+	wire.Bind(new(MeController), new(*MeControllerImpl)),
+)
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"api-gateway/app/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+)
+
+type RoleController interface {
+	GetAll(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	Create(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type RoleControllerImpl struct {
+	RoleService service.RoleService
+}
+
+func (u RoleControllerImpl) GetAll(ctx *gin.Context) {
+	u.RoleService.GetAllRoles(ctx)
+}
+
+func (u RoleControllerImpl) Get(ctx *gin.Context) {
+	u.RoleService.GetRoleById(ctx)
+}
+
+func (u RoleControllerImpl) Create(ctx *gin.Context) {
+	u.RoleService.AddRole(ctx)
+}
+
+func (u RoleControllerImpl) Update(ctx *gin.Context) {
+	u.RoleService.UpdateRole(ctx)
+}
+
+func (u RoleControllerImpl) Delete(ctx *gin.Context) {
+	u.RoleService.DeleteRole(ctx)
+}
+
+var roleControllerSet = wire.NewSet(
+	wire.Struct(new(RoleControllerImpl), "*"),
+	wire.Bind(new(RoleController), new(*RoleControllerImpl)),
+)
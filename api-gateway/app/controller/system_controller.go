@@ -2,7 +2,10 @@ package controller
 
 import (
 	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
 	"api-gateway/app/pkg"
+	"api-gateway/app/proxy"
+	"api-gateway/app/ratelimit"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -11,14 +14,41 @@ import (
 
 type SystemController interface {
 	Ping(ctx *gin.Context)
+	JWKS(ctx *gin.Context)
 }
 
-type SystemControllerImpl struct{}
+type SystemControllerImpl struct {
+	Routes      *proxy.Table
+	RateLimiter *ratelimit.Limiter
+}
 
 func (s SystemControllerImpl) Ping(c *gin.Context) {
-	defer pkg.PanicHandler(c)
+	/**
+	* Reports the health of the api-gateway and of the upstreams of its routes.
+	* The gateway itself is up, so an unhealthy upstream only degrades the status.
+	* The requests rejected by the rate limits are counted per group.
+	**/
+
+	data := dco.HealthResponse{Status: "ok", Routes: s.Routes.Health(), RateLimits: s.RateLimiter.Health()}
+	for _, route := range data.Routes {
+		for _, upstream := range route.Upstreams {
+			if !upstream.Healthy {
+				data.Status = "degraded"
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (s SystemControllerImpl) JWKS(c *gin.Context) {
+	/**
+	* Publishes the public keys of the access tokens, so other services can verify them.
+	* The key set is served as is, clients expect the format of RFC 7517.
+	**/
 
-	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, dco.JWTKeys.JWKS())
 }
 
 var systemControllerSet = wire.NewSet(
@@ -17,16 +17,56 @@ type UserController interface {
 	AddPermission(ctx *gin.Context)
 	DeletePermission(ctx *gin.Context)
 
+	AddRole(ctx *gin.Context)
+	DeleteRole(ctx *gin.Context)
+	SaveMembership(ctx *gin.Context)
+	DeleteMembership(ctx *gin.Context)
+	LinkPerson(ctx *gin.Context)
+	UnlinkPerson(ctx *gin.Context)
+
 	// Auth
 	Login(ctx *gin.Context)
 	Me(ctx *gin.Context)
 	Logout(ctx *gin.Context)
 	CheckAdmin(ctx *gin.Context)
+	Permissions(ctx *gin.Context)
+	Unlock(ctx *gin.Context)
+	Providers(ctx *gin.Context)
+	ExternalLogin(ctx *gin.Context)
+	ExternalCallback(ctx *gin.Context)
+
+	// Sessions
+	Refresh(ctx *gin.Context)
+	Sessions(ctx *gin.Context)
+	RevokeSession(ctx *gin.Context)
+	RevokeUserSessions(ctx *gin.Context)
+
+	// Passwords
+	ChangePassword(ctx *gin.Context)
+	RequestPasswordReset(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+
+	// Two-factor authentication
+	LoginTwoFactor(ctx *gin.Context)
+	EnrollTwoFactor(ctx *gin.Context)
+	ConfirmTwoFactor(ctx *gin.Context)
+	DisableTwoFactor(ctx *gin.Context)
+	RegenerateRecoveryCodes(ctx *gin.Context)
+	ResetTwoFactor(ctx *gin.Context)
+
+	// API keys
+	GetAPIKeys(ctx *gin.Context)
+	CreateAPIKey(ctx *gin.Context)
+	RevokeAPIKey(ctx *gin.Context)
 }
 
 type UserControllerImpl struct {
-	AuthService service.AuthService
-	UserService service.UserService
+	AuthService      service.AuthService
+	UserService      service.UserService
+	SessionService   service.SessionService
+	PasswordService  service.PasswordService
+	TwoFactorService service.TwoFactorService
+	APIKeyService    service.APIKeyService
 }
 
 func (u UserControllerImpl) GetAll(ctx *gin.Context) {	
@@ -61,6 +101,30 @@ func (u UserControllerImpl) DeletePermission(ctx *gin.Context) {
 	u.UserService.DeletePermission(ctx)
 }
 
+func (u UserControllerImpl) AddRole(ctx *gin.Context) {
+	u.UserService.AddRole(ctx)
+}
+
+func (u UserControllerImpl) DeleteRole(ctx *gin.Context) {
+	u.UserService.DeleteRole(ctx)
+}
+
+func (u UserControllerImpl) SaveMembership(ctx *gin.Context) {
+	u.UserService.SaveMembership(ctx)
+}
+
+func (u UserControllerImpl) DeleteMembership(ctx *gin.Context) {
+	u.UserService.DeleteMembership(ctx)
+}
+
+func (u UserControllerImpl) LinkPerson(ctx *gin.Context) {
+	u.UserService.LinkPerson(ctx)
+}
+
+func (u UserControllerImpl) UnlinkPerson(ctx *gin.Context) {
+	u.UserService.UnlinkPerson(ctx)
+}
+
 func (u UserControllerImpl) Login(ctx *gin.Context) {
 	u.AuthService.Login(ctx)
 }
@@ -77,7 +141,91 @@ func (u UserControllerImpl) CheckAdmin(ctx *gin.Context) {
 	u.AuthService.CheckAdmin(ctx)
 }
 
+func (u UserControllerImpl) Permissions(ctx *gin.Context) {
+	u.AuthService.Permissions(ctx)
+}
+
 var userControllerSet = wire.NewSet(
 	wire.Struct(new(UserControllerImpl), "*"),
 	wire.Bind(new(UserController), new(*UserControllerImpl)),
 )
+
+func (u UserControllerImpl) Unlock(ctx *gin.Context) {
+	u.AuthService.Unlock(ctx)
+}
+
+func (u UserControllerImpl) Providers(ctx *gin.Context) {
+	u.AuthService.Providers(ctx)
+}
+
+func (u UserControllerImpl) ExternalLogin(ctx *gin.Context) {
+	u.AuthService.ExternalLogin(ctx)
+}
+
+func (u UserControllerImpl) ExternalCallback(ctx *gin.Context) {
+	u.AuthService.ExternalCallback(ctx)
+}
+
+func (u UserControllerImpl) Refresh(ctx *gin.Context) {
+	u.SessionService.Refresh(ctx)
+}
+
+func (u UserControllerImpl) Sessions(ctx *gin.Context) {
+	u.SessionService.GetSessions(ctx)
+}
+
+func (u UserControllerImpl) RevokeSession(ctx *gin.Context) {
+	u.SessionService.RevokeSession(ctx)
+}
+
+func (u UserControllerImpl) RevokeUserSessions(ctx *gin.Context) {
+	u.SessionService.RevokeUserSessions(ctx)
+}
+
+func (u UserControllerImpl) ChangePassword(ctx *gin.Context) {
+	u.PasswordService.ChangePassword(ctx)
+}
+
+func (u UserControllerImpl) RequestPasswordReset(ctx *gin.Context) {
+	u.PasswordService.RequestPasswordReset(ctx)
+}
+
+func (u UserControllerImpl) ResetPassword(ctx *gin.Context) {
+	u.PasswordService.ResetPassword(ctx)
+}
+
+func (u UserControllerImpl) LoginTwoFactor(ctx *gin.Context) {
+	u.AuthService.LoginTwoFactor(ctx)
+}
+
+func (u UserControllerImpl) EnrollTwoFactor(ctx *gin.Context) {
+	u.TwoFactorService.EnrollTwoFactor(ctx)
+}
+
+func (u UserControllerImpl) ConfirmTwoFactor(ctx *gin.Context) {
+	u.TwoFactorService.ConfirmTwoFactor(ctx)
+}
+
+func (u UserControllerImpl) DisableTwoFactor(ctx *gin.Context) {
+	u.TwoFactorService.DisableTwoFactor(ctx)
+}
+
+func (u UserControllerImpl) RegenerateRecoveryCodes(ctx *gin.Context) {
+	u.TwoFactorService.RegenerateRecoveryCodes(ctx)
+}
+
+func (u UserControllerImpl) ResetTwoFactor(ctx *gin.Context) {
+	u.TwoFactorService.ResetTwoFactor(ctx)
+}
+
+func (u UserControllerImpl) GetAPIKeys(ctx *gin.Context) {
+	u.APIKeyService.GetAPIKeys(ctx)
+}
+
+func (u UserControllerImpl) CreateAPIKey(ctx *gin.Context) {
+	u.APIKeyService.CreateAPIKey(ctx)
+}
+
+func (u UserControllerImpl) RevokeAPIKey(ctx *gin.Context) {
+	u.APIKeyService.RevokeAPIKey(ctx)
+}
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKey struct {
+	// Named key for scripts and service accounts. It acts on behalf of its user with a subset of the permissions
+	BaseModel
+
+	Name   string    `gorm:"type:varchar(255);column:name;not null"`
+	UserID uuid.UUID `gorm:"type:uuid;column:user_id;not null;index"`
+	User   User      `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Only the SHA-256 hash of the key is stored, the prefix identifies the key in listings
+	KeyHash string `gorm:"type:varchar(64);column:key_hash;not null;uniqueIndex"`
+	Prefix  string `gorm:"type:varchar(16);column:prefix;not null"`
+
+	// The key holds these permissions as long as its user holds them
+	Permissions []Permission `gorm:"many2many:api_key_permissions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+}
+
+func (k APIKey) IsValid() bool {
+	// Checks if the key can still be used
+	return k.ExpiresAt.After(time.Now())
+}
+
+func (k APIKey) PermissionNames() []string {
+	// Helper function to collect the permissions of the key which are still held by its user.
+	// The user has to be loaded with its permissions and roles.
+	return k.PermissionNamesIn(k.User.DepartmentID)
+}
+
+func (k APIKey) PermissionNamesIn(departmentID uuid.UUID) []string {
+	// Helper function to collect the permissions of the key which are held by its user within a department
+	held := map[string]bool{}
+	for _, name := range k.User.PermissionNamesIn(departmentID) {
+		held[name] = true
+	}
+
+	names := []string{}
+	for _, permission := range k.Permissions {
+		if k.User.IsSystemAdmin() || held[permission.Name] {
+			names = append(names, permission.Name)
+		}
+	}
+	return names
+}
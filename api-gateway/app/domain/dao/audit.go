@@ -0,0 +1,23 @@
+package dao
+
+const (
+	AuditEventLoginLocked     = "login.locked"
+	AuditEventLoginUnlocked   = "login.unlocked"
+	AuditEventUserProvisioned = "user.provisioned"
+	AuditEventTwoFactorReset  = "two_factor.reset"
+	AuditEventAPIKeyCreated   = "api_key.created"
+	AuditEventAPIKeyRevoked   = "api_key.revoked"
+)
+
+type AuditEntry struct {
+	// Security relevant events, e.g. lockouts after failed logins
+	BaseModel
+
+	Event string `gorm:"type:varchar(64);column:event;not null;index"`
+	// The user or the client IP the event is about
+	Subject string `gorm:"type:varchar(255);column:subject;not null;index"`
+	// The user who triggered the event, empty for events triggered by the system
+	Actor     string `gorm:"type:varchar(255);column:actor"`
+	IPAddress string `gorm:"type:varchar(64);column:ip_address"`
+	Details   string `gorm:"type:text;column:details"`
+}
@@ -0,0 +1,23 @@
+package dao
+
+import (
+	"time"
+)
+
+const (
+	// Prefixes of the throttle keys, failed logins are tracked per username and per client IP
+	LoginThrottleUsernamePrefix = "username:"
+	LoginThrottleIPPrefix       = "ip:"
+)
+
+type LoginThrottle struct {
+	// Tracks the consecutive failed logins of a username or a client IP
+	Key           string     `gorm:"type:varchar(255);column:throttle_key;primaryKey"`
+	Failures      int        `gorm:"column:failures;not null;default:0"`
+	LastFailureAt time.Time  `gorm:"column:last_failure_at;not null"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+}
+
+func (t LoginThrottle) IsLocked() bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(time.Now())
+}
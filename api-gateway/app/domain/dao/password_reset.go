@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PasswordReset struct {
+	// A one-time token to reset the password of a user, it is sent to the user by email
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;column:user_id;not null;index"`
+	User   User      `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Only the SHA-256 hash of the token is stored
+	TokenHash string     `gorm:"type:varchar(64);column:token_hash;not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+}
+
+func (p PasswordReset) IsValid() bool {
+	// Checks if the token can still be used
+	return p.UsedAt == nil && p.ExpiresAt.After(time.Now())
+}
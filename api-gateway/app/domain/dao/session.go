@@ -0,0 +1,41 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	// A session is created at login and kept alive by rotating refresh tokens
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;column:user_id;not null;index"`
+	User   User      `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Only the SHA-256 hashes of the refresh tokens are stored
+	RefreshTokenHash string `gorm:"type:varchar(64);column:refresh_token_hash;not null;uniqueIndex"`
+	// The hash of the previous refresh token is kept to detect the reuse of a rotated token
+	PreviousRefreshTokenHash string     `gorm:"type:varchar(64);column:previous_refresh_token_hash;index"`
+	RotatedAt                *time.Time `gorm:"column:rotated_at"`
+	// Mutating requests have to repeat this token, it stays the same for the whole session
+	CSRFToken string `gorm:"type:varchar(64);column:csrf_token"`
+
+	UserAgent  string     `gorm:"type:varchar(255);column:user_agent"`
+	IPAddress  string     `gorm:"type:varchar(64);column:ip_address"`
+	LastUsedAt time.Time  `gorm:"column:last_used_at;not null"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+}
+
+func (s Session) IsActive() bool {
+	// Checks if the session can still be refreshed
+	return s.RevokedAt == nil && s.ExpiresAt.After(time.Now())
+}
+
+type RevokedToken struct {
+	// Entry of the token revocation list. Access tokens of a revoked session are rejected
+	// until they expire, afterwards the entry can be removed.
+	SessionID uuid.UUID `gorm:"type:uuid;column:session_id;primaryKey"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null;index"`
+}
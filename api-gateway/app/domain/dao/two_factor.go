@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactor struct {
+	// The TOTP second factor of a user, it is enabled once the user entered a valid code
+	UserID uuid.UUID `gorm:"type:uuid;column:user_id;primaryKey"`
+
+	Secret    string     `gorm:"type:varchar(64);column:secret;not null"`
+	EnabledAt *time.Time `gorm:"column:enabled_at"`
+	// The time step of the last accepted code, every code can only be used once
+	LastUsedStep int64 `gorm:"column:last_used_step;not null;default:0"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (t TwoFactor) IsEnabled() bool {
+	return t.EnabledAt != nil
+}
+
+type RecoveryCode struct {
+	// One-time codes to log in without the authenticator app
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;column:user_id;not null;index"`
+	User   User      `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Only the SHA-256 hash of the code is stored
+	CodeHash string     `gorm:"type:varchar(64);column:code_hash;not null"`
+	UsedAt   *time.Time `gorm:"column:used_at"`
+}
+
+type LoginChallenge struct {
+	// Issued after the password of a user with two-factor authentication was checked.
+	// The session is started once the second factor is entered.
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;column:user_id;not null;index"`
+	User   User      `gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Only the SHA-256 hash of the token is stored
+	TokenHash string    `gorm:"type:varchar(64);column:token_hash;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"column:expires_at;not null"`
+}
+
+func (l LoginChallenge) IsValid() bool {
+	return l.ExpiresAt.After(time.Now())
+}
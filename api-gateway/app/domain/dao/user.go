@@ -24,8 +24,16 @@ type Department struct {
 	BaseModel
 
 	Name string `gorm:"type:varchar(255);column:name;unique;not null"`
+	// The id of the department in the planner-backend, set by the synchronization
+	PlannerID string `gorm:"type:varchar(255);column:planner_id;index"`
 }
 
+// Users created in the gateway are authenticated with their local password
+const AuthProviderLocal = "local"
+
+// Service accounts have no password, they are only used with API keys
+const AuthProviderServiceAccount = "service-account"
+
 type User struct {
 	// This is a simple user model
 	BaseModel
@@ -34,14 +42,218 @@ type User struct {
 	// ->:false read-only field
 	Password string `gorm:"type:varchar(255);column:password;not null"`
 	Email    string `gorm:"type:varchar(255);column:email;not null"`
+	// Seeded accounts have to change their password before they can use the application
+	MustChangePassword bool `gorm:"column:must_change_password;not null;default:false"`
 
-	// boolean field
-	IsAdmin bool `gorm:"type:boolean;column:is_admin;not null;default:false" json:"is_admin"`
+	// Users of external identity providers are identified by the provider and their subject at the provider
+	AuthProvider    string  `gorm:"type:varchar(64);column:auth_provider;not null;default:local;index:idx_external_identity,unique"`
+	ExternalSubject *string `gorm:"type:varchar(255);column:external_subject;default:null;index:idx_external_identity,unique"`
 
 	// Each User belongs to a department
 	DepartmentID uuid.UUID  `gorm:"type:uuid;column:department_id;not null"`
 	Department   Department `gorm:"foreignKey:DepartmentID;references:ID"`
 
+	// A User can be linked to a person of the planner-backend to see the own shifts
+	PersonID *string `gorm:"type:varchar(255);column:person_id;default:null;index:idx_person_id,unique"`
+
+	// Each User can belong to further departments, with a role within each of them
+	Memberships []DepartmentMembership `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
 	// Each User has multiple permissions
 	Permissions []Permission `gorm:"many2many:user_permissions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Each User has multiple roles, optionally limited to a department
+	Roles []UserRole `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// The second factor of the user, nil until the user starts the enrollment
+	TwoFactor *TwoFactor `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+func (u User) IsExternal() bool {
+	// External users are authenticated by their provider and have no local password
+	return u.AuthProvider != "" && u.AuthProvider != AuthProviderLocal
+}
+
+func (u User) IsServiceAccount() bool {
+	// Service accounts are used by scripts and jobs, they cannot log in
+	return u.AuthProvider == AuthProviderServiceAccount
+}
+
+func (u User) HasTwoFactor() bool {
+	// Users with an enabled second factor have to enter a code on every login
+	return u.TwoFactor != nil && u.TwoFactor.IsEnabled()
+}
+
+func (u User) RequiresTwoFactor() bool {
+	// A second factor is required if one of the roles of the user requires it, regardless of the department
+	for _, role := range u.Roles {
+		if role.Role.RequireTwoFactor {
+			return true
+		}
+	}
+	return false
+}
+
+func (u User) IsSystemAdmin() bool {
+	// System admins hold every permission, regardless of the department
+	for _, role := range u.Roles {
+		if role.Role.Name == RoleSystemAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+func (u User) Departments() []Department {
+	// Helper function to collect the departments of the user, the department of the user comes first
+	departments := []Department{u.Department}
+	for _, membership := range u.Memberships {
+		if membership.DepartmentID != u.DepartmentID {
+			departments = append(departments, membership.Department)
+		}
+	}
+	return departments
+}
+
+func (u User) IsMemberOf(departmentID uuid.UUID) bool {
+	// The department of the user always counts as membership
+	if u.DepartmentID == departmentID {
+		return true
+	}
+	for _, membership := range u.Memberships {
+		if membership.DepartmentID == departmentID {
+			return true
+		}
+	}
+	return false
+}
+
+func (u User) MembershipRole(departmentID uuid.UUID) *Role {
+	// Returns the role of the membership in a department, nil if there is none
+	for _, membership := range u.Memberships {
+		if membership.DepartmentID == departmentID {
+			return membership.Role
+		}
+	}
+	return nil
+}
+
+func (u User) ActiveRoles() []UserRole {
+	// Helper function to collect the roles that apply to the department of the user
+	return u.RolesIn(u.DepartmentID)
+}
+
+func (u User) RolesIn(departmentID uuid.UUID) []UserRole {
+	// Helper function to collect the roles that apply within a department, including the role of the membership
+	roles := []UserRole{}
+	for _, role := range u.Roles {
+		if role.AppliesTo(departmentID) {
+			roles = append(roles, role)
+		}
+	}
+	for _, membership := range u.Memberships {
+		if membership.DepartmentID == departmentID && membership.Role != nil {
+			roles = append(roles, UserRole{
+				UserID:       u.ID,
+				RoleID:       membership.Role.ID,
+				Role:         *membership.Role,
+				DepartmentID: &membership.DepartmentID,
+				Department:   &membership.Department,
+			})
+		}
+	}
+	return roles
+}
+
+func (u User) RoleNames() []string {
+	// Helper function to collect the names of the roles that apply to the department of the user
+	return u.RoleNamesIn(u.DepartmentID)
+}
+
+func (u User) RoleNamesIn(departmentID uuid.UUID) []string {
+	// Helper function to collect the names of the roles that apply within a department
+	names := []string{}
+	for _, role := range u.RolesIn(departmentID) {
+		names = append(names, role.Role.Name)
+	}
+	return names
+}
+
+func (u User) PermissionNames() []string {
+	// Helper function to collect the names of the permissions of a user within the department of the user
+	return u.PermissionNamesIn(u.DepartmentID)
+}
+
+func (u User) PermissionNamesIn(departmentID uuid.UUID) []string {
+	// Helper function to collect the names of the permissions of a user within a department.
+	// Permissions are resolved from the direct permissions and the roles that apply within the department.
+	names := []string{}
+	seen := map[string]bool{}
+	add := func(permissions []Permission) {
+		for _, permission := range permissions {
+			if !seen[permission.Name] {
+				seen[permission.Name] = true
+				names = append(names, permission.Name)
+			}
+		}
+	}
+
+	add(u.Permissions)
+	for _, role := range u.RolesIn(departmentID) {
+		add(role.Role.Permissions)
+	}
+	return names
+}
+
+// Names of the default roles, they are created by the migration
+const (
+	RoleViewer          = "viewer"
+	RolePlanner         = "planner"
+	RoleDepartmentAdmin = "department-admin"
+	RoleSystemAdmin     = "system-admin"
+)
+
+type Role struct {
+	// A role is a named bundle of permissions
+	BaseModel
+
+	Name        string         `gorm:"type:varchar(255);column:name;unique;not null"`
+	Description sql.NullString `gorm:"type:varchar(255);column:description;default:null"`
+	// Users holding the role have to enable two-factor authentication
+	RequireTwoFactor bool `gorm:"column:require_two_factor;not null;default:false"`
+
+	Permissions []Permission `gorm:"many2many:role_permissions;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+type UserRole struct {
+	// Assignment of a role to a user. Without a department the role applies to every department
+	BaseModel
+
+	UserID uuid.UUID `gorm:"type:uuid;column:user_id;not null;index"`
+	RoleID uuid.UUID `gorm:"type:uuid;column:role_id;not null"`
+	Role   Role      `gorm:"foreignKey:RoleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	DepartmentID *uuid.UUID  `gorm:"type:uuid;column:department_id;default:null"`
+	Department   *Department `gorm:"foreignKey:DepartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Provisioned roles are mapped from the groups of an external provider and replaced on every login
+	Provisioned bool `gorm:"column:provisioned;not null;default:false"`
+}
+
+type DepartmentMembership struct {
+	// Membership of a user in a further department, the role of the membership applies within the department only
+	BaseModel
+
+	UserID       uuid.UUID  `gorm:"type:uuid;column:user_id;not null;index"`
+	DepartmentID uuid.UUID  `gorm:"type:uuid;column:department_id;not null;index"`
+	Department   Department `gorm:"foreignKey:DepartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+
+	// Without a role the user only holds the permissions which apply to every department
+	RoleID *uuid.UUID `gorm:"type:uuid;column:role_id;default:null"`
+	Role   *Role      `gorm:"foreignKey:RoleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
+
+func (r UserRole) AppliesTo(departmentID uuid.UUID) bool {
+	// Checks if the role assignment is valid within the given department
+	return r.DepartmentID == nil || *r.DepartmentID == departmentID
 }
@@ -1,30 +1,285 @@
 package dco
 
 import (
-	"os"
+	"api-gateway/app/jwtkeys"
+	"net/http"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// Name of the password provider, the local passwords are used if it is empty
+	Provider string `json:"provider"`
 }
 
-var JWTSigningKey = []byte(os.Getenv("GATEWAY_JWT_SIGNING_KEY"))
-var JWTExpirationTime = 12 * time.Hour
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// Either the code of the authenticator app or a recovery code is required
+type TwoFactorLoginRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool `json:"two_factor_required"`
+}
+
+type TwoFactorEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	// The otpauth URI to show as QR code
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	// The codes are only shown once
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type APIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+	// The key holds a subset of the permissions of its user
+	PermissionIDs []uuid.UUID `json:"permission_ids" binding:"required,min=1"`
+	ExpiresAt     time.Time   `json:"expires_at" binding:"required"`
+}
+
+type APIKeyResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+}
+
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	// The key is only shown once
+	Key string `json:"key"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// The keys which sign and verify the access tokens, loaded at startup
+var JWTKeys *jwtkeys.Keyring
+
+// The issuer of the access tokens, checked by the planner-backend
+const JWTIssuer = "api-gateway"
+
+// Access tokens are short-lived, sessions are kept alive by rotating refresh tokens
+var JWTExpirationTime = 15 * time.Minute
+var RefreshTokenExpirationTime = 7 * 24 * time.Hour
+
+// A rotated refresh token presented again within this period is rejected without revoking the session,
+// since concurrent requests of the same client may race for the refresh
+var RefreshTokenReuseGracePeriod = 30 * time.Second
+
+// Password reset tokens are sent by email and can be used once within this period
+var PasswordResetExpirationTime = time.Hour
+
+// The link in the password reset email, the token is appended as ?token= query parameter, loaded at startup
+var PasswordResetURL string
+
+// The second factor has to be entered within this period after the password
+var TwoFactorChallengeExpirationTime = 5 * time.Minute
+
+// Number of recovery codes generated for two-factor authentication
+var RecoveryCodeCount = 10
+
+// The issuer shown by the authenticator app, "Planner" is used if it is empty, loaded at startup
+var TOTPIssuer string
+
+// API keys cannot be valid for longer than this period
+var APIKeyMaxLifetime = 365 * 24 * time.Hour
+
+// The last use of an API key is stored at most once within this period
+var APIKeyLastUsedInterval = time.Minute
+
+// The login at an external provider has to be completed within this period
+var ExternalLoginExpirationTime = 10 * time.Minute
+
+// The client is redirected here after a login at an external provider, the root is used if it is empty, loaded at startup
+var PostLoginURL string
+
+// Attributes of the cookies set by the gateway, loaded at startup
+type CookieConfig struct {
+	// Cookies are only sent over HTTPS
+	Secure bool
+	// Cookies are shared with the subdomains of the domain, empty for cookies of the host only
+	Domain   string
+	SameSite http.SameSite
+}
+
+var Cookies = CookieConfig{SameSite: http.SameSiteLaxMode}
+
+const (
+	AccessTokenCookie  = "Authorization"
+	RefreshTokenCookie = "Refresh"
+	// The refresh token is only sent to the auth routes
+	RefreshTokenCookiePath = "/auth"
+	// Holds the state, nonce and code verifier of a login at an external provider
+	ExternalLoginCookie = "ExternalLogin"
+	// Holds the login challenge until the second factor is entered
+	TwoFactorChallengeCookie = "TwoFactor"
+	// Holds the CSRF token of the session, it is readable by the frontend
+	CSRFTokenCookie = "csrf_token"
+	// Mutating requests authenticated by the cookie repeat the CSRF token in this header
+	CSRFTokenHeader = "X-CSRF-Token"
+
+	// API keys are sent as "Authorization: Bearer <key>" or in this header
+	APIKeyHeader = "X-API-Key"
+	// Prefix of the generated API keys, it makes leaked keys easy to spot
+	APIKeyPrefix = "pk_"
+)
 
 type JWTClaim struct {
+	SessionID   string
 	Username    string
 	Department  string
+	Roles       []string
 	Permissions []string
 	IsAdmin     bool
+	// The id of the department in the planner-backend
+	PlannerDepartmentID string
+	// All departments of the user including the department above, with the roles and permissions within each of them
+	Memberships []DepartmentMembershipClaim
+	// Only the auth routes can be used until the password is changed
+	MustChangePassword bool
+	// Only the auth routes can be used until two-factor authentication is enabled
+	MustEnrollTwoFactor bool
+	// Set if the request was authenticated with an API key instead of a session
+	APIKeyID string `json:",omitempty"`
+	// The CSRF token of the session, the token is bound to the access token by the signature
+	CSRFToken string `json:",omitempty"`
 
 	jwt.RegisteredClaims
 }
 
+type DepartmentMembershipClaim struct {
+	Department          string
+	PlannerDepartmentID string
+	Roles               []string
+	Permissions         []string
+}
+
+func (c *JWTClaim) InDepartment(plannerDepartmentID string) (*JWTClaim, bool) {
+	/**
+	* Returns the claim with the roles and permissions of the user within a department of the planner-backend.
+	* Admins act in every department, other users only in the departments they belong to.
+	* The claim is returned unchanged if the user does not belong to the department.
+	**/
+	for _, membership := range c.Memberships {
+		if membership.PlannerDepartmentID == plannerDepartmentID {
+			scoped := *c
+			scoped.Department = membership.Department
+			scoped.PlannerDepartmentID = membership.PlannerDepartmentID
+			scoped.Roles = membership.Roles
+			scoped.Permissions = membership.Permissions
+			return &scoped, true
+		}
+	}
+
+	// tokens issued before the memberships only name the department of the user
+	if len(c.Memberships) == 0 && c.PlannerDepartmentID != "" && c.PlannerDepartmentID == plannerDepartmentID {
+		return c, true
+	}
+
+	if c.IsAdmin {
+		scoped := *c
+		scoped.Department = ""
+		scoped.PlannerDepartmentID = plannerDepartmentID
+		return &scoped, true
+	}
+	return c, false
+}
+
+func (c *JWTClaim) HasPermissionInAnyDepartment(permission string) bool {
+	// Checks the permissions of every department of the user
+	if c.HasPermission(permission) {
+		return true
+	}
+	for _, membership := range c.Memberships {
+		for _, p := range membership.Permissions {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *JWTClaim) HasPermission(permission string) bool {
+	// Admins hold every permission
+	if c.IsAdmin {
+		return true
+	}
+
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 type AuthResponse struct {
-	Username string `json:"username"`
-	Email    string `json:"email"`
+	Username    string                         `json:"username"`
+	Email       string                         `json:"email"`
+	Memberships []DepartmentMembershipResponse `json:"memberships"`
+	// The person of the planner-backend linked to the user, nil without link
+	Person *PlannerPerson `json:"person"`
+}
+
+type DepartmentMembershipResponse struct {
+	DepartmentID   uuid.UUID `json:"department_id"`
+	DepartmentName string    `json:"department_name"`
+	PlannerID      string    `json:"planner_id"`
+	// The role of the membership, nil for the department of the user or a membership without role
+	Role        *string  `json:"role"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+const (
+	// Admins and roles without a department hold their permissions regardless of the department
+	PermissionScopeGlobal = "global"
+	// Permissions assigned to a user or a role within a department are limited to that department
+	PermissionScopeDepartment = "department"
+)
+
+type PermissionScope struct {
+	Type           string     `json:"type"`
+	DepartmentID   *uuid.UUID `json:"department_id,omitempty"`
+	DepartmentName *string    `json:"department_name,omitempty"`
+}
+
+type EffectivePermissionResponse struct {
+	Name        string          `json:"name"`
+	Description *string         `json:"description"`
+	Scope       PermissionScope `json:"scope"`
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	// Current marks the session of the request
+	Current bool `json:"current"`
 }
@@ -0,0 +1,40 @@
+package dco
+
+/**
+* The identity of an authenticated user is forwarded to the planner-backend via these headers.
+* The planner-backend has to share the signing key to be able to verify the signature.
+**/
+const (
+	IdentityUserHeader        = "X-Identity-User"
+	IdentityDepartmentHeader  = "X-Identity-Department"
+	IdentityAdminHeader       = "X-Identity-Admin"
+	IdentityPermissionsHeader = "X-Identity-Permissions"
+	IdentityTimestampHeader   = "X-Identity-Timestamp"
+	IdentitySignatureHeader   = "X-Identity-Signature"
+)
+
+// IdentityHeaders lists all headers that are stripped from incoming client requests
+var IdentityHeaders = []string{
+	IdentityUserHeader,
+	IdentityDepartmentHeader,
+	IdentityAdminHeader,
+	IdentityPermissionsHeader,
+	IdentityTimestampHeader,
+	IdentitySignatureHeader,
+}
+
+// Shared with the planner-backend, loaded at startup
+var IdentitySigningKey []byte
+
+// The services call the internal routes of each other with a signed identity of these names
+const (
+	GatewayServiceName = "api-gateway"
+	PlannerServiceName = "planner-backend"
+)
+
+type Identity struct {
+	Username    string
+	Department  string
+	IsAdmin     bool
+	Permissions []string
+}
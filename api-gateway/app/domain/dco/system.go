@@ -0,0 +1,37 @@
+package dco
+
+import "time"
+
+// Health of the api-gateway, returned by /api/v1/ping
+type HealthResponse struct {
+	// "ok", or "degraded" if an upstream is unhealthy
+	Status string        `json:"status"`
+	Routes []RouteHealth `json:"routes"`
+	// Requests rejected by the rate limits since the start of the gateway
+	RateLimits []RateLimitHealth `json:"rate_limits"`
+}
+
+// Health of the upstreams of a route of the proxy
+type RouteHealth struct {
+	Name      string          `json:"name"`
+	Prefix    string          `json:"prefix"`
+	Upstreams []BackendHealth `json:"upstreams"`
+}
+
+type BackendHealth struct {
+	Target  string `json:"target"`
+	Healthy bool   `json:"healthy"`
+	// State of the circuit breaker: closed, open or half-open
+	Circuit   string     `json:"circuit"`
+	LastCheck *time.Time `json:"last_check"`
+	LastError string     `json:"last_error,omitempty"`
+	// Requests in flight
+	Active int64 `json:"active"`
+}
+
+type RateLimitHealth struct {
+	Group string `json:"group"`
+	// e.g. 60/1m0s, or off
+	Limit    string `json:"limit"`
+	Rejected int64  `json:"rejected"`
+}
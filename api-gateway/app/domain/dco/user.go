@@ -10,7 +10,20 @@ import (
 type DepartmentResponse struct {
 	BaseModel
 
-	Name string `json:"name"`
+	Name      string `json:"name"`
+	PlannerID string `json:"planner_id"`
+}
+
+// Result of the reconciliation of the departments with the planner-backend, by planner id
+type DepartmentReconciliationResponse struct {
+	// Created or renamed in the planner-backend
+	Pushed []string `json:"pushed"`
+	// Created in the api-gateway
+	Imported []string `json:"imported"`
+	// Deleted in the planner-backend, they were deleted in the api-gateway before
+	Deleted []string `json:"deleted"`
+	// Not imported since a department with the same name exists
+	Conflicts []string `json:"conflicts"`
 }
 
 type PermissionResponse struct {
@@ -20,6 +33,22 @@ type PermissionResponse struct {
 	Description *string `json:"description"`
 }
 
+type RoleResponse struct {
+	BaseModel
+
+	Name             string               `json:"name"`
+	Description      *string              `json:"description"`
+	Permissions      []PermissionResponse `json:"permissions"`
+	RequireTwoFactor bool                 `json:"require_two_factor"`
+}
+
+type UserRoleResponse struct {
+	// Without a department the role applies to every department
+	Name         string     `json:"name"`
+	RoleID       uuid.UUID  `json:"role_id"`
+	DepartmentID *uuid.UUID `json:"department_id"`
+}
+
 type UserResponse struct {
 	BaseModel
 
@@ -27,6 +56,15 @@ type UserResponse struct {
 	Email      string             `json:"email"`
 	IsAdmin    bool               `json:"is_admin"`
 	Department DepartmentResponse `json:"department"`
+	Roles      []UserRoleResponse `json:"roles"`
+	// All departments of the user including the department above
+	Memberships []DepartmentMembershipResponse `json:"memberships"`
+	// The person of the planner-backend linked to the user
+	PersonID *string `json:"person_id"`
+
+	MustChangePassword bool `json:"must_change_password"`
+	TwoFactorEnabled   bool `json:"two_factor_enabled"`
+	ServiceAccount     bool `json:"service_account"`
 }
 
 func (res UserResponse) MarshalJSON() ([]byte, error) {
@@ -50,6 +88,45 @@ func (res UserResponse) MarshalJSON() ([]byte, error) {
 /** Requests **/
 type DepartmentRequest struct {
 	Name string `json:"name" binding:"required"`
+	// Links the department to an existing department of the planner-backend, a new id is generated if it is empty
+	PlannerID string `json:"planner_id"`
+}
+
+// The planner-backend creates or renames a department with its planner id in the path
+type DepartmentSyncRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// A department of the planner-backend as returned by its internal API
+type PlannerDepartment struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Links a user to a person of the planner-backend
+type PersonLinkRequest struct {
+	PersonID string `json:"person_id" binding:"required"`
+}
+
+// A person of the planner-backend as returned by its API
+type PlannerPerson struct {
+	ID        string `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// A leave request of the logged in user, filed at the planner-backend for the linked person
+type LeaveRequest struct {
+	StartDate string  `json:"start_date" binding:"required"`
+	EndDate   string  `json:"end_date" binding:"required"`
+	Reason    *string `json:"reason"`
+}
+
+// A leave request as sent to the planner-backend, on behalf of the user
+type PlannerLeaveRequest struct {
+	LeaveRequest
+	RequestedBy string `json:"requested_by"`
 }
 
 type PermissionRequest struct {
@@ -57,29 +134,24 @@ type PermissionRequest struct {
 	Description *string `json:"description" binding:"omitempty"`
 }
 
+type RoleRequest struct {
+	Name             string      `json:"name" binding:"required"`
+	Description      *string     `json:"description" binding:"omitempty"`
+	PermissionIDs    []uuid.UUID `json:"permission_ids" binding:"omitempty"`
+	RequireTwoFactor bool        `json:"require_two_factor"`
+}
+
+// Adds a user to a further department, the role applies within the department only
+type MembershipRequest struct {
+	RoleID *uuid.UUID `json:"role_id"`
+}
+
 type UserRequest struct {
 	Username string `json:"username" binding:"required,alpha,len=4,excludesall=!@#$%^&*()_+-="`
-	Password string `json:"password" binding:"required"`
+	Password string `json:"password" binding:"required_unless=ServiceAccount true"`
 	Email    string `json:"email" binding:"required,email"`
-	IsAdmin  bool   `json:"is_admin"`
 
 	DepartmentID uuid.UUID `json:"department_id" binding:"required"`
-}
-
-func (req UserRequest) MarshalJSON() ([]byte, error) {
-	/* MarshalJSON is a function to marshal user request
-	 * @param req is UserRequest
-	 * @return ([]byte, error)
-	 */
-	type Alias UserRequest
-	x := Alias(req)
-
-	// handle boolean
-	if req.IsAdmin {
-		req.IsAdmin = true
-	} else {
-		req.IsAdmin = false
-	}
-
-	return json.Marshal(x)
+	// Service accounts have no password, they are used with API keys only
+	ServiceAccount bool `json:"service_account"`
 }
@@ -0,0 +1,30 @@
+package dto
+
+// APIErrorResponse is the envelope of a failed request
+type APIErrorResponse struct {
+	ResponseKey     string `json:"response_key"`
+	ResponseMessage string `json:"response_message"`
+	// Machine-readable, e.g. not_found or validation_failed
+	Code   string        `json:"code"`
+	Data   any           `json:"data"`
+	Errors []ErrorDetail `json:"errors"`
+}
+
+// Problem is a failed request as application/problem+json (RFC 7807), it is sent to clients which accept it
+type Problem struct {
+	Type     string        `json:"type"`
+	Title    string        `json:"title"`
+	Status   int           `json:"status"`
+	Detail   string        `json:"detail"`
+	Instance string        `json:"instance,omitempty"`
+	Code     string        `json:"code"`
+	Errors   []ErrorDetail `json:"errors"`
+}
+
+// ErrorDetail describes a single problem of a request, e.g. an invalid field
+type ErrorDetail struct {
+	// The JSON key of the field, nested fields are separated by dots
+	Field   string `json:"field,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,193 @@
+/**
+* This package holds the asymmetric keys used to sign and verify the access tokens.
+* Tokens are signed with RS256 (RSA) or EdDSA (Ed25519) and carry the id of their key in the kid header.
+* Several keys can be active at once, so keys can be rotated without invalidating the issued tokens:
+* - a new key is added and published in the JWKS before it signs tokens
+* - the old key stays for verification until the last token signed with it has expired
+**/
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Shorter RSA keys are rejected
+const MinRSAKeyBits = 2048
+
+// Key ids end up in file names and token headers
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	Public crypto.PublicKey
+
+	// nil for keys which only verify tokens
+	private crypto.PrivateKey
+}
+
+func NewKey(id string, key interface{}) (*Key, error) {
+	/**
+	* Creates a key from a private or a public RSA or Ed25519 key.
+	* Public keys can only verify tokens, e.g. keys of retired signers.
+	**/
+	if !keyIDPattern.MatchString(id) {
+		return nil, fmt.Errorf("key %q: invalid key id", id)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < MinRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA keys need at least %d bits", id, MinRSAKeyBits)
+		}
+		return &Key{ID: id, Method: jwt.SigningMethodRS256, Public: &k.PublicKey, private: k}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < MinRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA keys need at least %d bits", id, MinRSAKeyBits)
+		}
+		return &Key{ID: id, Method: jwt.SigningMethodRS256, Public: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: id, Method: jwt.SigningMethodEdDSA, Public: k.Public(), private: k}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: id, Method: jwt.SigningMethodEdDSA, Public: k}, nil
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %T, use RSA or Ed25519", id, key)
+	}
+}
+
+func GenerateKey(id string) (*Key, error) {
+	/* Generates a new Ed25519 key, used for tests and local development */
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(id, private)
+}
+
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+type Keyring struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+func NewKeyring(signingKeyID string, keys ...*Key) (*Keyring, error) {
+	/**
+	* Creates a keyring which signs with the given key and verifies with all keys.
+	* @param signingKeyID: The id of the key used to sign new tokens, it needs a private key
+	* @param keys: All active keys, including the signing key
+	**/
+	keyring := &Keyring{keys: map[string]*Key{}}
+	for _, key := range keys {
+		if _, exists := keyring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("key %s: key id is used twice", key.ID)
+		}
+		keyring.keys[key.ID] = key
+	}
+
+	signing, exists := keyring.keys[signingKeyID]
+	if !exists {
+		return nil, fmt.Errorf("signing key %q not found", signingKeyID)
+	}
+	if !signing.CanSign() {
+		return nil, fmt.Errorf("signing key %s: a private key is required", signingKeyID)
+	}
+	keyring.signing = signing
+
+	return keyring, nil
+}
+
+func (k *Keyring) SigningKeyID() string {
+	return k.signing.ID
+}
+
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	/* Signs the claims with the signing key and sets its id as kid header */
+	token := jwt.NewWithClaims(k.signing.Method, claims)
+	token.Header["kid"] = k.signing.ID
+	return token.SignedString(k.signing.private)
+}
+
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	/**
+	* Resolves the key of a token by its kid header, to be passed to jwt.Parse.
+	* The algorithm of the token has to match the key, so a public key cannot be used as HMAC secret.
+	**/
+	id, _ := token.Header["kid"].(string)
+	key, exists := k.keys[id]
+	if !exists {
+		return nil, fmt.Errorf("unknown key id %q", id)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), id)
+	}
+	return key.Public, nil
+}
+
+func (k *Keyring) Methods() []string {
+	/* The algorithms of the active keys, to be passed to jwt.WithValidMethods */
+	seen := map[string]bool{}
+	methods := []string{}
+	for _, key := range k.keys {
+		if !seen[key.Method.Alg()] {
+			seen[key.Method.Alg()] = true
+			methods = append(methods, key.Method.Alg())
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+/* JSON Web Key Set (RFC 7517) with the public keys */
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+
+	// RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Ed25519 keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+func (k *Keyring) JWKS() JWKS {
+	/* Publishes the public keys of all active keys, sorted by their id */
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range k.keys {
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Method.Alg()}
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool { return jwks.Keys[i].KeyID < jwks.Keys[j].KeyID })
+	return jwks
+}
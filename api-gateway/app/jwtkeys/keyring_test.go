@@ -0,0 +1,167 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testRSAKey, _ = rsa.GenerateKey(rand.Reader, MinRSAKeyBits)
+
+func parse(t *testing.T, keyring *Keyring, tokenString string) error {
+	/* Helper to verify a token with the keys of a keyring */
+	t.Helper()
+	_, err := jwt.Parse(tokenString, keyring.Keyfunc, jwt.WithValidMethods(keyring.Methods()))
+	return err
+}
+
+func TestNewKey(t *testing.T) {
+	/* Test New Key */
+	smallRSAKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	type newKeyTest struct {
+		id             string
+		key            interface{}
+		expectedMethod string
+		expectedSign   bool
+		expectedError  bool
+	}
+
+	testSteps := []newKeyTest{
+		{id: "rsa", key: testRSAKey, expectedMethod: "RS256", expectedSign: true},
+		{id: "rsa", key: &testRSAKey.PublicKey, expectedMethod: "RS256"},
+		{id: "ed", key: edPrivate, expectedMethod: "EdDSA", expectedSign: true},
+		{id: "ed", key: edPublic, expectedMethod: "EdDSA"},
+		{id: "small", key: smallRSAKey, expectedError: true},
+		{id: "hmac", key: []byte("secret"), expectedError: true},
+		{id: "../key", key: edPrivate, expectedError: true},
+		{id: "", key: edPrivate, expectedError: true},
+	}
+
+	for i, testStep := range testSteps {
+		key, err := NewKey(testStep.id, testStep.key)
+		if (err != nil) != testStep.expectedError {
+			t.Errorf("Step: %d. Expected error %v, got %v", i, testStep.expectedError, err)
+		}
+		if err != nil {
+			continue
+		}
+		if key.Method.Alg() != testStep.expectedMethod || key.CanSign() != testStep.expectedSign {
+			t.Errorf("Step: %d. Expected %s with sign %v, got %s with sign %v", i, testStep.expectedMethod, testStep.expectedSign, key.Method.Alg(), key.CanSign())
+		}
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	/* Test New Keyring */
+	signing, _ := GenerateKey("2024-01")
+	duplicate, _ := GenerateKey("2024-01")
+	public, _ := NewKey("public", &testRSAKey.PublicKey)
+
+	if _, err := NewKeyring("2024-01", signing, public); err != nil {
+		t.Errorf("Expected a keyring, got %v", err)
+	}
+	if _, err := NewKeyring("missing", signing); err == nil {
+		t.Errorf("Expected an error for a missing signing key")
+	}
+	if _, err := NewKeyring("public", signing, public); err == nil {
+		t.Errorf("Expected an error for a signing key without private key")
+	}
+	if _, err := NewKeyring("2024-01", signing, duplicate); err == nil {
+		t.Errorf("Expected an error for duplicate key ids")
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	/* Tokens of all active keys are accepted while only the signing key signs new tokens */
+	oldKey, _ := NewKey("old", testRSAKey)
+	newKey, _ := GenerateKey("new")
+	claims := jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+
+	before, _ := NewKeyring("old", oldKey, newKey)
+	oldToken, err := before.Sign(claims)
+	if err != nil {
+		t.Fatalf("Error happened: when sign token %v", err)
+	}
+
+	after, _ := NewKeyring("new", oldKey, newKey)
+	newToken, _ := after.Sign(claims)
+
+	token, _, _ := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	if token.Header["kid"] != "new" || token.Method.Alg() != "EdDSA" {
+		t.Errorf("Expected a token signed with the new key, got %v", token.Header)
+	}
+
+	for _, tokenString := range []string{oldToken, newToken} {
+		if err := parse(t, after, tokenString); err != nil {
+			t.Errorf("Expected the token to be valid, got %v", err)
+		}
+	}
+
+	// once the old key is removed its tokens are rejected
+	retired, _ := NewKeyring("new", newKey)
+	if err := parse(t, retired, oldToken); err == nil {
+		t.Errorf("Expected the token of a removed key to be rejected")
+	}
+}
+
+func TestKeyringRejectsForeignTokens(t *testing.T) {
+	/* Test that tokens with unknown keys or other algorithms are rejected */
+	key, _ := NewKey("rsa", testRSAKey)
+	edKey, _ := GenerateKey("ed")
+	keyring, _ := NewKeyring("rsa", key, edKey)
+	claims := jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+
+	// the public key must not be usable as HMAC secret
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmacToken.Header["kid"] = "rsa"
+	hmacString, _ := hmacToken.SignedString([]byte("secret"))
+
+	// a token claiming a key of another algorithm
+	otherKey, _ := GenerateKey("other")
+	mismatch := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	mismatch.Header["kid"] = "rsa"
+	mismatchString, _ := mismatch.SignedString(otherKey.private)
+
+	// a token of an unknown key
+	unknown, _ := NewKeyring("other", otherKey)
+	unknownString, _ := unknown.Sign(claims)
+
+	// a token without kid
+	withoutKid, _ := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(testRSAKey)
+
+	for i, tokenString := range []string{hmacString, mismatchString, unknownString, withoutKid} {
+		if err := parse(t, keyring, tokenString); err == nil {
+			t.Errorf("Step: %d. Expected the token to be rejected", i)
+		}
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	/* Test that the JWKS publishes the public keys */
+	rsaKey, _ := NewKey("b-rsa", testRSAKey)
+	edKey, _ := GenerateKey("a-ed")
+	keyring, _ := NewKeyring("a-ed", rsaKey, edKey)
+
+	jwks := keyring.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %d", len(jwks.Keys))
+	}
+
+	ed := jwks.Keys[0]
+	x, _ := base64.RawURLEncoding.DecodeString(ed.X)
+	if ed.KeyID != "a-ed" || ed.KeyType != "OKP" || ed.Curve != "Ed25519" || ed.Algorithm != "EdDSA" || !edKey.Public.(ed25519.PublicKey).Equal(ed25519.PublicKey(x)) {
+		t.Errorf("Expected the Ed25519 key, got %+v", ed)
+	}
+
+	rsa := jwks.Keys[1]
+	if rsa.KeyID != "b-rsa" || rsa.KeyType != "RSA" || rsa.Algorithm != "RS256" || rsa.E != "AQAB" || rsa.N == "" || rsa.X != "" {
+		t.Errorf("Expected the RSA key, got %+v", rsa)
+	}
+}
@@ -0,0 +1,98 @@
+package jwtkeys
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func ParsePEM(id string, data []byte) (*Key, error) {
+	/**
+	* Parses a PEM encoded key, as written by openssl.
+	* Private keys are read as PKCS #8 or PKCS #1, public keys as PKIX.
+	**/
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", id)
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported PEM block %q", id, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", id, err)
+	}
+
+	return NewKey(id, key)
+}
+
+func LoadDir(dir string, signingKeyID string) (*Keyring, error) {
+	/**
+	* Loads the keys from the <kid>.pem files of a directory.
+	* @param dir: The directory of the keys
+	* @param signingKeyID: The id of the key used to sign new tokens, optional if there is only one private key
+	* @return: The keyring with all keys of the directory
+	**/
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []*Key{}
+	signers := []string{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ParsePEM(strings.TrimSuffix(filepath.Base(file), ".pem"), data)
+		if err != nil {
+			return nil, err
+		}
+		if key.CanSign() {
+			signers = append(signers, key.ID)
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys found in %s", dir)
+	}
+	if signingKeyID == "" {
+		if len(signers) != 1 {
+			return nil, fmt.Errorf("found %d private keys in %s, the signing key id is required", len(signers), dir)
+		}
+		signingKeyID = signers[0]
+	}
+
+	return NewKeyring(signingKeyID, keys...)
+}
+
+func Load(dir string, signingKeyID string) *Keyring {
+	/**
+	* Loads the keys from the directory of GATEWAY_JWT_KEYS_DIR and signs with GATEWAY_JWT_SIGNING_KEY_ID.
+	* Tokens cannot be issued without keys, so the gateway refuses to start.
+	**/
+	keyring, err := LoadDir(dir, signingKeyID)
+	if err != nil {
+		slog.Error("Failed to load JWT keys", "dir", dir, "error", err)
+		panic(err)
+	}
+
+	slog.Info("Loaded JWT keys", "dir", dir, "keys", len(keyring.keys), "signing_key", keyring.SigningKeyID())
+	return keyring
+}
@@ -0,0 +1,87 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePEM(t *testing.T, dir string, name string, blockType string, der []byte) {
+	/* Helper to write a key file */
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		t.Fatalf("Error happened: when write key %v", err)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	/* Test Load Dir */
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	edDER, _ := x509.MarshalPKCS8PrivateKey(edPrivate)
+	edPublicDER, _ := x509.MarshalPKIXPublicKey(edPublic)
+	rsaDER := x509.MarshalPKCS1PrivateKey(testRSAKey)
+
+	t.Run("Single private key", func(t *testing.T) {
+		dir := t.TempDir()
+		writePEM(t, dir, "2024-02.pem", "PRIVATE KEY", edDER)
+		writePEM(t, dir, "2024-01.pem", "PUBLIC KEY", edPublicDER)
+		writePEM(t, dir, "README", "PRIVATE KEY", rsaDER)
+
+		keyring, err := LoadDir(dir, "")
+		if err != nil {
+			t.Fatalf("Expected a keyring, got %v", err)
+		}
+		if keyring.SigningKeyID() != "2024-02" || len(keyring.JWKS().Keys) != 2 {
+			t.Errorf("Expected to sign with 2024-02 and verify with 2 keys, got %s and %v", keyring.SigningKeyID(), keyring.JWKS())
+		}
+	})
+
+	t.Run("Several private keys", func(t *testing.T) {
+		dir := t.TempDir()
+		writePEM(t, dir, "ed.pem", "PRIVATE KEY", edDER)
+		writePEM(t, dir, "rsa.pem", "RSA PRIVATE KEY", rsaDER)
+
+		if _, err := LoadDir(dir, ""); err == nil {
+			t.Errorf("Expected an error without signing key id")
+		}
+		keyring, err := LoadDir(dir, "rsa")
+		if err != nil || keyring.SigningKeyID() != "rsa" {
+			t.Errorf("Expected to sign with rsa, got %v", err)
+		}
+	})
+
+	t.Run("Invalid keys", func(t *testing.T) {
+		if _, err := LoadDir(t.TempDir(), ""); err == nil {
+			t.Errorf("Expected an error for an empty directory")
+		}
+
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("secret"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadDir(dir, ""); err == nil {
+			t.Errorf("Expected an error for a file without PEM data")
+		}
+
+		dir = t.TempDir()
+		writePEM(t, dir, "cert.pem", "CERTIFICATE", edPublicDER)
+		if _, err := LoadDir(dir, ""); err == nil {
+			t.Errorf("Expected an error for an unsupported PEM block")
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	/* Test that the gateway refuses to start without keys */
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic without keys")
+		}
+	}()
+	Load(t.TempDir(), "")
+}
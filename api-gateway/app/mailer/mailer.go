@@ -0,0 +1,77 @@
+/**
+* This package sends emails to the users, e.g. the links to reset their password.
+* The SMTPMailer is used if GATEWAY_SMTP_ADDR is set. Otherwise the LogMailer writes
+* the emails to the log, which is enough for local development.
+**/
+package mailer
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(message Message) error
+}
+
+type SMTPMailer struct {
+	// Address of the SMTP server as host:port
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (m SMTPMailer) Send(message Message) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, err := net.SplitHostPort(m.Addr)
+		if err != nil {
+			return err
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	return smtp.SendMail(m.Addr, auth, m.From, []string{message.To}, buildMail(m.From, message))
+}
+
+type LogMailer struct{}
+
+func (m LogMailer) Send(message Message) error {
+	slog.Info("Sending email", "to", message.To, "subject", message.Subject, "body", message.Body)
+	return nil
+}
+
+func NewMailer(server SMTPMailer) Mailer {
+	/* Returns the SMTP mailer, falls back to the LogMailer if no SMTP server is configured */
+	if server.Addr == "" {
+		slog.Warn("GATEWAY_SMTP_ADDR is not set, emails are written to the log")
+		return LogMailer{}
+	}
+	return server
+}
+
+func buildMail(from string, message Message) []byte {
+	/* Builds a plain text mail, line breaks in the header values are removed */
+	header := strings.NewReplacer("\r", "", "\n", "")
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "From: %s\r\n", header.Replace(from))
+	fmt.Fprintf(&builder, "To: %s\r\n", header.Replace(message.To))
+	fmt.Fprintf(&builder, "Subject: %s\r\n", header.Replace(message.Subject))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(builder.String())
+}
@@ -0,0 +1,24 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMail(t *testing.T) {
+	mail := string(buildMail("planner@localhost", Message{
+		To:      "user@example.com\r\nBcc: attacker@example.com",
+		Subject: "Reset your password",
+		Body:    "line 1\nline 2",
+	}))
+
+	if strings.Contains(mail, "\r\nBcc:") {
+		t.Errorf("Expected header injection to be removed, got %q", mail)
+	}
+	if !strings.Contains(mail, "Subject: Reset your password\r\n") {
+		t.Errorf("Expected subject header, got %q", mail)
+	}
+	if !strings.HasSuffix(mail, "\r\n\r\nline 1\r\nline 2") {
+		t.Errorf("Expected body with CRLF line breaks, got %q", mail)
+	}
+}
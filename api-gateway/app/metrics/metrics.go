@@ -0,0 +1,148 @@
+/**
+* This package holds the Prometheus metrics of the api-gateway, which are served by /metrics.
+* The collectors are registered with the default registry, which also reports the Go runtime and the process.
+**/
+package metrics
+
+import (
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Outcomes of queries and jobs
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Jobs which run periodically
+const (
+	// Reconciles the departments with the planner-backend
+	JobDepartments = "departments"
+)
+
+// Steps of a login
+const (
+	LoginPassword  = "password"
+	LoginTwoFactor = "two_factor"
+	LoginExternal  = "external"
+)
+
+// Results of a login step
+const (
+	LoginSuccess = "success"
+	LoginFailure = "failure"
+	// Rejected by the login throttle before the credentials were checked
+	LoginThrottled = "throttled"
+	// The credentials are valid, the second factor is asked for
+	LoginChallenged = "two_factor_required"
+)
+
+// Reasons of failed upstream requests
+const (
+	// The request did not reach the upstream or no response was received
+	UpstreamTransport = "transport"
+	// The upstream answered with 502, 503 or 504
+	UpstreamUnavailable = "unavailable"
+	// No upstream of the route was available
+	UpstreamNone = "no_upstream"
+)
+
+var (
+	// Requests by route template, e.g. /api/v1/user/:userID, method and status code.
+	// Forwarded requests are reported by the prefix of their route, e.g. /api/v1/planner/*
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by route template, method and status code.",
+	}, []string{"route", "method", "status"})
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests by route template, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// Queries by the repository method which ran them, e.g. UserRepository and FindUserByUsername
+	PostgresQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "postgres_query_duration_seconds",
+		Help:    "Duration of the PostgreSQL queries by repository, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"repository", "method", "status"})
+
+	// Every attempt of a forwarded request, retries included
+	ProxyUpstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "proxy_upstream_duration_seconds",
+		Help:    "Latency of the upstreams until the response headers by route, upstream and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "upstream", "status"})
+	ProxyUpstreamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "proxy_upstream_errors_total",
+		Help: "Failed attempts of forwarded requests by route, upstream and reason.",
+	}, []string{"route", "upstream", "reason"})
+
+	SyncJobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "sync_job_duration_seconds",
+		Help:    "Duration of the synchronization jobs by job and status.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"job", "status"})
+
+	Logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_logins_total",
+		Help: "Login attempts by step and result.",
+	}, []string{"step", "result"})
+
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ratelimit_rejected_requests_total",
+		Help: "Requests rejected by the rate limits by group.",
+	}, []string{"group"})
+)
+
+func Handler() gin.HandlerFunc {
+	/* Serves the metrics of the default registry in the Prometheus text format */
+	return gin.WrapH(promhttp.Handler())
+}
+
+func Status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusOK
+}
+
+func ObserveSyncJob(job string, start time.Time, err error) {
+	SyncJobDuration.WithLabelValues(job, Status(err)).Observe(time.Since(start).Seconds())
+}
+
+func Login(step string, result string) {
+	Logins.WithLabelValues(step, result).Inc()
+}
+
+func RepositoryMethod(pkgPath string) (string, string, bool) {
+	/**
+	* Finds the repository method in the stack of the caller, e.g. UserRepository and FindUserByUsername
+	* for a query run by UserRepositoryImpl.FindUserByUsername. Helpers and closures of the package are skipped.
+	* @param pkgPath: The import path of the repositories
+	* @return false if the call does not come from a repository
+	**/
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if name, found := strings.CutPrefix(frame.Function, pkgPath+"."); found {
+			receiver, method, found := strings.Cut(name, ".")
+			receiver = strings.Trim(receiver, "(*)")
+			if found && strings.HasSuffix(receiver, "RepositoryImpl") {
+				method, _, _ = strings.Cut(method, ".")
+				return strings.TrimSuffix(receiver, "Impl"), method, true
+			}
+		}
+		if !more {
+			return "", "", false
+		}
+	}
+}
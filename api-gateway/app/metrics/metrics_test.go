@@ -0,0 +1,44 @@
+package metrics
+
+import "testing"
+
+type userRepositoryImpl struct{}
+
+func (userRepositoryImpl) FindUserByUsername() (string, string, bool) {
+	return query()
+}
+
+func (r *userRepositoryImpl) Save() (repository string, method string, ok bool) {
+	func() {
+		repository, method, ok = query()
+	}()
+	return
+}
+
+func query() (string, string, bool) {
+	// a helper shared by the methods, like the callbacks of gorm
+	return RepositoryMethod("api-gateway/app/metrics")
+}
+
+func TestRepositoryMethod(t *testing.T) {
+	tests := []struct {
+		name               string
+		call               func() (string, string, bool)
+		expectedRepository string
+		expectedMethod     string
+		expectedOK         bool
+	}{
+		{name: "method", call: userRepositoryImpl{}.FindUserByUsername, expectedRepository: "userRepository", expectedMethod: "FindUserByUsername", expectedOK: true},
+		{name: "closure of a pointer method", call: (&userRepositoryImpl{}).Save, expectedRepository: "userRepository", expectedMethod: "Save", expectedOK: true},
+		{name: "outside of a repository", call: query},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repository, method, ok := test.call()
+			if repository != test.expectedRepository || method != test.expectedMethod || ok != test.expectedOK {
+				t.Errorf("Expected %s.%s (%v), got %s.%s (%v)", test.expectedRepository, test.expectedMethod, test.expectedOK, repository, method, ok)
+			}
+		})
+	}
+}
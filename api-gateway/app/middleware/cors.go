@@ -2,26 +2,21 @@ package middleware
 
 import (
 	"net/http"
-	"os"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func compareOrigin(c *gin.Context) string {
-	/* Compares the origin of the request with the allowed origins */
+func compareOrigin(c *gin.Context, allowedOrigins []string) string {
+	/* Compares the origin of the request with the allowed origins of GATEWAY_ALLOWED_ORIGINS */
 	origin := c.Request.Header.Get("Origin")
 
-	allowedOrigins := os.Getenv("GATEWAY_ALLOWED_ORIGINS")
-
 	// If allowedOrigins is empty, allow all origins
-	if allowedOrigins == "" {
+	if len(allowedOrigins) == 0 {
 		return "*"
 	}
 
 	// If allowedOrigins is not empty, compare the origin with the allowed origins
-	allowedOriginsArr := strings.Split(allowedOrigins, ",")
-	for _, allowedOrigin := range allowedOriginsArr {
+	for _, allowedOrigin := range allowedOrigins {
 		if origin == allowedOrigin {
 			return origin
 		}
@@ -31,16 +26,18 @@ func compareOrigin(c *gin.Context) string {
 	return ""
 }
 
-func Cors() gin.HandlerFunc {
+func Cors(allowedOrigins []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Set the allowed origins
-		origin := compareOrigin(c)
+		origin := compareOrigin(c, allowedOrigins)
 
 		// Set the allowed headers
 		c.Header("Access-Control-Allow-Origin", origin)
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 		c.Header("Access-Control-Allow-Credentials", "true")
+		// the CSRF token is sent at login, the frontend may be served from another origin
+		c.Header("Access-Control-Expose-Headers", "X-CSRF-Token")
 
 		// If the request method is OPTIONS, return with status 200
 		if c.Request.Method == "OPTIONS" {
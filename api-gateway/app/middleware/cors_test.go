@@ -3,7 +3,7 @@ package middleware
 import (
 	"api-gateway/app/mock"
 	"net/http/httptest"
-	"os"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +24,14 @@ var compareOriginTests = []compareOriginTest{
 	{"", "", "*"},
 }
 
+func splitOrigins(allowedOrigins string) []string {
+	// the allowed origins are configured as comma separated list
+	if allowedOrigins == "" {
+		return nil
+	}
+	return strings.Split(allowedOrigins, ",")
+}
+
 func TestCompareOrigin(t *testing.T) {
 	for _, testStep := range compareOriginTests {
 		// prepare the necessary variables
@@ -33,11 +41,8 @@ func TestCompareOrigin(t *testing.T) {
 		// set the origin
 		ctx.Request.Header.Set("Origin", testStep.origin)
 
-		// set the allowed origins
-		os.Setenv("GATEWAY_ALLOWED_ORIGINS", testStep.allowedOrigins)
-
 		// compare the origin
-		origin := compareOrigin(ctx)
+		origin := compareOrigin(ctx, splitOrigins(testStep.allowedOrigins))
 		if origin != testStep.expected {
 			t.Errorf("Origin should be %s, got %s", testStep.expected, origin)
 		}
@@ -75,11 +80,8 @@ func TestCors(t *testing.T) {
 		// set the origin
 		ctx.Request.Header.Set("Origin", testStep.origin)
 
-		// set the allowed origins
-		os.Setenv("GATEWAY_ALLOWED_ORIGINS", testStep.allowedOrigins)
-
 		// call the cors middleware
-		Cors()(ctx)
+		Cors(splitOrigins(testStep.allowedOrigins))(ctx)
 
 		// check the response
 		origin := ctx.Writer.Header().Get("Access-Control-Allow-Origin")
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dco"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ForwardIdentity(revocations RevocationList, apiKeys APIKeyStore) gin.HandlerFunc {
+	/**
+	* This middleware is used for routes that are proxied to the backend services.
+	* Identity headers supplied by the client are always removed. If the request carries
+	* a valid token, the identity of the user is injected as signed headers.
+	* Requests without a valid token are forwarded anonymously.
+	* The Authorization and X-API-Key headers are not forwarded, the API key is resolved here.
+	**/
+	return func(c *gin.Context) {
+		for _, header := range dco.IdentityHeaders {
+			c.Request.Header.Del(header)
+		}
+
+		token, err := Authenticate(c, revocations, apiKeys)
+		c.Request.Header.Del("Authorization")
+		c.Request.Header.Del(dco.APIKeyHeader)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		// make the token available to the following middlewares
+		c.Set("retrievedToken", token)
+
+		if len(dco.IdentitySigningKey) == 0 {
+			slog.Warn("PLANNER_IDENTITY_SIGNING_KEY is not set, forwarding request without identity")
+			c.Next()
+			return
+		}
+
+		// the permissions within the department the request acts on are forwarded, the policy denies other departments
+		if departments, err := RequestDepartments(c); err == nil && len(departments) > 0 {
+			if scoped, member := token.InDepartment(departments[0]); member {
+				token = scoped
+			}
+		}
+
+		// the planner-backend knows the department by its planner id
+		identity := dco.Identity{
+			Username:    token.Username,
+			Department:  token.PlannerDepartmentID,
+			IsAdmin:     token.IsAdmin,
+			Permissions: token.Permissions,
+		}
+		// the signature covers the path the upstream receives, routes may strip their prefix, and the query
+		path := c.Request.URL.Path
+		if upstreamPath, exists := c.Get("upstreamPath"); exists {
+			path = upstreamPath.(string)
+		}
+		SetIdentityHeaders(c.Request.Header, identity, c.Request.Method, path, c.Request.URL.RawQuery)
+
+		c.Next()
+	}
+}
+
+func SetIdentityHeaders(header http.Header, identity dco.Identity, method string, path string, query string) {
+	/* Sets the signed identity headers on a request to the given method, path and raw query */
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	header.Set(dco.IdentityUserHeader, identity.Username)
+	header.Set(dco.IdentityDepartmentHeader, identity.Department)
+	header.Set(dco.IdentityAdminHeader, strconv.FormatBool(identity.IsAdmin))
+	header.Set(dco.IdentityPermissionsHeader, strings.Join(identity.Permissions, ","))
+	header.Set(dco.IdentityTimestampHeader, timestamp)
+	header.Set(dco.IdentitySignatureHeader, SignIdentity(identity, timestamp, method, path, query))
+}
+
+func SignIdentity(identity dco.Identity, timestamp string, method string, path string, query string) string {
+	/**
+	* Creates a HMAC-SHA256 signature over the identity and the request it belongs to.
+	* The raw query is covered as well, so a signed request cannot be replayed with other parameters.
+	* The planner-backend builds the same payload to verify the signature, so the
+	* order of the fields must not change.
+	**/
+	payload := strings.Join([]string{
+		identity.Username,
+		identity.Department,
+		strconv.FormatBool(identity.IsAdmin),
+		strings.Join(identity.Permissions, ","),
+		timestamp,
+		method,
+		path,
+		query,
+	}, "\n")
+
+	mac := hmac.New(sha256.New, dco.IdentitySigningKey)
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
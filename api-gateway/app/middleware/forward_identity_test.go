@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestForwardIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	token, err := mock.GenerateMockToken(dao.User{Username: "test", Roles: mock.SystemAdminRoles})
+	if err != nil {
+		t.Fatalf("Failed to create valid token: %v", err)
+	}
+
+	revocations := mock.NewSessionRepositoryMock()
+
+	type forwardIdentityTest struct {
+		setCookie        bool
+		spoofedUser      string
+		expectedUser     string
+		expectSignature  bool
+		expectedAdminVal string
+	}
+
+	testSteps := []forwardIdentityTest{
+		{setCookie: false, spoofedUser: "", expectedUser: "", expectSignature: false, expectedAdminVal: ""},
+		{setCookie: false, spoofedUser: "admin", expectedUser: "", expectSignature: false, expectedAdminVal: ""},
+		{setCookie: true, spoofedUser: "", expectedUser: "test", expectSignature: true, expectedAdminVal: "true"},
+		{setCookie: true, spoofedUser: "admin", expectedUser: "test", expectSignature: true, expectedAdminVal: "true"},
+	}
+
+	for i, testStep := range testSteps {
+		var forwarded http.Header
+
+		router := gin.New()
+
+		router.Use(ErrorHandler())
+		router.Use(ForwardIdentity(&revocations, nil))
+		router.GET("/api/v1/planner/test", func(c *gin.Context) {
+			forwarded = c.Request.Header.Clone()
+			c.Status(http.StatusOK)
+		})
+
+		req, _ := http.NewRequest("GET", "/api/v1/planner/test", nil)
+		if testStep.spoofedUser != "" {
+			req.Header.Set(dco.IdentityUserHeader, testStep.spoofedUser)
+			req.Header.Set(dco.IdentitySignatureHeader, "spoofed")
+		}
+		if testStep.setCookie {
+			req.AddCookie(&http.Cookie{Name: "Authorization", Value: token})
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if user := forwarded.Get(dco.IdentityUserHeader); user != testStep.expectedUser {
+			t.Errorf("Step %d: expected user header %q, got %q", i, testStep.expectedUser, user)
+		}
+		if admin := forwarded.Get(dco.IdentityAdminHeader); admin != testStep.expectedAdminVal {
+			t.Errorf("Step %d: expected admin header %q, got %q", i, testStep.expectedAdminVal, admin)
+		}
+
+		signature := forwarded.Get(dco.IdentitySignatureHeader)
+		if !testStep.expectSignature {
+			if signature != "" {
+				t.Errorf("Step %d: expected no signature, got %q", i, signature)
+			}
+			continue
+		}
+
+		expected := SignIdentity(dco.Identity{
+			Username:    "test",
+			Department:  forwarded.Get(dco.IdentityDepartmentHeader),
+			IsAdmin:     true,
+			Permissions: []string{},
+		}, forwarded.Get(dco.IdentityTimestampHeader), "GET", "/api/v1/planner/test", "")
+		if signature != expected {
+			t.Errorf("Step %d: expected signature %q, got %q", i, expected, signature)
+		}
+	}
+}
+
+func TestForwardIdentityAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	revocations := mock.NewSessionRepositoryMock()
+	apiKeys := mock.NewAPIKeyRepositoryMock()
+	apiKeys.On("FindAPIKeyByHash").Return(dao.APIKey{
+		User:        dao.User{Username: "import", Permissions: []dao.Permission{{Name: "person:write"}}},
+		Permissions: []dao.Permission{{Name: "person:write"}},
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, nil)
+
+	var forwarded http.Header
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(ForwardIdentity(&revocations, &apiKeys))
+	router.GET("/api/v1/planner/test", func(c *gin.Context) {
+		forwarded = c.Request.Header.Clone()
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/planner/test", nil)
+	req.Header.Set("Authorization", "Bearer pk_key")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if user := forwarded.Get(dco.IdentityUserHeader); user != "import" {
+		t.Errorf("Expected user header %q, got %q", "import", user)
+	}
+	if permissions := forwarded.Get(dco.IdentityPermissionsHeader); permissions != "person:write" {
+		t.Errorf("Expected permissions header %q, got %q", "person:write", permissions)
+	}
+	// the key is not passed to the planner-backend
+	if forwarded.Get("Authorization") != "" || forwarded.Get(dco.APIKeyHeader) != "" {
+		t.Errorf("Expected the API key to be removed, got %v", forwarded)
+	}
+}
+
+func TestForwardIdentityUpstreamPath(t *testing.T) {
+	/* Routes that strip their prefix need the identity to be signed for the path the upstream receives */
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	token, err := mock.GenerateMockToken(dao.User{Username: "test", Roles: mock.SystemAdminRoles})
+	if err != nil {
+		t.Fatalf("Failed to create valid token: %v", err)
+	}
+	revocations := mock.NewSessionRepositoryMock()
+
+	var forwarded http.Header
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.Use(func(c *gin.Context) {
+		c.Set("upstreamPath", "/shifts")
+	})
+	router.Use(ForwardIdentity(&revocations, nil))
+	router.GET("/api/v1/reporting/shifts", func(c *gin.Context) {
+		forwarded = c.Request.Header.Clone()
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/api/v1/reporting/shifts?month=5&year=2024", nil)
+	req.AddCookie(&http.Cookie{Name: "Authorization", Value: token})
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	expected := SignIdentity(dco.Identity{
+		Username:    "test",
+		Department:  forwarded.Get(dco.IdentityDepartmentHeader),
+		IsAdmin:     true,
+		Permissions: []string{},
+	}, forwarded.Get(dco.IdentityTimestampHeader), "GET", "/shifts", "month=5&year=2024")
+	if signature := forwarded.Get(dco.IdentitySignatureHeader); signature != expected {
+		t.Errorf("Expected the signature of the upstream path and the query %q, got %q", expected, signature)
+	}
+}
+
+func TestSignIdentity(t *testing.T) {
+	dco.IdentitySigningKey = []byte("secret")
+	identity := dco.Identity{Username: "test", Department: "department", Permissions: []string{"a", "b"}}
+
+	signature := SignIdentity(identity, "1", "GET", "/path", "a=1")
+	if signature != SignIdentity(identity, "1", "GET", "/path", "a=1") {
+		t.Errorf("Expected signature to be deterministic")
+	}
+	if signature == SignIdentity(identity, "1", "POST", "/path", "a=1") {
+		t.Errorf("Expected signature to depend on the method")
+	}
+	if signature == SignIdentity(identity, "2", "GET", "/path", "a=1") {
+		t.Errorf("Expected signature to depend on the timestamp")
+	}
+	if signature == SignIdentity(identity, "1", "GET", "/path", "a=2") {
+		t.Errorf("Expected signature to depend on the query")
+	}
+
+	identity.IsAdmin = true
+	if signature == SignIdentity(identity, "1", "GET", "/path", "a=1") {
+		t.Errorf("Expected signature to depend on the admin flag")
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/metrics"
+	"api-gateway/app/pkg"
+	"crypto/subtle"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Metrics() gin.HandlerFunc {
+	/**
+	* This middleware counts the requests and measures their latency by the route template,
+	* so the paths with ids do not create a series each. Forwarded requests are reported by the
+	* template of their route, which is set by the Match of the route table, the others as "unmatched".
+	**/
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.GetString("routeTemplate")
+		}
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+func RequiredMetricsToken(token string) gin.HandlerFunc {
+	/**
+	* This middleware protects /metrics with the bearer token of the scraper.
+	* The metrics are public if no token is configured.
+	**/
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		scheme, value, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(value)), []byte(token)) != 1 {
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"api-gateway/app/ratelimit"
+	"log/slog"
+	"math"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RateLimit(limiter *ratelimit.Limiter, group ratelimit.Group) gin.HandlerFunc {
+	/**
+	* This middleware rejects requests with 429 and Retry-After if the client exceeded the budget of the group.
+	* Without a group, the read or write budget is chosen by the method of the request.
+	* Clients are identified by their API key or user if it is used after the RequiredAuth or ForwardIdentity
+	* middleware, otherwise by their IP.
+	**/
+	return func(c *gin.Context) {
+		requestGroup := group
+		if requestGroup == "" {
+			requestGroup = ratelimit.GroupOf(c.Request.Method)
+		}
+
+		key := rateLimitKey(c)
+		if allowed, retryAfter := limiter.Allow(requestGroup, key); !allowed {
+			slog.Warn("Rate limit exceeded", "group", requestGroup, "client", key, "path", c.Request.URL.Path)
+			c.Header("Retry-After", strconv.Itoa(int(math.Max(1, math.Ceil(retryAfter.Seconds())))))
+			pkg.Abort(c, pkg.NewError(constant.TooManyRequests))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func rateLimitKey(c *gin.Context) string {
+	// API keys have a budget of their own, independent of the sessions of their user
+	if token, exists := c.Get("retrievedToken"); exists {
+		claim := token.(*dco.JWTClaim)
+		if claim.APIKeyID != "" {
+			return "api-key:" + claim.APIKeyID
+		}
+		if claim.Username != "" {
+			return "user:" + claim.Username
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/ratelimit"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[ratelimit.Group]ratelimit.Limit{
+		ratelimit.Read:  {Requests: 1, Period: time.Minute},
+		ratelimit.Write: {Requests: 2, Period: time.Minute},
+	})
+
+	type rateLimitTest struct {
+		method             string
+		claim              *dco.JWTClaim
+		expectedStatusCode int
+	}
+
+	testSteps := []rateLimitTest{
+		{method: "GET", claim: &dco.JWTClaim{Username: "test"}, expectedStatusCode: http.StatusOK},
+		{method: "GET", claim: &dco.JWTClaim{Username: "test"}, expectedStatusCode: http.StatusTooManyRequests},
+		// the write budget is separate
+		{method: "POST", claim: &dco.JWTClaim{Username: "test"}, expectedStatusCode: http.StatusOK},
+		// other users, API keys of the user and anonymous clients have budgets of their own
+		{method: "GET", claim: &dco.JWTClaim{Username: "other"}, expectedStatusCode: http.StatusOK},
+		{method: "GET", claim: &dco.JWTClaim{Username: "test", APIKeyID: "1"}, expectedStatusCode: http.StatusOK},
+		{method: "GET", claim: &dco.JWTClaim{Username: "test", APIKeyID: "1"}, expectedStatusCode: http.StatusTooManyRequests},
+		{method: "GET", claim: nil, expectedStatusCode: http.StatusOK},
+		{method: "GET", claim: nil, expectedStatusCode: http.StatusTooManyRequests},
+	}
+
+	for i, testStep := range testSteps {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			if testStep.claim != nil {
+				c.Set("retrievedToken", testStep.claim)
+			}
+		})
+		router.Handle(testStep.method, "/test", RateLimit(limiter, ""), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(testStep.method, "/test", nil)
+		router.ServeHTTP(w, req)
+
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+		if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") != "60" {
+			t.Errorf("Step %d: expected Retry-After 60, got %q", i, w.Header().Get("Retry-After"))
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bodies are read up to this size to find the department, larger bodies are rejected
+const maxDepartmentBodySize = 1 << 20
+
+var errBodyTooLarge = errors.New("request body is too large")
+
+// Query parameters of the planner-backend which name a department
+var departmentQueryParameters = []string{"departmentID", "department_id"}
+
+func RequestDepartments(c *gin.Context) ([]string, error) {
+	/**
+	* Returns the planner ids of the departments a request to the planner-backend acts on.
+	* They are read from the path /api/v1/planner/department/<id>, the departmentID query parameter
+	* and the department_id field of a JSON body. The result is cached for the following middlewares.
+	**/
+	if departments, exists := c.Get("requestDepartments"); exists {
+		return departments.([]string), nil
+	}
+
+	departments := []string{}
+	add := func(department string) {
+		if department == "" {
+			return
+		}
+		for _, d := range departments {
+			if d == department {
+				return
+			}
+		}
+		departments = append(departments, department)
+	}
+
+	segments := strings.Split(strings.Trim(c.Request.URL.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "planner" && segments[i+1] == "department" && i+2 < len(segments) {
+			add(segments[i+2])
+			break
+		}
+	}
+
+	query := c.Request.URL.Query()
+	for _, parameter := range departmentQueryParameters {
+		add(query.Get(parameter))
+	}
+
+	department, err := bodyDepartment(c.Request)
+	if err != nil {
+		return nil, err
+	}
+	add(department)
+
+	c.Set("requestDepartments", departments)
+	return departments, nil
+}
+
+func RequestPerson(c *gin.Context) string {
+	/* Returns the id of the person a request to /api/v1/planner/person/<id> acts on, empty for other requests */
+	segments := strings.Split(strings.Trim(c.Request.URL.Path, "/"), "/")
+	for i := 0; i+2 < len(segments); i++ {
+		if segments[i] == "planner" && segments[i+1] == "person" {
+			return segments[i+2]
+		}
+	}
+	return ""
+}
+
+func bodyDepartment(req *http.Request) (string, error) {
+	/**
+	* Reads the department_id of a JSON body, the body is restored for the proxy.
+	* The Content-Type is not checked, since the planner-backend binds JSON bodies regardless of it.
+	**/
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxDepartmentBodySize+1))
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), req.Body))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxDepartmentBodySize {
+		return "", errBodyTooLarge
+	}
+
+	var fields struct {
+		DepartmentID string `json:"department_id"`
+	}
+	// bodies which are not a JSON object do not name a department
+	json.Unmarshal(body, &fields)
+	return fields.DepartmentID, nil
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestDepartments(t *testing.T) {
+	/* Test that the departments are read from the path, the query and the body */
+	gin.SetMode(gin.TestMode)
+
+	type requestDepartmentsTest struct {
+		name          string
+		method        string
+		url           string
+		body          string
+		expected      []string
+		expectedError bool
+	}
+
+	testSteps := []requestDepartmentsTest{
+		{name: "path", method: "GET", url: "/api/v1/planner/department/d1/workplace/", expected: []string{"d1"}},
+		{name: "department list", method: "POST", url: "/api/v1/planner/department/", expected: []string{}},
+		{name: "query", method: "GET", url: "/api/v1/planner/workday/?departmentID=d2&date=2024-01-01", expected: []string{"d2"}},
+		{name: "body", method: "PUT", url: "/api/v1/planner/workday/", body: `{"department_id":"d3"}`, expected: []string{"d3"}},
+		{name: "body without content type", method: "POST", url: "/api/v1/planner/person/p1/department", body: `{"department_id":"d3"}`, expected: []string{"d3"}},
+		{name: "several departments", method: "PUT", url: "/api/v1/planner/department/d1?departmentID=d1", body: `{"department_id":"d2"}`, expected: []string{"d1", "d2"}},
+		{name: "body without department", method: "POST", url: "/api/v1/planner/person/", body: `[1, 2]`, expected: []string{}},
+		{name: "body too large", method: "PUT", url: "/api/v1/planner/workday/", body: `{"padding":"` + strings.Repeat("x", maxDepartmentBodySize) + `"}`, expectedError: true},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			var departments []string
+			var err error
+			var forwardedBody string
+
+			router := gin.New()
+
+			router.Use(ErrorHandler())
+			router.Any("/*any", func(c *gin.Context) {
+				departments, err = RequestDepartments(c)
+				// the cached result is returned to the following middlewares
+				if cached, _ := RequestDepartments(c); err == nil && !reflect.DeepEqual(cached, departments) {
+					t.Errorf("Expected the cached departments %v, got %v", departments, cached)
+				}
+				body, _ := io.ReadAll(c.Request.Body)
+				forwardedBody = string(body)
+			})
+
+			req, _ := http.NewRequest(testStep.method, testStep.url, strings.NewReader(testStep.body))
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if (err != nil) != testStep.expectedError {
+				t.Fatalf("Expected error %v, got %v", testStep.expectedError, err)
+			}
+			if err == nil && !reflect.DeepEqual(departments, testStep.expected) {
+				t.Errorf("Expected departments %v, got %v", testStep.expected, departments)
+			}
+			if forwardedBody != testStep.body {
+				t.Errorf("Expected the body to be forwarded unchanged")
+			}
+		})
+	}
+}
+
+func TestForwardIdentityInDepartment(t *testing.T) {
+	/* Test that the permissions within the department of the request are forwarded */
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	planner := dao.Role{Name: dao.RolePlanner, Permissions: []dao.Permission{{Name: "workday:write"}}}
+	primary := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, PlannerID: "planner1"}
+	further := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, PlannerID: "planner2"}
+	token, err := mock.GenerateMockToken(dao.User{
+		Username:     "test",
+		DepartmentID: primary.ID,
+		Department:   primary,
+		Memberships:  []dao.DepartmentMembership{{DepartmentID: further.ID, Department: further, Role: &planner}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create valid token: %v", err)
+	}
+	revocations := mock.NewSessionRepositoryMock()
+
+	type forwardIdentityInDepartmentTest struct {
+		url                 string
+		expectedDepartment  string
+		expectedPermissions string
+	}
+
+	testSteps := []forwardIdentityInDepartmentTest{
+		{url: "/api/v1/planner/person/", expectedDepartment: "planner1", expectedPermissions: ""},
+		{url: "/api/v1/planner/department/planner2/workplace/", expectedDepartment: "planner2", expectedPermissions: "workday:write"},
+		// foreign departments are denied by the policy, the identity of the user is forwarded
+		{url: "/api/v1/planner/department/planner3/workplace/", expectedDepartment: "planner1", expectedPermissions: ""},
+	}
+
+	for i, testStep := range testSteps {
+		var forwarded http.Header
+
+		router := gin.New()
+
+		router.Use(ErrorHandler())
+		router.Use(ForwardIdentity(&revocations, nil))
+		router.GET("/*any", func(c *gin.Context) {
+			forwarded = c.Request.Header.Clone()
+		})
+
+		req, _ := http.NewRequest("GET", testStep.url, nil)
+		req.AddCookie(&http.Cookie{Name: "Authorization", Value: token})
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if department := forwarded.Get(dco.IdentityDepartmentHeader); department != testStep.expectedDepartment {
+			t.Errorf("Step %d: expected department %q, got %q", i, testStep.expectedDepartment, department)
+		}
+		if permissions := forwarded.Get(dco.IdentityPermissionsHeader); permissions != testStep.expectedPermissions {
+			t.Errorf("Step %d: expected permissions %q, got %q", i, testStep.expectedPermissions, permissions)
+		}
+		if _, err := VerifyIdentity(forwarded, "GET", testStep.url, ""); err != nil {
+			t.Errorf("Step %d: expected a valid signature, got %v", i, err)
+		}
+	}
+}
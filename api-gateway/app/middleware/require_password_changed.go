@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RequirePasswordChanged() gin.HandlerFunc {
+	/**
+	* This middleware blocks users who have to change their password, e.g. seeded accounts.
+	* It must be used after the RequiredAuth or ForwardIdentity middleware. Requests without a token are passed on.
+	**/
+	return func(c *gin.Context) {
+		if token, exists := c.Get("retrievedToken"); exists && token.(*dco.JWTClaim).MustChangePassword {
+			slog.Info("Request denied until the password is changed", "username", token.(*dco.JWTClaim).Username)
+			pkg.Abort(c, pkg.NewError(constant.PasswordChangeRequired))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dco"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePasswordChanged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type requirePasswordChangedTest struct {
+		claim              *dco.JWTClaim
+		expectedStatusCode int
+	}
+
+	testSteps := []requirePasswordChangedTest{
+		{claim: nil, expectedStatusCode: http.StatusOK},
+		{claim: &dco.JWTClaim{}, expectedStatusCode: http.StatusOK},
+		{claim: &dco.JWTClaim{MustChangePassword: true}, expectedStatusCode: http.StatusForbidden},
+		{claim: &dco.JWTClaim{IsAdmin: true, MustChangePassword: true}, expectedStatusCode: http.StatusForbidden},
+	}
+
+	for i, testStep := range testSteps {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			if testStep.claim != nil {
+				c.Set("retrievedToken", testStep.claim)
+			}
+		})
+		router.GET("/test", RequirePasswordChanged(), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/test", nil)
+		router.ServeHTTP(w, req)
+
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RequirePermission(name string) gin.HandlerFunc {
+	/**
+	* This middleware is used for routes that require a given permission.
+	* It must be used after the RequiredAuth middleware. Admins hold every permission.
+	**/
+	return func(c *gin.Context) {
+		token, exists := c.Get("retrievedToken")
+		if !exists {
+			slog.Error("Error happened: when get token from context", "error", "token not found")
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+
+		claim := token.(*dco.JWTClaim)
+		if !claim.HasPermission(name) {
+			slog.Error("Error happened: when check permission", "username", claim.Username, "permission", name)
+			pkg.Abort(c, pkg.NewError(constant.Forbidden))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func RequirePermissionInAnyDepartment(name string) gin.HandlerFunc {
+	/**
+	* This middleware is used for routes whose service checks the permission within the department concerned.
+	* It only keeps out users that hold the permission in none of their departments.
+	* It must be used after the RequiredAuth middleware. Admins hold every permission.
+	**/
+	return func(c *gin.Context) {
+		token, exists := c.Get("retrievedToken")
+		if !exists {
+			slog.Error("Error happened: when get token from context", "error", "token not found")
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+
+		claim := token.(*dco.JWTClaim)
+		if !claim.HasPermissionInAnyDepartment(name) {
+			slog.Error("Error happened: when check permission", "username", claim.Username, "permission", name)
+			pkg.Abort(c, pkg.NewError(constant.Forbidden))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dco"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type requirePermissionTest struct {
+		claim              *dco.JWTClaim
+		expectedStatusCode int
+	}
+
+	testSteps := []requirePermissionTest{
+		{claim: nil, expectedStatusCode: http.StatusUnauthorized},
+		{claim: &dco.JWTClaim{Permissions: []string{}}, expectedStatusCode: http.StatusForbidden},
+		{claim: &dco.JWTClaim{Permissions: []string{"other"}}, expectedStatusCode: http.StatusForbidden},
+		{claim: &dco.JWTClaim{Permissions: []string{"user:write"}}, expectedStatusCode: http.StatusOK},
+		{claim: &dco.JWTClaim{IsAdmin: true}, expectedStatusCode: http.StatusOK},
+	}
+
+	for i, testStep := range testSteps {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			if testStep.claim != nil {
+				c.Set("retrievedToken", testStep.claim)
+			}
+		})
+		router.POST("/test", RequirePermission("user:write"), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/test", nil)
+		router.ServeHTTP(w, req)
+
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+	}
+}
+
+func TestRequirePermissionInAnyDepartment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type requirePermissionTest struct {
+		claim              *dco.JWTClaim
+		expectedStatusCode int
+	}
+
+	testSteps := []requirePermissionTest{
+		{claim: nil, expectedStatusCode: http.StatusUnauthorized},
+		{claim: &dco.JWTClaim{Permissions: []string{"other"}}, expectedStatusCode: http.StatusForbidden},
+		{claim: &dco.JWTClaim{Memberships: []dco.DepartmentMembershipClaim{{Permissions: []string{"other"}}}}, expectedStatusCode: http.StatusForbidden},
+		{claim: &dco.JWTClaim{Permissions: []string{"user:write"}}, expectedStatusCode: http.StatusOK},
+		{claim: &dco.JWTClaim{Memberships: []dco.DepartmentMembershipClaim{{}, {Permissions: []string{"user:write"}}}}, expectedStatusCode: http.StatusOK},
+		{claim: &dco.JWTClaim{IsAdmin: true}, expectedStatusCode: http.StatusOK},
+	}
+
+	for i, testStep := range testSteps {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			if testStep.claim != nil {
+				c.Set("retrievedToken", testStep.claim)
+			}
+		})
+		router.PUT("/test", RequirePermissionInAnyDepartment("user:write"), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/test", nil)
+		router.ServeHTTP(w, req)
+
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RequireTwoFactorEnrolled() gin.HandlerFunc {
+	/**
+	* This middleware blocks users whose roles require two-factor authentication until they enabled it.
+	* It must be used after the RequiredAuth or ForwardIdentity middleware. Requests without a token are passed on.
+	**/
+	return func(c *gin.Context) {
+		if token, exists := c.Get("retrievedToken"); exists && token.(*dco.JWTClaim).MustEnrollTwoFactor {
+			slog.Info("Request denied until two-factor authentication is enabled", "username", token.(*dco.JWTClaim).Username)
+			pkg.Abort(c, pkg.NewError(constant.TwoFactorRequired))
+			return
+		}
+
+		c.Next()
+	}
+}
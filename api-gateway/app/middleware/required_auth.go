@@ -2,44 +2,42 @@ package middleware
 
 import (
 	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
 	"api-gateway/app/domain/dco"
 	"api-gateway/app/pkg"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func RequiredAuth() gin.HandlerFunc {
+// RevocationList is checked for every token, tokens of revoked sessions are rejected
+type RevocationList interface {
+	IsRevoked(sessionID uuid.UUID) (bool, error)
+}
+
+// APIKeyStore resolves the API keys of scripts and service accounts
+type APIKeyStore interface {
+	FindAPIKeyByHash(hash string) (dao.APIKey, error)
+	TouchAPIKey(id uuid.UUID, usedAt time.Time, interval time.Duration) error
+}
+
+func RequiredAuth(revocations RevocationList, apiKeys APIKeyStore) gin.HandlerFunc {
 	// This middleware will be used for routes that require authentication
 	// It must be implemented in the /me route
+	// API keys are only accepted if a key store is given
 	return func(c *gin.Context) {
-		defer pkg.PanicHandler(c)
-
-		// Get the token from the header
-		tokenString, err := c.Request.Cookie("Authorization")
-		if err != nil {
-			pkg.PanicException(constant.Unauthorized)
-		}
-
-		// If the token is empty, return with status 401
-		if tokenString.Value == "" {
-			pkg.PanicException(constant.Unauthorized)
-		}
-
-		// decode and validate the token
-		token, err := DecodeToken(tokenString.Value)
+		token, err := Authenticate(c, revocations, apiKeys)
 		if err != nil {
-			pkg.PanicException(constant.Unauthorized)
-		}
-
-		// check if the token is expired
-		if token.ExpiresAt.Time.Before(time.Now()) {
-			slog.Error("Error happened: when parse token", "error", "token is expired")
-			pkg.PanicException(constant.Unauthorized)
+			slog.Error("Error happened: when authenticate request", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
 		}
 
 		// Set the retrieved token to the context
@@ -48,15 +46,124 @@ func RequiredAuth() gin.HandlerFunc {
 	}
 }
 
-func DecodeToken(tokenString string) (*dco.JWTClaim, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &dco.JWTClaim{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			message := fmt.Sprintf("unexpected signing method %s", token.Header["alg"])
-			return nil, errors.New(message)
+func Authenticate(c *gin.Context, revocations RevocationList, apiKeys APIKeyStore) (*dco.JWTClaim, error) {
+	/**
+	* Reads the access token from the cookie and validates it.
+	* The token must not be expired and its session must not be on the revocation list.
+	* Requests with an API key in the Authorization or X-API-Key header are authenticated with the key instead.
+	**/
+	if key := apiKeyFromRequest(c); key != "" {
+		if apiKeys == nil {
+			return nil, errors.New("api keys are not accepted")
 		}
+		return authenticateAPIKey(apiKeys, key)
+	}
+
+	// Get the token from the cookie
+	tokenString, err := c.Request.Cookie(dco.AccessTokenCookie)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the token is empty, the request is not authenticated
+	if tokenString.Value == "" {
+		return nil, errors.New("token is empty")
+	}
+
+	// decode and validate the token
+	token, err := DecodeToken(tokenString.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if the token is expired
+	if token.ExpiresAt == nil || token.ExpiresAt.Time.Before(time.Now()) {
+		return nil, errors.New("token is expired")
+	}
+
+	// tokens are bound to a session, which might have been revoked
+	sessionID, err := uuid.Parse(token.SessionID)
+	if err != nil {
+		return nil, errors.New("token has no session")
+	}
+	revoked, err := revocations.IsRevoked(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("session is revoked")
+	}
+
+	return token, nil
+}
+
+func authenticateAPIKey(apiKeys APIKeyStore, key string) (*dco.JWTClaim, error) {
+	/**
+	* Resolves an API key to a claim of its user. The claim only holds the permissions
+	* of the key which are still held by the user and is not bound to a session.
+	* A user who has to change the password or enroll a second factor cannot use the keys until then.
+	**/
+	apiKey, err := apiKeys.FindAPIKeyByHash(HashAPIKey(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiKey.IsValid() {
+		return nil, errors.New("api key is expired")
+	}
+
+	// the last use is informational, the request is not rejected if it cannot be stored
+	if err := apiKeys.TouchAPIKey(apiKey.ID, time.Now(), dco.APIKeyLastUsedInterval); err != nil {
+		slog.Warn("Could not store the last use of the api key", "key", apiKey.ID, "error", err)
+	}
+
+	// the key holds its permissions within each department of its user
+	memberships := []dco.DepartmentMembershipClaim{}
+	for _, department := range apiKey.User.Departments() {
+		memberships = append(memberships, dco.DepartmentMembershipClaim{
+			Department:          department.ID.String(),
+			PlannerDepartmentID: department.PlannerID,
+			Roles:               apiKey.User.RoleNamesIn(department.ID),
+			Permissions:         apiKey.PermissionNamesIn(department.ID),
+		})
+	}
+
+	return &dco.JWTClaim{
+		Username:    apiKey.User.Username,
+		Department:  apiKey.User.Department.ID.String(),
+		Roles:       apiKey.User.RoleNames(),
+		Permissions: apiKey.PermissionNames(),
+		APIKeyID:    apiKey.ID.String(),
+
+		PlannerDepartmentID: apiKey.User.Department.PlannerID,
+		Memberships:         memberships,
+		MustChangePassword:  apiKey.User.MustChangePassword,
+		MustEnrollTwoFactor: apiKey.User.RequiresTwoFactor() && !apiKey.User.HasTwoFactor(),
+	}, nil
+}
+
+func apiKeyFromRequest(c *gin.Context) string {
+	// API keys are sent as bearer token or in the X-API-Key header
+	if key := c.GetHeader(dco.APIKeyHeader); key != "" {
+		return key
+	}
+
+	scheme, key, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(key)
+	}
+	return ""
+}
 
-		return dco.JWTSigningKey, nil
-	})
+func HashAPIKey(key string) string {
+	// Only the SHA-256 hashes of the API keys are stored
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func DecodeToken(tokenString string) (*dco.JWTClaim, error) {
+	// the key is resolved by the kid header of the token
+	token, err := jwt.ParseWithClaims(tokenString, &dco.JWTClaim{}, dco.JWTKeys.Keyfunc, jwt.WithValidMethods(dco.JWTKeys.Methods()))
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"api-gateway/app/domain/dao"
 	"api-gateway/app/domain/dco"
+	"api-gateway/app/mock"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,13 +12,15 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 func TestDecodeToken(t *testing.T) {
 	// Define a valid token
-	validToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	validToken, err := dco.JWTKeys.Sign(jwt.MapClaims{
 		"foo": "bar",
-	}).SignedString(dco.JWTSigningKey)
+	})
 	if err != nil {
 		t.Fatalf("Failed to create valid token: %v", err)
 	}
@@ -39,15 +44,29 @@ func TestDecodeToken(t *testing.T) {
 			t.Errorf("Expected error, got nil")
 		}
 	})
+
+	t.Run("symmetric token", func(t *testing.T) {
+		// tokens signed with a shared secret are not accepted anymore
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"foo": "bar"})
+		token.Header["kid"] = dco.JWTKeys.SigningKeyID()
+		hmacToken, _ := token.SignedString([]byte("secret"))
+
+		_, err := DecodeToken(hmacToken)
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
 }
 
 func TestRequiredAuth(t *testing.T) {
 	// Create a new gin router
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.Use(ErrorHandler())
+	revocations := mock.NewSessionRepositoryMock()
 
 	// Add the middleware to the router
-	router.Use(RequiredAuth())
+	router.Use(RequiredAuth(&revocations, nil))
 
 	// Add a test route
 	router.GET("/test", func(c *gin.Context) {
@@ -64,12 +83,13 @@ func TestRequiredAuth(t *testing.T) {
 	}
 
 	// Test with valid token
-	validToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, dco.JWTClaim{
-		Username: "test",
+	validToken, err := dco.JWTKeys.Sign(dco.JWTClaim{
+		SessionID: uuid.NewString(),
+		Username:  "test",
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(dco.JWTExpirationTime)),
 		},
-	}).SignedString(dco.JWTSigningKey)
+	})
 	if err != nil {
 		t.Fatalf("Failed to create valid token: %v", err)
 	}
@@ -85,13 +105,50 @@ func TestRequiredAuth(t *testing.T) {
 			status, http.StatusOK)
 	}
 
+	// Test with token of a revoked session
+	revocations.On("IsRevoked").Return(true, nil)
+	req, _ = http.NewRequest("GET", "/test", nil)
+	req.AddCookie(&http.Cookie{
+		Name:  "Authorization",
+		Value: validToken,
+	})
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if status := resp.Code; status != http.StatusUnauthorized {
+		t.Errorf("Handler returned wrong status code: got %v want %v",
+			status, http.StatusUnauthorized)
+	}
+
+	// Test with token without session
+	revocations.On("IsRevoked").Return(false, nil)
+	noSessionToken, err := dco.JWTKeys.Sign(dco.JWTClaim{
+		Username: "test",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(dco.JWTExpirationTime)),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create valid token: %v", err)
+	}
+	req, _ = http.NewRequest("GET", "/test", nil)
+	req.AddCookie(&http.Cookie{
+		Name:  "Authorization",
+		Value: noSessionToken,
+	})
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if status := resp.Code; status != http.StatusUnauthorized {
+		t.Errorf("Handler returned wrong status code: got %v want %v",
+			status, http.StatusUnauthorized)
+	}
+
 	// Test with expired token
-	expiredToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, dco.JWTClaim{
+	expiredToken, err := dco.JWTKeys.Sign(dco.JWTClaim{
 		Username: "test",
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-dco.JWTExpirationTime)),
 		},
-	}).SignedString(dco.JWTSigningKey)
+	})
 	if err != nil {
 		t.Fatalf("Failed to create valid token: %v", err)
 	}
@@ -104,3 +161,114 @@ func TestRequiredAuth(t *testing.T) {
 			status, http.StatusUnauthorized)
 	}
 }
+
+func TestRequiredAuthAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	revocations := mock.NewSessionRepositoryMock()
+
+	// the user lost the role:write permission after the key was created
+	user := dao.User{
+		Username:    "import",
+		Permissions: []dao.Permission{{Name: "person:write"}},
+	}
+	apiKey := dao.APIKey{
+		BaseModel:   dao.BaseModel{ID: uuid.New()},
+		User:        user,
+		Permissions: []dao.Permission{{Name: "person:write"}, {Name: "role:write"}},
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	expiredKey := apiKey
+	expiredKey.ExpiresAt = time.Now().Add(-time.Hour)
+	// the flags of the user are kept, so the key is rejected like a session until they are resolved
+	passwordKey := apiKey
+	passwordKey.User.MustChangePassword = true
+	twoFactorKey := apiKey
+	twoFactorKey.User.Roles = []dao.UserRole{{Role: dao.Role{Name: "admin", RequireTwoFactor: true}}}
+
+	type requiredAuthAPIKeyTest struct {
+		header             string
+		value              string
+		apiKey             dao.APIKey
+		apiKeyError        error
+		withoutStore       bool
+		expectedStatusCode int
+		// the flags the claim is expected to carry
+		mustChangePassword  bool
+		mustEnrollTwoFactor bool
+	}
+
+	testSteps := []requiredAuthAPIKeyTest{
+		{header: "Authorization", value: "Bearer pk_key", apiKey: apiKey, expectedStatusCode: http.StatusOK},
+		{header: dco.APIKeyHeader, value: "pk_key", apiKey: apiKey, expectedStatusCode: http.StatusOK},
+		{header: "Authorization", value: "Bearer pk_key", apiKey: expiredKey, expectedStatusCode: http.StatusUnauthorized},
+		{header: "Authorization", value: "Bearer pk_key", apiKeyError: gorm.ErrRecordNotFound, expectedStatusCode: http.StatusUnauthorized},
+		{header: "Authorization", value: "Bearer pk_key", apiKeyError: errors.New("some error"), expectedStatusCode: http.StatusUnauthorized},
+		// the auth routes do not accept API keys
+		{header: "Authorization", value: "Bearer pk_key", apiKey: apiKey, withoutStore: true, expectedStatusCode: http.StatusUnauthorized},
+		{header: "Authorization", value: "Bearer pk_key", apiKey: passwordKey, expectedStatusCode: http.StatusForbidden, mustChangePassword: true},
+		{header: "Authorization", value: "Bearer pk_key", apiKey: twoFactorKey, expectedStatusCode: http.StatusForbidden, mustEnrollTwoFactor: true},
+	}
+
+	for i, testStep := range testSteps {
+		apiKeys := mock.NewAPIKeyRepositoryMock()
+		if testStep.apiKeyError != nil {
+			apiKeys.On("FindAPIKeyByHash").Return(nil, testStep.apiKeyError)
+		} else {
+			apiKeys.On("FindAPIKeyByHash").Return(testStep.apiKey, nil)
+		}
+
+		var claim *dco.JWTClaim
+		router := gin.New()
+		router.Use(ErrorHandler())
+		if testStep.withoutStore {
+			router.Use(RequiredAuth(&revocations, nil))
+		} else {
+			router.Use(RequiredAuth(&revocations, &apiKeys))
+		}
+		router.Use(func(c *gin.Context) {
+			token, _ := c.Get("retrievedToken")
+			claim = token.(*dco.JWTClaim)
+		})
+		router.Use(RequirePasswordChanged())
+		router.Use(RequireTwoFactorEnrolled())
+		router.GET("/test", func(c *gin.Context) {
+			token, _ := c.Get("retrievedToken")
+			claim = token.(*dco.JWTClaim)
+			c.String(http.StatusOK, "OK")
+		})
+
+		req, _ := http.NewRequest("GET", "/test", nil)
+		req.Header.Set(testStep.header, testStep.value)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+
+		if resp.Code != testStep.expectedStatusCode {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatusCode, resp.Code)
+		}
+		if claim != nil && (claim.MustChangePassword != testStep.mustChangePassword || claim.MustEnrollTwoFactor != testStep.mustEnrollTwoFactor) {
+			t.Errorf("Step %d: expected the flags of the user, got %+v", i, claim)
+		}
+		if testStep.expectedStatusCode != http.StatusOK {
+			continue
+		}
+
+		if claim.Username != "import" || claim.APIKeyID != apiKey.ID.String() {
+			t.Errorf("Step %d: expected claim of the key, got %+v", i, claim)
+		}
+		if !claim.HasPermission("person:write") || claim.HasPermission("role:write") {
+			t.Errorf("Step %d: expected only the permissions held by the user, got %v", i, claim.Permissions)
+		}
+		if len(apiKeys.Touched) != 1 {
+			t.Errorf("Step %d: expected the last use to be stored", i)
+		}
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	if HashAPIKey("pk_key") != HashAPIKey("pk_key") {
+		t.Errorf("Expected hash to be deterministic")
+	}
+	if HashAPIKey("pk_key") == HashAPIKey("pk_other") {
+		t.Errorf("Expected hash to depend on the key")
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ServiceIdentityMaxAge is the maximum age of a signed service identity before it is rejected
+var ServiceIdentityMaxAge = 5 * time.Minute
+
+func RequiredService(service string) gin.HandlerFunc {
+	/**
+	* This middleware is used for the internal routes which are only called by another service.
+	* The request must carry identity headers signed for the service with the identity signing key.
+	**/
+	return func(c *gin.Context) {
+		identity, err := VerifyIdentity(c.Request.Header, c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery)
+		if err != nil || identity.Username != service || !identity.IsAdmin {
+			slog.Error("Error happened: when verify service identity", "service", service, "error", err)
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func VerifyIdentity(header http.Header, method string, path string, query string) (*dco.Identity, error) {
+	/**
+	* Verifies the signed identity headers of a request, the counterpart of SetIdentityHeaders
+	* @param header: The header of the request
+	* @param method: The HTTP method of the request
+	* @param path: The URL path of the request
+	* @param query: The raw query of the request
+	* @return: The identity if the signature is valid
+	**/
+	if len(dco.IdentitySigningKey) == 0 {
+		return nil, errors.New("identity signing key is not configured")
+	}
+
+	timestamp := header.Get(dco.IdentityTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid identity timestamp")
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age > ServiceIdentityMaxAge || age < -ServiceIdentityMaxAge {
+		return nil, errors.New("identity timestamp is out of range")
+	}
+
+	isAdmin, err := strconv.ParseBool(header.Get(dco.IdentityAdminHeader))
+	if err != nil {
+		return nil, errors.New("invalid identity admin flag")
+	}
+
+	permissions := []string{}
+	if raw := header.Get(dco.IdentityPermissionsHeader); raw != "" {
+		permissions = strings.Split(raw, ",")
+	}
+
+	identity := dco.Identity{
+		Username:    header.Get(dco.IdentityUserHeader),
+		Department:  header.Get(dco.IdentityDepartmentHeader),
+		IsAdmin:     isAdmin,
+		Permissions: permissions,
+	}
+
+	expected, err := hex.DecodeString(SignIdentity(identity, timestamp, method, path, query))
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hex.DecodeString(header.Get(dco.IdentitySignatureHeader))
+	if err != nil {
+		return nil, errors.New("invalid identity signature")
+	}
+	if !hmac.Equal(expected, signature) {
+		return nil, errors.New("identity signature does not match")
+	}
+
+	return &identity, nil
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dco"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequiredService(t *testing.T) {
+	/* Internal routes only accept the signed identity of the service */
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	service := dco.Identity{Username: dco.PlannerServiceName, IsAdmin: true}
+	user := dco.Identity{Username: "test", IsAdmin: true}
+
+	type requiredServiceTest struct {
+		name               string
+		identity           *dco.Identity
+		signedPath         string
+		signedAt           time.Time
+		expectedStatusCode int
+	}
+
+	testSteps := []requiredServiceTest{
+		{name: "service identity is allowed", identity: &service, signedPath: "/internal/department/test", expectedStatusCode: http.StatusOK},
+		{name: "user identity is rejected", identity: &user, signedPath: "/internal/department/test", expectedStatusCode: http.StatusUnauthorized},
+		{name: "identity signed for another route is rejected", identity: &service, signedPath: "/internal/department/other", expectedStatusCode: http.StatusUnauthorized},
+		{name: "old identity is rejected", identity: &service, signedPath: "/internal/department/test", signedAt: time.Now().Add(-time.Hour), expectedStatusCode: http.StatusUnauthorized},
+		{name: "anonymous request is rejected", expectedStatusCode: http.StatusUnauthorized},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.PUT("/internal/department/:plannerID", RequiredService(dco.PlannerServiceName), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest("PUT", "/internal/department/test", nil)
+			if testStep.identity != nil {
+				SetIdentityHeaders(req.Header, *testStep.identity, "PUT", testStep.signedPath, "")
+			}
+			if !testStep.signedAt.IsZero() {
+				timestamp := strconv.FormatInt(testStep.signedAt.Unix(), 10)
+				req.Header.Set(dco.IdentityTimestampHeader, timestamp)
+				req.Header.Set(dco.IdentitySignatureHeader, SignIdentity(*testStep.identity, timestamp, "PUT", testStep.signedPath, ""))
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/domain/dto"
+	"api-gateway/app/pkg"
+	"api-gateway/app/policy"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersonDirectory finds the departments of the persons of the planner-backend
+type PersonDirectory interface {
+	// Returns the planner ids of the departments of a person, none for an unknown person
+	GetPersonDepartments(id string) ([]string, error)
+}
+
+func RoutePolicy(persons PersonDirectory) gin.HandlerFunc {
+	/**
+	* This middleware evaluates the policy of the route before a request is proxied to its upstream.
+	* It must be used after the Match of the route table, which provides the policy,
+	* and after the ForwardIdentity middleware, which provides the token of the user.
+	* Changes of a person act on the departments of the person, which are looked up in the planner-backend.
+	* Without the planner-backend they are only allowed for admins.
+	**/
+	return func(c *gin.Context) {
+		p := c.MustGet("routePolicy").(*policy.Policy)
+
+		var claim *dco.JWTClaim
+		if token, exists := c.Get("retrievedToken"); exists {
+			claim = token.(*dco.JWTClaim)
+		}
+
+		departments, err := RequestDepartments(c)
+		if err != nil {
+			slog.Error("Error happened: when read departments of request", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+			return
+		}
+
+		if person := RequestPerson(c); person != "" && claim != nil && !claim.IsAdmin && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			if persons == nil {
+				slog.Error("Error happened: when get departments of person", "person", person, "error", "planner-backend is not configured")
+				pkg.Abort(c, pkg.NewError(constant.Forbidden).WithMessage("Forbidden: The departments of the person cannot be checked"))
+				return
+			}
+			personDepartments, err := persons.GetPersonDepartments(person)
+			if err != nil {
+				slog.Error("Error happened: when get departments of person", "person", person, "error", err)
+				pkg.Abort(c, pkg.NewError(constant.BadGateway).Wrap(err))
+				return
+			}
+			departments = appendDepartments(departments, personDepartments...)
+		}
+
+		decision := p.Evaluate(claim, c.Request.Method, c.Request.URL.Path, departments...)
+		if decision.Allowed {
+			c.Next()
+			return
+		}
+
+		slog.Info("Request denied by policy", "method", c.Request.Method, "path", c.Request.URL.Path, "missing", decision.MissingPermissions, "departments", decision.ForeignDepartments)
+
+		if decision.Status == http.StatusUnauthorized {
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+
+		message := constant.Forbidden.GetResponseMessage()
+		if len(decision.ForeignDepartments) > 0 {
+			message = "Forbidden: Not a member of department " + strings.Join(decision.ForeignDepartments, ", ")
+		} else if len(decision.MissingPermissions) > 0 {
+			message = "Forbidden: Missing permission " + strings.Join(decision.MissingPermissions, ", ")
+		}
+
+		denied := pkg.NewError(constant.Forbidden).WithMessage(message)
+		for _, permission := range decision.MissingPermissions {
+			denied.WithDetails(dto.ErrorDetail{Code: "missing_permission", Message: permission})
+		}
+		for _, department := range decision.ForeignDepartments {
+			denied.WithDetails(dto.ErrorDetail{Code: "foreign_department", Message: department})
+		}
+		pkg.Abort(c, denied)
+	}
+}
+
+func appendDepartments(departments []string, more ...string) []string {
+	/* Returns a copy of the departments with the further departments which are not contained yet */
+	result := append([]string{}, departments...)
+	for _, department := range more {
+		found := false
+		for _, d := range result {
+			if d == department {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, department)
+		}
+	}
+	return result
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/policy"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRoutePolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	p := &policy.Policy{Rules: []policy.Rule{
+		{Method: http.MethodGet, Path: "/api/v1/reporting/public/*", Public: true},
+		{Method: "*", Path: "/api/v1/reporting/*", Permissions: []string{"report:read"}},
+	}}
+
+	type routePolicyTest struct {
+		claim              *dco.JWTClaim
+		path               string
+		expectedStatusCode int
+	}
+
+	testSteps := []routePolicyTest{
+		{claim: nil, path: "/api/v1/reporting/public/shifts", expectedStatusCode: http.StatusOK},
+		{claim: nil, path: "/api/v1/reporting/shifts", expectedStatusCode: http.StatusUnauthorized},
+		{claim: &dco.JWTClaim{Permissions: []string{}}, path: "/api/v1/reporting/shifts", expectedStatusCode: http.StatusForbidden},
+		{claim: &dco.JWTClaim{Permissions: []string{"report:read"}}, path: "/api/v1/reporting/shifts", expectedStatusCode: http.StatusOK},
+		// paths outside of the policy are denied
+		{claim: &dco.JWTClaim{Permissions: []string{"report:read"}}, path: "/api/v1/other", expectedStatusCode: http.StatusForbidden},
+	}
+
+	for i, testStep := range testSteps {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("routePolicy", p)
+			if testStep.claim != nil {
+				c.Set("retrievedToken", testStep.claim)
+			}
+		})
+		router.NoRoute(RoutePolicy(nil), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", testStep.path, nil)
+		router.ServeHTTP(w, req)
+
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+	}
+}
+
+type personDirectory map[string][]string
+
+func (d personDirectory) GetPersonDepartments(id string) ([]string, error) {
+	if departments, found := d[id]; found {
+		return departments, nil
+	}
+	return nil, errors.New("planner-backend is unavailable")
+}
+
+func TestRoutePolicyPersons(t *testing.T) {
+	/* Changes of a person are evaluated in the departments of the person, which the path does not name */
+	gin.SetMode(gin.TestMode)
+
+	p := &policy.Policy{Rules: []policy.Rule{
+		{Method: http.MethodGet, Path: "/api/v1/planner/*", Public: true},
+		{Method: "*", Path: "/api/v1/planner/person/*", Permissions: []string{"person:write"}},
+	}}
+	planner := &dco.JWTClaim{
+		PlannerDepartmentID: "planner1",
+		Permissions:         []string{"person:write"},
+		Memberships: []dco.DepartmentMembershipClaim{
+			{PlannerDepartmentID: "planner1", Permissions: []string{"person:write"}},
+		},
+	}
+	persons := personDirectory{"p1": {"planner1"}, "p2": {"planner2"}, "p3": {"planner1", "planner2"}, "new": {}}
+
+	type routePolicyPersonsTest struct {
+		name               string
+		claim              *dco.JWTClaim
+		persons            PersonDirectory
+		method             string
+		path               string
+		expectedStatusCode int
+	}
+
+	testSteps := []routePolicyPersonsTest{
+		{name: "person of the department", claim: planner, persons: persons, method: "PUT", path: "/api/v1/planner/person/p1", expectedStatusCode: http.StatusOK},
+		{name: "person of a foreign department", claim: planner, persons: persons, method: "DELETE", path: "/api/v1/planner/person/p2", expectedStatusCode: http.StatusForbidden},
+		{name: "absence of a person of a foreign department", claim: planner, persons: persons, method: "POST", path: "/api/v1/planner/person/p2/absency", expectedStatusCode: http.StatusForbidden},
+		{name: "person of a further foreign department", claim: planner, persons: persons, method: "PUT", path: "/api/v1/planner/person/p3", expectedStatusCode: http.StatusForbidden},
+		{name: "person without department", claim: planner, persons: persons, method: "PUT", path: "/api/v1/planner/person/new", expectedStatusCode: http.StatusOK},
+		{name: "reading is not looked up", claim: planner, persons: persons, method: "GET", path: "/api/v1/planner/person/unknown", expectedStatusCode: http.StatusOK},
+		{name: "admin is not looked up", claim: &dco.JWTClaim{IsAdmin: true}, persons: persons, method: "PUT", path: "/api/v1/planner/person/unknown", expectedStatusCode: http.StatusOK},
+		{name: "unavailable planner-backend", claim: planner, persons: persons, method: "PUT", path: "/api/v1/planner/person/unknown", expectedStatusCode: http.StatusBadGateway},
+		{name: "without planner-backend", claim: planner, method: "PUT", path: "/api/v1/planner/person/p1", expectedStatusCode: http.StatusForbidden},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.Use(func(c *gin.Context) {
+				c.Set("routePolicy", p)
+				c.Set("retrievedToken", testStep.claim)
+			})
+			router.NoRoute(RoutePolicy(testStep.persons), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testStep.method, testStep.path, nil)
+			router.ServeHTTP(w, req)
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
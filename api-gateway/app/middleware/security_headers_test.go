@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"api-gateway/app/mock"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	ctx := mock.GetGinTestContext(w, "GET", gin.Params{}, nil)
+
+	SecurityHeaders(0)(ctx)
+
+	expected := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "no-referrer",
+		"Content-Security-Policy":   "default-src 'none'; frame-ancestors 'none'",
+		"Strict-Transport-Security": "",
+	}
+	for header, value := range expected {
+		if actual := w.Header().Get(header); actual != value {
+			t.Errorf("%s should be %q, got %q", header, value, actual)
+		}
+	}
+
+	// HSTS is only sent if a max age is configured
+	w = httptest.NewRecorder()
+	ctx = mock.GetGinTestContext(w, "GET", gin.Params{}, nil)
+
+	SecurityHeaders(365 * 24 * time.Hour)(ctx)
+
+	if hsts := w.Header().Get("Strict-Transport-Security"); hsts != "max-age=31536000; includeSubDomains" {
+		t.Errorf("Unexpected Strict-Transport-Security %q", hsts)
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func VerifyCSRF() gin.HandlerFunc {
+	/**
+	* This middleware protects the requests authenticated by the cookie against cross-site request forgery.
+	* Mutating requests have to repeat the CSRF token of the session in the X-CSRF-Token header.
+	* The token is part of the signed access token, so a cookie planted by another site does not match.
+	* Requests with an API key or without a valid access token are not acting with a session and pass,
+	* the auth middlewares decide about them.
+	**/
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		if apiKeyFromRequest(c) != "" {
+			c.Next()
+			return
+		}
+
+		cookie, err := c.Request.Cookie(dco.AccessTokenCookie)
+		if err != nil || cookie.Value == "" {
+			c.Next()
+			return
+		}
+		token, err := DecodeToken(cookie.Value)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader(dco.CSRFTokenHeader)
+		if token.CSRFToken == "" || subtle.ConstantTimeCompare([]byte(header), []byte(token.CSRFToken)) != 1 {
+			slog.Warn("Request rejected, CSRF token is missing or invalid", "method", c.Request.Method, "path", c.Request.URL.Path, "user", token.Username)
+			pkg.Abort(c, pkg.NewError(constant.Forbidden).WithMessage("Invalid CSRF token"))
+			return
+		}
+
+		c.Next()
+	}
+}
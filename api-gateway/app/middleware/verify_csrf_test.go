@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestVerifyCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token, err := mock.GenerateMockToken(dao.User{Username: "test"})
+	if err != nil {
+		t.Fatalf("Failed to create valid token: %v", err)
+	}
+
+	type verifyCSRFTest struct {
+		method             string
+		cookie             string
+		csrfToken          string
+		apiKey             string
+		expectedStatusCode int
+	}
+
+	testSteps := []verifyCSRFTest{
+		{method: "POST", cookie: token, csrfToken: mock.CSRFToken, expectedStatusCode: http.StatusOK},
+		{method: "PUT", cookie: token, csrfToken: "forged", expectedStatusCode: http.StatusForbidden},
+		{method: "DELETE", cookie: token, expectedStatusCode: http.StatusForbidden},
+		{method: "GET", cookie: token, expectedStatusCode: http.StatusOK},
+		// requests without a session are left to the auth middlewares
+		{method: "POST", expectedStatusCode: http.StatusOK},
+		{method: "POST", cookie: "invalid", expectedStatusCode: http.StatusOK},
+		// the cookie is not used if an API key is sent
+		{method: "POST", cookie: token, apiKey: "pk_key", expectedStatusCode: http.StatusOK},
+	}
+
+	for i, testStep := range testSteps {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(VerifyCSRF())
+		router.Any("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(testStep.method, "/test", nil)
+		if testStep.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: dco.AccessTokenCookie, Value: testStep.cookie})
+		}
+		if testStep.csrfToken != "" {
+			req.Header.Set(dco.CSRFTokenHeader, testStep.csrfToken)
+		}
+		if testStep.apiKey != "" {
+			req.Header.Set(dco.APIKeyHeader, testStep.apiKey)
+		}
+		router.ServeHTTP(w, req)
+
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/* Mock file for api key repository */
+package mock
+
+import (
+	"api-gateway/app/domain/dao"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyRepositoryMock struct {
+	dataContainer      map[string]interface{}
+	errorContainer     map[string]error
+	primedFunctionName string
+
+	// Saved contains the keys passed to Save
+	Saved []dao.APIKey
+	// Touched contains the ids passed to TouchAPIKey
+	Touched []uuid.UUID
+}
+
+/* Mock interface implementations */
+func (r *APIKeyRepositoryMock) On(functionName string) Mock {
+	// set default value
+	r.dataContainer[functionName] = nil
+	r.errorContainer[functionName] = nil
+
+	// Set primed function name
+	r.primedFunctionName = functionName
+
+	return r
+}
+
+func (r *APIKeyRepositoryMock) Return(mockData interface{}, errorData error) Mock {
+	r.dataContainer[r.primedFunctionName] = mockData
+	r.errorContainer[r.primedFunctionName] = errorData
+
+	return r
+}
+
+/* Repostory interface implementations */
+func (r *APIKeyRepositoryMock) FindAPIKeysByUserID(userID uuid.UUID) ([]dao.APIKey, error) {
+	if r.dataContainer["FindAPIKeysByUserID"] == nil {
+		return nil, r.errorContainer["FindAPIKeysByUserID"]
+	}
+	return r.dataContainer["FindAPIKeysByUserID"].([]dao.APIKey), r.errorContainer["FindAPIKeysByUserID"]
+}
+
+func (r *APIKeyRepositoryMock) FindAPIKeyByHash(hash string) (dao.APIKey, error) {
+	if r.dataContainer["FindAPIKeyByHash"] == nil {
+		return dao.APIKey{}, r.errorContainer["FindAPIKeyByHash"]
+	}
+	return r.dataContainer["FindAPIKeyByHash"].(dao.APIKey), r.errorContainer["FindAPIKeyByHash"]
+}
+
+func (r *APIKeyRepositoryMock) Save(apiKey *dao.APIKey) (dao.APIKey, error) {
+	if r.errorContainer["Save"] != nil {
+		return dao.APIKey{}, r.errorContainer["Save"]
+	}
+	r.Saved = append(r.Saved, *apiKey)
+	return *apiKey, nil
+}
+
+func (r *APIKeyRepositoryMock) DeleteAPIKey(userID uuid.UUID, id uuid.UUID) error {
+	return r.errorContainer["DeleteAPIKey"]
+}
+
+func (r *APIKeyRepositoryMock) TouchAPIKey(id uuid.UUID, usedAt time.Time, interval time.Duration) error {
+	r.Touched = append(r.Touched, id)
+	return r.errorContainer["TouchAPIKey"]
+}
+
+/**
+ * Function to create new APIKeyRepositoryMock
+ * @param void
+ * @return APIKeyRepositoryMock
+ */
+func NewAPIKeyRepositoryMock() APIKeyRepositoryMock {
+	return APIKeyRepositoryMock{
+		dataContainer:  make(map[string]interface{}),
+		errorContainer: make(map[string]error),
+	}
+}
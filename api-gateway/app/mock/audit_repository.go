@@ -0,0 +1,18 @@
+/* Mock file for audit repository */
+package mock
+
+import "api-gateway/app/domain/dao"
+
+type AuditRepositoryMock struct {
+	// Entries contains the saved audit entries
+	Entries []dao.AuditEntry
+	Err     error
+}
+
+func (r *AuditRepositoryMock) Save(entry *dao.AuditEntry) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	r.Entries = append(r.Entries, *entry)
+	return nil
+}
@@ -14,6 +14,10 @@ func (m *SystemControllerMock) Ping(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "pong"})
 }
 
+func (m *SystemControllerMock) JWKS(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "JWKS"})
+}
+
 /** UserControllerMock */
 type UserControllerMock struct{}
 
@@ -61,6 +65,114 @@ func (m *UserControllerMock) CheckAdmin(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "CheckAdmin"})
 }
 
+func (m *UserControllerMock) Permissions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Permissions"})
+}
+
+func (m *UserControllerMock) Unlock(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Unlock"})
+}
+
+func (m *UserControllerMock) Providers(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Providers"})
+}
+
+func (m *UserControllerMock) ExternalLogin(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "ExternalLogin"})
+}
+
+func (m *UserControllerMock) ExternalCallback(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "ExternalCallback"})
+}
+
+func (m *UserControllerMock) LoginTwoFactor(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "LoginTwoFactor"})
+}
+
+func (m *UserControllerMock) EnrollTwoFactor(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "EnrollTwoFactor"})
+}
+
+func (m *UserControllerMock) ConfirmTwoFactor(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "ConfirmTwoFactor"})
+}
+
+func (m *UserControllerMock) DisableTwoFactor(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "DisableTwoFactor"})
+}
+
+func (m *UserControllerMock) RegenerateRecoveryCodes(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "RegenerateRecoveryCodes"})
+}
+
+func (m *UserControllerMock) ResetTwoFactor(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "ResetTwoFactor"})
+}
+
+func (m *UserControllerMock) GetAPIKeys(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "GetAPIKeys"})
+}
+
+func (m *UserControllerMock) CreateAPIKey(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "CreateAPIKey"})
+}
+
+func (m *UserControllerMock) RevokeAPIKey(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "RevokeAPIKey"})
+}
+
+func (m *UserControllerMock) Refresh(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Refresh"})
+}
+
+func (m *UserControllerMock) Sessions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Sessions"})
+}
+
+func (m *UserControllerMock) RevokeSession(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "RevokeSession"})
+}
+
+func (m *UserControllerMock) RevokeUserSessions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "RevokeUserSessions"})
+}
+
+func (m *UserControllerMock) ChangePassword(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "ChangePassword"})
+}
+
+func (m *UserControllerMock) RequestPasswordReset(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "RequestPasswordReset"})
+}
+
+func (m *UserControllerMock) ResetPassword(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "ResetPassword"})
+}
+
+func (m *UserControllerMock) AddRole(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "AddRole"})
+}
+
+func (m *UserControllerMock) DeleteRole(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "DeleteRole"})
+}
+
+func (m *UserControllerMock) SaveMembership(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "SaveMembership"})
+}
+
+func (m *UserControllerMock) DeleteMembership(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "DeleteMembership"})
+}
+
+func (m *UserControllerMock) LinkPerson(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "LinkPerson"})
+}
+
+func (m *UserControllerMock) UnlinkPerson(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "UnlinkPerson"})
+}
+
 type DepartmentControllerMock struct {
 }
 
@@ -84,6 +196,18 @@ func (m *DepartmentControllerMock) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Delete"})
 }
 
+func (m *DepartmentControllerMock) Sync(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Sync"})
+}
+
+func (m *DepartmentControllerMock) Remove(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Remove"})
+}
+
+func (m *DepartmentControllerMock) Reconcile(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Reconcile"})
+}
+
 /** PermissionControllerMock */
 type PermissionControllerMock struct{}
 
@@ -106,3 +230,49 @@ func (m *PermissionControllerMock) Update(ctx *gin.Context) {
 func (m *PermissionControllerMock) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Delete"})
 }
+
+/** RoleControllerMock */
+type RoleControllerMock struct{}
+
+func (m *RoleControllerMock) GetAll(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "GetAll"})
+}
+
+func (m *RoleControllerMock) Get(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Get"})
+}
+
+func (m *RoleControllerMock) Create(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Create"})
+}
+
+func (m *RoleControllerMock) Update(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Update"})
+}
+
+func (m *RoleControllerMock) Delete(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Delete"})
+}
+
+type MeControllerMock struct {
+}
+
+func (m *MeControllerMock) Assignments(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Assignments"})
+}
+
+func (m *MeControllerMock) Absences(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Absences"})
+}
+
+func (m *MeControllerMock) Hours(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Hours"})
+}
+
+func (m *MeControllerMock) LeaveRequests(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "LeaveRequests"})
+}
+
+func (m *MeControllerMock) RequestLeave(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "RequestLeave"})
+}
@@ -11,6 +11,11 @@ type DepartmentRepositoryMock struct {
 	dataContainer      map[string]interface{}
 	errorContainer     map[string]error
 	primedFunctionName string
+
+	// Saved contains the departments passed to Save
+	Saved []dao.Department
+	// Deleted contains the ids passed to DeleteDepartmentById
+	Deleted []uuid.UUID
 }
 
 /* Mock interface implementations */
@@ -56,7 +61,23 @@ func (r *DepartmentRepositoryMock) FindDepartmentById(id uuid.UUID) (dao.Departm
 	return r.dataContainer["FindDepartmentById"].(dao.Department), r.errorContainer["FindDepartmentById"]
 }
 
+func (r *DepartmentRepositoryMock) FindDepartmentByPlannerID(plannerID string) (dao.Department, error) {
+	if r.dataContainer["FindDepartmentByPlannerID"] == nil {
+		return dao.Department{}, r.errorContainer["FindDepartmentByPlannerID"]
+	}
+
+	return r.dataContainer["FindDepartmentByPlannerID"].(dao.Department), r.errorContainer["FindDepartmentByPlannerID"]
+}
+
+func (r *DepartmentRepositoryMock) FindDeletedPlannerIDs() ([]string, error) {
+	if r.dataContainer["FindDeletedPlannerIDs"] == nil {
+		return nil, r.errorContainer["FindDeletedPlannerIDs"]
+	}
+	return r.dataContainer["FindDeletedPlannerIDs"].([]string), r.errorContainer["FindDeletedPlannerIDs"]
+}
+
 func (r *DepartmentRepositoryMock) Save(Department *dao.Department) (dao.Department, error) {
+	r.Saved = append(r.Saved, *Department)
 	if r.dataContainer["Save"] == nil {
 		return dao.Department{}, r.errorContainer["Save"]
 	}
@@ -64,6 +85,7 @@ func (r *DepartmentRepositoryMock) Save(Department *dao.Department) (dao.Departm
 }
 
 func (r *DepartmentRepositoryMock) DeleteDepartmentById(id uuid.UUID) error {
+	r.Deleted = append(r.Deleted, id)
 	return r.errorContainer["DeleteDepartmentById"]
 }
 
@@ -0,0 +1,73 @@
+/* Mock file for login throttle repository */
+package mock
+
+import (
+	"api-gateway/app/domain/dao"
+	"time"
+)
+
+type LoginThrottleRepositoryMock struct {
+	dataContainer      map[string]interface{}
+	errorContainer     map[string]error
+	primedFunctionName string
+
+	// LockedKeys contains the keys passed to Lock, ResetKeys the keys passed to Reset
+	LockedKeys []string
+	ResetKeys  []string
+}
+
+/* Mock interface implementations */
+func (r *LoginThrottleRepositoryMock) On(functionName string) Mock {
+	// set default value
+	r.dataContainer[functionName] = nil
+	r.errorContainer[functionName] = nil
+
+	// Set primed function name
+	r.primedFunctionName = functionName
+
+	return r
+}
+
+func (r *LoginThrottleRepositoryMock) Return(mockData interface{}, errorData error) Mock {
+	r.dataContainer[r.primedFunctionName] = mockData
+	r.errorContainer[r.primedFunctionName] = errorData
+
+	return r
+}
+
+/* Repostory interface implementations */
+func (r *LoginThrottleRepositoryMock) FindLoginThrottle(key string) (dao.LoginThrottle, error) {
+	if r.dataContainer["FindLoginThrottle"] == nil {
+		return dao.LoginThrottle{Key: key}, r.errorContainer["FindLoginThrottle"]
+	}
+	return r.dataContainer["FindLoginThrottle"].(dao.LoginThrottle), r.errorContainer["FindLoginThrottle"]
+}
+
+func (r *LoginThrottleRepositoryMock) RecordFailure(key string, window time.Duration) (dao.LoginThrottle, error) {
+	if r.dataContainer["RecordFailure"] == nil {
+		return dao.LoginThrottle{Key: key, Failures: 1, LastFailureAt: time.Now()}, r.errorContainer["RecordFailure"]
+	}
+	return r.dataContainer["RecordFailure"].(dao.LoginThrottle), r.errorContainer["RecordFailure"]
+}
+
+func (r *LoginThrottleRepositoryMock) Lock(key string, until time.Time) error {
+	r.LockedKeys = append(r.LockedKeys, key)
+	return r.errorContainer["Lock"]
+}
+
+func (r *LoginThrottleRepositoryMock) Reset(key string) error {
+	r.ResetKeys = append(r.ResetKeys, key)
+	return r.errorContainer["Reset"]
+}
+
+/**
+ * Function to create new LoginThrottleRepositoryMock
+ * @param void
+ * @return LoginThrottleRepositoryMock
+ */
+func NewLoginThrottleRepositoryMock() LoginThrottleRepositoryMock {
+	return LoginThrottleRepositoryMock{
+		dataContainer:  make(map[string]interface{}),
+		errorContainer: make(map[string]error),
+	}
+}
@@ -0,0 +1,18 @@
+/* Mock file for the mailer */
+package mock
+
+import "api-gateway/app/mailer"
+
+type MailerMock struct {
+	// Sent contains the messages passed to Send
+	Sent []mailer.Message
+	Err  error
+}
+
+func (m *MailerMock) Send(message mailer.Message) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Sent = append(m.Sent, message)
+	return nil
+}
@@ -0,0 +1,68 @@
+/* Mock file for password reset repository */
+package mock
+
+import (
+	"api-gateway/app/domain/dao"
+
+	"github.com/google/uuid"
+)
+
+type PasswordResetRepositoryMock struct {
+	dataContainer      map[string]interface{}
+	errorContainer     map[string]error
+	primedFunctionName string
+}
+
+/* Mock interface implementations */
+func (r *PasswordResetRepositoryMock) On(functionName string) Mock {
+	// set default value
+	r.dataContainer[functionName] = nil
+	r.errorContainer[functionName] = nil
+
+	// Set primed function name
+	r.primedFunctionName = functionName
+
+	return r
+}
+
+func (r *PasswordResetRepositoryMock) Return(mockData interface{}, errorData error) Mock {
+	r.dataContainer[r.primedFunctionName] = mockData
+	r.errorContainer[r.primedFunctionName] = errorData
+
+	return r
+}
+
+/* Repostory interface implementations */
+func (r *PasswordResetRepositoryMock) Save(passwordReset *dao.PasswordReset) (dao.PasswordReset, error) {
+	if r.dataContainer["Save"] == nil {
+		return *passwordReset, r.errorContainer["Save"]
+	}
+	return r.dataContainer["Save"].(dao.PasswordReset), r.errorContainer["Save"]
+}
+
+func (r *PasswordResetRepositoryMock) FindPasswordResetByTokenHash(hash string) (dao.PasswordReset, error) {
+	if r.dataContainer["FindPasswordResetByTokenHash"] == nil {
+		return dao.PasswordReset{}, r.errorContainer["FindPasswordResetByTokenHash"]
+	}
+	return r.dataContainer["FindPasswordResetByTokenHash"].(dao.PasswordReset), r.errorContainer["FindPasswordResetByTokenHash"]
+}
+
+func (r *PasswordResetRepositoryMock) UsePasswordReset(id uuid.UUID) error {
+	return r.errorContainer["UsePasswordReset"]
+}
+
+func (r *PasswordResetRepositoryMock) InvalidatePasswordResetsOfUser(userID uuid.UUID) error {
+	return r.errorContainer["InvalidatePasswordResetsOfUser"]
+}
+
+/**
+ * Function to create new PasswordResetRepositoryMock
+ * @param void
+ * @return PasswordResetRepositoryMock
+ */
+func NewPasswordResetRepositoryMock() PasswordResetRepositoryMock {
+	return PasswordResetRepositoryMock{
+		dataContainer:  make(map[string]interface{}),
+		errorContainer: make(map[string]error),
+	}
+}
@@ -0,0 +1,64 @@
+/* Mock file for the client of the planner-backend */
+package mock
+
+import (
+	"api-gateway/app/domain/dco"
+	"net/http"
+	"net/url"
+)
+
+type PlannerClientMock struct {
+	// Departments is returned by GetDepartments
+	Departments []dco.PlannerDepartment
+	// Error is returned by every call
+	Error error
+
+	// Saved contains the departments passed to SaveDepartment
+	Saved []dco.PlannerDepartment
+	// Deleted contains the ids passed to DeleteDepartment
+	Deleted []string
+
+	// Person is returned by GetPerson
+	Person dco.PlannerPerson
+	// PersonDepartments is returned by GetPersonDepartments
+	PersonDepartments []string
+	// Body is returned by Forward
+	Body []byte
+	// Status is returned by Forward, 200 if not set
+	Status int
+	// Forwarded contains the methods, paths and queries passed to Forward
+	Forwarded []string
+	// ForwardedBodies contains the bodies passed to Forward
+	ForwardedBodies [][]byte
+}
+
+func (p *PlannerClientMock) GetDepartments() ([]dco.PlannerDepartment, error) {
+	return p.Departments, p.Error
+}
+
+func (p *PlannerClientMock) SaveDepartment(department dco.PlannerDepartment) error {
+	p.Saved = append(p.Saved, department)
+	return p.Error
+}
+
+func (p *PlannerClientMock) DeleteDepartment(id string) error {
+	p.Deleted = append(p.Deleted, id)
+	return p.Error
+}
+
+func (p *PlannerClientMock) GetPerson(id string) (dco.PlannerPerson, error) {
+	return p.Person, p.Error
+}
+
+func (p *PlannerClientMock) GetPersonDepartments(id string) ([]string, error) {
+	return p.PersonDepartments, p.Error
+}
+
+func (p *PlannerClientMock) Forward(identity dco.Identity, method string, path string, query url.Values, body []byte) (int, []byte, error) {
+	p.Forwarded = append(p.Forwarded, method+" "+path+"?"+query.Encode())
+	p.ForwardedBodies = append(p.ForwardedBodies, body)
+	if p.Status == 0 {
+		return http.StatusOK, p.Body, p.Error
+	}
+	return p.Status, p.Body, p.Error
+}
@@ -0,0 +1,81 @@
+/* Mock file for role repository */
+package mock
+
+import (
+	"api-gateway/app/domain/dao"
+
+	"github.com/google/uuid"
+)
+
+type RoleRepositoryMock struct {
+	dataContainer      map[string]interface{}
+	errorContainer     map[string]error
+	primedFunctionName string
+}
+
+/* Mock interface implementations */
+func (r *RoleRepositoryMock) On(functionName string) Mock {
+	// set default value
+	r.dataContainer[functionName] = nil
+	r.errorContainer[functionName] = nil
+
+	// Set primed function name
+	r.primedFunctionName = functionName
+
+	return r
+}
+
+func (r *RoleRepositoryMock) Return(mockData interface{}, errorData error) Mock {
+	r.dataContainer[r.primedFunctionName] = mockData
+	r.errorContainer[r.primedFunctionName] = errorData
+
+	return r
+}
+
+/* Repostory interface implementations */
+func (r *RoleRepositoryMock) FindAllRoles() ([]dao.Role, error) {
+	if r.dataContainer["FindAllRoles"] == nil {
+		return nil, r.errorContainer["FindAllRoles"]
+	}
+
+	return r.dataContainer["FindAllRoles"].([]dao.Role), r.errorContainer["FindAllRoles"]
+}
+
+func (r *RoleRepositoryMock) FindRoleByName(name string) (dao.Role, error) {
+	if r.dataContainer["FindRoleByName"] == nil {
+		return dao.Role{}, r.errorContainer["FindRoleByName"]
+	}
+
+	return r.dataContainer["FindRoleByName"].(dao.Role), r.errorContainer["FindRoleByName"]
+}
+
+func (r *RoleRepositoryMock) FindRoleById(id uuid.UUID) (dao.Role, error) {
+	if r.dataContainer["FindRoleById"] == nil {
+		return dao.Role{}, r.errorContainer["FindRoleById"]
+	}
+
+	return r.dataContainer["FindRoleById"].(dao.Role), r.errorContainer["FindRoleById"]
+}
+
+func (r *RoleRepositoryMock) Save(role *dao.Role) (dao.Role, error) {
+	if r.dataContainer["Save"] == nil {
+		return dao.Role{}, r.errorContainer["Save"]
+	}
+	return r.dataContainer["Save"].(dao.Role), r.errorContainer["Save"]
+}
+
+func (r *RoleRepositoryMock) DeleteRoleById(id uuid.UUID) error {
+	return r.errorContainer["DeleteRoleById"]
+}
+
+/**
+ * Function to create new RoleRepositoryMock
+ * @param void
+ * @return RoleRepositoryMock
+ */
+func NewRoleRepositoryMock() RoleRepositoryMock {
+	return RoleRepositoryMock{
+		dataContainer:  make(map[string]interface{}),
+		errorContainer: make(map[string]error),
+	}
+}
@@ -0,0 +1,97 @@
+/* Mock file for session repository */
+package mock
+
+import (
+	"api-gateway/app/domain/dao"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionRepositoryMock struct {
+	dataContainer      map[string]interface{}
+	errorContainer     map[string]error
+	primedFunctionName string
+}
+
+/* Mock interface implementations */
+func (r *SessionRepositoryMock) On(functionName string) Mock {
+	// set default value
+	r.dataContainer[functionName] = nil
+	r.errorContainer[functionName] = nil
+
+	// Set primed function name
+	r.primedFunctionName = functionName
+
+	return r
+}
+
+func (r *SessionRepositoryMock) Return(mockData interface{}, errorData error) Mock {
+	r.dataContainer[r.primedFunctionName] = mockData
+	r.errorContainer[r.primedFunctionName] = errorData
+
+	return r
+}
+
+/* Repostory interface implementations */
+func (r *SessionRepositoryMock) Save(session *dao.Session) (dao.Session, error) {
+	if r.dataContainer["Save"] == nil {
+		return *session, r.errorContainer["Save"]
+	}
+	return r.dataContainer["Save"].(dao.Session), r.errorContainer["Save"]
+}
+
+func (r *SessionRepositoryMock) FindSessionById(id uuid.UUID) (dao.Session, error) {
+	if r.dataContainer["FindSessionById"] == nil {
+		return dao.Session{}, r.errorContainer["FindSessionById"]
+	}
+	return r.dataContainer["FindSessionById"].(dao.Session), r.errorContainer["FindSessionById"]
+}
+
+func (r *SessionRepositoryMock) FindSessionByRefreshTokenHash(hash string) (dao.Session, error) {
+	if r.dataContainer["FindSessionByRefreshTokenHash"] == nil {
+		return dao.Session{}, r.errorContainer["FindSessionByRefreshTokenHash"]
+	}
+	return r.dataContainer["FindSessionByRefreshTokenHash"].(dao.Session), r.errorContainer["FindSessionByRefreshTokenHash"]
+}
+
+func (r *SessionRepositoryMock) FindActiveSessionsByUserId(userID uuid.UUID) ([]dao.Session, error) {
+	if r.dataContainer["FindActiveSessionsByUserId"] == nil {
+		return nil, r.errorContainer["FindActiveSessionsByUserId"]
+	}
+	return r.dataContainer["FindActiveSessionsByUserId"].([]dao.Session), r.errorContainer["FindActiveSessionsByUserId"]
+}
+
+func (r *SessionRepositoryMock) RotateRefreshToken(session *dao.Session, currentHash string) (bool, error) {
+	if r.dataContainer["RotateRefreshToken"] == nil {
+		return false, r.errorContainer["RotateRefreshToken"]
+	}
+	return r.dataContainer["RotateRefreshToken"].(bool), r.errorContainer["RotateRefreshToken"]
+}
+
+func (r *SessionRepositoryMock) RevokeSession(id uuid.UUID, tokensExpireAt time.Time) error {
+	return r.errorContainer["RevokeSession"]
+}
+
+func (r *SessionRepositoryMock) RevokeSessionsOfUser(userID uuid.UUID, tokensExpireAt time.Time) error {
+	return r.errorContainer["RevokeSessionsOfUser"]
+}
+
+func (r *SessionRepositoryMock) IsRevoked(sessionID uuid.UUID) (bool, error) {
+	if r.dataContainer["IsRevoked"] == nil {
+		return false, r.errorContainer["IsRevoked"]
+	}
+	return r.dataContainer["IsRevoked"].(bool), r.errorContainer["IsRevoked"]
+}
+
+/**
+ * Function to create new SessionRepositoryMock
+ * @param void
+ * @return SessionRepositoryMock
+ */
+func NewSessionRepositoryMock() SessionRepositoryMock {
+	return SessionRepositoryMock{
+		dataContainer:  make(map[string]interface{}),
+		errorContainer: make(map[string]error),
+	}
+}
@@ -3,26 +3,50 @@ package mock
 import (
 	"api-gateway/app/domain/dao"
 	"api-gateway/app/domain/dco"
+	"api-gateway/app/jwtkeys"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+func init() {
+	// tests sign and verify the tokens with a generated key
+	key, _ := jwtkeys.GenerateKey("test")
+	dco.JWTKeys, _ = jwtkeys.NewKeyring("test", key)
+}
+
+// The CSRF token of the mock tokens
+const CSRFToken = "csrf"
+
 func GenerateMockToken(user dao.User) (string, error) {
-	convertedPermissions := make([]string, len(user.Permissions))
-	for i, v := range user.Permissions {
-		convertedPermissions[i] = v.ConvertToID()
+	memberships := []dco.DepartmentMembershipClaim{}
+	for _, department := range user.Departments() {
+		memberships = append(memberships, dco.DepartmentMembershipClaim{
+			Department:          department.ID.String(),
+			PlannerDepartmentID: department.PlannerID,
+			Roles:               user.RoleNamesIn(department.ID),
+			Permissions:         user.PermissionNamesIn(department.ID),
+		})
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, dco.JWTClaim{
+	return dco.JWTKeys.Sign(dco.JWTClaim{
+		SessionID:   uuid.NewString(),
 		Username:    user.Username,
 		Department:  user.Department.ID.String(),
-		Permissions: convertedPermissions,
-		IsAdmin:     user.IsAdmin,
+		Roles:       user.RoleNames(),
+		Permissions: user.PermissionNames(),
+		IsAdmin:     user.IsSystemAdmin(),
+
+		PlannerDepartmentID: user.Department.PlannerID,
+		Memberships:         memberships,
+
+		MustChangePassword:  user.MustChangePassword,
+		MustEnrollTwoFactor: user.RequiresTwoFactor() && !user.HasTwoFactor(),
+		CSRFToken:           CSRFToken,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    dco.JWTIssuer,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(dco.JWTExpirationTime)),
 		},
 	})
-
-	return token.SignedString([]byte("secret"))
 }
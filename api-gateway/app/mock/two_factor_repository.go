@@ -0,0 +1,105 @@
+/* Mock file for two factor repository */
+package mock
+
+import (
+	"api-gateway/app/domain/dao"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactorRepositoryMock struct {
+	dataContainer      map[string]interface{}
+	errorContainer     map[string]error
+	primedFunctionName string
+
+	// RecoveryCodeHashes contains the hashes passed to ReplaceRecoveryCodes
+	RecoveryCodeHashes []string
+}
+
+/* Mock interface implementations */
+func (r *TwoFactorRepositoryMock) On(functionName string) Mock {
+	// set default value
+	r.dataContainer[functionName] = nil
+	r.errorContainer[functionName] = nil
+
+	// Set primed function name
+	r.primedFunctionName = functionName
+
+	return r
+}
+
+func (r *TwoFactorRepositoryMock) Return(mockData interface{}, errorData error) Mock {
+	r.dataContainer[r.primedFunctionName] = mockData
+	r.errorContainer[r.primedFunctionName] = errorData
+
+	return r
+}
+
+/* Repostory interface implementations */
+func (r *TwoFactorRepositoryMock) FindTwoFactorByUserID(userID uuid.UUID) (dao.TwoFactor, error) {
+	if r.dataContainer["FindTwoFactorByUserID"] == nil {
+		return dao.TwoFactor{}, r.errorContainer["FindTwoFactorByUserID"]
+	}
+	return r.dataContainer["FindTwoFactorByUserID"].(dao.TwoFactor), r.errorContainer["FindTwoFactorByUserID"]
+}
+
+func (r *TwoFactorRepositoryMock) Save(twoFactor *dao.TwoFactor) (dao.TwoFactor, error) {
+	if r.dataContainer["Save"] == nil {
+		return *twoFactor, r.errorContainer["Save"]
+	}
+	return r.dataContainer["Save"].(dao.TwoFactor), r.errorContainer["Save"]
+}
+
+func (r *TwoFactorRepositoryMock) Enable(userID uuid.UUID, step int64) error {
+	return r.errorContainer["Enable"]
+}
+
+func (r *TwoFactorRepositoryMock) UseStep(userID uuid.UUID, step int64) error {
+	return r.errorContainer["UseStep"]
+}
+
+func (r *TwoFactorRepositoryMock) DeleteTwoFactor(userID uuid.UUID) error {
+	return r.errorContainer["DeleteTwoFactor"]
+}
+
+func (r *TwoFactorRepositoryMock) ReplaceRecoveryCodes(userID uuid.UUID, hashes []string) error {
+	if r.errorContainer["ReplaceRecoveryCodes"] != nil {
+		return r.errorContainer["ReplaceRecoveryCodes"]
+	}
+	r.RecoveryCodeHashes = hashes
+	return nil
+}
+
+func (r *TwoFactorRepositoryMock) UseRecoveryCode(userID uuid.UUID, hash string) error {
+	return r.errorContainer["UseRecoveryCode"]
+}
+
+func (r *TwoFactorRepositoryMock) SaveLoginChallenge(challenge *dao.LoginChallenge) (dao.LoginChallenge, error) {
+	if r.dataContainer["SaveLoginChallenge"] == nil {
+		return *challenge, r.errorContainer["SaveLoginChallenge"]
+	}
+	return r.dataContainer["SaveLoginChallenge"].(dao.LoginChallenge), r.errorContainer["SaveLoginChallenge"]
+}
+
+func (r *TwoFactorRepositoryMock) FindLoginChallengeByTokenHash(hash string) (dao.LoginChallenge, error) {
+	if r.dataContainer["FindLoginChallengeByTokenHash"] == nil {
+		return dao.LoginChallenge{}, r.errorContainer["FindLoginChallengeByTokenHash"]
+	}
+	return r.dataContainer["FindLoginChallengeByTokenHash"].(dao.LoginChallenge), r.errorContainer["FindLoginChallengeByTokenHash"]
+}
+
+func (r *TwoFactorRepositoryMock) DeleteLoginChallenge(id uuid.UUID) error {
+	return r.errorContainer["DeleteLoginChallenge"]
+}
+
+/**
+ * Function to create new TwoFactorRepositoryMock
+ * @param void
+ * @return TwoFactorRepositoryMock
+ */
+func NewTwoFactorRepositoryMock() TwoFactorRepositoryMock {
+	return TwoFactorRepositoryMock{
+		dataContainer:  make(map[string]interface{}),
+		errorContainer: make(map[string]error),
+	}
+}
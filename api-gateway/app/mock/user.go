@@ -0,0 +1,6 @@
+package mock
+
+import "api-gateway/app/domain/dao"
+
+// SystemAdminRoles can be assigned to a dao.User to create a system admin in tests
+var SystemAdminRoles = []dao.UserRole{{Role: dao.Role{Name: dao.RoleSystemAdmin}}}
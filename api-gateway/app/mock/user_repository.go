@@ -10,6 +10,11 @@ type UserRepositoryMock struct {
 	dataContainer      map[string]interface{}
 	errorContainer     map[string]error
 	primedFunctionName string
+
+	// SavedMemberships contains the memberships passed to SaveMembership
+	SavedMemberships []dao.DepartmentMembership
+	// PersonIDs contains the person ids passed to SetPersonID
+	PersonIDs []*string
 }
 
 /* Mock interface implementations */
@@ -40,7 +45,8 @@ func (r *UserRepositoryMock) Return(mockData interface{}, errorData error) Mock
 			BaseModel: dao.BaseModel{
 				ID: mockData.(dao.User).DepartmentID,
 			},
-			Name: mockData.(dao.User).Department.Name,
+			Name:      mockData.(dao.User).Department.Name,
+			PlannerID: mockData.(dao.User).Department.PlannerID,
 		}
 		mockData = formattedData
 	}
@@ -68,6 +74,20 @@ func (r *UserRepositoryMock) FindUserByUsername(username string) (dao.User, erro
 	return r.dataContainer["FindUserByUsername"].(dao.User), r.errorContainer["FindUserByUsername"]
 }
 
+func (r *UserRepositoryMock) FindUserByExternalIdentity(provider string, subject string) (dao.User, error) {
+	if r.dataContainer["FindUserByExternalIdentity"] == nil {
+		return dao.User{}, r.errorContainer["FindUserByExternalIdentity"]
+	}
+	return r.dataContainer["FindUserByExternalIdentity"].(dao.User), r.errorContainer["FindUserByExternalIdentity"]
+}
+
+func (r *UserRepositoryMock) FindUserByPersonID(personID string) (dao.User, error) {
+	if r.dataContainer["FindUserByPersonID"] == nil {
+		return dao.User{}, r.errorContainer["FindUserByPersonID"]
+	}
+	return r.dataContainer["FindUserByPersonID"].(dao.User), r.errorContainer["FindUserByPersonID"]
+}
+
 func (r *UserRepositoryMock) FindUserById(id uuid.UUID) (dao.User, error) {
 	if r.dataContainer["FindUserById"] == nil {
 		return dao.User{}, r.errorContainer["FindUserById"]
@@ -84,6 +104,10 @@ func (r *UserRepositoryMock) Save(user *dao.User) (dao.User, error) {
 	return r.dataContainer["Save"].(dao.User), r.errorContainer["Save"]
 }
 
+func (r *UserRepositoryMock) UpdatePassword(id uuid.UUID, hash string, mustChangePassword bool) error {
+	return r.errorContainer["UpdatePassword"]
+}
+
 func (r *UserRepositoryMock) DeleteUser(id uuid.UUID) error {
 	return r.errorContainer["DeleteUser"]
 }
@@ -96,6 +120,32 @@ func (r *UserRepositoryMock) DeletePermissionFromUser(userID uuid.UUID, permissi
 	return r.errorContainer["DeletePermissionFromUser"]
 }
 
+func (r *UserRepositoryMock) AddRoleToUser(userRole *dao.UserRole) error {
+	return r.errorContainer["AddRoleToUser"]
+}
+
+func (r *UserRepositoryMock) DeleteRoleFromUser(userID uuid.UUID, roleID uuid.UUID, departmentID *uuid.UUID) error {
+	return r.errorContainer["DeleteRoleFromUser"]
+}
+
+func (r *UserRepositoryMock) SyncProvisionedRoles(userID uuid.UUID, roles []dao.UserRole) error {
+	return r.errorContainer["SyncProvisionedRoles"]
+}
+
+func (r *UserRepositoryMock) SaveMembership(membership *dao.DepartmentMembership) error {
+	r.SavedMemberships = append(r.SavedMemberships, *membership)
+	return r.errorContainer["SaveMembership"]
+}
+
+func (r *UserRepositoryMock) DeleteMembership(userID uuid.UUID, departmentID uuid.UUID) error {
+	return r.errorContainer["DeleteMembership"]
+}
+
+func (r *UserRepositoryMock) SetPersonID(userID uuid.UUID, personID *string) error {
+	r.PersonIDs = append(r.PersonIDs, personID)
+	return r.errorContainer["SetPersonID"]
+}
+
 /**
  * Function to create new UserRepositoryMock
  * @param void
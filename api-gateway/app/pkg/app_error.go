@@ -0,0 +1,73 @@
+package pkg
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dto"
+	"errors"
+)
+
+// Error is an error of the application, which is answered with the HTTP status of its key.
+// Handlers record it with Abort and return, the ErrorHandler middleware renders it.
+type Error struct {
+	Key constant.ResponseStatus
+	// Machine-readable, the code of the key unless a more specific one is set
+	Code    string
+	Message string
+	// Problems of single fields, e.g. of a failed validation
+	Details []dto.ErrorDetail
+	// The cause is logged, but never sent to the client
+	Err error
+}
+
+func NewError(key constant.ResponseStatus) *Error {
+	/* Creates an error with the code, the status and the default message of the key */
+	return &Error{
+		Key:     key,
+		Code:    key.GetErrorCode(),
+		Message: key.GetResponseMessage(),
+	}
+}
+
+func (e *Error) WithMessage(message string) *Error {
+	e.Message = message
+	return e
+}
+
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+func (e *Error) WithDetails(details ...dto.ErrorDetail) *Error {
+	e.Details = append(e.Details, details...)
+	return e
+}
+
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func (e *Error) Status() int {
+	return e.Key.GetHTTPStatus()
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Message + ": " + e.Err.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func AsError(err error) *Error {
+	/* Returns the application error in the chain of err, any other error is an unknown error */
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return NewError(constant.UnknownError).Wrap(err)
+}
@@ -0,0 +1,165 @@
+/**
+* This package calls the API of the planner-backend.
+* The departments are synchronized with its internal API: the api-gateway holds the registry of the departments,
+* the planner-backend keeps a copy with the same ids. The persons linked to users are read with its public API,
+* their leave requests are filed with its internal API.
+**/
+package planner
+
+import (
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/middleware"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Returned if the planner-backend does not know the requested resource
+var ErrNotFound = errors.New("not found")
+
+// Responses forwarded to the client are limited to this size
+const maxForwardedBodySize = 10 << 20
+
+type Client interface {
+	GetDepartments() ([]dco.PlannerDepartment, error)
+	// Creates, renames or restores the department with the given id
+	SaveDepartment(department dco.PlannerDepartment) error
+	DeleteDepartment(id string) error
+
+	GetPerson(id string) (dco.PlannerPerson, error)
+	// Returns the planner ids of the departments of a person, none for an unknown person
+	GetPersonDepartments(id string) ([]string, error)
+	// Sends a request with the given identity, returns the status code and the body of the response
+	Forward(identity dco.Identity, method string, path string, query url.Values, body []byte) (int, []byte, error)
+}
+
+type HTTPClient struct {
+	target string
+	client *http.Client
+}
+
+func NewClient(target string) *HTTPClient {
+	return &HTTPClient{
+		target: strings.TrimSuffix(target, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func NewClientForTarget(target string) Client {
+	/**
+	* Creates the client of the planner-backend in PLANNER_BACKEND_TARGET.
+	* Without a target the departments are not synchronized and the persons linked to users are not read.
+	**/
+	if target == "" {
+		slog.Info("PLANNER_BACKEND_TARGET is not set, departments are not synchronized and linked persons are not read")
+		return nil
+	}
+	return NewClient(target)
+}
+
+func (p *HTTPClient) GetDepartments() ([]dco.PlannerDepartment, error) {
+	var response struct {
+		Data []dco.PlannerDepartment `json:"data"`
+	}
+	if err := p.do(http.MethodGet, "/internal/department", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+func (p *HTTPClient) SaveDepartment(department dco.PlannerDepartment) error {
+	body, err := json.Marshal(map[string]string{"name": department.Name})
+	if err != nil {
+		return err
+	}
+	return p.do(http.MethodPut, "/internal/department/"+department.ID, body, nil)
+}
+
+func (p *HTTPClient) DeleteDepartment(id string) error {
+	return p.do(http.MethodDelete, "/internal/department/"+id, nil, nil)
+}
+
+func (p *HTTPClient) GetPerson(id string) (dco.PlannerPerson, error) {
+	var response struct {
+		Data dco.PlannerPerson `json:"data"`
+	}
+	if err := p.do(http.MethodGet, "/api/v1/planner/person/"+id, nil, &response); err != nil {
+		return dco.PlannerPerson{}, err
+	}
+	return response.Data, nil
+}
+
+func (p *HTTPClient) GetPersonDepartments(id string) ([]string, error) {
+	var response struct {
+		Data struct {
+			Departments []dco.PlannerDepartment `json:"departments"`
+		} `json:"data"`
+	}
+	err := p.do(http.MethodGet, "/api/v1/planner/person/"+id, nil, &response)
+	if errors.Is(err, ErrNotFound) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	departments := []string{}
+	for _, department := range response.Data.Departments {
+		departments = append(departments, department.ID)
+	}
+	return departments, nil
+}
+
+func (p *HTTPClient) Forward(identity dco.Identity, method string, path string, query url.Values, body []byte) (int, []byte, error) {
+	resp, err := p.send(method, path, query, body, identity)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxForwardedBodySize))
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, responseBody, nil
+}
+
+func (p *HTTPClient) do(method string, path string, body []byte, result interface{}) error {
+	/* Sends a request signed with the identity of the api-gateway and decodes the response into result */
+	resp, err := p.send(method, path, nil, body, dco.Identity{Username: dco.GatewayServiceName, IsAdmin: true})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, path, ErrNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: unexpected status code %d", method, path, resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (p *HTTPClient) send(method string, path string, query url.Values, body []byte, identity dco.Identity) (*http.Response, error) {
+	/* Sends a request signed with the given identity */
+	target := &url.URL{Path: path, RawQuery: query.Encode()}
+	req, err := http.NewRequest(method, p.target+target.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	middleware.SetIdentityHeaders(req.Header, identity, method, path, target.RawQuery)
+
+	return p.client.Do(req)
+}
@@ -0,0 +1,120 @@
+package planner
+
+import (
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	/* Test that the departments are synchronized signed with the identity of the api-gateway */
+	dco.IdentitySigningKey = []byte("secret")
+
+	type request struct {
+		method string
+		path   string
+		name   string
+	}
+	requests := []request{}
+	status := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := middleware.VerifyIdentity(r.Header, r.Method, r.URL.Path, r.URL.RawQuery)
+		if err != nil || identity.Username != dco.GatewayServiceName {
+			t.Errorf("Expected a signed request of the api-gateway, got %v", err)
+		}
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, request{method: r.Method, path: r.URL.Path, name: body["name"]})
+
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []dco.PlannerDepartment{{ID: "department1", Name: "Department 1"}}})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL + "/")
+	departments, err := client.GetDepartments()
+	if err != nil || len(departments) != 1 || departments[0] != (dco.PlannerDepartment{ID: "department1", Name: "Department 1"}) {
+		t.Errorf("Expected the departments of the planner-backend, got %v and %v", departments, err)
+	}
+	if err := client.SaveDepartment(dco.PlannerDepartment{ID: "department 1", Name: "Department 1"}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := client.DeleteDepartment("department 1"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	expected := []request{
+		{method: http.MethodGet, path: "/internal/department"},
+		{method: http.MethodPut, path: "/internal/department/department 1", name: "Department 1"},
+		{method: http.MethodDelete, path: "/internal/department/department 1"},
+	}
+	if len(requests) != len(expected) || requests[0] != expected[0] || requests[1] != expected[1] || requests[2] != expected[2] {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+
+	status = http.StatusUnauthorized
+	if err := client.DeleteDepartment("department1"); err == nil {
+		t.Errorf("Expected an error for a rejected request")
+	}
+}
+
+func TestClientPerson(t *testing.T) {
+	/* Test that persons are read as api-gateway and the requests of users are forwarded with their identity */
+	dco.IdentitySigningKey = []byte("secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := middleware.VerifyIdentity(r.Header, r.Method, r.URL.Path, r.URL.RawQuery)
+		if err != nil {
+			t.Errorf("Expected a signed request, got %v", err)
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/planner/person/p1":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"id": "p1", "first_name": "Jane",
+				"departments": []dco.PlannerDepartment{{ID: "planner1", Name: "Department 1"}},
+			}})
+		case "/api/v1/planner/person/p1/workday":
+			if identity.Username != "jane" || r.URL.Query().Get("start_date") != "2024-01-01" {
+				t.Errorf("Expected the identity and the query of the user, got %v and %v", identity, r.URL.RawQuery)
+			}
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte(`{"data":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	person, err := client.GetPerson("p1")
+	if err != nil || person != (dco.PlannerPerson{ID: "p1", FirstName: "Jane"}) {
+		t.Errorf("Expected the person of the planner-backend, got %v and %v", person, err)
+	}
+	if _, err := client.GetPerson("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	departments, err := client.GetPersonDepartments("p1")
+	if err != nil || !reflect.DeepEqual(departments, []string{"planner1"}) {
+		t.Errorf("Expected the departments of the person, got %v and %v", departments, err)
+	}
+	if departments, err := client.GetPersonDepartments("unknown"); err != nil || len(departments) != 0 {
+		t.Errorf("Expected no departments of an unknown person, got %v and %v", departments, err)
+	}
+
+	status, body, err := client.Forward(dco.Identity{Username: "jane"}, http.MethodGet, "/api/v1/planner/person/p1/workday", url.Values{"start_date": {"2024-01-01"}}, nil)
+	if err != nil || status != http.StatusTeapot || string(body) != `{"data":[]}` {
+		t.Errorf("Expected the response of the planner-backend, got %d %s and %v", status, body, err)
+	}
+}
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"time"
+)
+
+type LoginPolicy struct {
+	// Failed attempts of a username until the username is locked
+	MaxFailures int
+	// Failed attempts of a client IP until the IP is locked, an IP may be shared by several users
+	MaxFailuresPerIP int
+	// Failures older than the window are forgotten
+	FailureWindow   time.Duration
+	LockoutDuration time.Duration
+	// The delay between two attempts doubles with every failure, up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultLoginPolicy is used for every setting that is not configured
+var DefaultLoginPolicy = LoginPolicy{
+	MaxFailures:      5,
+	MaxFailuresPerIP: 20,
+	FailureWindow:    15 * time.Minute,
+	LockoutDuration:  15 * time.Minute,
+	BaseDelay:        time.Second,
+	MaxDelay:         30 * time.Second,
+}
+
+func (p LoginPolicy) Delay(failures int) time.Duration {
+	/**
+	* Returns the time a client has to wait after the given number of consecutive failures
+	* @param failures: The number of failed attempts
+	* @return: The delay until the next attempt is accepted
+	**/
+	if failures <= 0 || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return min(delay, p.MaxDelay)
+}
@@ -0,0 +1,25 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginPolicyDelay(t *testing.T) {
+	loginPolicy := LoginPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	testSteps := map[int]time.Duration{
+		0:   0,
+		1:   time.Second,
+		2:   2 * time.Second,
+		4:   8 * time.Second,
+		5:   10 * time.Second,
+		100: 10 * time.Second,
+	}
+
+	for failures, expected := range testSteps {
+		if delay := loginPolicy.Delay(failures); delay != expected {
+			t.Errorf("Failures %d: expected %v, got %v", failures, expected, delay)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package policy
+
+import (
+	"testing"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	type passwordCheckTest struct {
+		policy     PasswordPolicy
+		password   string
+		violations int
+	}
+
+	testSteps := []passwordCheckTest{
+		{DefaultPasswordPolicy, "CorrectHorse42", 0},
+		{DefaultPasswordPolicy, "admin", 3},
+		{DefaultPasswordPolicy, "correcthorse42", 1},
+		{DefaultPasswordPolicy, "CORRECTHORSE42", 1},
+		{DefaultPasswordPolicy, "CorrectHorseBattery", 1},
+		{PasswordPolicy{MinLength: 4, RequireSymbol: true}, "pass", 1},
+		{PasswordPolicy{MinLength: 4, RequireSymbol: true}, "pa$s", 0},
+		{PasswordPolicy{MinLength: 4}, "äöüß", 0},
+	}
+
+	for i, testStep := range testSteps {
+		if violations := testStep.policy.Check(testStep.password); len(violations) != testStep.violations {
+			t.Errorf("Step %d: expected %d violations, got %v", i, testStep.violations, violations)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+/**
+* This package evaluates the access policy for requests proxied to the planner-backend.
+* A policy is a list of rules mapping a method and a path pattern to the permissions
+* a user must hold. Rules can be overridden per department of the user.
+* Requests acting on departments are only allowed for members, with their permissions within the department.
+**/
+package policy
+
+import (
+	"api-gateway/app/domain/dco"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type Rule struct {
+	// HTTP method of the request, "*" matches every method
+	Method string `json:"method"`
+	// Path pattern: "*" matches exactly one segment, a trailing "*" matches the rest of the path
+	Path string `json:"path"`
+	// Public rules can be accessed without authentication
+	Public bool `json:"public"`
+	// Names of the permissions a user must hold
+	Permissions []string `json:"permissions"`
+}
+
+type Policy struct {
+	Rules []Rule `json:"rules"`
+	// Department specific rules, keyed by the department of the user. They are evaluated before the global rules
+	Departments map[string][]Rule `json:"departments"`
+}
+
+type Decision struct {
+	Allowed bool
+	// Status is the HTTP status to respond with if the request is denied
+	Status             int
+	MissingPermissions []string
+	// Departments of the request the user does not belong to
+	ForeignDepartments []string
+}
+
+// DefaultPolicy is the policy of config/planner_policy.example.json without its department specific rules.
+// GET requests stay open except for the leave requests of a department and the plan of a person,
+// changes require the permission of the resource. Requests without a matching rule are denied.
+var DefaultPolicy = Policy{
+	Rules: []Rule{
+		{Method: "*", Path: "/api/v1/planner/department/*/leave-request/*", Permissions: []string{"absency:write"}},
+		{Method: http.MethodGet, Path: "/api/v1/planner/person/*/workday", Permissions: []string{}},
+		{Method: http.MethodGet, Path: "/api/v1/planner/person/*/hours", Permissions: []string{}},
+		{Method: http.MethodGet, Path: "/api/v1/planner/*", Public: true},
+
+		{Method: "*", Path: "/api/v1/planner/department/*", Permissions: []string{"department:write"}},
+		{Method: "*", Path: "/api/v1/planner/person/*/absency/*", Permissions: []string{"absency:write"}},
+		{Method: "*", Path: "/api/v1/planner/person/*", Permissions: []string{"person:write"}},
+		{Method: http.MethodPut, Path: "/api/v1/planner/workday/*", Permissions: []string{"workday:write"}},
+		{Method: "*", Path: "/api/v1/planner/workday/assign", Permissions: []string{"workday:assign"}},
+	},
+}
+
+func Authenticated(prefix string) *Policy {
+	/* Creates a policy that requires an authenticated user for every request below the prefix */
+	return &Policy{
+		Rules: []Rule{
+			{Method: "*", Path: strings.TrimSuffix(prefix, "/") + "/*", Permissions: []string{}},
+		},
+	}
+}
+
+func Load(path string) (*Policy, error) {
+	/**
+	* Loads and validates a policy from a JSON file
+	* @param path: The path of the file
+	* @return: The loaded policy
+	**/
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(file, &policy); err != nil {
+		return nil, err
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+func LoadOrDefault(path string) (*Policy, error) {
+	/**
+	* Loads the policy from a file, see Load
+	* @param path: The path of the file, the DefaultPolicy is used if it is empty
+	* @return: The loaded policy
+	**/
+	if path == "" {
+		slog.Info("No policy file is set, using the default policy")
+		policy := DefaultPolicy
+		return &policy, nil
+	}
+
+	policy, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Loaded policy", "path", path, "rules", len(policy.Rules))
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	/* Checks that every rule has a method and a path */
+	validate := func(rules []Rule) error {
+		for i, rule := range rules {
+			if rule.Method == "" || rule.Path == "" {
+				return fmt.Errorf("rule %d: method and path are required", i)
+			}
+			if !strings.HasPrefix(rule.Path, "/") {
+				return fmt.Errorf("rule %d: path must start with /", i)
+			}
+		}
+		return nil
+	}
+
+	if err := validate(p.Rules); err != nil {
+		return err
+	}
+	for department, rules := range p.Departments {
+		if err := validate(rules); err != nil {
+			return errors.Join(fmt.Errorf("department %s", department), err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Policy) Evaluate(claim *dco.JWTClaim, method string, path string, departments ...string) Decision {
+	/**
+	* Evaluates the policy for a request. The first matching rule decides.
+	* Requests without a matching rule are denied.
+	* @param claim: The claim of the user, nil for anonymous requests
+	* @param method: The HTTP method of the request
+	* @param path: The URL path of the request
+	* @param departments: The planner ids of the departments the request acts on
+	* @return: The decision
+	**/
+	// the rules and permissions of the department the request acts on apply
+	claims := []*dco.JWTClaim{claim}
+	foreign := []string{}
+	if claim != nil && len(departments) > 0 {
+		claims = []*dco.JWTClaim{}
+		for _, department := range departments {
+			scoped, member := claim.InDepartment(department)
+			if !member {
+				foreign = append(foreign, department)
+			}
+			claims = append(claims, scoped)
+		}
+	}
+
+	rule, found := p.match(claims[0], method, path)
+	if !found {
+		return Decision{Allowed: false, Status: http.StatusForbidden}
+	}
+
+	if rule.Public {
+		return Decision{Allowed: true}
+	}
+
+	if claim == nil {
+		return Decision{Allowed: false, Status: http.StatusUnauthorized}
+	}
+
+	if len(foreign) > 0 {
+		return Decision{Allowed: false, Status: http.StatusForbidden, ForeignDepartments: foreign}
+	}
+
+	missing := []string{}
+	for _, permission := range rule.Permissions {
+		for _, scoped := range claims {
+			if !scoped.HasPermission(permission) {
+				missing = append(missing, permission)
+				break
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return Decision{Allowed: false, Status: http.StatusForbidden, MissingPermissions: missing}
+	}
+
+	return Decision{Allowed: true}
+}
+
+func (p *Policy) match(claim *dco.JWTClaim, method string, path string) (Rule, bool) {
+	/* Returns the first rule matching the request, department rules first */
+	if claim != nil {
+		for _, rule := range p.Departments[claim.Department] {
+			if rule.Matches(method, path) {
+				return rule, true
+			}
+		}
+	}
+
+	for _, rule := range p.Rules {
+		if rule.Matches(method, path) {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+func (r Rule) Matches(method string, path string) bool {
+	/* Checks if the rule matches a method and a path */
+	if r.Method != "*" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+
+	patternSegments := strings.Split(strings.Trim(r.Path, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, segment := range patternSegments {
+		// a trailing wildcard matches the rest of the path, including nothing
+		if segment == "*" && i == len(patternSegments)-1 {
+			return len(pathSegments) >= i
+		}
+
+		if i >= len(pathSegments) {
+			return false
+		}
+
+		if segment != "*" && segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return len(patternSegments) == len(pathSegments)
+}
@@ -0,0 +1,291 @@
+package policy
+
+import (
+	"api-gateway/app/domain/dco"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRuleMatches(t *testing.T) {
+	type ruleMatchesTest struct {
+		rule     Rule
+		method   string
+		path     string
+		expected bool
+	}
+
+	testSteps := []ruleMatchesTest{
+		{Rule{Method: "PUT", Path: "/api/v1/planner/workday/*"}, "PUT", "/api/v1/planner/workday/", true},
+		{Rule{Method: "PUT", Path: "/api/v1/planner/workday/*"}, "PUT", "/api/v1/planner/workday/assign", true},
+		{Rule{Method: "PUT", Path: "/api/v1/planner/workday/*"}, "POST", "/api/v1/planner/workday/assign", false},
+		{Rule{Method: "*", Path: "/api/v1/planner/workday/*"}, "DELETE", "/api/v1/planner/workday/assign", true},
+		{Rule{Method: "post", Path: "/api/v1/planner/person/*/absency"}, "POST", "/api/v1/planner/person/1/absency", true},
+		{Rule{Method: "POST", Path: "/api/v1/planner/person/*/absency"}, "POST", "/api/v1/planner/person/1/absency/2024-01-01", false},
+		{Rule{Method: "POST", Path: "/api/v1/planner/person"}, "POST", "/api/v1/planner/person/", true},
+		{Rule{Method: "POST", Path: "/api/v1/planner/person"}, "POST", "/api/v1/planner/department/", false},
+	}
+
+	for i, testStep := range testSteps {
+		if actual := testStep.rule.Matches(testStep.method, testStep.path); actual != testStep.expected {
+			t.Errorf("Step %d: expected %v, got %v", i, testStep.expected, actual)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	policy := Policy{
+		Rules: []Rule{
+			{Method: "GET", Path: "/api/v1/planner/*", Public: true},
+			{Method: "PUT", Path: "/api/v1/planner/workday/*", Permissions: []string{"workday:write"}},
+			{Method: "*", Path: "/api/v1/planner/workday/assign", Permissions: []string{"workday:assign"}},
+		},
+		Departments: map[string][]Rule{
+			"department1": {
+				{Method: "*", Path: "/api/v1/planner/workday/assign", Permissions: []string{"workday:write", "workday:assign"}},
+			},
+		},
+	}
+
+	type evaluateTest struct {
+		claim    *dco.JWTClaim
+		method   string
+		path     string
+		expected Decision
+	}
+
+	testSteps := []evaluateTest{
+		// public rules
+		{nil, "GET", "/api/v1/planner/workday/", Decision{Allowed: true}},
+		// unauthenticated
+		{nil, "PUT", "/api/v1/planner/workday/", Decision{Allowed: false, Status: http.StatusUnauthorized}},
+		// missing permission
+		{&dco.JWTClaim{Permissions: []string{}}, "PUT", "/api/v1/planner/workday/", Decision{Allowed: false, Status: http.StatusForbidden, MissingPermissions: []string{"workday:write"}}},
+		// granted permission
+		{&dco.JWTClaim{Permissions: []string{"workday:write"}}, "PUT", "/api/v1/planner/workday/", Decision{Allowed: true}},
+		// admins bypass the permissions
+		{&dco.JWTClaim{IsAdmin: true}, "PUT", "/api/v1/planner/workday/", Decision{Allowed: true}},
+		// department rules take precedence
+		{&dco.JWTClaim{Department: "department2", Permissions: []string{"workday:assign"}}, "POST", "/api/v1/planner/workday/assign", Decision{Allowed: true}},
+		{&dco.JWTClaim{Department: "department1", Permissions: []string{"workday:assign"}}, "POST", "/api/v1/planner/workday/assign", Decision{Allowed: false, Status: http.StatusForbidden, MissingPermissions: []string{"workday:write"}}},
+		// no rule matches
+		{&dco.JWTClaim{IsAdmin: true}, "DELETE", "/api/v1/planner/person/1", Decision{Allowed: false, Status: http.StatusForbidden}},
+	}
+
+	for i, testStep := range testSteps {
+		actual := policy.Evaluate(testStep.claim, testStep.method, testStep.path)
+		if !reflect.DeepEqual(actual, testStep.expected) {
+			t.Errorf("Step %d: expected %+v, got %+v", i, testStep.expected, actual)
+		}
+	}
+}
+
+func TestEvaluateDepartments(t *testing.T) {
+	/* Requests acting on departments are evaluated with the permissions of the user within them */
+	policy := Policy{
+		Rules: []Rule{
+			{Method: "GET", Path: "/api/v1/planner/*", Public: true},
+			{Method: "PUT", Path: "/api/v1/planner/workday/*", Permissions: []string{"workday:write"}},
+		},
+		Departments: map[string][]Rule{
+			"gateway2": {
+				{Method: "PUT", Path: "/api/v1/planner/workday/*", Permissions: []string{"workday:write", "workday:assign"}},
+			},
+		},
+	}
+
+	planner := &dco.JWTClaim{
+		Department:          "gateway1",
+		PlannerDepartmentID: "planner1",
+		Permissions:         []string{"workday:write"},
+		Memberships: []dco.DepartmentMembershipClaim{
+			{Department: "gateway1", PlannerDepartmentID: "planner1", Permissions: []string{"workday:write"}},
+			{Department: "gateway2", PlannerDepartmentID: "planner2", Permissions: []string{"workday:write", "workday:assign"}},
+			{Department: "gateway3", PlannerDepartmentID: "planner3", Permissions: []string{}},
+		},
+	}
+	// a token issued before the memberships
+	legacy := &dco.JWTClaim{Department: "gateway1", PlannerDepartmentID: "planner1", Permissions: []string{"workday:write"}}
+
+	type evaluateDepartmentsTest struct {
+		name        string
+		claim       *dco.JWTClaim
+		method      string
+		departments []string
+		expected    Decision
+	}
+
+	testSteps := []evaluateDepartmentsTest{
+		{name: "department of the user", claim: planner, method: "PUT", departments: []string{"planner1"}, expected: Decision{Allowed: true}},
+		{name: "further department with department rules", claim: planner, method: "PUT", departments: []string{"planner2"}, expected: Decision{Allowed: true}},
+		{name: "membership without permission", claim: planner, method: "PUT", departments: []string{"planner3"}, expected: Decision{Allowed: false, Status: http.StatusForbidden, MissingPermissions: []string{"workday:write"}}},
+		{name: "foreign department", claim: planner, method: "PUT", departments: []string{"planner4"}, expected: Decision{Allowed: false, Status: http.StatusForbidden, ForeignDepartments: []string{"planner4"}}},
+		{name: "one of several departments is foreign", claim: planner, method: "PUT", departments: []string{"planner1", "planner4"}, expected: Decision{Allowed: false, Status: http.StatusForbidden, ForeignDepartments: []string{"planner4"}}},
+		{name: "public rule of a foreign department", claim: planner, method: "GET", departments: []string{"planner4"}, expected: Decision{Allowed: true}},
+		{name: "admin acts in every department", claim: &dco.JWTClaim{IsAdmin: true}, method: "PUT", departments: []string{"planner4"}, expected: Decision{Allowed: true}},
+		{name: "legacy token in the department of the user", claim: legacy, method: "PUT", departments: []string{"planner1"}, expected: Decision{Allowed: true}},
+		{name: "legacy token in a foreign department", claim: legacy, method: "PUT", departments: []string{"planner2"}, expected: Decision{Allowed: false, Status: http.StatusForbidden, ForeignDepartments: []string{"planner2"}}},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			actual := policy.Evaluate(testStep.claim, testStep.method, "/api/v1/planner/workday/", testStep.departments...)
+			if !reflect.DeepEqual(actual, testStep.expected) {
+				t.Errorf("Expected %+v, got %+v", testStep.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvaluatePersonDepartments(t *testing.T) {
+	/* Routes of a person name no department, they are evaluated in the departments of the person */
+	policy := Policy{Rules: []Rule{
+		{Method: "GET", Path: "/api/v1/planner/*", Public: true},
+		{Method: "*", Path: "/api/v1/planner/person/*", Permissions: []string{"person:write"}},
+	}}
+	planner := &dco.JWTClaim{
+		Department:          "gateway1",
+		PlannerDepartmentID: "planner1",
+		Permissions:         []string{"person:write"},
+		Memberships: []dco.DepartmentMembershipClaim{
+			{Department: "gateway1", PlannerDepartmentID: "planner1", Permissions: []string{"person:write"}},
+		},
+	}
+
+	// the departments of the person, as found by the RoutePolicy middleware
+	if decision := policy.Evaluate(planner, "PUT", "/api/v1/planner/person/1", "planner1"); !decision.Allowed {
+		t.Errorf("Expected a person of the department to be allowed, got %+v", decision)
+	}
+	expected := Decision{Allowed: false, Status: http.StatusForbidden, ForeignDepartments: []string{"planner2"}}
+	if decision := policy.Evaluate(planner, "DELETE", "/api/v1/planner/person/2", "planner2"); !reflect.DeepEqual(decision, expected) {
+		t.Errorf("Expected a person of a foreign department to be denied with %+v, got %+v", expected, decision)
+	}
+	if decision := policy.Evaluate(planner, "POST", "/api/v1/planner/person/2/absency", "planner2"); decision.Allowed {
+		t.Errorf("Expected absences of a person of a foreign department to be denied, got %+v", decision)
+	}
+}
+
+func TestDefaultPolicyLeaveRequests(t *testing.T) {
+	/* The leave requests of a department are only visible to its members holding absency:write */
+	member := &dco.JWTClaim{Department: "gateway1", PlannerDepartmentID: "planner1", Permissions: []string{"absency:write"}}
+	viewer := &dco.JWTClaim{Department: "gateway1", PlannerDepartmentID: "planner1"}
+	path := "/api/v1/planner/department/planner1/leave-request/"
+
+	if decision := DefaultPolicy.Evaluate(nil, "GET", path, "planner1"); decision.Allowed {
+		t.Errorf("Expected anonymous requests to be denied, got %+v", decision)
+	}
+	if decision := DefaultPolicy.Evaluate(member, "GET", path, "planner1"); !decision.Allowed {
+		t.Errorf("Expected members to be allowed, got %+v", decision)
+	}
+	if decision := DefaultPolicy.Evaluate(viewer, "POST", "/api/v1/planner/department/planner1/leave-request/1/approve", "planner1"); decision.Allowed {
+		t.Errorf("Expected members without absency:write to be denied, got %+v", decision)
+	}
+	if decision := DefaultPolicy.Evaluate(member, "POST", "/api/v1/planner/department/planner2/leave-request/1/approve", "planner2"); decision.Allowed {
+		t.Errorf("Expected foreign departments to be denied, got %+v", decision)
+	}
+	if decision := DefaultPolicy.Evaluate(nil, "GET", "/api/v1/planner/department/planner1/workplace/", "planner1"); !decision.Allowed {
+		t.Errorf("Expected other GET requests to stay public, got %+v", decision)
+	}
+}
+
+func TestDefaultPolicyChanges(t *testing.T) {
+	/* Changes of the planner require the permission of the resource */
+	viewer := &dco.JWTClaim{Department: "gateway1", PlannerDepartmentID: "planner1"}
+	planner := &dco.JWTClaim{Department: "gateway1", PlannerDepartmentID: "planner1", Permissions: []string{"person:write", "workday:write", "workday:assign"}}
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{"PUT", "/api/v1/planner/person/1"},
+		{"PUT", "/api/v1/planner/workday/"},
+		{"POST", "/api/v1/planner/workday/assign"},
+		{"DELETE", "/api/v1/planner/workday/assign"},
+	}
+	for _, request := range requests {
+		if decision := DefaultPolicy.Evaluate(viewer, request.method, request.path, "planner1"); decision.Allowed {
+			t.Errorf("Expected %s %s to be denied without permission, got %+v", request.method, request.path, decision)
+		}
+		if decision := DefaultPolicy.Evaluate(planner, request.method, request.path, "planner1"); !decision.Allowed {
+			t.Errorf("Expected %s %s to be allowed with permission, got %+v", request.method, request.path, decision)
+		}
+	}
+
+	// routes without a rule are denied
+	if decision := DefaultPolicy.Evaluate(planner, "POST", "/api/v1/planner/unknown", "planner1"); decision.Allowed {
+		t.Errorf("Expected requests without a rule to be denied, got %+v", decision)
+	}
+}
+
+func TestDefaultPolicyMatchesExample(t *testing.T) {
+	/* The default policy is the example policy without its department specific rules */
+	example, err := Load("../../config/planner_policy.example.json")
+	if err != nil {
+		t.Fatalf("Expected the example policy to be valid, got %v", err)
+	}
+	if !reflect.DeepEqual(example.Rules, DefaultPolicy.Rules) {
+		t.Errorf("Expected the default rules to match the example, got %+v", DefaultPolicy.Rules)
+	}
+}
+
+func TestDefaultPolicyPersonPlan(t *testing.T) {
+	/* The workdays and hours of a person are not public, the planner-backend checks the departments of the person */
+	user := &dco.JWTClaim{Department: "gateway1", PlannerDepartmentID: "planner1"}
+
+	for _, path := range []string{"/api/v1/planner/person/1/workday", "/api/v1/planner/person/1/hours"} {
+		if decision := DefaultPolicy.Evaluate(nil, "GET", path); decision.Allowed || decision.Status != http.StatusUnauthorized {
+			t.Errorf("Expected anonymous requests of %s to be denied, got %+v", path, decision)
+		}
+		if decision := DefaultPolicy.Evaluate(user, "GET", path); !decision.Allowed {
+			t.Errorf("Expected authenticated requests of %s to be allowed, got %+v", path, decision)
+		}
+	}
+	if decision := DefaultPolicy.Evaluate(nil, "GET", "/api/v1/planner/person/1/absency"); !decision.Allowed {
+		t.Errorf("Expected the absences of a person to stay public, got %+v", decision)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	os.WriteFile(valid, []byte(`{"rules": [{"method": "GET", "path": "/api/v1/planner/*", "public": true}]}`), 0644)
+	policy, err := Load(valid)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(policy.Rules) != 1 {
+		t.Errorf("Expected 1 rule, got %d", len(policy.Rules))
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte(`{"rules": [{"method": "GET"}]}`), 0644)
+	if _, err := Load(invalid); err == nil {
+		t.Errorf("Expected an error for a rule without path")
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+
+	// the example shipped with the repository must stay valid
+	if _, err := Load("../../config/planner_policy.example.json"); err != nil {
+		t.Errorf("Expected the example policy to be valid, got %v", err)
+	}
+}
+
+func TestLoadOrDefault(t *testing.T) {
+	policy, err := LoadOrDefault("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(*policy, DefaultPolicy) {
+		t.Errorf("Expected the default policy, got %+v", policy)
+	}
+
+	if _, err := LoadOrDefault(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("Expected an error for a missing file")
+	}
+}
@@ -0,0 +1,90 @@
+/* Here there are the providers of the components configured by the config of the gateway */
+package app
+
+import (
+	"api-gateway/app/authprovider"
+	"api-gateway/app/mailer"
+	"api-gateway/app/planner"
+	"api-gateway/app/policy"
+	"api-gateway/app/proxy"
+	"api-gateway/app/ratelimit"
+	"api-gateway/app/repository"
+	"api-gateway/config"
+)
+
+func newPlannerClient(c config.Config) planner.Client {
+	return planner.NewClientForTarget(c.PlannerBackendTarget)
+}
+
+func newRouteTable(c config.Config) (*proxy.Table, error) {
+	plannerPolicy, err := policy.LoadOrDefault(c.Proxy.PlannerPolicyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyConfig := proxy.DefaultConfig
+	proxyConfig.DialTimeout = c.Proxy.DialTimeout
+	proxyConfig.ResponseTimeout = c.Proxy.ResponseTimeout
+	proxyConfig.Retries = c.Proxy.Retries
+	proxyConfig.FailureThreshold = c.Proxy.FailureThreshold
+	proxyConfig.OpenDuration = c.Proxy.OpenDuration
+	proxyConfig.HealthInterval = c.Proxy.HealthInterval
+
+	return proxy.OpenTable(proxyConfig, proxy.Source{
+		File:           c.Proxy.RoutesFile,
+		ReloadInterval: c.Proxy.RoutesReloadInterval,
+		PlannerTarget:  c.PlannerBackendTarget,
+		PlannerPolicy:  plannerPolicy,
+	})
+}
+
+func newRateLimiter(c config.Config) (*ratelimit.Limiter, error) {
+	limits := map[ratelimit.Group]ratelimit.Limit{}
+	values := map[ratelimit.Group]string{
+		ratelimit.Auth:  c.RateLimit.Auth,
+		ratelimit.Read:  c.RateLimit.Read,
+		ratelimit.Write: c.RateLimit.Write,
+	}
+	for group, value := range values {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			return nil, err
+		}
+		limits[group] = limit
+	}
+	return ratelimit.NewMemoryLimiter(limits), nil
+}
+
+func newMailer(c config.Config) mailer.Mailer {
+	return mailer.NewMailer(mailer.SMTPMailer{
+		Addr:     c.SMTP.Addr,
+		From:     c.SMTP.From,
+		Username: c.SMTP.Username,
+		Password: c.SMTP.Password,
+	})
+}
+
+func newPasswordPolicy(c config.Config) policy.PasswordPolicy {
+	return policy.PasswordPolicy{
+		MinLength:        c.PasswordPolicy.MinLength,
+		RequireUppercase: c.PasswordPolicy.RequireUppercase,
+		RequireLowercase: c.PasswordPolicy.RequireLowercase,
+		RequireDigit:     c.PasswordPolicy.RequireDigit,
+		RequireSymbol:    c.PasswordPolicy.RequireSymbol,
+	}
+}
+
+func newLoginPolicy(c config.Config) policy.LoginPolicy {
+	return policy.LoginPolicy{
+		MaxFailures:      c.LoginPolicy.MaxFailures,
+		MaxFailuresPerIP: c.LoginPolicy.MaxFailuresPerIP,
+		FailureWindow:    c.LoginPolicy.FailureWindow,
+		LockoutDuration:  c.LoginPolicy.LockoutDuration,
+		BaseDelay:        c.LoginPolicy.BaseDelay,
+		MaxDelay:         c.LoginPolicy.MaxDelay,
+	}
+}
+
+func newAuthProviders(c config.Config, userRepository repository.UserRepository) (*authprovider.Registry, error) {
+	return authprovider.Load(c.AuthProvidersFile, userRepository)
+}
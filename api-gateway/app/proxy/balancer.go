@@ -0,0 +1,135 @@
+package proxy
+
+import (
+	"api-gateway/app/metrics"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// upstream is a single instance of the service behind a route
+type upstream struct {
+	target  *url.URL
+	breaker *Breaker
+	// name of the route, the attempts are reported to /metrics with it
+	route string
+	// requests in flight, used by the least-connections balancer
+	active atomic.Int64
+
+	mu        sync.Mutex
+	healthy   bool
+	lastCheck *time.Time
+	lastError string
+}
+
+func (u *upstream) rewrite(req *http.Request, path string) {
+	/* Directs the request to the upstream, the path is appended to the path of the upstream */
+	req.URL.Scheme = u.target.Scheme
+	req.URL.Host = u.target.Host
+	req.URL.Path = strings.TrimSuffix(u.target.Path, "/") + path
+	req.URL.RawPath = ""
+	req.Host = ""
+}
+
+func (u *upstream) send(base http.RoundTripper, req *http.Request) (*http.Response, error) {
+	/* Sends a single attempt, its outcome drives the circuit breaker of the upstream and is reported to /metrics */
+	u.active.Add(1)
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		u.active.Add(-1)
+		// the client went away, the upstream is not to blame
+		if req.Context().Err() == nil {
+			u.breaker.Failure()
+			metrics.ProxyUpstreamDuration.WithLabelValues(u.route, u.target.String(), metrics.StatusError).Observe(time.Since(start).Seconds())
+			metrics.ProxyUpstreamErrors.WithLabelValues(u.route, u.target.String(), metrics.UpstreamTransport).Inc()
+		}
+		return nil, err
+	}
+	metrics.ProxyUpstreamDuration.WithLabelValues(u.route, u.target.String(), strconv.Itoa(resp.StatusCode)).Observe(time.Since(start).Seconds())
+
+	if isUnavailable(resp.StatusCode) {
+		u.breaker.Failure()
+		metrics.ProxyUpstreamErrors.WithLabelValues(u.route, u.target.String(), metrics.UpstreamUnavailable).Inc()
+	} else {
+		u.breaker.Success()
+	}
+
+	// the connection is active until the response is passed on
+	resp.Body = &trackedBody{ReadCloser: resp.Body, upstream: u}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	upstream *upstream
+	once     sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(func() { b.upstream.active.Add(-1) })
+	return b.ReadCloser.Close()
+}
+
+// balancer orders the upstreams of a route by preference for the next request
+type balancer interface {
+	order(upstreams []*upstream) []*upstream
+}
+
+func newBalancer(strategy string) balancer {
+	if strategy == LeastConnections {
+		return &leastConnections{}
+	}
+	return &roundRobin{}
+}
+
+type roundRobin struct {
+	counter atomic.Uint64
+}
+
+func (b *roundRobin) order(upstreams []*upstream) []*upstream {
+	if len(upstreams) == 0 {
+		return upstreams
+	}
+
+	start := int((b.counter.Add(1) - 1) % uint64(len(upstreams)))
+	ordered := make([]*upstream, 0, len(upstreams))
+	ordered = append(ordered, upstreams[start:]...)
+	return append(ordered, upstreams[:start]...)
+}
+
+type leastConnections struct {
+	// upstreams with the same number of connections take turns
+	roundRobin
+}
+
+func (b *leastConnections) order(upstreams []*upstream) []*upstream {
+	ordered := b.roundRobin.order(upstreams)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].active.Load() < ordered[j].active.Load()
+	})
+	return ordered
+}
+
+func pick(b balancer, upstreams []*upstream, failed *upstream) *upstream {
+	/**
+	* Chooses the first upstream in the order of the balancer whose circuit lets the request through.
+	* The upstream of a failed attempt is only chosen again if no other upstream is available.
+	* @return: The upstream, nil if no upstream is available
+	**/
+	for _, u := range b.order(upstreams) {
+		if u != failed && u.breaker.Allow() {
+			return u
+		}
+	}
+	if failed != nil && failed.breaker.Allow() {
+		return failed
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"sync"
+	"time"
+)
+
+// States of the circuit breaker
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// Breaker opens the circuit after consecutive failures of the backend.
+// While open the requests are rejected, after OpenDuration a single request probes the backend:
+// a success closes the circuit, a failure opens it again.
+type Breaker struct {
+	threshold    int
+	openDuration time.Duration
+	now          func() time.Time
+
+	mu           sync.Mutex
+	state        string
+	failures     int
+	openedAt     time.Time
+	probeStarted time.Time
+}
+
+func NewBreaker(threshold int, openDuration time.Duration) *Breaker {
+	return &Breaker{
+		threshold:    threshold,
+		openDuration: openDuration,
+		now:          time.Now,
+		state:        CircuitClosed,
+	}
+}
+
+func (b *Breaker) Allow() bool {
+	/* Checks if a request may be sent to the backend */
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < b.openDuration {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.probeStarted = now
+		return true
+	case CircuitHalfOpen:
+		// a probe that never reported back does not block the circuit forever
+		if now.Sub(b.probeStarted) < b.openDuration {
+			return false
+		}
+		b.probeStarted = now
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.openDuration {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
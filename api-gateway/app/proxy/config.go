@@ -0,0 +1,43 @@
+/**
+* This package forwards requests to the backend services, e.g. the planner-backend.
+* - Routes: A table maps path prefixes to pools of upstreams, it is reloaded without a restart
+* - Balancing: The upstreams of a route are chosen round-robin or by the least active connections
+* - Timeouts: Connecting and waiting for the response headers are limited, a hung backend does not hang the gateway
+* - Retries: Idempotent GET requests are retried on the next upstream if it cannot be reached or answers 502, 503 or 504
+* - Circuit breaker: After too many consecutive failures an upstream is skipped for a while
+* - Health checks: The upstreams are checked periodically, the result is shown by the /ping of the gateway
+* Errors of the proxy are returned in the APIResponse envelope.
+**/
+package proxy
+
+import (
+	"time"
+)
+
+// Config holds the settings shared by all routes
+type Config struct {
+	// Time to establish a connection to the backend
+	DialTimeout time.Duration
+	// Time to wait for the response headers of the backend after the request was sent
+	ResponseTimeout time.Duration
+	// Additional attempts of a failed GET request
+	Retries    int
+	RetryDelay time.Duration
+	// Consecutive failures until the circuit opens
+	FailureThreshold int
+	// Time the circuit stays open until a request is let through again
+	OpenDuration time.Duration
+	// Interval of the health checks
+	HealthInterval time.Duration
+}
+
+// DefaultConfig is used for every setting that is not configured
+var DefaultConfig = Config{
+	DialTimeout:      5 * time.Second,
+	ResponseTimeout:  30 * time.Second,
+	Retries:          2,
+	RetryDelay:       100 * time.Millisecond,
+	FailureThreshold: 5,
+	OpenDuration:     30 * time.Second,
+	HealthInterval:   15 * time.Second,
+}
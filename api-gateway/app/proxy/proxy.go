@@ -0,0 +1,292 @@
+package proxy
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/metrics"
+	"api-gateway/app/pkg"
+	"api-gateway/app/policy"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errNoUpstream = errors.New("no upstream available")
+
+// Route forwards the requests below its prefix to a pool of upstreams
+type Route struct {
+	Name        string
+	Prefix      string
+	StripPrefix bool
+	// Evaluated before a request of the route is forwarded
+	Policy *policy.Policy
+
+	config     Config
+	healthPath string
+	upstreams  []*upstream
+	balancer   balancer
+	reverse    *httputil.ReverseProxy
+	transport  *http.Transport
+	client     *http.Client
+}
+
+// forwarding is the state of a request while it is forwarded, the upstream changes with retries
+type forwarding struct {
+	upstream *upstream
+	path     string
+}
+
+type forwardingKey struct{}
+
+func NewRoute(config Config, route RouteConfig, p *policy.Policy) (*Route, error) {
+	/**
+	* Creates the route with its pool of upstreams
+	* @param config: The timeouts, retries and circuit breaker settings
+	* @param route: The entry of the route table
+	* @param p: The policy of the route
+	**/
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: config.DialTimeout, KeepAlive: 30 * time.Second}).DialContext,
+		TLSHandshakeTimeout:   config.DialTimeout,
+		ResponseHeaderTimeout: config.ResponseTimeout,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   16,
+	}
+
+	r := &Route{
+		Name:        route.Name,
+		Prefix:      strings.TrimSuffix(route.Prefix, "/"),
+		StripPrefix: route.StripPrefix,
+		Policy:      p,
+		config:      config,
+		healthPath:  route.HealthPath,
+		balancer:    newBalancer(route.Balancer),
+		transport:   transport,
+		client:      &http.Client{Transport: transport, Timeout: config.DialTimeout + config.ResponseTimeout},
+	}
+	if r.healthPath == "" {
+		r.healthPath = r.UpstreamPath(r.Prefix + "/ping")
+	}
+
+	for _, target := range route.Upstreams {
+		parsed, err := parseUpstream(target)
+		if err != nil {
+			return nil, err
+		}
+		r.upstreams = append(r.upstreams, &upstream{
+			target:  parsed,
+			breaker: NewBreaker(config.FailureThreshold, config.OpenDuration),
+			route:   r.Name,
+		})
+	}
+
+	r.reverse = &httputil.ReverseProxy{
+		Rewrite:      r.rewrite,
+		Transport:    &retryTransport{route: r, base: transport},
+		ErrorHandler: r.errorHandler,
+	}
+
+	return r, nil
+}
+
+func (r *Route) UpstreamPath(path string) string {
+	/* Returns the path of a request as it is sent to the upstream */
+	if !r.StripPrefix {
+		return path
+	}
+	if stripped := strings.TrimPrefix(path, r.Prefix); stripped != "" {
+		return stripped
+	}
+	return "/"
+}
+
+func (r *Route) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	u := pick(r.balancer, r.upstreams, nil)
+	if u == nil {
+		slog.Warn("No upstream available, request rejected", "route", r.Name, "path", req.URL.Path)
+		metrics.ProxyUpstreamErrors.WithLabelValues(r.Name, "", metrics.UpstreamNone).Inc()
+		pkg.WriteError(w, req, pkg.NewError(constant.ServiceUnavailable).Wrap(errNoUpstream))
+		return
+	}
+
+	ctx := context.WithValue(req.Context(), forwardingKey{}, &forwarding{upstream: u, path: r.UpstreamPath(req.URL.Path)})
+	r.reverse.ServeHTTP(w, req.WithContext(ctx))
+}
+
+func (r *Route) StartHealthChecks(ctx context.Context) {
+	/* Checks the health of the upstreams every HealthInterval until the context is done */
+	if len(r.upstreams) == 0 {
+		return
+	}
+	slog.Info("Initializing health checks", "route", r.Name, "upstreams", len(r.upstreams), "interval", r.config.HealthInterval)
+
+	go func() {
+		r.CheckHealth(ctx)
+		ticker := time.NewTicker(r.config.HealthInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+func (r *Route) CheckHealth(ctx context.Context) {
+	/* Checks the health path of every upstream once, the results also drive the circuit breakers */
+	var wg sync.WaitGroup
+	for _, u := range r.upstreams {
+		wg.Add(1)
+		go func(u *upstream) {
+			defer wg.Done()
+			r.checkUpstream(ctx, u)
+		}(u)
+	}
+	wg.Wait()
+}
+
+func (r *Route) checkUpstream(ctx context.Context, u *upstream) {
+	err := r.ping(ctx, u)
+
+	now := time.Now()
+	u.mu.Lock()
+	u.healthy = err == nil
+	u.lastCheck = &now
+	u.lastError = ""
+	if err != nil {
+		u.lastError = err.Error()
+	}
+	u.mu.Unlock()
+
+	if err != nil {
+		slog.Warn("Health check failed", "route", r.Name, "upstream", u.target.String(), "error", err)
+		u.breaker.Failure()
+		return
+	}
+	u.breaker.Success()
+}
+
+func (r *Route) Health() dco.RouteHealth {
+	/* Returns the health of the upstreams of the route */
+	health := dco.RouteHealth{Name: r.Name, Prefix: r.Prefix, Upstreams: []dco.BackendHealth{}}
+
+	for _, u := range r.upstreams {
+		u.mu.Lock()
+		health.Upstreams = append(health.Upstreams, dco.BackendHealth{
+			Target:    u.target.String(),
+			Healthy:   u.healthy,
+			Circuit:   u.breaker.State(),
+			LastCheck: u.lastCheck,
+			LastError: u.lastError,
+			Active:    u.active.Load(),
+		})
+		u.mu.Unlock()
+	}
+
+	return health
+}
+
+func (r *Route) ping(ctx context.Context, u *upstream) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.target.JoinPath(r.healthPath).String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (r *Route) rewrite(pr *httputil.ProxyRequest) {
+	pr.SetXForwarded()
+	f := pr.In.Context().Value(forwardingKey{}).(*forwarding)
+	f.upstream.rewrite(pr.Out, f.path)
+}
+
+func (r *Route) errorHandler(w http.ResponseWriter, req *http.Request, err error) {
+	/* Answers a failed request in the APIResponse envelope */
+	if errors.Is(err, context.Canceled) && req.Context().Err() != nil {
+		// the client went away, the upstream is not to blame
+		slog.Info("Request canceled by the client", "route", r.Name, "path", req.URL.Path)
+		return
+	}
+
+	slog.Error("Error happened: when forward request", "route", r.Name, "path", req.URL.Path, "error", err)
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errNoUpstream):
+		pkg.WriteError(w, req, pkg.NewError(constant.ServiceUnavailable).Wrap(err))
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		pkg.WriteError(w, req, pkg.NewError(constant.GatewayTimeout).Wrap(err))
+	default:
+		pkg.WriteError(w, req, pkg.NewError(constant.BadGateway).Wrap(err))
+	}
+}
+
+func isUnavailable(code int) bool {
+	return code == http.StatusBadGateway || code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// retryTransport retries idempotent requests without a body on the next upstream if the upstream is unavailable
+type retryTransport struct {
+	route *Route
+	base  http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := req.Context().Value(forwardingKey{}).(*forwarding)
+
+	resp, err := f.upstream.send(t.base, req)
+	if !isRetryable(req) {
+		return resp, err
+	}
+
+	for attempt := 1; attempt <= t.route.config.Retries && (err != nil || isUnavailable(resp.StatusCode)); attempt++ {
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.route.config.RetryDelay * time.Duration(attempt)):
+		}
+
+		next := pick(t.route.balancer, t.route.upstreams, f.upstream)
+		if next == nil {
+			metrics.ProxyUpstreamErrors.WithLabelValues(t.route.Name, "", metrics.UpstreamNone).Inc()
+			return nil, errNoUpstream
+		}
+		slog.Warn("Retrying request", "route", t.route.Name, "upstream", next.target.String(), "path", req.URL.Path, "attempt", attempt)
+
+		f.upstream = next
+		req = req.Clone(req.Context())
+		next.rewrite(req, f.path)
+		resp, err = next.send(t.base, req)
+	}
+
+	return resp, err
+}
+
+func isRetryable(req *http.Request) bool {
+	return (req.Method == http.MethodGet || req.Method == http.MethodHead) && (req.Body == nil || req.Body == http.NoBody)
+}
@@ -0,0 +1,345 @@
+package proxy
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dto"
+	"api-gateway/app/metrics"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	prometheusdto "github.com/prometheus/client_model/go"
+)
+
+func testConfig() Config {
+	/* Helper to build a configuration with short timeouts */
+	config := DefaultConfig
+	config.ResponseTimeout = 200 * time.Millisecond
+	config.RetryDelay = time.Millisecond
+	config.FailureThreshold = 3
+	return config
+}
+
+func testRoute(t *testing.T, config Config, route RouteConfig, upstreams ...string) *Route {
+	/* Helper to build a route of the planner API to the upstreams */
+	t.Helper()
+	if route.Name == "" {
+		route.Name = "planner"
+		route.Prefix = "/api/v1/planner"
+	}
+	route.Upstreams = upstreams
+
+	r, err := NewRoute(config, route, nil)
+	if err != nil {
+		t.Fatalf("Error while creating route: %s", err)
+	}
+	return r
+}
+
+func serve(r *Route, method string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, "/api/v1/planner/department/", nil))
+	return w
+}
+
+func responseKey(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body dto.APIResponse[interface{}]
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Error while decoding response: %s", err)
+	}
+	return body.ResponseKey
+}
+
+func TestRetries(t *testing.T) {
+	/* GET requests are retried while the backend is unavailable, other methods are not */
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	p := testRoute(t, testConfig(), RouteConfig{}, server.URL)
+
+	if w := serve(p, http.MethodGet); w.Code != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("Expected the GET request to succeed after 3 attempts, got %d after %d", w.Code, calls.Load())
+	}
+
+	calls.Store(0)
+	if w := serve(p, http.MethodPost); w.Code != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("Expected the POST request to be sent once, got %d after %d", w.Code, calls.Load())
+	}
+
+	// retries move on to the next upstream
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer up.Close()
+
+	config := testConfig()
+	config.Retries = 1
+	config.FailureThreshold = 100
+	p = testRoute(t, config, RouteConfig{}, down.URL, up.URL)
+	for i := 0; i < 4; i++ {
+		if w := serve(p, http.MethodGet); w.Code != http.StatusOK {
+			t.Errorf("Expected the GET request to be retried on the healthy upstream, got %d", w.Code)
+		}
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	/* Errors of the proxy are returned in the APIResponse envelope */
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	defer server.Close()
+
+	config := testConfig()
+	config.Retries = 0
+	p := testRoute(t, config, RouteConfig{}, server.URL)
+
+	w := serve(p, http.MethodGet)
+	if w.Code != http.StatusGatewayTimeout || responseKey(t, w) != constant.GatewayTimeout.GetResponseStatus() {
+		t.Errorf("Expected a gateway timeout for a hung backend, got %d", w.Code)
+	}
+
+	// nothing listens on the target
+	server.Close()
+	w = serve(p, http.MethodGet)
+	if w.Code != http.StatusBadGateway || responseKey(t, w) != constant.BadGateway.GetResponseStatus() {
+		t.Errorf("Expected a bad gateway for an unreachable backend, got %d", w.Code)
+	}
+
+	// no upstream configured
+	w = serve(testRoute(t, config, RouteConfig{}), http.MethodGet)
+	if w.Code != http.StatusServiceUnavailable || responseKey(t, w) != constant.ServiceUnavailable.GetResponseStatus() {
+		t.Errorf("Expected service unavailable without a backend, got %d", w.Code)
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	/* After consecutive failures the requests are rejected without reaching the backend */
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config := testConfig()
+	config.Retries = 0
+	p := testRoute(t, config, RouteConfig{}, server.URL)
+
+	for i := 0; i < config.FailureThreshold; i++ {
+		serve(p, http.MethodGet)
+	}
+	if state := p.upstreams[0].breaker.State(); state != CircuitOpen {
+		t.Fatalf("Expected the circuit to be open, got %s", state)
+	}
+
+	calls.Store(0)
+	w := serve(p, http.MethodGet)
+	if w.Code != http.StatusServiceUnavailable || calls.Load() != 0 {
+		t.Errorf("Expected the request to be rejected by the open circuit, got %d after %d calls", w.Code, calls.Load())
+	}
+
+	// a successful health check closes the circuit
+	healthy.Store(true)
+	p.CheckHealth(context.Background())
+	if w := serve(p, http.MethodGet); w.Code != http.StatusOK {
+		t.Errorf("Expected the request to pass after a successful health check, got %d", w.Code)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	breaker.Failure()
+	if !breaker.Allow() {
+		t.Errorf("Expected the circuit to stay closed below the threshold")
+	}
+	breaker.Failure()
+	if breaker.Allow() || breaker.State() != CircuitOpen {
+		t.Errorf("Expected the circuit to open at the threshold, got %s", breaker.State())
+	}
+
+	// a single probe is let through after the open duration
+	now = now.Add(time.Minute)
+	if !breaker.Allow() || breaker.Allow() {
+		t.Errorf("Expected a single probe in the half-open circuit")
+	}
+	breaker.Failure()
+	if breaker.State() != CircuitOpen {
+		t.Errorf("Expected a failed probe to open the circuit again, got %s", breaker.State())
+	}
+
+	now = now.Add(time.Minute)
+	breaker.Allow()
+	breaker.Success()
+	if breaker.State() != CircuitClosed || !breaker.Allow() {
+		t.Errorf("Expected a successful probe to close the circuit, got %s", breaker.State())
+	}
+}
+
+func TestHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/planner/ping" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	p := testRoute(t, testConfig(), RouteConfig{}, server.URL)
+	if health := p.Health().Upstreams[0]; health.Healthy || health.LastCheck != nil {
+		t.Errorf("Expected no health before the first check, got %+v", health)
+	}
+
+	p.CheckHealth(context.Background())
+	if health := p.Health().Upstreams[0]; !health.Healthy || health.LastCheck == nil || health.Circuit != CircuitClosed {
+		t.Errorf("Expected a healthy backend, got %+v", health)
+	}
+
+	server.Close()
+	p.CheckHealth(context.Background())
+	if health := p.Health().Upstreams[0]; health.Healthy || health.LastError == "" {
+		t.Errorf("Expected an unhealthy backend with the error, got %+v", health)
+	}
+
+	if health := testRoute(t, testConfig(), RouteConfig{}).Health(); health.Name != "planner" || len(health.Upstreams) != 0 {
+		t.Errorf("Expected no upstreams without a backend, got %+v", health)
+	}
+}
+
+func TestBalancer(t *testing.T) {
+	var calls [2]atomic.Int32
+	servers := []*httptest.Server{}
+	for i := range calls {
+		counter := &calls[i]
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			counter.Add(1)
+		}))
+		defer server.Close()
+		servers = append(servers, server)
+	}
+
+	p := testRoute(t, testConfig(), RouteConfig{}, servers[0].URL, servers[1].URL)
+	for i := 0; i < 4; i++ {
+		serve(p, http.MethodGet)
+	}
+	if calls[0].Load() != 2 || calls[1].Load() != 2 {
+		t.Errorf("Expected round-robin to alternate the upstreams, got %d and %d", calls[0].Load(), calls[1].Load())
+	}
+
+	p = testRoute(t, testConfig(), RouteConfig{Balancer: LeastConnections}, servers[0].URL, servers[1].URL)
+	p.upstreams[0].active.Store(5)
+	for i := 0; i < 2; i++ {
+		if u := pick(p.balancer, p.upstreams, nil); u != p.upstreams[1] {
+			t.Errorf("Expected the upstream with the least connections, got %s", u.target)
+		}
+	}
+
+	// an open circuit is skipped
+	for i := 0; i < testConfig().FailureThreshold; i++ {
+		p.upstreams[1].breaker.Failure()
+	}
+	if u := pick(p.balancer, p.upstreams, nil); u != p.upstreams[0] {
+		t.Errorf("Expected the upstream with the closed circuit, got %s", u.target)
+	}
+
+	// the connections are released once the response is passed on
+	p.upstreams[0].active.Store(0)
+	serve(p, http.MethodGet)
+	if active := p.upstreams[0].active.Load(); active != 0 {
+		t.Errorf("Expected no active connections after the request, got %d", active)
+	}
+}
+
+func TestStripPrefix(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.RequestURI()
+	}))
+	defer server.Close()
+
+	route := RouteConfig{Name: "reporting", Prefix: "/api/v1/reporting", StripPrefix: true}
+	p := testRoute(t, testConfig(), route, server.URL+"/reporting")
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/reporting/shifts?month=5", nil))
+	if w.Code != http.StatusOK || path != "/reporting/shifts?month=5" {
+		t.Errorf("Expected the prefix to be replaced by the path of the upstream, got %d for %s", w.Code, path)
+	}
+
+	if p.UpstreamPath("/api/v1/reporting") != "/" || p.healthPath != "/ping" {
+		t.Errorf("Expected the root and the health path of the upstream, got %s and %s", p.UpstreamPath("/api/v1/reporting"), p.healthPath)
+	}
+}
+
+func TestUpstreamMetrics(t *testing.T) {
+	/* Every attempt is reported with the latency of the upstream, failed attempts are counted by reason */
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	config := testConfig()
+	config.FailureThreshold = 100
+	p := testRoute(t, config, RouteConfig{Name: "metrics", Prefix: "/api/v1/planner"}, server.URL)
+	unavailable := metrics.ProxyUpstreamErrors.WithLabelValues("metrics", server.URL, metrics.UpstreamUnavailable)
+
+	if w := serve(p, http.MethodGet); w.Code != http.StatusOK {
+		t.Fatalf("Expected the GET request to succeed, got %d", w.Code)
+	}
+	if count := sampleCount(t, metrics.ProxyUpstreamDuration.WithLabelValues("metrics", server.URL, "503")); count != 2 {
+		t.Errorf("Expected 2 unavailable attempts, got %d", count)
+	}
+	if count := sampleCount(t, metrics.ProxyUpstreamDuration.WithLabelValues("metrics", server.URL, "200")); count != 1 {
+		t.Errorf("Expected 1 successful attempt, got %d", count)
+	}
+	if count := testutil.ToFloat64(unavailable); count != 2 {
+		t.Errorf("Expected 2 errors, got %v", count)
+	}
+
+	// no upstream configured
+	serve(testRoute(t, config, RouteConfig{Name: "metrics", Prefix: "/api/v1/planner"}), http.MethodGet)
+	if count := testutil.ToFloat64(metrics.ProxyUpstreamErrors.WithLabelValues("metrics", "", metrics.UpstreamNone)); count != 1 {
+		t.Errorf("Expected 1 request without upstream, got %v", count)
+	}
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	var metric prometheusdto.Metric
+	if err := observer.(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatalf("Error while reading metric: %s", err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
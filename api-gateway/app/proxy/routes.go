@@ -0,0 +1,114 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Strategies to choose the upstream of a request
+const (
+	RoundRobin       = "round-robin"
+	LeastConnections = "least-connections"
+)
+
+// RouteConfig is an entry of the route table
+type RouteConfig struct {
+	// Name of the route, shown by the health checks
+	Name string `json:"name"`
+	// Requests below the path prefix are forwarded, the longest matching prefix wins
+	Prefix string `json:"prefix"`
+	// Base URLs of the upstreams, e.g. http://planner-backend:8080
+	Upstreams []string `json:"upstreams"`
+	// round-robin (default) or least-connections
+	Balancer string `json:"balancer"`
+	// Removes the prefix from the path before the request is forwarded
+	StripPrefix bool `json:"strip_prefix"`
+	// Policy of the route. Without a policy every request of the route requires an authenticated user
+	PolicyFile string `json:"policy_file"`
+	// Path of the upstream checked by the health checks, defaults to /ping below the forwarded prefix
+	HealthPath string `json:"health_path"`
+}
+
+type RouteTable struct {
+	Routes []RouteConfig `json:"routes"`
+}
+
+func LoadRoutes(path string) (*RouteTable, error) {
+	/**
+	* Loads and validates a route table from a JSON file
+	* @param path: The path of the file
+	* @return: The loaded route table
+	**/
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var table RouteTable
+	if err := json.Unmarshal(file, &table); err != nil {
+		return nil, err
+	}
+
+	if err := table.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+func (t *RouteTable) Validate() error {
+	/* Checks the names, prefixes, upstreams and balancers of the routes */
+	names := map[string]bool{}
+	prefixes := map[string]bool{}
+
+	for i := range t.Routes {
+		route := &t.Routes[i]
+		route.Prefix = strings.TrimSuffix(route.Prefix, "/")
+
+		if route.Name == "" {
+			return fmt.Errorf("route %d: name is required", i)
+		}
+		if names[route.Name] {
+			return fmt.Errorf("route %s: name is not unique", route.Name)
+		}
+		names[route.Name] = true
+
+		if !strings.HasPrefix(route.Prefix, "/") {
+			return fmt.Errorf("route %s: prefix must start with / and must not be the root", route.Name)
+		}
+		if prefixes[route.Prefix] {
+			return fmt.Errorf("route %s: prefix %s is not unique", route.Name, route.Prefix)
+		}
+		prefixes[route.Prefix] = true
+
+		if route.Balancer != "" && route.Balancer != RoundRobin && route.Balancer != LeastConnections {
+			return fmt.Errorf("route %s: unknown balancer %q, expected %s or %s", route.Name, route.Balancer, RoundRobin, LeastConnections)
+		}
+		if route.HealthPath != "" && !strings.HasPrefix(route.HealthPath, "/") {
+			return fmt.Errorf("route %s: health_path must start with /", route.Name)
+		}
+
+		if len(route.Upstreams) == 0 {
+			return fmt.Errorf("route %s: at least one upstream is required", route.Name)
+		}
+		for _, upstream := range route.Upstreams {
+			if _, err := parseUpstream(upstream); err != nil {
+				return errors.Join(fmt.Errorf("route %s", route.Name), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseUpstream(upstream string) (*url.URL, error) {
+	target, err := url.Parse(upstream)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return nil, fmt.Errorf("invalid upstream %q, expected e.g. http://planner-backend:8080", upstream)
+	}
+	return target, nil
+}
@@ -0,0 +1,315 @@
+package proxy
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"api-gateway/app/policy"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The route file is checked for changes in this interval unless the Source sets another one
+const DefaultReloadInterval = 30 * time.Second
+
+// Table holds the routes of the gateway, they are swapped as a whole when the route file is reloaded
+type Table struct {
+	config Config
+	// the route file, empty if the routes are fixed
+	path           string
+	reloadInterval time.Duration
+	routes         atomic.Pointer[[]*Route]
+
+	// serializes the reloads
+	mu       sync.Mutex
+	modified time.Time
+	// the context of Start, the health checks of the current routes are stopped by cancel
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewTable(config Config, routes ...*Route) *Table {
+	/* Creates a table of fixed routes */
+	t := &Table{config: config}
+	t.store(routes)
+	return t
+}
+
+func LoadTable(config Config, path string) (*Table, error) {
+	/**
+	* Creates a table from a route file, see LoadRoutes
+	* @param config: The settings shared by all routes
+	* @param path: The path of the route file
+	**/
+	t := &Table{config: config, path: path, reloadInterval: DefaultReloadInterval}
+	if err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Source tells where the routes of a table come from
+type Source struct {
+	// The route file, which is checked for changes every ReloadInterval
+	File           string
+	ReloadInterval time.Duration
+	// Without a file a single route forwards /api/v1/planner to the planner-backend with this policy,
+	// its requests are answered with 503 without a target
+	PlannerTarget string
+	PlannerPolicy *policy.Policy
+}
+
+func OpenTable(config Config, source Source) (*Table, error) {
+	/**
+	* Creates the route table of the gateway from the route file or the route of the planner-backend
+	* @param config: The settings shared by all routes
+	* @param source: The route file or the planner-backend
+	**/
+	if source.File == "" {
+		slog.Info("No route file is set, forwarding /api/v1/planner to PLANNER_BACKEND_TARGET")
+		route, err := plannerRoute(config, source.PlannerTarget, source.PlannerPolicy)
+		if err != nil {
+			return nil, err
+		}
+		return NewTable(config, route), nil
+	}
+
+	t, err := LoadTable(config, source.File)
+	if err != nil {
+		return nil, err
+	}
+	if source.ReloadInterval > 0 {
+		t.reloadInterval = source.ReloadInterval
+	}
+	return t, nil
+}
+
+func plannerRoute(config Config, target string, plannerPolicy *policy.Policy) (*Route, error) {
+	route := RouteConfig{Name: "planner", Prefix: "/api/v1/planner"}
+
+	if target == "" {
+		slog.Info("PLANNER_BACKEND_TARGET is not set, requests of the planner API are not forwarded")
+	} else {
+		route.Upstreams = []string{target}
+	}
+
+	if plannerPolicy == nil {
+		defaultPolicy := policy.DefaultPolicy
+		plannerPolicy = &defaultPolicy
+	}
+
+	r, err := NewRoute(config, route, plannerPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PLANNER_BACKEND_TARGET %q, expected e.g. http://planner-backend:8080", target)
+	}
+	return r, nil
+}
+
+func (t *Table) Route(path string) *Route {
+	/* Returns the route with the longest prefix matching the path, nil if no route matches */
+	if t == nil {
+		return nil
+	}
+
+	for _, route := range *t.routes.Load() {
+		if path == route.Prefix || strings.HasPrefix(path, route.Prefix+"/") {
+			return route
+		}
+	}
+	return nil
+}
+
+func (t *Table) Routes() []*Route {
+	if t == nil {
+		return nil
+	}
+	return *t.routes.Load()
+}
+
+func (t *Table) Match(c *gin.Context) {
+	/**
+	* Resolves the route of a request for the following middlewares: the route, its policy
+	* and the path the upstream receives. Requests without a route are answered with 404.
+	**/
+
+	route := t.Route(c.Request.URL.Path)
+	if route == nil {
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	}
+
+	c.Set("route", route)
+	c.Set("routePolicy", route.Policy)
+	c.Set("upstreamPath", route.UpstreamPath(c.Request.URL.Path))
+	// the requests of the route are reported to /metrics by its prefix
+	c.Set("routeTemplate", route.Prefix+"/*")
+
+	c.Next()
+}
+
+func (t *Table) Forward(c *gin.Context) {
+	/* Forwards the request to the route resolved by Match */
+	c.MustGet("route").(*Route).ServeHTTP(c.Writer, c.Request)
+}
+
+func (t *Table) Health() []dco.RouteHealth {
+	health := []dco.RouteHealth{}
+	for _, route := range t.Routes() {
+		health = append(health, route.Health())
+	}
+	return health
+}
+
+func (t *Table) Start(ctx context.Context) {
+	/**
+	* Starts the health checks of the upstreams until the context is done.
+	* A route file is reloaded on SIGHUP and when it was modified.
+	**/
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	t.ctx = ctx
+	t.startHealthChecks(*t.routes.Load())
+	t.mu.Unlock()
+
+	if t.path == "" {
+		return
+	}
+	slog.Info("Watching route file", "path", t.path, "interval", t.reloadInterval)
+
+	go func() {
+		hangup := make(chan os.Signal, 1)
+		signal.Notify(hangup, syscall.SIGHUP)
+		defer signal.Stop(hangup)
+
+		ticker := time.NewTicker(t.reloadInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hangup:
+				t.Reload()
+			case <-ticker.C:
+				if t.modifiedSince() {
+					t.Reload()
+				}
+			}
+		}
+	}()
+}
+
+func (t *Table) Reload() error {
+	/**
+	* Loads the route file again and replaces all routes at once.
+	* The current routes stay in place if the file is invalid.
+	**/
+	if t.path == "" {
+		return errors.New("the routes are not loaded from a file")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		slog.Error("Failed to load routes", "path", t.path, "error", err)
+		return err
+	}
+
+	// an invalid file is loaded again once it is modified
+	t.modified = info.ModTime()
+
+	routes, err := t.load()
+	if err != nil {
+		slog.Error("Failed to load routes", "path", t.path, "error", err)
+		return err
+	}
+
+	previous := t.routes.Load()
+	t.store(routes)
+
+	// the new routes start with closed circuits and are checked right away
+	if t.cancel != nil {
+		t.cancel()
+	}
+	if t.ctx != nil {
+		t.startHealthChecks(routes)
+	}
+	if previous != nil {
+		for _, route := range *previous {
+			route.transport.CloseIdleConnections()
+		}
+	}
+
+	slog.Info("Loaded routes", "path", t.path, "routes", len(routes))
+	return nil
+}
+
+func (t *Table) load() ([]*Route, error) {
+	table, err := LoadRoutes(t.path)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := []*Route{}
+	for _, config := range table.Routes {
+		p := policy.Authenticated(config.Prefix)
+		if config.PolicyFile != "" {
+			if p, err = policy.Load(config.PolicyFile); err != nil {
+				return nil, errors.Join(fmt.Errorf("route %s: policy %s", config.Name, config.PolicyFile), err)
+			}
+		}
+
+		route, err := NewRoute(t.config, config, p)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("route %s", config.Name), err)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func (t *Table) store(routes []*Route) {
+	// the longest prefix wins
+	sort.SliceStable(routes, func(i, j int) bool {
+		return len(routes[i].Prefix) > len(routes[j].Prefix)
+	})
+	t.routes.Store(&routes)
+}
+
+func (t *Table) startHealthChecks(routes []*Route) {
+	ctx, cancel := context.WithCancel(t.ctx)
+	t.cancel = cancel
+	for _, route := range routes {
+		route.StartHealthChecks(ctx)
+	}
+}
+
+func (t *Table) modifiedSince() bool {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		slog.Warn("Failed to check route file", "path", t.path, "error", err)
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !info.ModTime().Equal(t.modified)
+}
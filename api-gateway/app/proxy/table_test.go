@@ -0,0 +1,142 @@
+package proxy
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeRoutes(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Error while writing routes: %s", err)
+	}
+}
+
+func TestTableRoute(t *testing.T) {
+	table := NewTable(testConfig(),
+		testRoute(t, testConfig(), RouteConfig{}),
+		testRoute(t, testConfig(), RouteConfig{Name: "leave", Prefix: "/api/v1/planner/leave/"}),
+	)
+
+	tests := map[string]string{
+		"/api/v1/planner":               "planner",
+		"/api/v1/planner/department/1":  "planner",
+		"/api/v1/planner/leave":         "leave",
+		"/api/v1/planner/leave/1":       "leave",
+		"/api/v1/planner/leave-request": "planner",
+	}
+	for path, name := range tests {
+		if route := table.Route(path); route == nil || route.Name != name {
+			t.Errorf("Expected route %s for %s, got %+v", name, path, route)
+		}
+	}
+
+	if route := table.Route("/api/v1/plannerx"); route != nil {
+		t.Errorf("Expected no route for a partial segment, got %s", route.Name)
+	}
+
+	var empty *Table
+	if empty.Route("/api/v1/planner") != nil || len(empty.Health()) != 0 {
+		t.Errorf("Expected no routes without a table")
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	writeRoutes(t, path, `{"routes": [{"name": "planner", "prefix": "/api/v1/planner", "upstreams": ["http://planner-backend:8080"]}]}`)
+
+	table, err := LoadTable(testConfig(), path)
+	if err != nil {
+		t.Fatalf("Error while loading routes: %s", err)
+	}
+	if route := table.Route("/api/v1/planner/ping"); route == nil || route.Policy == nil {
+		t.Fatalf("Expected the planner route with the default policy")
+	}
+	if table.modifiedSince() {
+		t.Errorf("Expected the loaded file to be unmodified")
+	}
+
+	writeRoutes(t, path, `{"routes": [
+		{"name": "planner", "prefix": "/api/v1/planner", "upstreams": ["http://planner-backend-1:8080", "http://planner-backend-2:8080"], "balancer": "least-connections"},
+		{"name": "reporting", "prefix": "/api/v1/reporting", "upstreams": ["http://reporting:8080"], "strip_prefix": true}
+	]}`)
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+	if !table.modifiedSince() {
+		t.Errorf("Expected the file to be modified")
+	}
+	if err := table.Reload(); err != nil {
+		t.Fatalf("Error while reloading routes: %s", err)
+	}
+	if len(table.Routes()) != 2 || len(table.Route("/api/v1/planner").upstreams) != 2 || table.Route("/api/v1/reporting/x") == nil {
+		t.Errorf("Expected the reloaded routes, got %+v", table.Health())
+	}
+
+	// an invalid file keeps the current routes
+	writeRoutes(t, path, `{"routes": [{"name": "planner", "prefix": "/api/v1/planner", "upstreams": []}]}`)
+	if err := table.Reload(); err == nil {
+		t.Errorf("Expected an error for an invalid file")
+	}
+	if len(table.Routes()) != 2 {
+		t.Errorf("Expected the current routes to stay in place, got %d", len(table.Routes()))
+	}
+}
+
+func TestLoadRoutes(t *testing.T) {
+	invalid := map[string]string{
+		"name":      `{"routes": [{"prefix": "/a", "upstreams": ["http://a"]}]}`,
+		"duplicate": `{"routes": [{"name": "a", "prefix": "/a", "upstreams": ["http://a"]}, {"name": "b", "prefix": "/a/", "upstreams": ["http://a"]}]}`,
+		"root":      `{"routes": [{"name": "a", "prefix": "/", "upstreams": ["http://a"]}]}`,
+		"upstream":  `{"routes": [{"name": "a", "prefix": "/a", "upstreams": ["a:8080"]}]}`,
+		"balancer":  `{"routes": [{"name": "a", "prefix": "/a", "upstreams": ["http://a"], "balancer": "random"}]}`,
+		"policy":    `{"routes": [{"name": "a", "prefix": "/a", "upstreams": ["http://a"], "policy_file": "missing.json"}]}`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "routes.json")
+			writeRoutes(t, path, content)
+			if _, err := LoadTable(testConfig(), path); err == nil {
+				t.Errorf("Expected an error for %s", content)
+			}
+		})
+	}
+}
+
+func TestOpenTable(t *testing.T) {
+	table, err := OpenTable(testConfig(), Source{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	route := table.Route("/api/v1/planner/ping")
+	if route == nil || len(route.upstreams) != 0 {
+		t.Fatalf("Expected the planner route without upstreams, got %+v", route)
+	}
+
+	table, err = OpenTable(testConfig(), Source{PlannerTarget: "http://planner-backend:8080"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	route = table.Route("/api/v1/planner/ping")
+	if route == nil || len(route.upstreams) != 1 || route.healthPath != "/api/v1/planner/ping" {
+		t.Fatalf("Expected the planner route to the target, got %+v", route)
+	}
+	if decision := route.Policy.Evaluate(nil, http.MethodGet, "/api/v1/planner/ping"); !decision.Allowed {
+		t.Errorf("Expected the default policy of the planner")
+	}
+
+	if _, err := OpenTable(testConfig(), Source{PlannerTarget: "planner-backend"}); err == nil {
+		t.Errorf("Expected an error for an invalid target")
+	}
+
+	path := filepath.Join(t.TempDir(), "routes.json")
+	writeRoutes(t, path, `{"routes": [{"name": "reporting", "prefix": "/api/v1/reporting", "upstreams": ["http://reporting:8080"]}]}`)
+	table, err = OpenTable(testConfig(), Source{File: path, ReloadInterval: time.Minute, PlannerTarget: "http://planner-backend:8080"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if table.Route("/api/v1/planner/ping") != nil || table.Route("/api/v1/reporting") == nil || table.reloadInterval != time.Minute {
+		t.Errorf("Expected the routes of the file, got %+v", table.Routes())
+	}
+}
@@ -0,0 +1,165 @@
+/**
+* This package limits the request rates of the clients of the gateway with token buckets.
+* Every client has a bucket per group of routes, which holds the requests of a period and is refilled continuously.
+**/
+package ratelimit
+
+import (
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/metrics"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Group of routes with a budget of its own
+type Group string
+
+const (
+	// The public routes of /auth, e.g. the login
+	Auth Group = "auth"
+	// Requests which do not change data: GET, HEAD and OPTIONS
+	Read Group = "read"
+	// Requests which change data
+	Write Group = "write"
+)
+
+// Groups in the order they are reported
+var Groups = []Group{Auth, Read, Write}
+
+func GroupOf(method string) Group {
+	/* Returns the read or the write group by the method of the request */
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return Read
+	default:
+		return Write
+	}
+}
+
+// Limit allows a burst of Requests, which are refilled within Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (l Limit) Disabled() bool {
+	return l.Requests <= 0
+}
+
+func (l Limit) String() string {
+	if l.Disabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+func ParseLimit(value string) (Limit, error) {
+	/**
+	* Parses a limit like "60/1m" (60 requests per minute) or "5/s".
+	* "0" and "off" disable the limit.
+	**/
+	if value == "0" || value == "off" {
+		return Limit{}, nil
+	}
+
+	requests, period, found := strings.Cut(value, "/")
+	if !found {
+		return Limit{}, fmt.Errorf("expected requests/period, got %q", value)
+	}
+
+	limit := Limit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests < 0 {
+		return Limit{}, fmt.Errorf("invalid number of requests %q", requests)
+	}
+
+	// a unit without a number means a single unit, e.g. "s" is "1s"
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+		return Limit{}, fmt.Errorf("invalid period %q", period)
+	}
+
+	return limit, nil
+}
+
+// Store holds the buckets of the clients. The buckets are kept in memory by default,
+// a shared store lets the replicas of the gateway enforce a common budget.
+type Store interface {
+	// Take removes a token from the bucket of the key. If the bucket is empty,
+	// it returns false and the time until the next token is available.
+	Take(key string, limit Limit) (bool, time.Duration, error)
+}
+
+// Limiter checks the requests of the clients against the limit of their group
+type Limiter struct {
+	store    Store
+	limits   map[Group]Limit
+	rejected map[Group]*atomic.Int64
+}
+
+func NewLimiter(store Store, limits map[Group]Limit) *Limiter {
+	l := &Limiter{store: store, limits: limits, rejected: map[Group]*atomic.Int64{}}
+	for _, group := range Groups {
+		l.rejected[group] = &atomic.Int64{}
+	}
+	return l
+}
+
+func NewMemoryLimiter(limits map[Group]Limit) *Limiter {
+	/* Creates a limiter which keeps the buckets in memory, the budgets are not shared by the replicas */
+	slog.Info("Rate limits", "auth", limits[Auth], "read", limits[Read], "write", limits[Write])
+	return NewLimiter(NewMemoryStore(), limits)
+}
+
+func (l *Limiter) Allow(group Group, key string) (bool, time.Duration) {
+	/**
+	* Takes a request of the client from the bucket of the group.
+	* Requests are allowed if the store fails, a broken store must not take down the gateway.
+	* @param group: The group of the route
+	* @param key: The client, e.g. user:<name>, api-key:<id> or ip:<address>
+	* @return false and the time until the next request is allowed if the limit is exceeded
+	**/
+	if l == nil {
+		return true, 0
+	}
+
+	limit := l.limits[group]
+	if limit.Disabled() {
+		return true, 0
+	}
+
+	allowed, retryAfter, err := l.store.Take(string(group)+":"+key, limit)
+	if err != nil {
+		slog.Warn("Failed to check the rate limit", "group", group, "key", key, "error", err)
+		return true, 0
+	}
+	if !allowed {
+		l.rejected[group].Add(1)
+		metrics.RateLimitRejections.WithLabelValues(string(group)).Inc()
+	}
+	return allowed, retryAfter
+}
+
+func (l *Limiter) Health() []dco.RateLimitHealth {
+	/* Reports the limits and the rejected requests of each group */
+	health := []dco.RateLimitHealth{}
+	if l == nil {
+		return health
+	}
+
+	for _, group := range Groups {
+		health = append(health, dco.RateLimitHealth{
+			Group:    string(group),
+			Limit:    l.limits[group].String(),
+			Rejected: l.rejected[group].Load(),
+		})
+	}
+	return health
+}
@@ -0,0 +1,138 @@
+package ratelimit
+
+import (
+	"api-gateway/app/metrics"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseLimit(t *testing.T) {
+	valid := map[string]Limit{
+		"60/1m":  {Requests: 60, Period: time.Minute},
+		"5/s":    {Requests: 5, Period: time.Second},
+		"100/1h": {Requests: 100, Period: time.Hour},
+		"0":      {},
+		"off":    {},
+	}
+	for value, expected := range valid {
+		limit, err := ParseLimit(value)
+		if err != nil || limit != expected {
+			t.Errorf("Expected %+v for %q, got %+v (%v)", expected, value, limit, err)
+		}
+	}
+
+	for _, value := range []string{"", "60", "x/1m", "-1/1m", "60/", "60/0s", "60/week"} {
+		if _, err := ParseLimit(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Period: time.Minute}
+
+	// the burst is allowed right away
+	for i := 0; i < 2; i++ {
+		if allowed, _, _ := store.Take("a", limit); !allowed {
+			t.Fatalf("Expected request %d to be allowed", i)
+		}
+	}
+	allowed, retryAfter, _ := store.Take("a", limit)
+	if allowed || retryAfter != 30*time.Second {
+		t.Errorf("Expected a rejection for 30s, got %v and %s", allowed, retryAfter)
+	}
+
+	// other keys have buckets of their own
+	if allowed, _, _ := store.Take("b", limit); !allowed {
+		t.Errorf("Expected another key to be allowed")
+	}
+
+	// a token is refilled after half of the period
+	now = now.Add(30 * time.Second)
+	if allowed, _, _ := store.Take("a", limit); !allowed {
+		t.Errorf("Expected a refilled token")
+	}
+	if allowed, _, _ := store.Take("a", limit); allowed {
+		t.Errorf("Expected a single refilled token")
+	}
+
+	// full buckets are removed
+	now = now.Add(2 * sweepInterval)
+	store.Take("c", limit)
+	if len(store.buckets) != 1 {
+		t.Errorf("Expected the idle buckets to be removed, got %d", len(store.buckets))
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(key string, limit Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("unavailable")
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(NewMemoryStore(), map[Group]Limit{
+		Auth: {Requests: 1, Period: time.Minute},
+		Read: {Requests: 1, Period: time.Minute},
+	})
+	rejected := testutil.ToFloat64(metrics.RateLimitRejections.WithLabelValues(string(Auth)))
+
+	if allowed, _ := limiter.Allow(Auth, "ip:1"); !allowed {
+		t.Errorf("Expected the first request to be allowed")
+	}
+	if allowed, retryAfter := limiter.Allow(Auth, "ip:1"); allowed || retryAfter <= 0 {
+		t.Errorf("Expected the second request to be rejected")
+	}
+	// the groups have separate budgets
+	if allowed, _ := limiter.Allow(Read, "ip:1"); !allowed {
+		t.Errorf("Expected the read budget to be separate")
+	}
+	// groups without a limit are not limited
+	for i := 0; i < 3; i++ {
+		if allowed, _ := limiter.Allow(Write, "ip:1"); !allowed {
+			t.Errorf("Expected the write group to be unlimited")
+		}
+	}
+
+	health := limiter.Health()
+	if len(health) != 3 || health[0].Rejected != 1 || health[1].Rejected != 0 || health[2].Limit != "off" {
+		t.Errorf("Unexpected health %+v", health)
+	}
+	// the rejections are also reported to /metrics
+	if count := testutil.ToFloat64(metrics.RateLimitRejections.WithLabelValues(string(Auth))); count != rejected+1 {
+		t.Errorf("Expected %v rejections, got %v", rejected+1, count)
+	}
+
+	// a failing store does not block the requests
+	limiter = NewLimiter(failingStore{}, map[Group]Limit{Read: {Requests: 1, Period: time.Minute}})
+	if allowed, _ := limiter.Allow(Read, "ip:1"); !allowed {
+		t.Errorf("Expected the request to be allowed if the store fails")
+	}
+
+	var disabled *Limiter
+	if allowed, _ := disabled.Allow(Read, "ip:1"); !allowed || len(disabled.Health()) != 0 {
+		t.Errorf("Expected no limits without a limiter")
+	}
+}
+
+func TestGroupOf(t *testing.T) {
+	tests := map[string]Group{
+		http.MethodGet:    Read,
+		http.MethodHead:   Read,
+		http.MethodPost:   Write,
+		http.MethodPut:    Write,
+		http.MethodDelete: Write,
+	}
+	for method, expected := range tests {
+		if group := GroupOf(method); group != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, method, group)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"api-gateway/app/domain/dao"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	FindAPIKeysByUserID(userID uuid.UUID) ([]dao.APIKey, error)
+	// Loads the key with its user, the permissions and roles of the user are needed to resolve the key
+	FindAPIKeyByHash(hash string) (dao.APIKey, error)
+	Save(apiKey *dao.APIKey) (dao.APIKey, error)
+	// Removes a key of the user, fails with ErrRecordNotFound if there is none
+	DeleteAPIKey(userID uuid.UUID, id uuid.UUID) error
+	// Stores the last use of the key, it is only written once per interval to keep the requests cheap
+	TouchAPIKey(id uuid.UUID, usedAt time.Time, interval time.Duration) error
+}
+
+type APIKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func (r APIKeyRepositoryImpl) FindAPIKeysByUserID(userID uuid.UUID) ([]dao.APIKey, error) {
+	var apiKeys []dao.APIKey
+	err := r.db.Preload("Permissions").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&apiKeys).Error
+	if err != nil {
+		slog.Error("Got an error finding api keys of user.", "error", err)
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+func (r APIKeyRepositoryImpl) FindAPIKeyByHash(hash string) (dao.APIKey, error) {
+	var apiKey dao.APIKey
+	err := r.db.
+		Preload("Permissions").
+		Preload("User.Permissions").
+		Preload("User.Department").
+		Preload("User.Roles.Role.Permissions").
+		Preload("User.Memberships.Department").
+		Preload("User.Memberships.Role.Permissions").
+		Preload("User.TwoFactor").
+		Where("key_hash = ?", hash).
+		First(&apiKey).Error
+	if err != nil {
+		slog.Error("Got and error when find api key by hash.", "error", err)
+		return dao.APIKey{}, err
+	}
+	return apiKey, nil
+}
+
+func (r APIKeyRepositoryImpl) Save(apiKey *dao.APIKey) (dao.APIKey, error) {
+	if err := r.db.Omit("User").Save(apiKey).Error; err != nil {
+		slog.Error("Got an error when save api key.", "error", err)
+		return dao.APIKey{}, err
+	}
+	return *apiKey, nil
+}
+
+func (r APIKeyRepositoryImpl) DeleteAPIKey(userID uuid.UUID, id uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&dao.APIKey{})
+	if result.Error != nil {
+		slog.Error("Got an error when delete api key.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r APIKeyRepositoryImpl) TouchAPIKey(id uuid.UUID, usedAt time.Time, interval time.Duration) error {
+	err := r.db.Model(&dao.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-interval)).
+		Update("last_used_at", usedAt).Error
+	if err != nil {
+		slog.Error("Got an error when touch api key.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func APIKeyRepositoryInit(db *gorm.DB) *APIKeyRepositoryImpl {
+	db.AutoMigrate(&dao.APIKey{})
+	return &APIKeyRepositoryImpl{
+		db: db,
+	}
+}
+
+var apiKeyRepositorySet = wire.NewSet(
+	APIKeyRepositoryInit,
+	wire.Bind(new(APIKeyRepository), new(*APIKeyRepositoryImpl)),
+)
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"api-gateway/app/domain/dao"
+	"log/slog"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Save(entry *dao.AuditEntry) error
+}
+
+type AuditRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func (r AuditRepositoryImpl) Save(entry *dao.AuditEntry) error {
+	if err := r.db.Create(entry).Error; err != nil {
+		slog.Error("Got an error when save audit entry.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func AuditRepositoryInit(db *gorm.DB) *AuditRepositoryImpl {
+	db.AutoMigrate(&dao.AuditEntry{})
+	return &AuditRepositoryImpl{
+		db: db,
+	}
+}
+
+var auditRepositorySet = wire.NewSet(
+	AuditRepositoryInit,
+	wire.Bind(new(AuditRepository), new(*AuditRepositoryImpl)),
+)
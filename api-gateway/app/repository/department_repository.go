@@ -13,6 +13,9 @@ type DepartmentRepository interface {
 	FindAllDepartments() ([]dao.Department, error)
 	FindDepartmentByName(name string) (dao.Department, error)
 	FindDepartmentById(id uuid.UUID) (dao.Department, error)
+	FindDepartmentByPlannerID(plannerID string) (dao.Department, error)
+	// Returns the planner ids of deleted departments, so the synchronization does not restore them
+	FindDeletedPlannerIDs() ([]string, error)
 	Save(Department *dao.Department) (dao.Department, error)
 	DeleteDepartmentById(id uuid.UUID) error
 }
@@ -60,6 +63,28 @@ func (r DepartmentRepositoryImpl) FindDepartmentById(id uuid.UUID) (dao.Departme
 	return Department, nil
 }
 
+func (r DepartmentRepositoryImpl) FindDepartmentByPlannerID(plannerID string) (dao.Department, error) {
+	var department dao.Department
+	err := r.db.First(&department, "planner_id = ?", plannerID).Error
+	if err != nil {
+		slog.Error("Got and error when find Department by planner id.", "error", err)
+		return dao.Department{}, err
+	}
+	return department, nil
+}
+
+func (r DepartmentRepositoryImpl) FindDeletedPlannerIDs() ([]string, error) {
+	var plannerIDs []string
+	err := r.db.Unscoped().Model(&dao.Department{}).
+		Where("deleted_at IS NOT NULL AND planner_id <> ''").
+		Pluck("planner_id", &plannerIDs).Error
+	if err != nil {
+		slog.Error("Got an error finding deleted Departments.", "error", err)
+		return nil, err
+	}
+	return plannerIDs, nil
+}
+
 func (r DepartmentRepositoryImpl) Save(Department *dao.Department) (dao.Department, error) {
 	if err := r.db.Save(Department).Error; err != nil {
 		slog.Error("Got an error when save Department.", "error", err)
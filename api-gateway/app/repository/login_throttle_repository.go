@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"api-gateway/app/domain/dao"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type LoginThrottleRepository interface {
+	// Returns an empty throttle if the key has no failures
+	FindLoginThrottle(key string) (dao.LoginThrottle, error)
+	// Counts a failure, failures older than the window are forgotten
+	RecordFailure(key string, window time.Duration) (dao.LoginThrottle, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+type LoginThrottleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func (r LoginThrottleRepositoryImpl) FindLoginThrottle(key string) (dao.LoginThrottle, error) {
+	var throttle dao.LoginThrottle
+	err := r.db.Where("throttle_key = ?", key).First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return dao.LoginThrottle{Key: key}, nil
+	}
+	if err != nil {
+		slog.Error("Got and error when find login throttle.", "error", err)
+		return dao.LoginThrottle{}, err
+	}
+	return throttle, nil
+}
+
+func (r LoginThrottleRepositoryImpl) RecordFailure(key string, window time.Duration) (dao.LoginThrottle, error) {
+	// the counter is incremented in a single statement, so concurrent attempts are all counted
+	now := time.Now()
+	var throttle dao.LoginThrottle
+	err := r.db.Raw(`
+		INSERT INTO login_throttles (throttle_key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING *`, key, now, now.Add(-window)).Scan(&throttle).Error
+	if err != nil {
+		slog.Error("Got an error when record login failure.", "error", err)
+		return dao.LoginThrottle{}, err
+	}
+	return throttle, nil
+}
+
+func (r LoginThrottleRepositoryImpl) Lock(key string, until time.Time) error {
+	err := r.db.Model(&dao.LoginThrottle{}).Where("throttle_key = ?", key).Update("locked_until", until).Error
+	if err != nil {
+		slog.Error("Got an error when lock login.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r LoginThrottleRepositoryImpl) Reset(key string) error {
+	err := r.db.Where("throttle_key = ?", key).Delete(&dao.LoginThrottle{}).Error
+	if err != nil {
+		slog.Error("Got an error when reset login throttle.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func LoginThrottleRepositoryInit(db *gorm.DB) *LoginThrottleRepositoryImpl {
+	db.AutoMigrate(&dao.LoginThrottle{})
+	return &LoginThrottleRepositoryImpl{
+		db: db,
+	}
+}
+
+var loginThrottleRepositorySet = wire.NewSet(
+	LoginThrottleRepositoryInit,
+	wire.Bind(new(LoginThrottleRepository), new(*LoginThrottleRepositoryImpl)),
+)
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"api-gateway/app/metrics"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// QueryMetrics is a plugin of gorm, which reports the duration of every statement to /metrics
+// by the repository method which ran it. Statements outside of the repositories, e.g. of the migration, are not reported.
+type QueryMetrics struct{}
+
+func (QueryMetrics) Name() string {
+	return "metrics"
+}
+
+func (QueryMetrics) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("metrics:before_create", startQuery),
+		callbacks.Create().After("gorm:create").Register("metrics:after_create", observeQuery),
+		callbacks.Query().Before("gorm:query").Register("metrics:before_query", startQuery),
+		callbacks.Query().After("gorm:query").Register("metrics:after_query", observeQuery),
+		callbacks.Update().Before("gorm:update").Register("metrics:before_update", startQuery),
+		callbacks.Update().After("gorm:update").Register("metrics:after_update", observeQuery),
+		callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", startQuery),
+		callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", observeQuery),
+		callbacks.Row().Before("gorm:row").Register("metrics:before_row", startQuery),
+		callbacks.Row().After("gorm:row").Register("metrics:after_row", observeQuery),
+		callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", startQuery),
+		callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", observeQuery),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func observeQuery(db *gorm.DB) {
+	/* Records the duration of the statement, a missing record is not an error */
+	start, ok := db.InstanceGet(queryStartKey)
+	if !ok {
+		return
+	}
+	repository, method, ok := metrics.RepositoryMethod("api-gateway/app/repository")
+	if !ok {
+		return
+	}
+
+	status := metrics.StatusOK
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		status = metrics.StatusError
+	}
+	metrics.PostgresQueryDuration.WithLabelValues(repository, method, status).Observe(time.Since(start.(time.Time)).Seconds())
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"api-gateway/app/domain/dao"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepository interface {
+	Save(passwordReset *dao.PasswordReset) (dao.PasswordReset, error)
+	FindPasswordResetByTokenHash(hash string) (dao.PasswordReset, error)
+
+	// Marks the token as used, a token can only be used once
+	UsePasswordReset(id uuid.UUID) error
+	// Invalidates the open tokens of a user, e.g. when a new token is requested
+	InvalidatePasswordResetsOfUser(userID uuid.UUID) error
+}
+
+type PasswordResetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func (r PasswordResetRepositoryImpl) Save(passwordReset *dao.PasswordReset) (dao.PasswordReset, error) {
+	if err := r.db.Omit("User").Save(passwordReset).Error; err != nil {
+		slog.Error("Got an error when save password reset.", "error", err)
+		return dao.PasswordReset{}, err
+	}
+	return *passwordReset, nil
+}
+
+func (r PasswordResetRepositoryImpl) FindPasswordResetByTokenHash(hash string) (dao.PasswordReset, error) {
+	var passwordReset dao.PasswordReset
+	if err := r.db.Where("token_hash = ?", hash).First(&passwordReset).Error; err != nil {
+		slog.Error("Got and error when find password reset by token.", "error", err)
+		return dao.PasswordReset{}, err
+	}
+	return passwordReset, nil
+}
+
+func (r PasswordResetRepositoryImpl) UsePasswordReset(id uuid.UUID) error {
+	// the condition on used_at prevents that concurrent requests use the same token twice
+	result := r.db.Model(&dao.PasswordReset{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		slog.Error("Got an error when use password reset.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r PasswordResetRepositoryImpl) InvalidatePasswordResetsOfUser(userID uuid.UUID) error {
+	err := r.db.Model(&dao.PasswordReset{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+	if err != nil {
+		slog.Error("Got an error when invalidate password resets of user.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func PasswordResetRepositoryInit(db *gorm.DB) *PasswordResetRepositoryImpl {
+	db.AutoMigrate(&dao.PasswordReset{})
+	return &PasswordResetRepositoryImpl{
+		db: db,
+	}
+}
+
+var passwordResetRepositorySet = wire.NewSet(
+	PasswordResetRepositoryInit,
+	wire.Bind(new(PasswordResetRepository), new(*PasswordResetRepositoryImpl)),
+)
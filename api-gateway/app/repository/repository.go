@@ -5,5 +5,12 @@ import "github.com/google/wire"
 var RepositorySet = wire.NewSet(
 	userRepositorySet,
 	permissionRepositorySet,
+	roleRepositorySet,
+	sessionRepositorySet,
+	passwordResetRepositorySet,
+	loginThrottleRepositorySet,
+	auditRepositorySet,
+	twoFactorRepositorySet,
+	apiKeyRepositorySet,
 	departmentRepositorySet,
 )
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"api-gateway/app/domain/dao"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	FindAllRoles() ([]dao.Role, error)
+	FindRoleByName(name string) (dao.Role, error)
+	FindRoleById(id uuid.UUID) (dao.Role, error)
+	Save(role *dao.Role) (dao.Role, error)
+	DeleteRoleById(id uuid.UUID) error
+}
+
+type RoleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func (r RoleRepositoryImpl) FindAllRoles() ([]dao.Role, error) {
+	var roles []dao.Role
+
+	if err := r.db.Preload("Permissions").Find(&roles).Error; err != nil {
+		slog.Error("Got an error finding all roles.", "error", err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r RoleRepositoryImpl) FindRoleByName(name string) (dao.Role, error) {
+	var role dao.Role
+	err := r.db.Preload("Permissions").First(&role, "name = ?", name).Error
+	if err != nil {
+		slog.Error("Got and error when find role by name.", "error", err)
+		return dao.Role{}, err
+	}
+	return role, nil
+}
+
+func (r RoleRepositoryImpl) FindRoleById(id uuid.UUID) (dao.Role, error) {
+	role := dao.Role{
+		BaseModel: dao.BaseModel{
+			ID: id,
+		},
+	}
+	err := r.db.Preload("Permissions").First(&role).Error
+	if err != nil {
+		slog.Error("Got and error when find role by id.", "error", err)
+		return dao.Role{}, err
+	}
+	return role, nil
+}
+
+func (r RoleRepositoryImpl) Save(role *dao.Role) (dao.Role, error) {
+	// the permissions of the role are replaced by the given ones
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		return tx.Model(role).Association("Permissions").Replace(role.Permissions)
+	})
+	if err != nil {
+		slog.Error("Got an error when save role.", "error", err)
+		return dao.Role{}, err
+	}
+	return *role, nil
+}
+
+func (r RoleRepositoryImpl) DeleteRoleById(id uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// remove the assignments of the role first
+		if err := tx.Where("role_id = ?", id).Delete(&dao.UserRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&dao.Role{}, id).Error
+	})
+	if err != nil {
+		slog.Error("Got an error when delete role.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func RoleRepositoryInit(db *gorm.DB) *RoleRepositoryImpl {
+	db.AutoMigrate(&dao.Role{}, &dao.UserRole{})
+	return &RoleRepositoryImpl{
+		db: db,
+	}
+}
+
+var roleRepositorySet = wire.NewSet(
+	RoleRepositoryInit,
+	wire.Bind(new(RoleRepository), new(*RoleRepositoryImpl)),
+)
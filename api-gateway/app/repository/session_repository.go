@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"api-gateway/app/domain/dao"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Save(session *dao.Session) (dao.Session, error)
+	FindSessionById(id uuid.UUID) (dao.Session, error)
+	FindSessionByRefreshTokenHash(hash string) (dao.Session, error)
+	FindActiveSessionsByUserId(userID uuid.UUID) ([]dao.Session, error)
+	// Replaces the refresh token only if the session still holds the given one and is not revoked,
+	// false if another request rotated or revoked the session first
+	RotateRefreshToken(session *dao.Session, currentHash string) (bool, error)
+
+	// Revoked sessions are added to the revocation list until their access tokens expire
+	RevokeSession(id uuid.UUID, tokensExpireAt time.Time) error
+	RevokeSessionsOfUser(userID uuid.UUID, tokensExpireAt time.Time) error
+	IsRevoked(sessionID uuid.UUID) (bool, error)
+}
+
+type SessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func (r SessionRepositoryImpl) Save(session *dao.Session) (dao.Session, error) {
+	if err := r.db.Omit("User").Save(session).Error; err != nil {
+		slog.Error("Got an error when save session.", "error", err)
+		return dao.Session{}, err
+	}
+	return *session, nil
+}
+
+func (r SessionRepositoryImpl) FindSessionById(id uuid.UUID) (dao.Session, error) {
+	session := dao.Session{
+		BaseModel: dao.BaseModel{
+			ID: id,
+		},
+	}
+	if err := r.db.First(&session).Error; err != nil {
+		slog.Error("Got and error when find session by id.", "error", err)
+		return dao.Session{}, err
+	}
+	return session, nil
+}
+
+func (r SessionRepositoryImpl) FindSessionByRefreshTokenHash(hash string) (dao.Session, error) {
+	// the previous hash is matched as well, so the reuse of a rotated token can be detected
+	var session dao.Session
+	err := r.db.Where("refresh_token_hash = ? OR previous_refresh_token_hash = ?", hash, hash).First(&session).Error
+	if err != nil {
+		slog.Error("Got and error when find session by refresh token.", "error", err)
+		return dao.Session{}, err
+	}
+	return session, nil
+}
+
+func (r SessionRepositoryImpl) FindActiveSessionsByUserId(userID uuid.UUID) ([]dao.Session, error) {
+	var sessions []dao.Session
+	err := r.db.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		slog.Error("Got an error finding sessions of user.", "error", err)
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r SessionRepositoryImpl) RotateRefreshToken(session *dao.Session, currentHash string) (bool, error) {
+	// a single conditional update, so concurrent refreshes and revocations cannot be overwritten
+	result := r.db.Model(&dao.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.ID, currentHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":          session.RefreshTokenHash,
+			"previous_refresh_token_hash": session.PreviousRefreshTokenHash,
+			"rotated_at":                  session.RotatedAt,
+			"last_used_at":                session.LastUsedAt,
+			"expires_at":                  session.ExpiresAt,
+			"csrf_token":                  session.CSRFToken,
+		})
+	if result.Error != nil {
+		slog.Error("Got an error when rotate refresh token.", "error", result.Error)
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r SessionRepositoryImpl) RevokeSession(id uuid.UUID, tokensExpireAt time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, tx.Where("id = ?", id), tokensExpireAt)
+	})
+	if err != nil {
+		slog.Error("Got an error when revoke session.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r SessionRepositoryImpl) RevokeSessionsOfUser(userID uuid.UUID, tokensExpireAt time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return revokeSessions(tx, tx.Where("user_id = ?", userID), tokensExpireAt)
+	})
+	if err != nil {
+		slog.Error("Got an error when revoke sessions of user.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r SessionRepositoryImpl) IsRevoked(sessionID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&dao.RevokedToken{}).
+		Where("session_id = ? AND expires_at > ?", sessionID, time.Now()).
+		Count(&count).Error
+	if err != nil {
+		slog.Error("Got an error when check revocation list.", "error", err)
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func revokeSessions(tx *gorm.DB, query *gorm.DB, tokensExpireAt time.Time) error {
+	/**
+	 * Marks the active sessions matching the query as revoked and adds them to the revocation list.
+	 * Expired entries of the revocation list are removed on the way.
+	 **/
+	var ids []uuid.UUID
+	if err := query.Model(&dao.Session{}).Where("revoked_at IS NULL").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, id := range ids {
+		if err := tx.Model(&dao.Session{}).Where("id = ?", id).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(&dao.RevokedToken{SessionID: id, ExpiresAt: tokensExpireAt}).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Where("expires_at <= ?", now).Delete(&dao.RevokedToken{}).Error
+}
+
+func SessionRepositoryInit(db *gorm.DB) *SessionRepositoryImpl {
+	db.AutoMigrate(&dao.Session{}, &dao.RevokedToken{})
+	return &SessionRepositoryImpl{
+		db: db,
+	}
+}
+
+var sessionRepositorySet = wire.NewSet(
+	SessionRepositoryInit,
+	wire.Bind(new(SessionRepository), new(*SessionRepositoryImpl)),
+)
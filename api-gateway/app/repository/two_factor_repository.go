@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"api-gateway/app/domain/dao"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository interface {
+	FindTwoFactorByUserID(userID uuid.UUID) (dao.TwoFactor, error)
+	// Starts a new enrollment, an existing second factor of the user is replaced
+	Save(twoFactor *dao.TwoFactor) (dao.TwoFactor, error)
+	Enable(userID uuid.UUID, step int64) error
+	// Stores the step of an accepted code, fails with ErrRecordNotFound if a code of the step was used already
+	UseStep(userID uuid.UUID, step int64) error
+	// Removes the second factor and the recovery codes of a user
+	DeleteTwoFactor(userID uuid.UUID) error
+
+	// Replaces the recovery codes of a user
+	ReplaceRecoveryCodes(userID uuid.UUID, hashes []string) error
+	// Marks an unused recovery code as used, fails with ErrRecordNotFound if there is none
+	UseRecoveryCode(userID uuid.UUID, hash string) error
+
+	SaveLoginChallenge(challenge *dao.LoginChallenge) (dao.LoginChallenge, error)
+	FindLoginChallengeByTokenHash(hash string) (dao.LoginChallenge, error)
+	// Removes the challenge, fails with ErrRecordNotFound if it was removed already
+	DeleteLoginChallenge(id uuid.UUID) error
+}
+
+type TwoFactorRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func (r TwoFactorRepositoryImpl) FindTwoFactorByUserID(userID uuid.UUID) (dao.TwoFactor, error) {
+	var twoFactor dao.TwoFactor
+	if err := r.db.Where("user_id = ?", userID).First(&twoFactor).Error; err != nil {
+		slog.Error("Got and error when find two factor by user id.", "error", err)
+		return dao.TwoFactor{}, err
+	}
+	return twoFactor, nil
+}
+
+func (r TwoFactorRepositoryImpl) Save(twoFactor *dao.TwoFactor) (dao.TwoFactor, error) {
+	if err := r.db.Save(twoFactor).Error; err != nil {
+		slog.Error("Got an error when save two factor.", "error", err)
+		return dao.TwoFactor{}, err
+	}
+	return *twoFactor, nil
+}
+
+func (r TwoFactorRepositoryImpl) Enable(userID uuid.UUID, step int64) error {
+	result := r.db.Model(&dao.TwoFactor{}).
+		Where("user_id = ? AND enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{"enabled_at": time.Now(), "last_used_step": step})
+	if result.Error != nil {
+		slog.Error("Got an error when enable two factor.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r TwoFactorRepositoryImpl) UseStep(userID uuid.UUID, step int64) error {
+	// the condition on the last step prevents that concurrent requests use the same code twice
+	result := r.db.Model(&dao.TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		slog.Error("Got an error when use two factor step.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r TwoFactorRepositoryImpl) DeleteTwoFactor(userID uuid.UUID) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&dao.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&dao.TwoFactor{}).Error
+	})
+	if err != nil {
+		slog.Error("Got an error when delete two factor.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r TwoFactorRepositoryImpl) ReplaceRecoveryCodes(userID uuid.UUID, hashes []string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&dao.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		for _, hash := range hashes {
+			if err := tx.Omit("User").Create(&dao.RecoveryCode{UserID: userID, CodeHash: hash}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Got an error when replace recovery codes.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r TwoFactorRepositoryImpl) UseRecoveryCode(userID uuid.UUID, hash string) error {
+	result := r.db.Model(&dao.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		slog.Error("Got an error when use recovery code.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r TwoFactorRepositoryImpl) SaveLoginChallenge(challenge *dao.LoginChallenge) (dao.LoginChallenge, error) {
+	if err := r.db.Omit("User").Save(challenge).Error; err != nil {
+		slog.Error("Got an error when save login challenge.", "error", err)
+		return dao.LoginChallenge{}, err
+	}
+	return *challenge, nil
+}
+
+func (r TwoFactorRepositoryImpl) FindLoginChallengeByTokenHash(hash string) (dao.LoginChallenge, error) {
+	var challenge dao.LoginChallenge
+	if err := r.db.Where("token_hash = ?", hash).First(&challenge).Error; err != nil {
+		slog.Error("Got and error when find login challenge by token.", "error", err)
+		return dao.LoginChallenge{}, err
+	}
+	return challenge, nil
+}
+
+func (r TwoFactorRepositoryImpl) DeleteLoginChallenge(id uuid.UUID) error {
+	result := r.db.Delete(&dao.LoginChallenge{}, id)
+	if result.Error != nil {
+		slog.Error("Got an error when delete login challenge.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func TwoFactorRepositoryInit(db *gorm.DB) *TwoFactorRepositoryImpl {
+	db.AutoMigrate(&dao.TwoFactor{}, &dao.RecoveryCode{}, &dao.LoginChallenge{})
+	return &TwoFactorRepositoryImpl{
+		db: db,
+	}
+}
+
+var twoFactorRepositorySet = wire.NewSet(
+	TwoFactorRepositoryInit,
+	wire.Bind(new(TwoFactorRepository), new(*TwoFactorRepositoryImpl)),
+)
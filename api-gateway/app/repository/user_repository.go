@@ -15,19 +15,46 @@ type UserRepository interface {
 	Save(user *dao.User) (dao.User, error)
 	DeleteUser(id uuid.UUID) error
 	FindUserByUsername(username string) (dao.User, error)
+	FindUserByExternalIdentity(provider string, subject string) (dao.User, error)
+	FindUserByPersonID(personID string) (dao.User, error)
+	UpdatePassword(id uuid.UUID, hash string, mustChangePassword bool) error
 
 	AddPermissionToUser(userID uuid.UUID, permissionID uuid.UUID) error
 	DeletePermissionFromUser(userID uuid.UUID, permissionID uuid.UUID) error
+
+	AddRoleToUser(userRole *dao.UserRole) error
+	DeleteRoleFromUser(userID uuid.UUID, roleID uuid.UUID, departmentID *uuid.UUID) error
+	// Replaces the provisioned roles of a user, roles assigned through the API are kept
+	SyncProvisionedRoles(userID uuid.UUID, roles []dao.UserRole) error
+
+	// Adds the user to a department or changes the role of the membership
+	SaveMembership(membership *dao.DepartmentMembership) error
+	DeleteMembership(userID uuid.UUID, departmentID uuid.UUID) error
+
+	// Links the user to a person of the planner-backend, nil removes the link
+	SetPersonID(userID uuid.UUID, personID *string) error
 }
 
 type UserRepositoryImpl struct {
 	db *gorm.DB
 }
 
+func (u UserRepositoryImpl) withRelations() *gorm.DB {
+	// Loads the relations needed to resolve the effective permissions of a user
+	return u.db.
+		Preload("Permissions").
+		Preload("Department").
+		Preload("Roles.Role.Permissions").
+		Preload("Roles.Department").
+		Preload("Memberships.Department").
+		Preload("Memberships.Role.Permissions").
+		Preload("TwoFactor")
+}
+
 func (u UserRepositoryImpl) FindAllUsers() ([]dao.User, error) {
 	var users []dao.User
 
-	if err := u.db.Preload("Permissions").Preload("Department").Find(&users).Error; err != nil {
+	if err := u.withRelations().Find(&users).Error; err != nil {
 		slog.Error("Got an error finding all couples.", "error", err)
 		return nil, err
 	}
@@ -39,7 +66,7 @@ func (u UserRepositoryImpl) FindUserByUsername(username string) (dao.User, error
 	user := dao.User{
 		Username: username,
 	}
-	err := u.db.Preload("Permissions").Preload("Department").Where("username = ?", username).First(&user).Error
+	err := u.withRelations().Where("username = ?", username).First(&user).Error
 	if err != nil {
 		slog.Error("Got and error when find user by username.", "error", err)
 		return dao.User{}, err
@@ -47,13 +74,33 @@ func (u UserRepositoryImpl) FindUserByUsername(username string) (dao.User, error
 	return user, nil
 }
 
+func (u UserRepositoryImpl) FindUserByExternalIdentity(provider string, subject string) (dao.User, error) {
+	var user dao.User
+	err := u.withRelations().Where("auth_provider = ? AND external_subject = ?", provider, subject).First(&user).Error
+	if err != nil {
+		slog.Error("Got and error when find user by external identity.", "error", err)
+		return dao.User{}, err
+	}
+	return user, nil
+}
+
+func (u UserRepositoryImpl) FindUserByPersonID(personID string) (dao.User, error) {
+	var user dao.User
+	err := u.withRelations().Where("person_id = ?", personID).First(&user).Error
+	if err != nil {
+		slog.Error("Got and error when find user by person id.", "error", err)
+		return dao.User{}, err
+	}
+	return user, nil
+}
+
 func (u UserRepositoryImpl) FindUserById(id uuid.UUID) (dao.User, error) {
 	user := dao.User{
 		BaseModel: dao.BaseModel{
 			ID: id,
 		},
 	}
-	err := u.db.Preload("Permissions").Preload("Department").First(&user).Error
+	err := u.withRelations().First(&user).Error
 	if err != nil {
 		slog.Error("Got and error when find user by id.", "error", err)
 		return dao.User{}, err
@@ -62,13 +109,29 @@ func (u UserRepositoryImpl) FindUserById(id uuid.UUID) (dao.User, error) {
 }
 
 func (u UserRepositoryImpl) Save(user *dao.User) (dao.User, error) {
-	if err := u.db.Preload("Department").Preload("Permissions").Save(user).Error; err != nil {
+	// roles, memberships and the second factor are managed by their own methods
+	if err := u.db.Omit("Roles", "Memberships", "TwoFactor").Save(user).Error; err != nil {
 		slog.Error("Got an error when save user.", "error", err)
 		return dao.User{}, err
 	}
 	return *user, nil
 }
 
+func (u UserRepositoryImpl) UpdatePassword(id uuid.UUID, hash string, mustChangePassword bool) error {
+	result := u.db.Model(&dao.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":             hash,
+		"must_change_password": mustChangePassword,
+	})
+	if result.Error != nil {
+		slog.Error("Got an error when update password of user.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (u UserRepositoryImpl) DeleteUser(id uuid.UUID) error {
 	err := u.db.Delete(&dao.User{}, id).Error
 	if err != nil {
@@ -86,7 +149,7 @@ func (u UserRepositoryImpl) AddPermissionToUser(userID uuid.UUID, permissionID u
 	}
 	permission := dao.Permission{
 		BaseModel: dao.BaseModel{
-			ID: userID,
+			ID: permissionID,
 		},
 	}
 	err := u.db.Model(&user).Association("Permissions").Append(&permission)
@@ -105,7 +168,7 @@ func (u UserRepositoryImpl) DeletePermissionFromUser(userID uuid.UUID, permissio
 	}
 	permission := dao.Permission{
 		BaseModel: dao.BaseModel{
-			ID: userID,
+			ID: permissionID,
 		},
 	}
 	err := u.db.Model(&user).Association("Permissions").Delete(&permission)
@@ -116,8 +179,101 @@ func (u UserRepositoryImpl) DeletePermissionFromUser(userID uuid.UUID, permissio
 	return nil
 }
 
+func (u UserRepositoryImpl) AddRoleToUser(userRole *dao.UserRole) error {
+	if err := u.db.Create(userRole).Error; err != nil {
+		slog.Error("Got an error when add role to user.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (u UserRepositoryImpl) DeleteRoleFromUser(userID uuid.UUID, roleID uuid.UUID, departmentID *uuid.UUID) error {
+	query := u.db.Where("user_id = ? AND role_id = ?", userID, roleID)
+	if departmentID == nil {
+		query = query.Where("department_id IS NULL")
+	} else {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+
+	result := query.Delete(&dao.UserRole{})
+	if result.Error != nil {
+		slog.Error("Got an error when remove role from user.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (u UserRepositoryImpl) SyncProvisionedRoles(userID uuid.UUID, roles []dao.UserRole) error {
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND provisioned = ?", userID, true).Delete(&dao.UserRole{}).Error; err != nil {
+			return err
+		}
+		for i := range roles {
+			roles[i].UserID = userID
+			roles[i].Provisioned = true
+			if err := tx.Omit("Role", "Department").Create(&roles[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Got an error when sync provisioned roles of user.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (u UserRepositoryImpl) SaveMembership(membership *dao.DepartmentMembership) error {
+	var existing dao.DepartmentMembership
+	err := u.db.Where("user_id = ? AND department_id = ?", membership.UserID, membership.DepartmentID).First(&existing).Error
+	switch err {
+	case nil:
+		membership.ID = existing.ID
+		membership.CreatedAt = existing.CreatedAt
+	case gorm.ErrRecordNotFound:
+		break
+	default:
+		slog.Error("Got an error when find membership of user.", "error", err)
+		return err
+	}
+
+	if err := u.db.Omit("Department", "Role").Save(membership).Error; err != nil {
+		slog.Error("Got an error when save membership of user.", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (u UserRepositoryImpl) DeleteMembership(userID uuid.UUID, departmentID uuid.UUID) error {
+	result := u.db.Where("user_id = ? AND department_id = ?", userID, departmentID).Delete(&dao.DepartmentMembership{})
+	if result.Error != nil {
+		slog.Error("Got an error when delete membership of user.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (u UserRepositoryImpl) SetPersonID(userID uuid.UUID, personID *string) error {
+	result := u.db.Model(&dao.User{}).Where("id = ?", userID).Update("person_id", personID)
+	if result.Error != nil {
+		slog.Error("Got an error when set person of user.", "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func UserRepositoryInit(db *gorm.DB) *UserRepositoryImpl {
-	db.AutoMigrate(&dao.User{})
+	db.AutoMigrate(&dao.User{}, &dao.DepartmentMembership{})
 	return &UserRepositoryImpl{
 		db: db,
 	}
@@ -1,37 +1,80 @@
 package router
 
 import (
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/metrics"
 	"api-gateway/app/middleware"
+	"api-gateway/app/ratelimit"
 	"api-gateway/config"
-	"net/http/httputil"
-	"net/url"
-	"os"
+	"log/slog"
 
 	"github.com/gin-gonic/gin"
 )
 
 func Init(init *config.Injector) *gin.Engine {
 	// set gin to release mode
-	if os.Getenv("MODE") == "production" {
+	if init.Config.Production() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
 
+	// the client IP of logs and rate limits is only taken from X-Forwarded-For if the request comes from a trusted proxy
+	if err := router.SetTrustedProxies(init.Server.TrustedProxies); err != nil {
+		slog.Error("Invalid trusted proxies", "variable", "GATEWAY_TRUSTED_PROXIES", "error", err)
+		panic(err)
+	}
+
 	// gin Middlewares
 	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.Metrics())
+	// failed requests are answered by this middleware, it also recovers from panics
+	router.Use(middleware.ErrorHandler())
 
 	// insert custom middlewares here
-	router.Use(middleware.Cors())
+	router.Use(middleware.Cors(init.Config.AllowedOrigins))
+	router.Use(middleware.SecurityHeaders(init.Server.HSTSMaxAge))
+	// mutating requests authenticated by the cookie have to carry the CSRF token of the session
+	router.Use(middleware.VerifyCSRF())
+
+	// scraped by Prometheus, protected by GATEWAY_METRICS_TOKEN if it is set
+	router.GET("/metrics", middleware.RequiredMetricsToken(init.Config.MetricsToken), metrics.Handler())
+
+	// public keys of the access tokens, used by the planner-backend to verify them
+	router.GET("/.well-known/jwks.json", init.SystemCtrl.JWKS)
+
+	// changes of the departments reported by the planner-backend
+	internal := router.Group("/internal", middleware.RequiredService(dco.PlannerServiceName))
+	{
+		internal.PUT("/department/:plannerID", init.DepartmentCtrl.Sync)
+		internal.DELETE("/department/:plannerID", init.DepartmentCtrl.Remove)
+	}
 
 	auth := router.Group("/auth")
 	{
-		auth.POST("/login", init.UserCtrl.Login)
-		auth.POST("/logout", init.UserCtrl.Logout)
-		auth.Use(middleware.RequiredAuth())
+		// the public routes share the auth budget of the client IP
+		authLimit := middleware.RateLimit(init.RateLimiter, ratelimit.Auth)
+		auth.POST("/login", authLimit, init.UserCtrl.Login)
+		auth.POST("/login/two-factor", authLimit, init.UserCtrl.LoginTwoFactor)
+		auth.POST("/logout", authLimit, init.UserCtrl.Logout)
+		auth.POST("/refresh", authLimit, init.UserCtrl.Refresh)
+		auth.POST("/password/reset", authLimit, init.UserCtrl.ResetPassword)
+		auth.GET("/providers", authLimit, init.UserCtrl.Providers)
+		auth.GET("/:provider/login", authLimit, init.UserCtrl.ExternalLogin)
+		auth.GET("/:provider/callback", authLimit, init.UserCtrl.ExternalCallback)
+		// the account is managed within a session, API keys are not accepted here
+		auth.Use(middleware.RequiredAuth(init.SessionRepository, nil))
+		auth.Use(middleware.RateLimit(init.RateLimiter, ""))
 		auth.GET("/me", init.UserCtrl.Me) // ?department=XXX
 		auth.GET("/check-admin", init.UserCtrl.CheckAdmin)
+		auth.GET("/permissions", init.UserCtrl.Permissions)
+		auth.GET("/sessions", init.UserCtrl.Sessions)
+		auth.DELETE("/sessions/:sessionID", init.UserCtrl.RevokeSession)
+		auth.PUT("/password", init.UserCtrl.ChangePassword)
+		auth.POST("/two-factor", init.UserCtrl.EnrollTwoFactor)
+		auth.POST("/two-factor/confirm", init.UserCtrl.ConfirmTwoFactor)
+		auth.DELETE("/two-factor", init.UserCtrl.DisableTwoFactor)
+		auth.POST("/two-factor/recovery-codes", init.UserCtrl.RegenerateRecoveryCodes)
 	}
 
 	/** These API requests stay here and are handled by api-gateway */
@@ -40,48 +83,99 @@ func Init(init *config.Injector) *gin.Engine {
 		gatewayAPI.GET("/ping", init.SystemCtrl.Ping)
 
 		// Secured routes
-		gatewayAPI.Use(middleware.RequiredAuth())
+		gatewayAPI.Use(middleware.RequiredAuth(init.SessionRepository, init.APIKeyRepository))
+		// the read and write budgets of the user or API key
+		gatewayAPI.Use(middleware.RateLimit(init.RateLimiter, ""))
+		gatewayAPI.Use(middleware.RequirePasswordChanged())
+		gatewayAPI.Use(middleware.RequireTwoFactorEnrolled())
 		user := gatewayAPI.Group("/user")
 		{
 			user.GET("", init.UserCtrl.GetAll)
 			user.GET("/:userID", init.UserCtrl.Get)
-			user.POST("", init.UserCtrl.Create)
-			user.PUT("/:userID", init.UserCtrl.Update)
-			user.DELETE("/:userID", init.UserCtrl.Delete)
+			user.POST("", middleware.RequirePermission("user:write"), init.UserCtrl.Create)
+			user.PUT("/:userID", middleware.RequirePermissionInAnyDepartment("user:write"), init.UserCtrl.Update)
+			user.DELETE("/:userID", middleware.RequirePermissionInAnyDepartment("user:write"), init.UserCtrl.Delete)
+			// force the logout of a user
+			user.DELETE("/:userID/sessions", middleware.RequirePermission("session:revoke"), init.UserCtrl.RevokeUserSessions)
+			// send a password reset link to the user
+			user.POST("/:userID/password-reset", middleware.RequirePermissionInAnyDepartment("user:write"), init.UserCtrl.RequestPasswordReset)
+			// unlock a user locked after too many failed logins
+			user.DELETE("/:userID/lockout", middleware.RequirePermissionInAnyDepartment("user:write"), init.UserCtrl.Unlock)
+			// remove the second factor of a user who lost the device
+			user.DELETE("/:userID/two-factor", middleware.RequirePermissionInAnyDepartment("user:write"), init.UserCtrl.ResetTwoFactor)
+			// manage the API keys of a service account
+			user.GET("/:userID/api-key", middleware.RequirePermission("user:write"), init.UserCtrl.GetAPIKeys)
+			user.POST("/:userID/api-key", middleware.RequirePermission("user:write"), init.UserCtrl.CreateAPIKey)
+			user.DELETE("/:userID/api-key/:apiKeyID", middleware.RequirePermission("user:write"), init.UserCtrl.RevokeAPIKey)
 		}
-		userPermission := user.Group("/:userID/permission")
-		userPermission.POST("/:permissionId", init.UserCtrl.AddPermission)
-		userPermission.DELETE("/:permissionId", init.UserCtrl.DeletePermission)
+		// a permission granted directly is as powerful as a role
+		userPermission := user.Group("/:userID/permission", middleware.RequirePermission("role:write"))
+		userPermission.POST("/:permissionID", init.UserCtrl.AddPermission)
+		userPermission.DELETE("/:permissionID", init.UserCtrl.DeletePermission)
+		userRole := user.Group("/:userID/role", middleware.RequirePermission("role:write"))
+		userRole.POST("/:roleID", init.UserCtrl.AddRole) // ?department_id=XXX
+		userRole.DELETE("/:roleID", init.UserCtrl.DeleteRole)
+		// further departments of the user, with a role within each of them
+		userDepartment := user.Group("/:userID/department", middleware.RequirePermissionInAnyDepartment("user:write"))
+		userDepartment.PUT("/:departmentID", init.UserCtrl.SaveMembership)
+		userDepartment.DELETE("/:departmentID", init.UserCtrl.DeleteMembership)
+		// the person of the planner-backend linked to the user
+		userPerson := user.Group("/:userID/person", middleware.RequirePermission("user:write"))
+		userPerson.PUT("", init.UserCtrl.LinkPerson)
+		userPerson.DELETE("", init.UserCtrl.UnlinkPerson)
+
+		// planner data of the person linked to the logged in user
+		me := gatewayAPI.Group("/me")
+		me.GET("/assignments", init.MeCtrl.Assignments) // ?start_date=...&end_date=...
+		me.GET("/absences", init.MeCtrl.Absences)       // ?start_date=...&end_date=...
+		me.GET("/hours", init.MeCtrl.Hours)             // ?start_date=...&end_date=...
+		me.GET("/leave-requests", init.MeCtrl.LeaveRequests)
+		me.POST("/leave-requests", init.MeCtrl.RequestLeave)
+
+		// API keys of the logged in user
+		apiKey := gatewayAPI.Group("/api-key")
+		apiKey.GET("", init.UserCtrl.GetAPIKeys)
+		apiKey.POST("", init.UserCtrl.CreateAPIKey)
+		apiKey.DELETE("/:apiKeyID", init.UserCtrl.RevokeAPIKey)
 
 		department := gatewayAPI.Group("/department")
 		department.GET("", init.DepartmentCtrl.GetAll)
 		department.GET("/:departmentID", init.DepartmentCtrl.Get)
 
-		department.POST("", init.DepartmentCtrl.Create)
-		department.PUT("/:departmentID", init.DepartmentCtrl.Update)
-		department.DELETE("/:departmentID", init.DepartmentCtrl.Delete)
+		department.POST("", middleware.RequirePermission("department:write"), init.DepartmentCtrl.Create)
+		department.PUT("/:departmentID", middleware.RequirePermission("department:write"), init.DepartmentCtrl.Update)
+		department.DELETE("/:departmentID", middleware.RequirePermission("department:write"), init.DepartmentCtrl.Delete)
+		// repair the departments of the planner-backend
+		department.POST("/reconcile", middleware.RequirePermission("department:write"), init.DepartmentCtrl.Reconcile)
 
 		permission := gatewayAPI.Group("/permission")
 		permission.GET("", init.PermissionCtrl.GetAll)
 		permission.GET("/:permissionID", init.PermissionCtrl.Get)
-		permission.POST("", init.PermissionCtrl.Create)
-		permission.PUT("/:permissionID", init.PermissionCtrl.Update)
-		permission.DELETE("/:permissionID", init.PermissionCtrl.Delete)
-	}
+		// the permissions are granted through the roles, so they are managed like them
+		permission.POST("", middleware.RequirePermission("role:write"), init.PermissionCtrl.Create)
+		permission.PUT("/:permissionID", middleware.RequirePermission("role:write"), init.PermissionCtrl.Update)
+		permission.DELETE("/:permissionID", middleware.RequirePermission("role:write"), init.PermissionCtrl.Delete)
 
-	/** These API requests are forwarded to planner-backend service */
-	// TODO: Add middleware to check if the user has permission to access the planner-backend
-	// The problem is that GET requests should be allowed for everyone, but POST, PUT, DELETE should be allowed only for users with the right permissions
-	// The middleware should check the user's permissions and the request method
-	plannerAPI := router.Group("/api/v1/planner")
-	{
-		targetStr := os.Getenv("PLANNER_BACKEND_TARGET")
-		url, _ := url.Parse(targetStr)
-		proxy := httputil.NewSingleHostReverseProxy(url)
-		plannerAPI.Any("/*any", func(c *gin.Context) {
-			proxy.ServeHTTP(c.Writer, c.Request)
-		})
+		role := gatewayAPI.Group("/role")
+		role.GET("", init.RoleCtrl.GetAll)
+		role.GET("/:roleID", init.RoleCtrl.Get)
+		role.POST("", middleware.RequirePermission("role:write"), init.RoleCtrl.Create)
+		role.PUT("/:roleID", middleware.RequirePermission("role:write"), init.RoleCtrl.Update)
+		role.DELETE("/:roleID", middleware.RequirePermission("role:write"), init.RoleCtrl.Delete)
 	}
 
+	/** The other requests are forwarded by the route table, e.g. /api/v1/planner to the planner-backend */
+	// The routes are reloaded at runtime, so they are resolved per request instead of being registered
+	// The policy of the route decides which permissions are required for a given method and path
+	router.NoRoute(
+		init.Routes.Match,
+		middleware.ForwardIdentity(init.SessionRepository, init.APIKeyRepository),
+		middleware.RateLimit(init.RateLimiter, ""),
+		middleware.RequirePasswordChanged(),
+		middleware.RequireTwoFactorEnrolled(),
+		middleware.RoutePolicy(init.Planner),
+		init.Routes.Forward,
+	)
+
 	return router
 }
@@ -1,20 +1,37 @@
 package router
 
 import (
+	"api-gateway/app/controller"
 	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/jwtkeys"
+	"api-gateway/app/middleware"
 	"api-gateway/app/mock"
+	"api-gateway/app/proxy"
 	"api-gateway/config"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 var token string
-var authErrorString = "{\"response_key\":\"Unauthorized\",\"response_message\":\"Unauthorized\",\"data\":null}"
+var adminToken string
+var mustChangePasswordToken string
+var mustEnrollTwoFactorToken string
+var authErrorString = "{\"response_key\":\"Unauthorized\",\"response_message\":\"Unauthorized: Please check your credentials\",\"code\":\"unauthorized\",\"data\":null,\"errors\":[]}"
+var forbiddenErrorString = "{\"response_key\":\"Forbidden\",\"response_message\":\"Forbidden: Missing permission\",\"code\":\"forbidden\",\"data\":null,\"errors\":[]}"
+var passwordChangeRequiredErrorString = "{\"response_key\":\"Password Change Required\",\"response_message\":\"Password Change Required: Please change your password\",\"code\":\"password_change_required\",\"data\":null,\"errors\":[]}"
+var twoFactorRequiredErrorString = "{\"response_key\":\"Two Factor Required\",\"response_message\":\"Two Factor Required: Please enable two-factor authentication\",\"code\":\"two_factor_required\",\"data\":null,\"errors\":[]}"
+
+var sessionRepository = mock.NewSessionRepositoryMock()
+var apiKeyRepository = mock.NewAPIKeyRepositoryMock()
 
 func TestMain(m *testing.M) {
 	// Generate a mock token
@@ -27,6 +44,29 @@ func TestMain(m *testing.M) {
 
 	token = t
 
+	adminToken, err = mock.GenerateMockToken(dao.User{Username: "admin", Roles: mock.SystemAdminRoles})
+	if err != nil {
+		fmt.Printf("Error generating token: %v", err)
+		os.Exit(1)
+	}
+
+	mustChangePasswordToken, err = mock.GenerateMockToken(dao.User{Username: "admin", Roles: mock.SystemAdminRoles, MustChangePassword: true})
+	if err != nil {
+		fmt.Printf("Error generating token: %v", err)
+		os.Exit(1)
+	}
+
+	mustEnrollTwoFactorToken, err = mock.GenerateMockToken(dao.User{Username: "test", Roles: []dao.UserRole{{Role: dao.Role{Name: "planner", RequireTwoFactor: true}}}})
+	if err != nil {
+		fmt.Printf("Error generating token: %v", err)
+		os.Exit(1)
+	}
+
+	apiKeyRepository.On("FindAPIKeyByHash").Return(dao.APIKey{
+		User:      dao.User{Username: "impt", AuthProvider: dao.AuthProviderServiceAccount},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
 	// Run the tests and exit
 	os.Exit(m.Run())
 	token = ""
@@ -37,6 +77,13 @@ type RouterTest struct {
 	url              string // /api/v1/user
 	expectedResponse string // {"message": "GetAll"}
 	shouldLogin      bool
+	asAdmin          bool
+	// uses a token of a user who has to change the password
+	mustChangePassword bool
+	// uses a token of a user whose role requires two-factor authentication
+	mustEnrollTwoFactor bool
+	// sends an API key as bearer token instead of the cookie
+	withAPIKey bool
 }
 
 func TestRouter(t *testing.T) {
@@ -47,15 +94,25 @@ func TestRouter(t *testing.T) {
 		DepartmentCtrl: &mock.DepartmentControllerMock{},
 		UserCtrl:       &mock.UserControllerMock{},
 		PermissionCtrl: &mock.PermissionControllerMock{},
+		RoleCtrl:       &mock.RoleControllerMock{},
+		MeCtrl:         &mock.MeControllerMock{},
+
+		SessionRepository: &sessionRepository,
+		APIKeyRepository:  &apiKeyRepository,
+		// the planner route without a target
+		Routes: plannerTable(""),
 	}
 
 	t.Run("Test Department Routes", func(t *testing.T) {
 		var testSteps = []RouterTest{
 			{httpMethod: "GET", url: "/api/v1/department", expectedResponse: "{\"message\":\"GetAll\"}", shouldLogin: true},
 			{httpMethod: "GET", url: "/api/v1/department/1", expectedResponse: "{\"message\":\"Get\"}", shouldLogin: true},
-			{httpMethod: "POST", url: "/api/v1/department", expectedResponse: "{\"message\":\"Create\"}", shouldLogin: true},
-			{httpMethod: "PUT", url: "/api/v1/department/1", expectedResponse: "{\"message\":\"Update\"}", shouldLogin: true},
-			{httpMethod: "DELETE", url: "/api/v1/department/1", expectedResponse: "{\"message\":\"Delete\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/department", expectedResponse: "{\"message\":\"Create\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "PUT", url: "/api/v1/department/1", expectedResponse: "{\"message\":\"Update\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/department/1", expectedResponse: "{\"message\":\"Delete\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "POST", url: "/api/v1/department", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "PUT", url: "/api/v1/department/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/department/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
 			{httpMethod: "DELETE", url: "/api/v1/department/1", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "GET", url: "/api/v1/department", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "GET", url: "/api/v1/department/1", expectedResponse: authErrorString, shouldLogin: false},
@@ -70,10 +127,15 @@ func TestRouter(t *testing.T) {
 
 			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
 			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
 				req.AddCookie(&http.Cookie{
 					Name:  "Authorization",
-					Value: token,
+					Value: value,
 				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
 			}
 
 			router.ServeHTTP(w, req)
@@ -89,11 +151,17 @@ func TestRouter(t *testing.T) {
 		var testSteps = []RouterTest{
 			{httpMethod: "GET", url: "/api/v1/user", expectedResponse: "{\"message\":\"GetAll\"}", shouldLogin: true},
 			{httpMethod: "GET", url: "/api/v1/user/1", expectedResponse: "{\"message\":\"Get\"}", shouldLogin: true},
-			{httpMethod: "POST", url: "/api/v1/user", expectedResponse: "{\"message\":\"Create\"}", shouldLogin: true},
-			{httpMethod: "PUT", url: "/api/v1/user/1", expectedResponse: "{\"message\":\"Update\"}", shouldLogin: true},
-			{httpMethod: "DELETE", url: "/api/v1/user/1", expectedResponse: "{\"message\":\"Delete\"}", shouldLogin: true},
-			{httpMethod: "POST", url: "/api/v1/user/1/permission/1", expectedResponse: "{\"message\":\"AddPermission\"}", shouldLogin: true},
-			{httpMethod: "DELETE", url: "/api/v1/user/1/permission/1", expectedResponse: "{\"message\":\"DeletePermission\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/user", expectedResponse: "{\"message\":\"Create\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "PUT", url: "/api/v1/user/1", expectedResponse: "{\"message\":\"Update\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1", expectedResponse: "{\"message\":\"Delete\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "POST", url: "/api/v1/user/1/permission/1", expectedResponse: "{\"message\":\"AddPermission\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/permission/1", expectedResponse: "{\"message\":\"DeletePermission\"}", shouldLogin: true, asAdmin: true},
+			// a viewer must not grant permissions to anyone, themselves included
+			{httpMethod: "POST", url: "/api/v1/user", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "PUT", url: "/api/v1/user/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/user/1/permission/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/permission/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
 			{httpMethod: "GET", url: "/api/v1/user", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "GET", url: "/api/v1/user/detail", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "POST", url: "/api/v1/user", expectedResponse: authErrorString, shouldLogin: false},
@@ -101,6 +169,291 @@ func TestRouter(t *testing.T) {
 			{httpMethod: "DELETE", url: "/api/v1/user/1", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "POST", url: "/api/v1/user/1/permission/1", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "DELETE", url: "/api/v1/user/1/permission/1", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "PUT", url: "/api/v1/user/1/department/1", expectedResponse: "{\"message\":\"SaveMembership\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/department/1", expectedResponse: "{\"message\":\"DeleteMembership\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "PUT", url: "/api/v1/user/1/department/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "PUT", url: "/api/v1/user/1/department/1", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/department/1", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "PUT", url: "/api/v1/user/1/person", expectedResponse: "{\"message\":\"LinkPerson\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/person", expectedResponse: "{\"message\":\"UnlinkPerson\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "PUT", url: "/api/v1/user/1/person", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/person", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "GET", url: "/api/v1/me/assignments", expectedResponse: "{\"message\":\"Assignments\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/api/v1/me/absences", expectedResponse: "{\"message\":\"Absences\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/api/v1/me/hours", expectedResponse: "{\"message\":\"Hours\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/api/v1/me/leave-requests", expectedResponse: "{\"message\":\"LeaveRequests\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/me/leave-requests", expectedResponse: "{\"message\":\"RequestLeave\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/me/leave-requests", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "GET", url: "/api/v1/me/assignments", expectedResponse: authErrorString, shouldLogin: false},
+		}
+
+		for i, testStep := range testSteps {
+			router := Init(init)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
+			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
+				req.AddCookie(&http.Cookie{
+					Name:  "Authorization",
+					Value: value,
+				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+			}
+
+			router.ServeHTTP(w, req)
+
+			if w.Body.String() != testStep.expectedResponse {
+				t.Errorf("Expected body to be %v, got %v", testStep.expectedResponse, w.Body.String())
+			}
+			t.Logf("Test %v passed", i)
+		}
+	})
+
+	t.Run("Test Auth Routes", func(t *testing.T) {
+		var testSteps = []RouterTest{
+			{httpMethod: "GET", url: "/auth/me", expectedResponse: "{\"message\":\"Me\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/auth/permissions", expectedResponse: "{\"message\":\"Permissions\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/auth/me", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "GET", url: "/auth/permissions", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "POST", url: "/auth/refresh", expectedResponse: "{\"message\":\"Refresh\"}", shouldLogin: false},
+			{httpMethod: "GET", url: "/auth/sessions", expectedResponse: "{\"message\":\"Sessions\"}", shouldLogin: true},
+			{httpMethod: "DELETE", url: "/auth/sessions/1", expectedResponse: "{\"message\":\"RevokeSession\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/auth/sessions", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/sessions", expectedResponse: "{\"message\":\"RevokeUserSessions\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/sessions", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "GET", url: "/auth/providers", expectedResponse: "{\"message\":\"Providers\"}", shouldLogin: false},
+			{httpMethod: "GET", url: "/auth/keycloak/login", expectedResponse: "{\"message\":\"ExternalLogin\"}", shouldLogin: false},
+			{httpMethod: "GET", url: "/auth/keycloak/callback", expectedResponse: "{\"message\":\"ExternalCallback\"}", shouldLogin: false},
+		}
+
+		for i, testStep := range testSteps {
+			router := Init(init)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
+			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
+				req.AddCookie(&http.Cookie{
+					Name:  "Authorization",
+					Value: value,
+				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+			}
+
+			router.ServeHTTP(w, req)
+
+			if w.Body.String() != testStep.expectedResponse {
+				t.Errorf("Expected body to be %v, got %v", testStep.expectedResponse, w.Body.String())
+			}
+			t.Logf("Test %v passed", i)
+		}
+	})
+
+	t.Run("Test Password Routes", func(t *testing.T) {
+		var testSteps = []RouterTest{
+			{httpMethod: "PUT", url: "/auth/password", expectedResponse: "{\"message\":\"ChangePassword\"}", shouldLogin: true},
+			{httpMethod: "PUT", url: "/auth/password", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "POST", url: "/auth/password/reset", expectedResponse: "{\"message\":\"ResetPassword\"}", shouldLogin: false},
+			{httpMethod: "POST", url: "/api/v1/user/1/password-reset", expectedResponse: "{\"message\":\"RequestPasswordReset\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "POST", url: "/api/v1/user/1/password-reset", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/lockout", expectedResponse: "{\"message\":\"Unlock\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/lockout", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			// users who have to change their password can only use the auth routes
+			{httpMethod: "PUT", url: "/auth/password", expectedResponse: "{\"message\":\"ChangePassword\"}", shouldLogin: true, mustChangePassword: true},
+			{httpMethod: "GET", url: "/auth/me", expectedResponse: "{\"message\":\"Me\"}", shouldLogin: true, mustChangePassword: true},
+			{httpMethod: "GET", url: "/api/v1/user", expectedResponse: passwordChangeRequiredErrorString, shouldLogin: true, mustChangePassword: true},
+			{httpMethod: "PUT", url: "/api/v1/planner/workday/1", expectedResponse: passwordChangeRequiredErrorString, shouldLogin: true, mustChangePassword: true},
+		}
+
+		for i, testStep := range testSteps {
+			router := Init(init)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
+			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
+				if testStep.mustChangePassword {
+					value = mustChangePasswordToken
+				}
+				req.AddCookie(&http.Cookie{
+					Name:  "Authorization",
+					Value: value,
+				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+			}
+
+			router.ServeHTTP(w, req)
+
+			if w.Body.String() != testStep.expectedResponse {
+				t.Errorf("Expected body to be %v, got %v", testStep.expectedResponse, w.Body.String())
+			}
+			t.Logf("Test %v passed", i)
+		}
+	})
+
+	t.Run("Test Two Factor Routes", func(t *testing.T) {
+		var testSteps = []RouterTest{
+			{httpMethod: "POST", url: "/auth/login/two-factor", expectedResponse: "{\"message\":\"LoginTwoFactor\"}", shouldLogin: false},
+			{httpMethod: "POST", url: "/auth/two-factor", expectedResponse: "{\"message\":\"EnrollTwoFactor\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/auth/two-factor/confirm", expectedResponse: "{\"message\":\"ConfirmTwoFactor\"}", shouldLogin: true},
+			{httpMethod: "DELETE", url: "/auth/two-factor", expectedResponse: "{\"message\":\"DisableTwoFactor\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/auth/two-factor/recovery-codes", expectedResponse: "{\"message\":\"RegenerateRecoveryCodes\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/auth/two-factor", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "DELETE", url: "/auth/two-factor", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/two-factor", expectedResponse: "{\"message\":\"ResetTwoFactor\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/two-factor", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			// users whose role requires two-factor authentication can only use the auth routes until they enrolled
+			{httpMethod: "POST", url: "/auth/two-factor", expectedResponse: "{\"message\":\"EnrollTwoFactor\"}", shouldLogin: true, mustEnrollTwoFactor: true},
+			{httpMethod: "GET", url: "/auth/me", expectedResponse: "{\"message\":\"Me\"}", shouldLogin: true, mustEnrollTwoFactor: true},
+			{httpMethod: "GET", url: "/api/v1/user", expectedResponse: twoFactorRequiredErrorString, shouldLogin: true, mustEnrollTwoFactor: true},
+			{httpMethod: "PUT", url: "/api/v1/planner/workday/1", expectedResponse: twoFactorRequiredErrorString, shouldLogin: true, mustEnrollTwoFactor: true},
+		}
+
+		for i, testStep := range testSteps {
+			router := Init(init)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
+			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
+				if testStep.mustEnrollTwoFactor {
+					value = mustEnrollTwoFactorToken
+				}
+				req.AddCookie(&http.Cookie{
+					Name:  "Authorization",
+					Value: value,
+				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+			}
+
+			router.ServeHTTP(w, req)
+
+			if w.Body.String() != testStep.expectedResponse {
+				t.Errorf("Expected body to be %v, got %v", testStep.expectedResponse, w.Body.String())
+			}
+			t.Logf("Test %v passed", i)
+		}
+	})
+
+	t.Run("Test API Key Routes", func(t *testing.T) {
+		var testSteps = []RouterTest{
+			{httpMethod: "GET", url: "/api/v1/api-key", expectedResponse: "{\"message\":\"GetAPIKeys\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/api-key", expectedResponse: "{\"message\":\"CreateAPIKey\"}", shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/api-key/1", expectedResponse: "{\"message\":\"RevokeAPIKey\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/api/v1/api-key", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "GET", url: "/api/v1/user/1/api-key", expectedResponse: "{\"message\":\"GetAPIKeys\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "POST", url: "/api/v1/user/1/api-key", expectedResponse: "{\"message\":\"CreateAPIKey\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/api-key/1", expectedResponse: "{\"message\":\"RevokeAPIKey\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "GET", url: "/api/v1/user/1/api-key", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/user/1/api-key", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			// API keys are accepted by the API but not by the auth routes
+			{httpMethod: "GET", url: "/api/v1/department", expectedResponse: "{\"message\":\"GetAll\"}", withAPIKey: true},
+			{httpMethod: "GET", url: "/api/v1/user/1/api-key", expectedResponse: forbiddenErrorString, withAPIKey: true},
+			{httpMethod: "GET", url: "/auth/me", expectedResponse: authErrorString, withAPIKey: true},
+		}
+
+		for i, testStep := range testSteps {
+			router := Init(init)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
+			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
+				req.AddCookie(&http.Cookie{
+					Name:  "Authorization",
+					Value: value,
+				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+			}
+			if testStep.withAPIKey {
+				req.Header.Set("Authorization", "Bearer pk_key")
+			}
+
+			router.ServeHTTP(w, req)
+
+			if w.Body.String() != testStep.expectedResponse {
+				t.Errorf("Expected body to be %v, got %v", testStep.expectedResponse, w.Body.String())
+			}
+			t.Logf("Test %v passed", i)
+		}
+	})
+
+	t.Run("Test CSRF", func(t *testing.T) {
+		csrfErrorString := "{\"response_key\":\"Forbidden\",\"response_message\":\"Invalid CSRF token\",\"code\":\"forbidden\",\"data\":null,\"errors\":[]}"
+
+		type csrfTest struct {
+			httpMethod       string
+			url              string
+			csrfToken        string
+			withCookie       bool
+			withAPIKey       bool
+			expectedResponse string
+		}
+
+		testSteps := []csrfTest{
+			{httpMethod: "POST", url: "/api/v1/api-key", withCookie: true, csrfToken: mock.CSRFToken, expectedResponse: "{\"message\":\"CreateAPIKey\"}"},
+			{httpMethod: "POST", url: "/api/v1/api-key", withCookie: true, expectedResponse: csrfErrorString},
+			{httpMethod: "DELETE", url: "/api/v1/api-key/1", withCookie: true, csrfToken: "forged", expectedResponse: csrfErrorString},
+			{httpMethod: "POST", url: "/auth/logout", withCookie: true, expectedResponse: csrfErrorString},
+			// proxied routes are protected as well
+			{httpMethod: "POST", url: "/api/v1/planner/test", withCookie: true, expectedResponse: csrfErrorString},
+			// reading requests and requests without a session do not need the token
+			{httpMethod: "GET", url: "/api/v1/api-key", withCookie: true, expectedResponse: "{\"message\":\"GetAPIKeys\"}"},
+			{httpMethod: "POST", url: "/api/v1/api-key", expectedResponse: authErrorString},
+			{httpMethod: "POST", url: "/auth/login", expectedResponse: "{\"message\":\"Login\"}"},
+			{httpMethod: "POST", url: "/api/v1/api-key", withAPIKey: true, expectedResponse: "{\"message\":\"CreateAPIKey\"}"},
+		}
+
+		for i, testStep := range testSteps {
+			router := Init(init)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
+			if testStep.withCookie {
+				req.AddCookie(&http.Cookie{Name: "Authorization", Value: token})
+			}
+			if testStep.csrfToken != "" {
+				req.Header.Set(dco.CSRFTokenHeader, testStep.csrfToken)
+			}
+			if testStep.withAPIKey {
+				req.Header.Set("Authorization", "Bearer pk_key")
+			}
+
+			router.ServeHTTP(w, req)
+
+			if w.Body.String() != testStep.expectedResponse {
+				t.Errorf("Step %d: expected body to be %v, got %v", i, testStep.expectedResponse, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("Test Role Routes", func(t *testing.T) {
+		var testSteps = []RouterTest{
+			{httpMethod: "GET", url: "/api/v1/role", expectedResponse: "{\"message\":\"GetAll\"}", shouldLogin: true},
+			{httpMethod: "GET", url: "/api/v1/role/1", expectedResponse: "{\"message\":\"Get\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/role", expectedResponse: "{\"message\":\"Create\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "PUT", url: "/api/v1/role/1", expectedResponse: "{\"message\":\"Update\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/role/1", expectedResponse: "{\"message\":\"Delete\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "POST", url: "/api/v1/user/1/role/1", expectedResponse: "{\"message\":\"AddRole\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/role/1", expectedResponse: "{\"message\":\"DeleteRole\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "POST", url: "/api/v1/role", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "PUT", url: "/api/v1/role/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/role/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/user/1/role/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/user/1/role/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "GET", url: "/api/v1/role", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "POST", url: "/api/v1/role", expectedResponse: authErrorString, shouldLogin: false},
+			{httpMethod: "POST", url: "/api/v1/user/1/role/1", expectedResponse: authErrorString, shouldLogin: false},
 		}
 
 		for i, testStep := range testSteps {
@@ -108,10 +461,15 @@ func TestRouter(t *testing.T) {
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
 			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
 				req.AddCookie(&http.Cookie{
 					Name:  "Authorization",
-					Value: token,
+					Value: value,
 				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
 			}
 
 			router.ServeHTTP(w, req)
@@ -127,9 +485,12 @@ func TestRouter(t *testing.T) {
 		var testSteps = []RouterTest{
 			{httpMethod: "GET", url: "/api/v1/permission", expectedResponse: "{\"message\":\"GetAll\"}", shouldLogin: true},
 			{httpMethod: "GET", url: "/api/v1/permission/1", expectedResponse: "{\"message\":\"Get\"}", shouldLogin: true},
-			{httpMethod: "POST", url: "/api/v1/permission", expectedResponse: "{\"message\":\"Create\"}", shouldLogin: true},
-			{httpMethod: "PUT", url: "/api/v1/permission/1", expectedResponse: "{\"message\":\"Update\"}", shouldLogin: true},
-			{httpMethod: "DELETE", url: "/api/v1/permission/1", expectedResponse: "{\"message\":\"Delete\"}", shouldLogin: true},
+			{httpMethod: "POST", url: "/api/v1/permission", expectedResponse: "{\"message\":\"Create\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "PUT", url: "/api/v1/permission/1", expectedResponse: "{\"message\":\"Update\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "DELETE", url: "/api/v1/permission/1", expectedResponse: "{\"message\":\"Delete\"}", shouldLogin: true, asAdmin: true},
+			{httpMethod: "POST", url: "/api/v1/permission", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "PUT", url: "/api/v1/permission/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
+			{httpMethod: "DELETE", url: "/api/v1/permission/1", expectedResponse: forbiddenErrorString, shouldLogin: true},
 			{httpMethod: "GET", url: "/api/v1/permission", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "GET", url: "/api/v1/permission/1", expectedResponse: authErrorString, shouldLogin: false},
 			{httpMethod: "POST", url: "/api/v1/permission", expectedResponse: authErrorString, shouldLogin: false},
@@ -142,10 +503,15 @@ func TestRouter(t *testing.T) {
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest(testStep.httpMethod, testStep.url, nil)
 			if testStep.shouldLogin {
+				value := token
+				if testStep.asAdmin {
+					value = adminToken
+				}
 				req.AddCookie(&http.Cookie{
 					Name:  "Authorization",
-					Value: token,
+					Value: value,
 				})
+				req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
 			}
 
 			router.ServeHTTP(w, req)
@@ -166,6 +532,10 @@ func TestPing(t *testing.T) {
 		DepartmentCtrl: &mock.DepartmentControllerMock{},
 		UserCtrl:       &mock.UserControllerMock{},
 		PermissionCtrl: &mock.PermissionControllerMock{},
+		RoleCtrl:       &mock.RoleControllerMock{},
+		MeCtrl:         &mock.MeControllerMock{},
+
+		SessionRepository: &sessionRepository,
 	}
 
 	router := Init(init)
@@ -183,6 +553,154 @@ func TestPing(t *testing.T) {
 
 }
 
+func TestMetrics(t *testing.T) {
+	/* The requests are reported by their route template, /metrics requires the token of the scraper */
+	gin.SetMode(gin.TestMode)
+	init := &config.Injector{
+		Config:         config.Config{MetricsToken: "scraper"},
+		SystemCtrl:     &mock.SystemControllerMock{},
+		DepartmentCtrl: &mock.DepartmentControllerMock{},
+		UserCtrl:       &mock.UserControllerMock{},
+		PermissionCtrl: &mock.PermissionControllerMock{},
+		RoleCtrl:       &mock.RoleControllerMock{},
+		MeCtrl:         &mock.MeControllerMock{},
+
+		SessionRepository: &sessionRepository,
+	}
+
+	router := Init(init)
+	req, _ := http.NewRequest("GET", "/api/v1/ping", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	w := httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/metrics", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code 401 without the token, got %v", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req.Header.Set("Authorization", "Bearer scraper")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code 200 got %v", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `http_requests_total{method="GET",route="/api/v1/ping",status="200"}`) {
+		t.Errorf("Expected the requests of /api/v1/ping, got %v", w.Body.String())
+	}
+}
+
+func TestServerSettings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	init := &config.Injector{
+		SystemCtrl:     &mock.SystemControllerMock{},
+		DepartmentCtrl: &mock.DepartmentControllerMock{},
+		UserCtrl:       &mock.UserControllerMock{},
+		PermissionCtrl: &mock.PermissionControllerMock{},
+		RoleCtrl:       &mock.RoleControllerMock{},
+		MeCtrl:         &mock.MeControllerMock{},
+
+		SessionRepository: &sessionRepository,
+		Server: config.Server{
+			TrustedProxies: []string{"10.0.0.0/8"},
+			HSTSMaxAge:     time.Hour,
+		},
+	}
+
+	router := Init(init)
+	router.GET("/client-ip", func(c *gin.Context) {
+		c.String(http.StatusOK, c.ClientIP())
+	})
+
+	// the forwarded client IP is only used behind a trusted proxy
+	clients := map[string]string{
+		"10.1.2.3:4000":     "203.0.113.7",
+		"198.51.100.1:4000": "198.51.100.1",
+	}
+	for remote, expected := range clients {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/client-ip", nil)
+		req.RemoteAddr = remote
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		router.ServeHTTP(w, req)
+
+		if w.Body.String() != expected {
+			t.Errorf("Expected client IP %s from %s, got %s", expected, remote, w.Body.String())
+		}
+		if hsts := w.Header().Get("Strict-Transport-Security"); hsts != "max-age=3600; includeSubDomains" {
+			t.Errorf("Unexpected Strict-Transport-Security %q", hsts)
+		}
+		if w.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Errorf("Expected the security headers")
+		}
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	/* The key set is public and contains the key which signs the access tokens */
+	gin.SetMode(gin.TestMode)
+	init := &config.Injector{
+		SystemCtrl:     &controller.SystemControllerImpl{},
+		DepartmentCtrl: &mock.DepartmentControllerMock{},
+		UserCtrl:       &mock.UserControllerMock{},
+		PermissionCtrl: &mock.PermissionControllerMock{},
+		RoleCtrl:       &mock.RoleControllerMock{},
+		MeCtrl:         &mock.MeControllerMock{},
+
+		SessionRepository: &sessionRepository,
+	}
+
+	router := Init(init)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code 200 got %v", w.Code)
+	}
+
+	var jwks jwtkeys.JWKS
+	if err := json.Unmarshal(w.Body.Bytes(), &jwks); err != nil {
+		t.Fatalf("Error happened: when parse response %v", err)
+	}
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != dco.JWTKeys.SigningKeyID() {
+		t.Errorf("Expected the signing key, got %v", w.Body.String())
+	}
+}
+
+func TestInternalRoutes(t *testing.T) {
+	/* The internal routes are only reachable with the signed identity of the planner-backend */
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+	init := &config.Injector{
+		SystemCtrl:     &mock.SystemControllerMock{},
+		DepartmentCtrl: &mock.DepartmentControllerMock{},
+		UserCtrl:       &mock.UserControllerMock{},
+		PermissionCtrl: &mock.PermissionControllerMock{},
+		RoleCtrl:       &mock.RoleControllerMock{},
+		MeCtrl:         &mock.MeControllerMock{},
+
+		SessionRepository: &sessionRepository,
+	}
+	router := Init(init)
+
+	for i, method := range []string{"PUT", "DELETE"} {
+		req, _ := http.NewRequest(method, "/internal/department/planner1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Step %d: expected status code 401 without identity, got %v", i, w.Code)
+		}
+
+		middleware.SetIdentityHeaders(req.Header, dco.Identity{Username: dco.PlannerServiceName, IsAdmin: true}, method, "/internal/department/planner1", "")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Step %d: expected status code 200 with identity, got %v", i, w.Code)
+		}
+	}
+}
+
 type ResponseRecorder struct {
 	*httptest.ResponseRecorder
 	closeNotify chan bool
@@ -205,9 +723,6 @@ func TestPlannerProxy(t *testing.T) {
 	}))
 	defer mockServer.Close()
 
-	// set proxy url
-	os.Setenv("PLANNER_BACKEND_TARGET", mockServer.URL)
-
 	// prepare routes
 	gin.SetMode(gin.TestMode)
 	init := &config.Injector{
@@ -216,6 +731,11 @@ func TestPlannerProxy(t *testing.T) {
 		DepartmentCtrl: &mock.DepartmentControllerMock{},
 		UserCtrl:       &mock.UserControllerMock{},
 		PermissionCtrl: &mock.PermissionControllerMock{},
+		RoleCtrl:       &mock.RoleControllerMock{},
+		MeCtrl:         &mock.MeControllerMock{},
+
+		SessionRepository: &sessionRepository,
+		Routes:            plannerTable(mockServer.URL),
 	}
 	router := Init(init)
 
@@ -231,4 +751,72 @@ func TestPlannerProxy(t *testing.T) {
 	if w.Body.String() != "{\"message\":\"mock server response\"}\n" {
 		t.Errorf("Expected body to be {\"message\":\"mock server response\"}, got %v", w.Body.String())
 	}
+
+	// mutating requests require an authenticated user
+	w = NewResponseRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/planner/person/", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Body.String() != authErrorString {
+		t.Errorf("Expected body to be %v, got %v", authErrorString, w.Body.String())
+	}
+
+	// with the permission of the resource
+	w = NewResponseRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/planner/person/", nil)
+	req.AddCookie(&http.Cookie{
+		Name:  "Authorization",
+		Value: token,
+	})
+	req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code 403 got %v", w.Code)
+	}
+
+	w = NewResponseRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/planner/person/", nil)
+	req.AddCookie(&http.Cookie{
+		Name:  "Authorization",
+		Value: adminToken,
+	})
+	req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code 200 got %v", w.Code)
+	}
+
+	// requests without a rule are denied
+	w = NewResponseRecorder()
+	req, _ = http.NewRequest("POST", "/api/v1/planner/test", nil)
+	req.AddCookie(&http.Cookie{
+		Name:  "Authorization",
+		Value: adminToken,
+	})
+	req.Header.Set(dco.CSRFTokenHeader, mock.CSRFToken)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status code 403 got %v", w.Code)
+	}
+
+	// paths without a route are not forwarded
+	w = NewResponseRecorder()
+	req, _ = http.NewRequest("GET", "/api/v1/unknown", nil)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code 404 got %v", w.Code)
+	}
+}
+
+func plannerTable(target string) *proxy.Table {
+	/* The route table without a route file, which forwards /api/v1/planner to the target */
+	table, err := proxy.OpenTable(proxy.DefaultConfig, proxy.Source{PlannerTarget: target})
+	if err != nil {
+		panic(err)
+	}
+	return table
 }
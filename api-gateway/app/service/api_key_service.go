@@ -0,0 +1,271 @@
+/**
+* This package handles the API keys of scripts and service accounts.
+* - GetAPIKeys: List the keys of a user
+* - CreateAPIKey: Create a key with a subset of the permissions of its user, the key is only returned once
+* - RevokeAPIKey: Delete a key of a user
+* Users manage their own keys. Admins manage the keys of other users on the user routes,
+* new keys can only be created for service accounts there.
+**/
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/middleware"
+	"api-gateway/app/pkg"
+	"api-gateway/app/repository"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type APIKeyService interface {
+	GetAPIKeys(c *gin.Context)
+	CreateAPIKey(c *gin.Context)
+	RevokeAPIKey(c *gin.Context)
+}
+
+type APIKeyServiceImpl struct {
+	UserRepository       repository.UserRepository
+	PermissionRepository repository.PermissionRepository
+	APIKeyRepository     repository.APIKeyRepository
+	AuditRepository      repository.AuditRepository
+}
+
+func (a APIKeyServiceImpl) GetAPIKeys(c *gin.Context) {
+	/* Lists the keys of the logged in user or of the user given by the route */
+	slog.Info("start to execute program get api keys")
+
+	_, user, err := a.keyOwner(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	apiKeys, err := a.APIKeyRepository.FindAPIKeysByUserID(user.ID)
+	if err != nil {
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapAPIKeyListToAPIKeyResponseList(apiKeys)))
+}
+
+func (a APIKeyServiceImpl) CreateAPIKey(c *gin.Context) {
+	/**
+	* Creates a key for the logged in user or for the service account given by the route.
+	* The permissions of the key must be held by its user and by the user creating it.
+	**/
+	slog.Info("start to execute program create api key")
+
+	var request dco.APIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	if !request.ExpiresAt.After(time.Now()) || request.ExpiresAt.After(time.Now().Add(dco.APIKeyMaxLifetime)) {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("The expiry must be in the future and within a year"))
+		return
+	}
+
+	claim, user, err := a.keyOwner(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if c.Param("userID") != "" && !user.IsServiceAccount() {
+		pkg.Abort(c, pkg.NewError(constant.Forbidden).WithMessage("Keys of other users can only be created for service accounts"))
+		return
+	}
+
+	// the key can hold the permissions of every department of the user
+	held := []string{}
+	for _, department := range user.Departments() {
+		held = append(held, user.PermissionNamesIn(department.ID)...)
+	}
+	permissions := []dao.Permission{}
+	for _, id := range request.PermissionIDs {
+		permission, err := a.PermissionRepository.FindPermissionById(id)
+		switch err {
+		case nil:
+			break
+		case gorm.ErrRecordNotFound:
+			pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+			return
+		default:
+			slog.Error("Error happened: when get data from database", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
+
+		if !claim.HasPermissionInAnyDepartment(permission.Name) || !(user.IsSystemAdmin() || slices.Contains(held, permission.Name)) {
+			slog.Error("Error happened: when check permission of api key", "username", user.Username, "permission", permission.Name)
+			pkg.Abort(c, pkg.NewError(constant.Forbidden))
+			return
+		}
+		permissions = append(permissions, permission)
+	}
+
+	token, _, err := generateToken()
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	key := dco.APIKeyPrefix + token
+
+	apiKey, err := a.APIKeyRepository.Save(&dao.APIKey{
+		Name:        request.Name,
+		UserID:      user.ID,
+		KeyHash:     middleware.HashAPIKey(key),
+		Prefix:      key[:len(dco.APIKeyPrefix)+6],
+		Permissions: permissions,
+		ExpiresAt:   request.ExpiresAt,
+	})
+	if err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	saveAuditEntry(a.AuditRepository, &dao.AuditEntry{
+		Event:     dao.AuditEventAPIKeyCreated,
+		Subject:   user.Username,
+		Actor:     claim.Username,
+		IPAddress: c.ClientIP(),
+		Details:   apiKey.Name,
+	})
+
+	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, dco.CreatedAPIKeyResponse{
+		APIKeyResponse: mapAPIKeyToAPIKeyResponse(apiKey),
+		Key:            key,
+	}))
+}
+
+func (a APIKeyServiceImpl) RevokeAPIKey(c *gin.Context) {
+	/* Deletes a key, requests with the key are rejected immediately */
+	slog.Info("start to execute program revoke api key")
+
+	apiKeyID, err := uuid.Parse(c.Param("apiKeyID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	claim, user, err := a.keyOwner(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	switch err := a.APIKeyRepository.DeleteAPIKey(user.ID, apiKeyID); err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when deleting data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	saveAuditEntry(a.AuditRepository, &dao.AuditEntry{
+		Event:     dao.AuditEventAPIKeyRevoked,
+		Subject:   user.Username,
+		Actor:     claim.Username,
+		IPAddress: c.ClientIP(),
+		Details:   apiKeyID.String(),
+	})
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (a APIKeyServiceImpl) keyOwner(c *gin.Context) (*dco.JWTClaim, dao.User, error) {
+	/**
+	* Helper to load the user whose keys are managed, the user of the route or the logged in user.
+	* Keys are managed within a session, so a leaked key cannot be used to create further keys.
+	**/
+	token, exists := c.Get("retrievedToken")
+	if !exists {
+		slog.Error("Error happened: when get token from cookie", "error", "token not found")
+		return nil, dao.User{}, pkg.NewError(constant.Unauthorized)
+	}
+	claim := token.(*dco.JWTClaim)
+	if claim.APIKeyID != "" {
+		return nil, dao.User{}, pkg.NewError(constant.Forbidden).WithMessage("API keys cannot be managed with an API key")
+	}
+
+	var user dao.User
+	var err error
+	if c.Param("userID") != "" {
+		userID, parseErr := uuid.Parse(c.Param("userID"))
+		if parseErr != nil {
+			slog.Error("Error happened: when parsing uuid", "error", parseErr)
+			return nil, dao.User{}, pkg.NewError(constant.InvalidRequest)
+		}
+		user, err = a.UserRepository.FindUserById(userID)
+	} else {
+		user, err = a.UserRepository.FindUserByUsername(claim.Username)
+	}
+
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		return nil, dao.User{}, pkg.NewError(constant.DataNotFound)
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		return nil, dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+
+	return claim, user, nil
+}
+
+var apiKeyServiceSet = wire.NewSet(
+	wire.Struct(new(APIKeyServiceImpl), "*"),
+	wire.Bind(new(APIKeyService), new(*APIKeyServiceImpl)),
+)
+
+func mapAPIKeyToAPIKeyResponse(apiKey dao.APIKey) dco.APIKeyResponse {
+	/* mapAPIKeyToAPIKeyResponse is a function to map an api key to its response, the hash is not exposed
+	 * @param apiKey is dao.APIKey
+	 * @return dco.APIKeyResponse
+	 */
+	permissions := []string{}
+	for _, permission := range apiKey.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+
+	return dco.APIKeyResponse{
+		ID:          apiKey.ID,
+		Name:        apiKey.Name,
+		Prefix:      apiKey.Prefix,
+		Permissions: permissions,
+		CreatedAt:   apiKey.CreatedAt,
+		ExpiresAt:   apiKey.ExpiresAt,
+		LastUsedAt:  apiKey.LastUsedAt,
+	}
+}
+
+func mapAPIKeyListToAPIKeyResponseList(apiKeys []dao.APIKey) []dco.APIKeyResponse {
+	/* mapAPIKeyListToAPIKeyResponseList is a function to map api keys to their responses
+	 * @param apiKeys is []dao.APIKey
+	 * @return []dco.APIKeyResponse
+	 */
+	result := []dco.APIKeyResponse{}
+	for _, apiKey := range apiKeys {
+		result = append(result, mapAPIKeyToAPIKeyResponse(apiKey))
+	}
+	return result
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/domain/dto"
+	"api-gateway/app/middleware"
+	"api-gateway/app/mock"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func apiKeyTestContext(t *testing.T, w *httptest.ResponseRecorder, method string, params gin.Params, body interface{}, user dao.User, viaAPIKey bool) *gin.Context {
+	/* Helper to create a context of a logged in user, optionally authenticated with an API key */
+	c := mock.GetGinTestContext(w, method, params, body)
+
+	token, err := mock.GenerateMockToken(user)
+	if err != nil {
+		t.Error("Error happened: when generate mock token", "error", err)
+	}
+	claim, _ := middleware.DecodeToken(token)
+	if viaAPIKey {
+		claim.APIKeyID = uuid.NewString()
+	}
+	c.Set("retrievedToken", claim)
+	return c
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	/* Test Create API Key */
+	personWrite := dao.Permission{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "person:write"}
+	roleWrite := dao.Permission{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "role:write"}
+
+	user := dao.User{Username: "test", Permissions: []dao.Permission{personWrite}}
+	admin := dao.User{Username: "admin", Roles: mock.SystemAdminRoles}
+	serviceAccount := dao.User{Username: "impt", AuthProvider: dao.AuthProviderServiceAccount, Permissions: []dao.Permission{personWrite}}
+
+	validRequest := map[string]interface{}{
+		"name":           "import",
+		"permission_ids": []string{personWrite.ID.String()},
+		"expires_at":     time.Now().Add(30 * 24 * time.Hour),
+	}
+
+	type createAPIKeyTest struct {
+		request            map[string]interface{}
+		caller             dao.User
+		owner              dao.User
+		userID             string
+		viaAPIKey          bool
+		permission         dao.Permission
+		permissionError    error
+		saveError          error
+		expectedStatusCode int
+	}
+
+	testSteps := []createAPIKeyTest{
+		{
+			request:            validRequest,
+			caller:             user,
+			owner:              user,
+			permission:         personWrite,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			// admins create keys for service accounts
+			request:            validRequest,
+			caller:             admin,
+			owner:              serviceAccount,
+			userID:             uuid.NewString(),
+			permission:         personWrite,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			// keys of other users can only be created for service accounts
+			request:            validRequest,
+			caller:             admin,
+			owner:              user,
+			userID:             uuid.NewString(),
+			permission:         personWrite,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			// the user does not hold the permission
+			request:            validRequest,
+			caller:             user,
+			owner:              user,
+			permission:         roleWrite,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			// a leaked key cannot create further keys
+			request:            validRequest,
+			caller:             user,
+			owner:              user,
+			viaAPIKey:          true,
+			permission:         personWrite,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			request:            validRequest,
+			caller:             user,
+			owner:              user,
+			permissionError:    gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request: map[string]interface{}{
+				"name":           "import",
+				"permission_ids": []string{personWrite.ID.String()},
+				"expires_at":     time.Now().Add(-time.Hour),
+			},
+			caller:             user,
+			owner:              user,
+			permission:         personWrite,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request: map[string]interface{}{
+				"name":           "import",
+				"permission_ids": []string{personWrite.ID.String()},
+				"expires_at":     time.Now().Add(2 * dco.APIKeyMaxLifetime),
+			},
+			caller:             user,
+			owner:              user,
+			permission:         personWrite,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request: map[string]interface{}{
+				"name":       "import",
+				"expires_at": time.Now().Add(time.Hour),
+			},
+			caller:             user,
+			owner:              user,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            validRequest,
+			caller:             user,
+			owner:              user,
+			permission:         personWrite,
+			saveError:          errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock := mock.NewUserRepositoryMock()
+			permissionRepoMock := mock.NewPermissionRepositoryMock()
+			apiKeyRepoMock := mock.NewAPIKeyRepositoryMock()
+			auditRepoMock := mock.AuditRepositoryMock{}
+			apiKeyService := APIKeyServiceImpl{
+				UserRepository:       &userRepoMock,
+				PermissionRepository: &permissionRepoMock,
+				APIKeyRepository:     &apiKeyRepoMock,
+				AuditRepository:      &auditRepoMock,
+			}
+
+			userRepoMock.On("FindUserByUsername").Return(testStep.owner, nil)
+			userRepoMock.On("FindUserById").Return(testStep.owner, nil)
+			permissionRepoMock.On("FindPermissionById").Return(testStep.permission, testStep.permissionError)
+			apiKeyRepoMock.On("Save").Return(nil, testStep.saveError)
+
+			params := gin.Params{}
+			if testStep.userID != "" {
+				params = gin.Params{{Key: "userID", Value: testStep.userID}}
+			}
+			w := httptest.NewRecorder()
+			c := apiKeyTestContext(t, w, "POST", params, testStep.request, testStep.caller, testStep.viaAPIKey)
+
+			serve(c, apiKeyService.CreateAPIKey)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedStatusCode != http.StatusCreated {
+				return
+			}
+
+			var response dto.APIResponse[dco.CreatedAPIKeyResponse]
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Step: %d. Error happened: when parse response %v", i, err)
+			}
+			if !strings.HasPrefix(response.Data.Key, dco.APIKeyPrefix) || !strings.HasPrefix(response.Data.Key, response.Data.Prefix) {
+				t.Errorf("Step: %d. Expected a key with prefix %q, got %+v", i, dco.APIKeyPrefix, response.Data)
+			}
+
+			// only the hash of the key is stored
+			if len(apiKeyRepoMock.Saved) != 1 || apiKeyRepoMock.Saved[0].KeyHash != middleware.HashAPIKey(response.Data.Key) {
+				t.Errorf("Step: %d. Expected the hash of the key to be stored", i)
+			}
+			if len(auditRepoMock.Entries) != 1 || auditRepoMock.Entries[0].Event != dao.AuditEventAPIKeyCreated {
+				t.Errorf("Step: %d. Expected an audit entry, got %v", i, auditRepoMock.Entries)
+			}
+		})
+	}
+}
+
+func TestGetAPIKeys(t *testing.T) {
+	/* Test Get API Keys */
+	userRepoMock := mock.NewUserRepositoryMock()
+	apiKeyRepoMock := mock.NewAPIKeyRepositoryMock()
+	apiKeyService := APIKeyServiceImpl{
+		UserRepository:   &userRepoMock,
+		APIKeyRepository: &apiKeyRepoMock,
+	}
+
+	user := dao.User{Username: "test"}
+	apiKeys := []dao.APIKey{{
+		Name:        "import",
+		KeyHash:     "hash",
+		Prefix:      "pk_abcdef",
+		Permissions: []dao.Permission{{Name: "person:write"}},
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}}
+
+	type getAPIKeysTest struct {
+		userError          error
+		apiKeyError        error
+		expectedStatusCode int
+	}
+
+	testSteps := []getAPIKeysTest{
+		{expectedStatusCode: http.StatusOK},
+		{userError: gorm.ErrRecordNotFound, expectedStatusCode: http.StatusNotFound},
+		{apiKeyError: errors.New("some error"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock.On("FindUserByUsername").Return(user, testStep.userError)
+			apiKeyRepoMock.On("FindAPIKeysByUserID").Return(apiKeys, testStep.apiKeyError)
+
+			w := httptest.NewRecorder()
+			c := apiKeyTestContext(t, w, "GET", gin.Params{}, nil, user, false)
+
+			serve(c, apiKeyService.GetAPIKeys)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			if strings.Contains(w.Body.String(), "hash") {
+				t.Errorf("Step: %d. Expected the hash not to be exposed, got %s", i, w.Body.String())
+			}
+			var response dto.APIResponse[[]dco.APIKeyResponse]
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Step: %d. Error happened: when parse response %v", i, err)
+			}
+			if len(response.Data) != 1 || response.Data[0].Permissions[0] != "person:write" {
+				t.Errorf("Step: %d. Expected the key of the user, got %+v", i, response.Data)
+			}
+		})
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	/* Test Revoke API Key */
+	type revokeAPIKeyTest struct {
+		apiKeyID           string
+		deleteError        error
+		expectedStatusCode int
+		expectedAudit      int
+	}
+
+	testSteps := []revokeAPIKeyTest{
+		{apiKeyID: uuid.NewString(), expectedStatusCode: http.StatusOK, expectedAudit: 1},
+		{apiKeyID: "invalid", expectedStatusCode: http.StatusBadRequest},
+		// keys of other users are not found
+		{apiKeyID: uuid.NewString(), deleteError: gorm.ErrRecordNotFound, expectedStatusCode: http.StatusNotFound},
+		{apiKeyID: uuid.NewString(), deleteError: errors.New("some error"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock := mock.NewUserRepositoryMock()
+			apiKeyRepoMock := mock.NewAPIKeyRepositoryMock()
+			auditRepoMock := mock.AuditRepositoryMock{}
+			apiKeyService := APIKeyServiceImpl{
+				UserRepository:   &userRepoMock,
+				APIKeyRepository: &apiKeyRepoMock,
+				AuditRepository:  &auditRepoMock,
+			}
+
+			user := dao.User{Username: "test"}
+			userRepoMock.On("FindUserByUsername").Return(user, nil)
+			apiKeyRepoMock.On("DeleteAPIKey").Return(nil, testStep.deleteError)
+
+			w := httptest.NewRecorder()
+			c := apiKeyTestContext(t, w, "DELETE", gin.Params{{Key: "apiKeyID", Value: testStep.apiKeyID}}, nil, user, false)
+
+			serve(c, apiKeyService.RevokeAPIKey)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+			if len(auditRepoMock.Entries) != testStep.expectedAudit {
+				t.Errorf("Step: %d. Expected %d audit entries, got %d", i, testStep.expectedAudit, len(auditRepoMock.Entries))
+			}
+		})
+	}
+}
@@ -3,24 +3,35 @@
 * It has three main functions:
 * - Login: Generate a JWT token
 * - Me: Get the user data from the JWT token
-* - Logout: invalidate a JWT token
+* - Logout: revoke the session of the client
+* - Permissions: Get the effective permissions of the user
+* - Unlock: Unlock a user locked after too many failed logins
+* - Providers, ExternalLogin, ExternalCallback: Login at an external identity provider
+* - LoginTwoFactor: Complete the login of a user with two-factor authentication
 **/
 package service
 
 import (
+	"api-gateway/app/authprovider"
 	"api-gateway/app/constant"
 	"api-gateway/app/domain/dao"
 	"api-gateway/app/domain/dco"
+	"api-gateway/app/metrics"
+	"api-gateway/app/middleware"
 	"api-gateway/app/pkg"
+	"api-gateway/app/planner"
+	"api-gateway/app/policy"
 	"api-gateway/app/repository"
+	"crypto/subtle"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/google/wire"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -29,63 +40,104 @@ type AuthService interface {
 	Me(c *gin.Context)
 	Logout(c *gin.Context)
 	CheckAdmin(c *gin.Context)
+	Permissions(c *gin.Context)
+	Unlock(c *gin.Context)
+	Providers(c *gin.Context)
+	ExternalLogin(c *gin.Context)
+	ExternalCallback(c *gin.Context)
+	LoginTwoFactor(c *gin.Context)
 }
 
 type AuthServiceImpl struct {
-	UserRepository repository.UserRepository
+	UserRepository          repository.UserRepository
+	PermissionRepository    repository.PermissionRepository
+	SessionRepository       repository.SessionRepository
+	LoginThrottleRepository repository.LoginThrottleRepository
+	AuditRepository         repository.AuditRepository
+	LoginPolicy             policy.LoginPolicy
+	AuthProviders           *authprovider.Registry
+	DepartmentRepository    repository.DepartmentRepository
+	RoleRepository          repository.RoleRepository
+	TwoFactorRepository     repository.TwoFactorRepository
+	// The linked person is read from the planner-backend, nil if it is not configured
+	Planner planner.Client
 }
 
 func (a AuthServiceImpl) Login(c *gin.Context) {
 	/**
 	* Take in the username and password from the request body
-	* Check if the username and password are correct
-	* If correct, generate a JWT token and return it to the client via httpOnly cookie
+	* Check the credentials with the requested password provider, the local passwords by default
+	* If correct, start a session and return the access and refresh tokens to the client via httpOnly cookies
+	* Users with two-factor authentication receive a login challenge instead, see LoginTwoFactor
+	* Failed attempts are throttled per username and per client IP
 	**/
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program login")
 
 	var request dco.LoginRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		slog.Error("Error happened: when mapping request", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	user, err := a.UserRepository.FindUserByUsername(request.Username)
-	switch err {
-	case nil:
+	provider, ok := a.AuthProviders.PasswordProvider(request.Provider)
+	if !ok {
+		slog.Error("Error happened: unknown password provider", "provider", request.Provider)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Unknown authentication provider"))
+		return
+	}
+
+	usernameKey, ipKey := loginThrottleKeys(c, request.Username)
+	if err := a.checkLoginThrottle(c, metrics.LoginPassword, usernameKey, ipKey); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	identity, err := provider.Authenticate(c.Request.Context(), request.Username, request.Password)
+	switch {
+	case err == nil:
 		break
-	case gorm.ErrRecordNotFound:
-		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.Unauthorized)
+	case errors.Is(err, authprovider.ErrInvalidCredentials):
+		slog.Error("Error happened: when authenticate user", "provider", provider.Name(), "error", err)
+		a.recordLoginFailure(c, metrics.LoginPassword, usernameKey, ipKey)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
 	default:
-		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		slog.Error("Error happened: when authenticate user", "provider", provider.Name(), "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
-
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
-		slog.Error("Error happened: when compare password", "error", err)
-		pkg.PanicException(constant.Unauthorized)
+	user, err := a.resolveUser(identity)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, dco.JWTClaim{
-		Username:   user.Username,
-		Department: user.Department.ID.String(),
-		IsAdmin:    user.IsAdmin,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(dco.JWTExpirationTime)),
-		},
-	})
+	// the failures of the username are kept until the second factor was entered
+	if user.HasTwoFactor() {
+		if err := a.startLoginChallenge(c, user); err != nil {
+			pkg.Abort(c, err)
+			return
+		}
+		metrics.Login(metrics.LoginPassword, metrics.LoginChallenged)
+		c.JSON(http.StatusAccepted, pkg.BuildResponse(constant.Success, dco.TwoFactorChallengeResponse{TwoFactorRequired: true}))
+		return
+	}
+	a.resetLoginThrottle(usernameKey)
 
-	// sign the token with the secret key
-	tokenString, err := token.SignedString(dco.JWTSigningKey)
+	// start a new session, the client receives a short-lived access token and a refresh token
+	session, err := startSession(c, a.SessionRepository, user)
 	if err != nil {
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, err)
+		return
+	}
+	if err := issueAccessToken(c, user, session.ID, session.CSRFToken); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
+	metrics.Login(metrics.LoginPassword, metrics.LoginSuccess)
 
-	// set the token in the cookie
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", tokenString, int(dco.JWTExpirationTime.Seconds()), "/", "", false, true)
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapUserToUserResponse(user)))
 }
 
@@ -94,14 +146,14 @@ func (a AuthServiceImpl) Me(c *gin.Context) {
 	* This function gets the Cookie from the request header and checks it for validity.
 	* If the token is valid, it returns the user data to be used in the frontend.
 	**/
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program me")
 
 	// get the token from the cookie
 	claim, exists := c.Get("retrievedToken")
 	if !exists {
 		slog.Error("Error happened: when get token from cookie", "error", "token not found")
-		pkg.PanicException(constant.Unauthorized)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
 	}
 
 	// find the user data from the database
@@ -111,37 +163,80 @@ func (a AuthServiceImpl) Me(c *gin.Context) {
 		break
 	case gorm.ErrRecordNotFound:
 		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.Unauthorized)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
 	default:
 		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	if data.IsAdmin {
-		c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapUserToAuthResponse(data)))
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
 		return
 	}
+
 	// if departmentQuery parameter is not "", check if user belongs to the department
 	departmentQuery := c.Query("department")
-	if departmentQuery == "" {
-		c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapUserToAuthResponse(data)))
+	member := data.IsSystemAdmin() || departmentQuery == ""
+	// the planner-backend knows the department by its planner id, older clients pass the name
+	for _, department := range data.Departments() {
+		if departmentQuery == department.PlannerID || departmentQuery == department.Name {
+			member = true
+		}
+	}
+	if !member {
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
 		return
 	}
 
-	if departmentQuery == data.Department.Name {
-		c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapUserToAuthResponse(data)))
-		return
+	response := mapUserToAuthResponse(data)
+	response.Person = a.linkedPerson(data)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, response))
+}
+
+func (a AuthServiceImpl) linkedPerson(user dao.User) *dco.PlannerPerson {
+	/* Helper to read the person linked to the user, only the id is returned if the planner-backend cannot be reached */
+	if user.PersonID == nil {
+		return nil
 	}
 
-	pkg.PanicException(constant.Unauthorized)
+	person := dco.PlannerPerson{ID: *user.PersonID}
+	if a.Planner == nil {
+		return &person
+	}
+	found, err := a.Planner.GetPerson(*user.PersonID)
+	if err != nil {
+		slog.Warn("Error happened: when get linked person from planner-backend", "person", *user.PersonID, "error", err)
+		return &person
+	}
+	return &found
 }
 
 func (a AuthServiceImpl) Logout(c *gin.Context) {
-	defer pkg.PanicHandler(c)
+	/**
+	* Revokes the session of the client and clears the cookies.
+	* The access token might be expired already, so the session is looked up by the refresh token first.
+	**/
 	slog.Info("start to execute program logout")
 
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", "", -1, "/", "", false, true)
+	var sessionID uuid.UUID
+	if refreshToken, err := c.Cookie(dco.RefreshTokenCookie); err == nil && refreshToken != "" {
+		if session, err := a.SessionRepository.FindSessionByRefreshTokenHash(hashToken(refreshToken)); err == nil {
+			sessionID = session.ID
+		}
+	}
+	if accessToken, err := c.Cookie(dco.AccessTokenCookie); sessionID == uuid.Nil && err == nil && accessToken != "" {
+		if claim, err := middleware.DecodeToken(accessToken); err == nil {
+			sessionID, _ = uuid.Parse(claim.SessionID)
+		}
+	}
+
+	if sessionID != uuid.Nil {
+		if err := a.SessionRepository.RevokeSession(sessionID, time.Now().Add(dco.JWTExpirationTime)); err != nil {
+			slog.Error("Error happened: when revoke session", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
+	}
+
+	clearAuthCookies(c)
 	c.Status(http.StatusOK)
 }
 
@@ -151,14 +246,14 @@ var authServiceSet = wire.NewSet(
 )
 
 func (a AuthServiceImpl) CheckAdmin(c *gin.Context) {
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program check admin")
 
 	// get the token from the cookie
 	claim, exists := c.Get("retrievedToken")
 	if !exists {
 		slog.Error("Error happened: when get token from cookie", "error", "token not found")
-		pkg.PanicException(constant.Unauthorized)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
 	}
 
 	if claim.(*dco.JWTClaim).IsAdmin {
@@ -166,7 +261,402 @@ func (a AuthServiceImpl) CheckAdmin(c *gin.Context) {
 		return
 	}
 
-	pkg.PanicException(constant.Unauthorized)
+	pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+	return
+}
+
+func (a AuthServiceImpl) Permissions(c *gin.Context) {
+	/**
+	* Returns the effective permissions of the user along with their scope.
+	* System admins hold every permission globally. All other users hold their permissions
+	* within each of their departments: their assigned permissions and the permissions of the
+	* roles that apply within the department, including the role of the membership.
+	**/
+	slog.Info("start to execute program get effective permissions")
+
+	claim, exists := c.Get("retrievedToken")
+	if !exists {
+		slog.Error("Error happened: when get token from cookie", "error", "token not found")
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	// the permissions are read from the database, since the token might be outdated
+	user, err := a.UserRepository.FindUserByUsername(claim.(*dco.JWTClaim).Username)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if user.IsSystemAdmin() {
+		permissions, err := a.PermissionRepository.FindAllPermissions()
+		if err != nil {
+			slog.Error("Error happened: when get data from database", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
+
+		scope := dco.PermissionScope{Type: dco.PermissionScopeGlobal}
+		c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapPermissionListToEffectivePermissionResponseList(permissions, scope)))
+		return
+	}
+
+	// the permissions are scoped to each department of the user, like the memberships of the token:
+	// the direct permissions and the roles that apply within the department
+	result := []dco.EffectivePermissionResponse{}
+	for _, department := range user.Departments() {
+		department := department
+		scope := dco.PermissionScope{
+			Type:           dco.PermissionScopeDepartment,
+			DepartmentID:   &department.ID,
+			DepartmentName: &department.Name,
+		}
+		result = append(result, mapPermissionListToEffectivePermissionResponseList(user.Permissions, scope)...)
+		for _, role := range user.RolesIn(department.ID) {
+			result = append(result, mapPermissionListToEffectivePermissionResponseList(role.Role.Permissions, scope)...)
+		}
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, uniqueEffectivePermissions(result)))
+}
+
+func (a AuthServiceImpl) Unlock(c *gin.Context) {
+	/**
+	* Unlocks a user locked after too many failed logins and forgets the failures.
+	* Locked client IPs are unlocked when their lockout expires.
+	**/
+	slog.Info("start to execute program unlock user")
+
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	user, err := a.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	claim, err := authorizeUserChange(c, user, "user:write")
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	usernameKey, _ := loginThrottleKeys(c, user.Username)
+	if err := a.LoginThrottleRepository.Reset(usernameKey); err != nil {
+		slog.Error("Error happened: when reset login throttle", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	a.audit(&dao.AuditEntry{
+		Event:     dao.AuditEventLoginUnlocked,
+		Subject:   usernameKey,
+		Actor:     claim.Username,
+		IPAddress: c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (a AuthServiceImpl) Providers(c *gin.Context) {
+	/* Lists the enabled providers, so the login page can offer them */
+	slog.Info("start to execute program get auth providers")
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, a.AuthProviders.Providers()))
+}
+
+func (a AuthServiceImpl) ExternalLogin(c *gin.Context) {
+	/**
+	* Redirects the client to the login page of an external provider.
+	* The state, nonce and PKCE code verifier are kept in a short-lived cookie and checked on the callback.
+	**/
+	slog.Info("start to execute program external login")
+
+	provider, err := a.redirectProvider(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	state, _, stateErr := generateToken()
+	nonce, _, nonceErr := generateToken()
+	codeVerifier, _, verifierErr := generateToken()
+	if err := errors.Join(stateErr, nonceErr, verifierErr); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	// the provider redirects back to the callback from another site, a strict cookie would not be sent
+	c.SetSameSite(http.SameSiteLaxMode)
+	if dco.Cookies.SameSite == http.SameSiteNoneMode {
+		c.SetSameSite(http.SameSiteNoneMode)
+	}
+	c.SetCookie(dco.ExternalLoginCookie, strings.Join([]string{state, nonce, codeVerifier}, "."), int(dco.ExternalLoginExpirationTime.Seconds()), dco.RefreshTokenCookiePath, dco.Cookies.Domain, dco.Cookies.Secure, true)
+	c.Redirect(http.StatusFound, provider.AuthCodeURL(state, nonce, codeVerifier))
+}
+
+func (a AuthServiceImpl) ExternalCallback(c *gin.Context) {
+	/**
+	* Completes the login at an external provider.
+	* The code is exchanged for the identity of the user, who is provisioned on the first login.
+	* Then a session is started and the client is redirected to the application.
+	**/
+	slog.Info("start to execute program external login callback")
+
+	provider, err := a.redirectProvider(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	// every login can be completed once
+	cookie, err := c.Cookie(dco.ExternalLoginCookie)
+	setCookie(c, dco.ExternalLoginCookie, "", -1, dco.RefreshTokenCookiePath)
+
+	parts := strings.Split(cookie, ".")
+	if err != nil || len(parts) != 3 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(c.Query("state"))) != 1 {
+		slog.Error("Error happened: when check login state", "provider", provider.Name())
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Invalid login state"))
+		return
+	}
+
+	if reason := c.Query("error"); reason != "" {
+		slog.Error("Error happened: provider rejected login", "provider", provider.Name(), "error", reason, "description", c.Query("error_description"))
+		metrics.Login(metrics.LoginExternal, metrics.LoginFailure)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	identity, err := provider.Exchange(c.Request.Context(), c.Query("code"), parts[1], parts[2])
+	if err != nil {
+		slog.Error("Error happened: when exchange code", "provider", provider.Name(), "error", err)
+		metrics.Login(metrics.LoginExternal, metrics.LoginFailure)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	user, err := a.resolveUser(identity)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	redirectURL := dco.PostLoginURL
+	if redirectURL == "" {
+		redirectURL = "/"
+	}
+
+	// the application asks for the second factor and completes the login with LoginTwoFactor
+	if user.HasTwoFactor() {
+		if err := a.startLoginChallenge(c, user); err != nil {
+			pkg.Abort(c, err)
+			return
+		}
+		metrics.Login(metrics.LoginExternal, metrics.LoginChallenged)
+		separator := "?"
+		if strings.Contains(redirectURL, "?") {
+			separator = "&"
+		}
+		c.Redirect(http.StatusFound, redirectURL+separator+"two_factor_required=true")
+		return
+	}
+
+	session, err := startSession(c, a.SessionRepository, user)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if err := issueAccessToken(c, user, session.ID, session.CSRFToken); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	metrics.Login(metrics.LoginExternal, metrics.LoginSuccess)
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+func (a AuthServiceImpl) LoginTwoFactor(c *gin.Context) {
+	/**
+	* Completes the login of a user with two-factor authentication.
+	* The login challenge of the password step is exchanged for a session if the code or recovery code is valid.
+	* Failed attempts are throttled like failed passwords.
+	**/
+	slog.Info("start to execute program login two factor")
+
+	var request dco.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil || (request.Code == "" && request.RecoveryCode == "") {
+		slog.Error("Error happened: when mapping request", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	token, err := c.Cookie(dco.TwoFactorChallengeCookie)
+	if err != nil || token == "" {
+		slog.Error("Error happened: when get login challenge from cookie", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	challenge, err := a.TwoFactorRepository.FindLoginChallengeByTokenHash(hashToken(token))
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	if !challenge.IsValid() {
+		slog.Error("Error happened: login challenge expired", "challenge", challenge.ID)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	user, err := a.UserRepository.FindUserById(challenge.UserID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	usernameKey, ipKey := loginThrottleKeys(c, user.Username)
+	if err := a.checkLoginThrottle(c, metrics.LoginTwoFactor, usernameKey, ipKey); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	valid, err := verifySecondFactor(a.TwoFactorRepository, user, request.Code, request.RecoveryCode)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if !valid {
+		slog.Error("Error happened: when verify second factor", "username", user.Username)
+		a.recordLoginFailure(c, metrics.LoginTwoFactor, usernameKey, ipKey)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	// every challenge can be completed once
+	switch err := a.TwoFactorRepository.DeleteLoginChallenge(challenge.ID); err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	default:
+		slog.Error("Error happened: when deleting data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	a.resetLoginThrottle(usernameKey)
+
+	setCookie(c, dco.TwoFactorChallengeCookie, "", -1, dco.RefreshTokenCookiePath)
+
+	session, err := startSession(c, a.SessionRepository, user)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if err := issueAccessToken(c, user, session.ID, session.CSRFToken); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	metrics.Login(metrics.LoginTwoFactor, metrics.LoginSuccess)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapUserToUserResponse(user)))
+}
+
+func (a AuthServiceImpl) startLoginChallenge(c *gin.Context, user dao.User) error {
+	/* Stores a login challenge for the user and sets its token as cookie for the auth routes */
+	token, hash, err := generateToken()
+	if err != nil {
+		return err
+	}
+	_, err = a.TwoFactorRepository.SaveLoginChallenge(&dao.LoginChallenge{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(dco.TwoFactorChallengeExpirationTime),
+	})
+	if err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		return pkg.NewError(constant.UnknownError)
+	}
+
+	setCookie(c, dco.TwoFactorChallengeCookie, token, int(dco.TwoFactorChallengeExpirationTime.Seconds()), dco.RefreshTokenCookiePath)
+	return nil
+}
+
+func (a AuthServiceImpl) redirectProvider(c *gin.Context) (authprovider.RedirectProvider, error) {
+	provider, ok := a.AuthProviders.RedirectProvider(c.Param("provider"))
+	if !ok {
+		slog.Error("Error happened: unknown redirect provider", "provider", c.Param("provider"))
+		return nil, pkg.NewError(constant.DataNotFound)
+	}
+	return provider, nil
+}
+
+func uniqueEffectivePermissions(permissions []dco.EffectivePermissionResponse) []dco.EffectivePermissionResponse {
+	/**
+	* This function removes permissions granted multiple times within the same scope
+	**/
+	result := []dco.EffectivePermissionResponse{}
+	seen := map[string]bool{}
+	for _, permission := range permissions {
+		key := permission.Name + "|" + permission.Scope.Type
+		if permission.Scope.DepartmentID != nil {
+			key += "|" + permission.Scope.DepartmentID.String()
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, permission)
+	}
+	return result
+}
+
+func mapPermissionListToEffectivePermissionResponseList(permissions []dao.Permission, scope dco.PermissionScope) []dco.EffectivePermissionResponse {
+	/**
+	* This function maps permissions to effective permissions with the given scope
+	**/
+	result := []dco.EffectivePermissionResponse{}
+	for _, permission := range permissions {
+		response := mapPermissionToPermissionResponse(permission)
+		result = append(result, dco.EffectivePermissionResponse{
+			Name:        response.Name,
+			Description: response.Description,
+			Scope:       scope,
+		})
+	}
+	return result
 }
 
 func mapUserToAuthResponse(user dao.User) dco.AuthResponse {
@@ -174,7 +664,8 @@ func mapUserToAuthResponse(user dao.User) dco.AuthResponse {
 	* This function maps the user data from the database to the response data
 	**/
 	return dco.AuthResponse{
-		Username: user.Username,
-		Email:    user.Email,
+		Username:    user.Username,
+		Email:       user.Email,
+		Memberships: mapUserToMembershipResponseList(user),
 	}
 }
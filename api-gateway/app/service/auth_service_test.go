@@ -1,20 +1,25 @@
 package service
 
 import (
+	"api-gateway/app/authprovider"
 	"api-gateway/app/domain/dao"
 	"api-gateway/app/domain/dco"
 	"api-gateway/app/domain/dto"
+	"api-gateway/app/metrics"
 	"api-gateway/app/middleware"
 	"api-gateway/app/mock"
+	"api-gateway/app/policy"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -31,9 +36,16 @@ func TestLoginSimple(t *testing.T) {
 
 	// mock
 	mockUserRepository := mock.NewUserRepositoryMock()
+	mockSessionRepository := mock.NewSessionRepositoryMock()
+	mockLoginThrottleRepository := mock.NewLoginThrottleRepositoryMock()
 
 	authService := AuthServiceImpl{
-		UserRepository: &mockUserRepository,
+		UserRepository:          &mockUserRepository,
+		SessionRepository:       &mockSessionRepository,
+		LoginThrottleRepository: &mockLoginThrottleRepository,
+		AuditRepository:         &mock.AuditRepositoryMock{},
+		LoginPolicy:             policy.DefaultLoginPolicy,
+		AuthProviders:           authprovider.NewRegistry(authprovider.LocalProvider{UserRepository: &mockUserRepository}),
 	}
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.DefaultCost)
@@ -97,8 +109,7 @@ func TestLoginSimple(t *testing.T) {
 			w := httptest.NewRecorder()
 			ctx := mock.GetGinTestContext(w, "POST", gin.Params{}, testStep.mockRequestData)
 
-			authService.Login(ctx)
-
+			serve(ctx, authService.Login)
 			if w.Code != testStep.expectedStatusCode {
 				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
 			}
@@ -119,6 +130,223 @@ func TestLoginSimple(t *testing.T) {
 	}
 }
 
+func TestLoginThrottle(t *testing.T) {
+	// define test struct
+	type authLoginThrottleTest struct {
+		password           string
+		throttle           dao.LoginThrottle
+		recordedFailure    dao.LoginThrottle
+		expectedStatusCode int
+		expectedLocked     int
+		expectedReset      int
+		expectedRetryAfter bool
+		expectedResult     string
+	}
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.MinCost)
+	lockedUntil := time.Now().Add(time.Minute)
+	loginPolicy := policy.DefaultLoginPolicy
+
+	testSteps := []authLoginThrottleTest{
+		{
+			// successful logins forget the failures of the username
+			password:           "test",
+			expectedStatusCode: http.StatusOK,
+			expectedReset:      1,
+			expectedResult:     metrics.LoginSuccess,
+		},
+		{
+			// failures are counted without a lock
+			password:           "wrong",
+			recordedFailure:    dao.LoginThrottle{Failures: 1, LastFailureAt: time.Now()},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResult:     metrics.LoginFailure,
+		},
+		{
+			// the username and the IP are locked after too many failures
+			password:           "wrong",
+			recordedFailure:    dao.LoginThrottle{Failures: loginPolicy.MaxFailuresPerIP, LastFailureAt: time.Now()},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedLocked:     2,
+			expectedResult:     metrics.LoginFailure,
+		},
+		{
+			// locked keys are rejected even with the correct password
+			password:           "test",
+			throttle:           dao.LoginThrottle{Failures: loginPolicy.MaxFailures, LastFailureAt: time.Now(), LockedUntil: &lockedUntil},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedRetryAfter: true,
+			expectedResult:     metrics.LoginThrottled,
+		},
+		{
+			// the delay after a failure has to pass
+			password:           "test",
+			throttle:           dao.LoginThrottle{Failures: 3, LastFailureAt: time.Now()},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedRetryAfter: true,
+			expectedResult:     metrics.LoginThrottled,
+		},
+		{
+			// the delay has passed
+			password:           "test",
+			throttle:           dao.LoginThrottle{Failures: 3, LastFailureAt: time.Now().Add(-time.Minute)},
+			expectedStatusCode: http.StatusOK,
+			expectedReset:      1,
+			expectedResult:     metrics.LoginSuccess,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test Step: %d", i), func(t *testing.T) {
+			mockUserRepository := mock.NewUserRepositoryMock()
+			mockSessionRepository := mock.NewSessionRepositoryMock()
+			mockLoginThrottleRepository := mock.NewLoginThrottleRepositoryMock()
+			mockAuditRepository := mock.AuditRepositoryMock{}
+			authService := AuthServiceImpl{
+				UserRepository:          &mockUserRepository,
+				SessionRepository:       &mockSessionRepository,
+				LoginThrottleRepository: &mockLoginThrottleRepository,
+				AuditRepository:         &mockAuditRepository,
+				LoginPolicy:             loginPolicy,
+				AuthProviders:           authprovider.NewRegistry(authprovider.LocalProvider{UserRepository: &mockUserRepository}),
+			}
+
+			// Set mock data
+			mockUserRepository.On("FindUserByUsername").Return(dao.User{Username: "test", Password: string(hashedPassword)}, nil)
+			if testStep.throttle.Failures > 0 {
+				mockLoginThrottleRepository.On("FindLoginThrottle").Return(testStep.throttle, nil)
+			}
+			if testStep.recordedFailure.Failures > 0 {
+				mockLoginThrottleRepository.On("RecordFailure").Return(testStep.recordedFailure, nil)
+			}
+
+			logins := metrics.Logins.WithLabelValues(metrics.LoginPassword, testStep.expectedResult)
+			before := testutil.ToFloat64(logins)
+
+			w := httptest.NewRecorder()
+			ctx := mock.GetGinTestContext(w, "POST", gin.Params{}, map[string]interface{}{
+				"username": "test",
+				"password": testStep.password,
+			})
+
+			serve(ctx, authService.Login)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if len(mockLoginThrottleRepository.LockedKeys) != testStep.expectedLocked {
+				t.Errorf("Expected %d locked keys but got %v", testStep.expectedLocked, mockLoginThrottleRepository.LockedKeys)
+			}
+			if len(mockAuditRepository.Entries) != testStep.expectedLocked {
+				t.Errorf("Expected %d audit entries but got %d", testStep.expectedLocked, len(mockAuditRepository.Entries))
+			}
+			if len(mockLoginThrottleRepository.ResetKeys) != testStep.expectedReset {
+				t.Errorf("Expected %d reset keys but got %v", testStep.expectedReset, mockLoginThrottleRepository.ResetKeys)
+			}
+			if retryAfter := w.Header().Get("Retry-After"); testStep.expectedRetryAfter && retryAfter == "" {
+				t.Errorf("Expected Retry-After header to be set")
+			}
+			if count := testutil.ToFloat64(logins); count != before+1 {
+				t.Errorf("Expected the login to be counted as %s", testStep.expectedResult)
+			}
+		})
+	}
+}
+
+func TestUnlock(t *testing.T) {
+	// define test struct
+	type authUnlockTest struct {
+		userID             string
+		user               dao.User
+		caller             dao.User
+		userError          error
+		resetError         error
+		expectedStatusCode int
+	}
+
+	testSteps := []authUnlockTest{
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			userID:             "invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			resetError:         errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// the user belongs to a department the caller does not administer
+			userID:             "00000000-0000-0000-0000-000000000001",
+			user:               dao.User{Username: "test", Department: dao.Department{PlannerID: "planner-b"}},
+			caller:             departmentAdmin("planner-a"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			user:               dao.User{Username: "test", Department: dao.Department{PlannerID: "planner-a"}, Roles: mock.SystemAdminRoles},
+			caller:             departmentAdmin("planner-a"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			mockUserRepository := mock.NewUserRepositoryMock()
+			mockLoginThrottleRepository := mock.NewLoginThrottleRepositoryMock()
+			mockAuditRepository := mock.AuditRepositoryMock{}
+			authService := AuthServiceImpl{
+				UserRepository:          &mockUserRepository,
+				LoginThrottleRepository: &mockLoginThrottleRepository,
+				AuditRepository:         &mockAuditRepository,
+			}
+
+			// the user and the caller default to a user and a system admin
+			user, caller := testStep.user, testStep.caller
+			if user.Username == "" {
+				user = dao.User{Username: "test"}
+			}
+			if caller.Username == "" {
+				caller = dao.User{Username: "admin", Roles: mock.SystemAdminRoles}
+			}
+
+			mockUserRepository.On("FindUserById").Return(user, testStep.userError)
+			mockLoginThrottleRepository.On("Reset").Return(nil, testStep.resetError)
+
+			w := httptest.NewRecorder()
+			ctx := mock.GetGinTestContext(w, "DELETE", gin.Params{{Key: "userID", Value: testStep.userID}}, nil)
+
+			token, err := mock.GenerateMockToken(caller)
+			if err != nil {
+				t.Error("Error happened: when generate mock token", "error", err)
+			}
+			claim, _ := middleware.DecodeToken(token)
+			ctx.Set("retrievedToken", claim)
+
+			serve(ctx, authService.Unlock)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedStatusCode != http.StatusOK {
+				return
+			}
+			if len(mockLoginThrottleRepository.ResetKeys) != 1 || mockLoginThrottleRepository.ResetKeys[0] != dao.LoginThrottleUsernamePrefix+"test" {
+				t.Errorf("Expected the username to be unlocked but got %v", mockLoginThrottleRepository.ResetKeys)
+			}
+			if len(mockAuditRepository.Entries) != 1 || mockAuditRepository.Entries[0].Actor != "admin" {
+				t.Errorf("Expected an audit entry by admin but got %v", mockAuditRepository.Entries)
+			}
+		})
+	}
+}
+
 func TestMe(t *testing.T) {
 	// define test struct
 	type authMeTest struct {
@@ -147,7 +375,7 @@ func TestMe(t *testing.T) {
 				"department": "test",
 			},
 			expectedValue: dao.User{
-				IsAdmin: true,
+				Roles: mock.SystemAdminRoles,
 				Department: dao.Department{
 					Name: "test",
 				},
@@ -164,7 +392,7 @@ func TestMe(t *testing.T) {
 				"department": "test2",
 			},
 			expectedValue: dao.User{
-				IsAdmin: true,
+				Roles: mock.SystemAdminRoles,
 				Department: dao.Department{
 					Name: "test",
 				},
@@ -182,7 +410,6 @@ func TestMe(t *testing.T) {
 			},
 			expectedValue: dao.User{
 				Username: "test",
-				IsAdmin:  false,
 				Department: dao.Department{
 					Name: "test",
 				},
@@ -199,7 +426,6 @@ func TestMe(t *testing.T) {
 				"department": "test2",
 			},
 			expectedValue: dao.User{
-				IsAdmin: false,
 				Department: dao.Department{
 					Name: "test",
 				},
@@ -287,8 +513,7 @@ func TestMe(t *testing.T) {
 			}
 
 			// send request
-			authService.Me(ctx)
-
+			serve(ctx, authService.Me)
 			// check status code
 			if w.Code != testStep.expectedStatusCode {
 				t.Errorf("Step %d. Expected status code %d but got %d", i, testStep.expectedStatusCode, w.Code)
@@ -332,8 +557,7 @@ func TestLogoutSimple(t *testing.T) {
 			w := httptest.NewRecorder()
 			ctx := mock.GetGinTestContext(w, "POST", gin.Params{}, nil)
 
-			authService.Logout(ctx)
-
+			serve(ctx, authService.Logout)
 			if w.Code != testStep.expectedStatusCode {
 				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
 			}
@@ -388,7 +612,11 @@ func TestCheckAdmin(t *testing.T) {
 
 			// generate mock token
 			if testStep.setCookie {
-				token, err := mock.GenerateMockToken(dao.User{IsAdmin: testStep.isAdmin})
+				user := dao.User{}
+				if testStep.isAdmin {
+					user.Roles = mock.SystemAdminRoles
+				}
+				token, err := mock.GenerateMockToken(user)
 				if err != nil {
 					t.Error("Error happened: when generate mock token", "error", err)
 				}
@@ -396,8 +624,7 @@ func TestCheckAdmin(t *testing.T) {
 				ctx.Set("retrievedToken", claim)
 			}
 
-			authService.CheckAdmin(ctx)
-
+			serve(ctx, authService.CheckAdmin)
 			if w.Code != testStep.expectedStatusCode {
 				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
 			}
@@ -425,3 +652,390 @@ func TestCheckAdmin(t *testing.T) {
 		})
 	}
 }
+
+func TestPermissions(t *testing.T) {
+	// define test struct
+	type authPermissionsTest struct {
+		user               dao.User
+		allPermissions     []dao.Permission
+		setCookie          bool
+		mockError          error
+		expectedStatusCode int
+		expectedNames      []string
+		expectedScope      string
+		// the departments of the expected permissions, not checked if empty
+		expectedDepartments []uuid.UUID
+	}
+
+	// mock
+	mockUserRepository := mock.NewUserRepositoryMock()
+	mockPermissionRepository := mock.NewPermissionRepositoryMock()
+	authService := AuthServiceImpl{
+		UserRepository:       &mockUserRepository,
+		PermissionRepository: &mockPermissionRepository,
+	}
+
+	departmentID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	otherDepartmentID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	testSteps := []authPermissionsTest{
+		{
+			// users hold their permissions within their department
+			user: dao.User{
+				Username:     "test",
+				DepartmentID: departmentID,
+				Department:   dao.Department{Name: "test"},
+				Permissions:  []dao.Permission{{Name: "workday:write"}},
+			},
+			setCookie:          true,
+			expectedStatusCode: http.StatusOK,
+			expectedNames:      []string{"workday:write"},
+			expectedScope:      dco.PermissionScopeDepartment,
+		},
+		{
+			// roles without a department grant their permissions within each department, duplicates are removed
+			user: dao.User{
+				Username:     "test",
+				DepartmentID: departmentID,
+				Roles: []dao.UserRole{
+					{Role: dao.Role{Name: dao.RolePlanner, Permissions: []dao.Permission{{Name: "workday:assign"}}}},
+					{Role: dao.Role{Name: "assigner", Permissions: []dao.Permission{{Name: "workday:assign"}}}},
+				},
+			},
+			setCookie:          true,
+			expectedStatusCode: http.StatusOK,
+			expectedNames:      []string{"workday:assign"},
+			expectedScope:      dco.PermissionScopeDepartment,
+		},
+		{
+			// the role of a membership is limited to the department of the membership
+			user: dao.User{
+				Username:     "test",
+				DepartmentID: departmentID,
+				Permissions:  []dao.Permission{{Name: "workday:write"}},
+				Roles: []dao.UserRole{
+					{Role: dao.Role{Name: dao.RoleViewer, Permissions: []dao.Permission{{Name: "person:read"}}}, DepartmentID: &departmentID},
+				},
+				Memberships: []dao.DepartmentMembership{
+					{
+						DepartmentID: otherDepartmentID,
+						Department:   dao.Department{BaseModel: dao.BaseModel{ID: otherDepartmentID}},
+						Role:         &dao.Role{Name: dao.RolePlanner, Permissions: []dao.Permission{{Name: "workday:assign"}}},
+					},
+				},
+			},
+			setCookie:           true,
+			expectedStatusCode:  http.StatusOK,
+			expectedNames:       []string{"workday:write", "person:read", "workday:write", "workday:assign"},
+			expectedScope:       dco.PermissionScopeDepartment,
+			expectedDepartments: []uuid.UUID{departmentID, departmentID, otherDepartmentID, otherDepartmentID},
+		},
+		{
+			// admins hold every permission globally
+			user: dao.User{
+				Username: "admin",
+				Roles:    mock.SystemAdminRoles,
+			},
+			allPermissions:     []dao.Permission{{Name: "workday:write"}, {Name: "person:write"}},
+			setCookie:          true,
+			expectedStatusCode: http.StatusOK,
+			expectedNames:      []string{"workday:write", "person:write"},
+			expectedScope:      dco.PermissionScopeGlobal,
+		},
+		{
+			user:               dao.User{},
+			setCookie:          false,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			user:               dao.User{Username: "test"},
+			setCookie:          true,
+			mockError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			user:               dao.User{Username: "test"},
+			setCookie:          true,
+			mockError:          errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			mockUserRepository.On("FindUserByUsername").Return(testStep.user, testStep.mockError)
+			mockPermissionRepository.On("FindAllPermissions").Return(testStep.allPermissions, nil)
+
+			w := httptest.NewRecorder()
+			ctx := mock.GetGinTestContext(w, "GET", gin.Params{}, nil)
+
+			if testStep.setCookie {
+				token, err := mock.GenerateMockToken(testStep.user)
+				if err != nil {
+					t.Error("Error happened: when generate mock token", "error", err)
+				}
+				claim, _ := middleware.DecodeToken(token)
+				ctx.Set("retrievedToken", claim)
+			}
+
+			serve(ctx, authService.Permissions)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var responseBody dto.APIResponse[[]dco.EffectivePermissionResponse]
+			if err := json.Unmarshal(w.Body.Bytes(), &responseBody); err != nil {
+				t.Error("Error happened: when unmarshal response body", "error", err)
+			}
+			if len(responseBody.Data) != len(testStep.expectedNames) {
+				t.Fatalf("Expected %d permissions but got %d", len(testStep.expectedNames), len(responseBody.Data))
+			}
+			for j, permission := range responseBody.Data {
+				if permission.Name != testStep.expectedNames[j] {
+					t.Errorf("Expected permission %s but got %s", testStep.expectedNames[j], permission.Name)
+				}
+				if permission.Scope.Type != testStep.expectedScope {
+					t.Errorf("Expected scope %s but got %s", testStep.expectedScope, permission.Scope.Type)
+				}
+				if len(testStep.expectedDepartments) > 0 && (permission.Scope.DepartmentID == nil || *permission.Scope.DepartmentID != testStep.expectedDepartments[j]) {
+					t.Errorf("Expected permission %s within department %s but got %v", permission.Name, testStep.expectedDepartments[j], permission.Scope.DepartmentID)
+				}
+			}
+		})
+	}
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	// define test struct
+	type authLoginTwoFactorTest struct {
+		request            map[string]interface{}
+		challengeCookie    string
+		challenge          dao.LoginChallenge
+		challengeError     error
+		deleteError        error
+		expectedStatusCode int
+		expectedReset      int
+		cookieExpected     bool
+	}
+
+	enabledAt := time.Now()
+	user := dao.User{Username: "test", TwoFactor: &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt}}
+	validCode := currentTOTPCode(t)
+	validChallenge := dao.LoginChallenge{ExpiresAt: time.Now().Add(time.Minute)}
+
+	testSteps := []authLoginTwoFactorTest{
+		{
+			request:            map[string]interface{}{"code": validCode},
+			challengeCookie:    "challenge",
+			challenge:          validChallenge,
+			expectedStatusCode: http.StatusOK,
+			expectedReset:      1,
+			cookieExpected:     true,
+		},
+		{
+			request:            map[string]interface{}{"recovery_code": "abcd-efgh"},
+			challengeCookie:    "challenge",
+			challenge:          validChallenge,
+			expectedStatusCode: http.StatusOK,
+			expectedReset:      1,
+			cookieExpected:     true,
+		},
+		{
+			request:            map[string]interface{}{},
+			challengeCookie:    "challenge",
+			challenge:          validChallenge,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// the password step was skipped
+			request:            map[string]interface{}{"code": validCode},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			request:            map[string]interface{}{"code": validCode},
+			challengeCookie:    "challenge",
+			challengeError:     gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			request:            map[string]interface{}{"code": validCode},
+			challengeCookie:    "challenge",
+			challenge:          dao.LoginChallenge{ExpiresAt: time.Now().Add(-time.Minute)},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			request:            map[string]interface{}{"code": "000000"},
+			challengeCookie:    "challenge",
+			challenge:          validChallenge,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			// the challenge was completed by a concurrent request
+			request:            map[string]interface{}{"code": validCode},
+			challengeCookie:    "challenge",
+			challenge:          validChallenge,
+			deleteError:        gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test Step: %d", i), func(t *testing.T) {
+			mockUserRepository := mock.NewUserRepositoryMock()
+			mockSessionRepository := mock.NewSessionRepositoryMock()
+			mockLoginThrottleRepository := mock.NewLoginThrottleRepositoryMock()
+			mockTwoFactorRepository := mock.NewTwoFactorRepositoryMock()
+			authService := AuthServiceImpl{
+				UserRepository:          &mockUserRepository,
+				SessionRepository:       &mockSessionRepository,
+				LoginThrottleRepository: &mockLoginThrottleRepository,
+				AuditRepository:         &mock.AuditRepositoryMock{},
+				LoginPolicy:             policy.DefaultLoginPolicy,
+				TwoFactorRepository:     &mockTwoFactorRepository,
+			}
+
+			// Set mock data
+			mockUserRepository.On("FindUserById").Return(user, nil)
+			mockLoginThrottleRepository.On("RecordFailure").Return(dao.LoginThrottle{Failures: 1, LastFailureAt: time.Now()}, nil)
+			if testStep.challengeError == nil {
+				mockTwoFactorRepository.On("FindLoginChallengeByTokenHash").Return(testStep.challenge, nil)
+			} else {
+				mockTwoFactorRepository.On("FindLoginChallengeByTokenHash").Return(nil, testStep.challengeError)
+			}
+			mockTwoFactorRepository.On("DeleteLoginChallenge").Return(nil, testStep.deleteError)
+
+			w := httptest.NewRecorder()
+			builder := mock.NewTestContextBuilder().WithResponseRecorder(w).WithMethod("POST").WithBody(testStep.request)
+			if testStep.challengeCookie != "" {
+				builder = builder.WithHeader("Cookie", dco.TwoFactorChallengeCookie+"="+testStep.challengeCookie)
+			}
+			ctx, err := builder.Build()
+			if err != nil {
+				t.Fatalf("Error happened: when build context %v", err)
+			}
+
+			serve(ctx, authService.LoginTwoFactor)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if len(mockLoginThrottleRepository.ResetKeys) != testStep.expectedReset {
+				t.Errorf("Expected %d reset keys but got %v", testStep.expectedReset, mockLoginThrottleRepository.ResetKeys)
+			}
+
+			accessTokenSet := false
+			for _, cookie := range w.Result().Cookies() {
+				if cookie.Name == dco.AccessTokenCookie && cookie.Value != "" {
+					accessTokenSet = true
+				}
+			}
+			if accessTokenSet != testStep.cookieExpected {
+				t.Errorf("Expected access token cookie to be set: %v", testStep.cookieExpected)
+			}
+		})
+	}
+}
+
+func TestLoginWithTwoFactor(t *testing.T) {
+	/* Users with two-factor authentication receive a login challenge instead of a session */
+	mockUserRepository := mock.NewUserRepositoryMock()
+	mockSessionRepository := mock.NewSessionRepositoryMock()
+	mockLoginThrottleRepository := mock.NewLoginThrottleRepositoryMock()
+	mockTwoFactorRepository := mock.NewTwoFactorRepositoryMock()
+	authService := AuthServiceImpl{
+		UserRepository:          &mockUserRepository,
+		SessionRepository:       &mockSessionRepository,
+		LoginThrottleRepository: &mockLoginThrottleRepository,
+		AuditRepository:         &mock.AuditRepositoryMock{},
+		LoginPolicy:             policy.DefaultLoginPolicy,
+		AuthProviders:           authprovider.NewRegistry(authprovider.LocalProvider{UserRepository: &mockUserRepository}),
+		TwoFactorRepository:     &mockTwoFactorRepository,
+	}
+
+	enabledAt := time.Now()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.MinCost)
+	mockUserRepository.On("FindUserByUsername").Return(dao.User{
+		Username:  "test",
+		Password:  string(hashedPassword),
+		TwoFactor: &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	ctx := mock.GetGinTestContext(w, "POST", gin.Params{}, map[string]interface{}{
+		"username": "test",
+		"password": "test",
+	})
+
+	serve(ctx, authService.Login)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status code %d but got %d", http.StatusAccepted, w.Code)
+	}
+	// the failures of the username are kept until the second factor is checked
+	if len(mockLoginThrottleRepository.ResetKeys) != 0 {
+		t.Errorf("Expected no reset keys but got %v", mockLoginThrottleRepository.ResetKeys)
+	}
+
+	challengeSet := false
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == dco.AccessTokenCookie {
+			t.Errorf("Expected no access token before the second factor")
+		}
+		if cookie.Name == dco.TwoFactorChallengeCookie && cookie.Value != "" {
+			challengeSet = true
+		}
+	}
+	if !challengeSet {
+		t.Errorf("Expected the login challenge cookie to be set")
+	}
+}
+
+func TestMeLinkedPerson(t *testing.T) {
+	/* Test that the person linked to the user is returned, only its id if the planner-backend fails */
+	mockUserRepository := mock.NewUserRepositoryMock()
+	plannerMock := mock.PlannerClientMock{Person: dco.PlannerPerson{ID: "p1", FirstName: "Jane", LastName: "Doe"}}
+
+	personID := "p1"
+	user := dao.User{Username: "test", PersonID: &personID}
+
+	type meLinkedPersonTest struct {
+		user           dao.User
+		planner        *mock.PlannerClientMock
+		plannerError   error
+		expectedPerson *dco.PlannerPerson
+	}
+
+	testSteps := []meLinkedPersonTest{
+		{user: user, planner: &plannerMock, expectedPerson: &plannerMock.Person},
+		{user: user, planner: &plannerMock, plannerError: errors.New("some error"), expectedPerson: &dco.PlannerPerson{ID: "p1"}},
+		{user: user, expectedPerson: &dco.PlannerPerson{ID: "p1"}},
+		{user: dao.User{Username: "test"}, planner: &plannerMock},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			authService := AuthServiceImpl{UserRepository: &mockUserRepository}
+			if testStep.planner != nil {
+				testStep.planner.Error = testStep.plannerError
+				authService.Planner = testStep.planner
+			}
+			mockUserRepository.On("FindUserByUsername").Return(testStep.user, nil)
+
+			w := httptest.NewRecorder()
+			ctx, _ := mock.NewTestContextBuilder().WithMethod("GET").WithResponseRecorder(w).Build()
+			token, _ := mock.GenerateMockToken(testStep.user)
+			claim, _ := middleware.DecodeToken(token)
+			ctx.Set("retrievedToken", claim)
+
+			serve(ctx, authService.Me)
+			var responseBody dto.APIResponse[dco.AuthResponse]
+			if err := json.Unmarshal(w.Body.Bytes(), &responseBody); err != nil {
+				t.Fatal("Error happened: when unmarshal response body", "error", err)
+			}
+			person := responseBody.Data.Person
+			if (person == nil) != (testStep.expectedPerson == nil) || (person != nil && *person != *testStep.expectedPerson) {
+				t.Errorf("Step %d. Expected person %v but got %v", i, testStep.expectedPerson, person)
+			}
+		})
+	}
+}
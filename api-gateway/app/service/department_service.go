@@ -5,6 +5,7 @@ import (
 	"api-gateway/app/domain/dao"
 	"api-gateway/app/domain/dco"
 	"api-gateway/app/pkg"
+	"api-gateway/app/planner"
 	"api-gateway/app/repository"
 	"log/slog"
 	"net/http"
@@ -21,10 +22,18 @@ type DepartmentService interface {
 	AddDepartment(c *gin.Context)
 	UpdateDepartment(c *gin.Context)
 	DeleteDepartment(c *gin.Context)
+
+	// Used by the planner-backend to report its changes, they are not pushed back
+	SyncDepartment(c *gin.Context)
+	RemoveDepartment(c *gin.Context)
+	ReconcileDepartments(c *gin.Context)
+	Reconcile() (dco.DepartmentReconciliationResponse, error)
 }
 
 type DepartmentServiceImpl struct {
 	DepartmentRepository repository.DepartmentRepository
+	// Pushes the changes to the planner-backend, nil if it is not configured
+	Planner planner.Client
 }
 
 func (d DepartmentServiceImpl) GetAllDepartments(c *gin.Context) {
@@ -32,13 +41,13 @@ func (d DepartmentServiceImpl) GetAllDepartments(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all departments")
 
 	rawData, err := d.DepartmentRepository.FindAllDepartments()
 	if err != nil {
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapDepartmentListToDepartmentResponseList(rawData)
@@ -51,14 +60,14 @@ func (d DepartmentServiceImpl) GetDepartmentById(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get department by id")
 
 	id := c.Param("departmentID")
 	departmentID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error when parsing uuid. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	rawData, err := d.DepartmentRepository.FindDepartmentById(departmentID)
@@ -67,10 +76,12 @@ func (d DepartmentServiceImpl) GetDepartmentById(c *gin.Context) {
 		break
 	case gorm.ErrRecordNotFound:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapDepartmentToDepartmentResponse(rawData)
@@ -84,13 +95,13 @@ func (d DepartmentServiceImpl) AddDepartment(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add department")
 
 	var rawRequest dco.DepartmentRequest
 	if err := c.ShouldBindJSON(&rawRequest); err != nil {
 		slog.Error("Error happened: when mapping request from FE. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	request := mapDepartmentRequestToDepartment(rawRequest)
 
@@ -98,19 +109,40 @@ func (d DepartmentServiceImpl) AddDepartment(c *gin.Context) {
 	_, err := d.DepartmentRepository.FindDepartmentByName(request.Name)
 	switch err {
 	case nil:
-		pkg.PanicException(constant.Conflict)
+		pkg.Abort(c, pkg.NewError(constant.Conflict))
+		return
 	case gorm.ErrRecordNotFound:
 		break
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if request.PlannerID == "" {
+		request.PlannerID = uuid.NewString()
+	} else {
+		_, err := d.DepartmentRepository.FindDepartmentByPlannerID(request.PlannerID)
+		switch err {
+		case nil:
+			pkg.Abort(c, pkg.NewError(constant.Conflict))
+			return
+		case gorm.ErrRecordNotFound:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
 	}
 
 	rawData, err := d.DepartmentRepository.Save(&request)
 	if err != nil {
 		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
+	d.pushSave(rawData)
 
 	data := mapDepartmentToDepartmentResponse(rawData)
 
@@ -122,35 +154,39 @@ func (d DepartmentServiceImpl) UpdateDepartment(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update department")
 
 	id := c.Param("departmentID")
 	departmentID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error when parsing uuid. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var rawRequest dco.DepartmentRequest
 	if err := c.ShouldBindJSON(&rawRequest); err != nil {
 		slog.Error("Error happened: when mapping request from FE. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	request := mapDepartmentRequestToDepartment(rawRequest)
 
 	oldData, err := d.DepartmentRepository.FindDepartmentById(departmentID)
 	if err != nil {
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	}
 
 	oldData.Name = request.Name
 	rawData, err := d.DepartmentRepository.Save(&oldData)
 	if err != nil {
 		slog.Error("Error when updating data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
+	d.pushSave(rawData)
 
 	data := mapDepartmentToDepartmentResponse(rawData)
 
@@ -163,14 +199,27 @@ func (d DepartmentServiceImpl) DeleteDepartment(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete department")
 
 	id := c.Param("departmentID")
 	departmentID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error when parsing uuid. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	department, err := d.DepartmentRepository.FindDepartmentById(departmentID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	err = d.DepartmentRepository.DeleteDepartmentById(departmentID)
@@ -178,10 +227,18 @@ func (d DepartmentServiceImpl) DeleteDepartment(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when deleting data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if d.Planner != nil && department.PlannerID != "" {
+		if err := d.Planner.DeleteDepartment(department.PlannerID); err != nil {
+			slog.Error("Error happened: when push department to planner-backend", "department", department.PlannerID, "error", err)
+		}
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -192,6 +249,16 @@ var departmentServiceSet = wire.NewSet(
 	wire.Bind(new(DepartmentService), new(*DepartmentServiceImpl)),
 )
 
+func (d DepartmentServiceImpl) pushSave(department dao.Department) {
+	/* Pushes a created or renamed department to the planner-backend, failures are repaired by the reconciliation */
+	if d.Planner == nil || department.PlannerID == "" {
+		return
+	}
+	if err := d.Planner.SaveDepartment(dco.PlannerDepartment{ID: department.PlannerID, Name: department.Name}); err != nil {
+		slog.Error("Error happened: when push department to planner-backend", "department", department.PlannerID, "error", err)
+	}
+}
+
 func mapDepartmentToDepartmentResponse(department dao.Department) dco.DepartmentResponse {
 	/* mapDepartmentToDepartmentResponse is a function to map department to department response
 	 * @param department is dao.Department
@@ -203,7 +270,8 @@ func mapDepartmentToDepartmentResponse(department dao.Department) dco.Department
 			CreatedAt: department.CreatedAt,
 			UpdatedAt: department.UpdatedAt,
 		},
-		Name: department.Name,
+		Name:      department.Name,
+		PlannerID: department.PlannerID,
 	}
 }
 
@@ -225,6 +293,7 @@ func mapDepartmentRequestToDepartment(req dco.DepartmentRequest) dao.Department
 	 * @return dao.Department
 	 */
 	return dao.Department{
-		Name: req.Name,
+		Name:      req.Name,
+		PlannerID: req.PlannerID,
 	}
 }
@@ -75,8 +75,7 @@ func TestUpdateDepartment(t *testing.T) {
 			ctx := mock.GetGinTestContext(w, "PUT", testStep.ParamsToGinParams(), testStep.mockRequestData)
 
 			// Test function
-			departmentService.UpdateDepartment(ctx)
-
+			serve(ctx, departmentService.UpdateDepartment)
 			// Assert
 			// Get response from GIN context
 			response := w.Result()
@@ -149,8 +148,7 @@ func TestDeleteDepartment(t *testing.T) {
 			ctx := mock.GetGinTestContext(w, "DELETE", testStep.ParamsToGinParams(), nil)
 
 			// Test function
-			departmentService.DeleteDepartment(ctx)
-
+			serve(ctx, departmentService.DeleteDepartment)
 			// Assert
 			// Get response from GIN context
 			response := w.Result()
@@ -222,8 +220,7 @@ func TestAddDepartment(t *testing.T) {
 			ctx := mock.GetGinTestContext(w, "POST", gin.Params{}, testStep.mockRequestData)
 
 			// Test function
-			departmentService.AddDepartment(ctx)
-
+			serve(ctx, departmentService.AddDepartment)
 			// Assert
 			// Get response from GIN context
 			response := w.Result()
@@ -313,8 +310,7 @@ func TestGetAllDepartments(t *testing.T) {
 			ctx := mock.GetGinTestContext(w, "GET", gin.Params{}, nil)
 
 			// Test function
-			departmentService.GetAllDepartments(ctx)
-
+			serve(ctx, departmentService.GetAllDepartments)
 			// Assert
 			// Get response from GIN context
 			response := w.Result()
@@ -394,8 +390,7 @@ func TestGetDepartmentById(t *testing.T) {
 			}, nil)
 
 			// Test function
-			departmentService.GetDepartmentById(ctx)
-
+			serve(ctx, departmentService.GetDepartmentById)
 			// Assert
 			// Get response from GIN context
 			response := w.Result()
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (d DepartmentServiceImpl) SyncDepartment(c *gin.Context) {
+	/* SyncDepartment applies a department created or renamed in the planner-backend
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program sync department")
+
+	plannerID := c.Param("plannerID")
+
+	var request dco.DepartmentSyncRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request from planner-backend. Error", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	department, err := d.DepartmentRepository.FindDepartmentByPlannerID(plannerID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		// a department of the same name which is not linked yet is taken over
+		department, err = d.DepartmentRepository.FindDepartmentByName(request.Name)
+		switch err {
+		case nil:
+			if department.PlannerID != "" {
+				pkg.Abort(c, pkg.NewError(constant.Conflict))
+				return
+			}
+		case gorm.ErrRecordNotFound:
+			department = dao.Department{}
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
+		department.PlannerID = plannerID
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if department.Name != request.Name {
+		other, err := d.DepartmentRepository.FindDepartmentByName(request.Name)
+		switch err {
+		case nil:
+			if other.ID != department.ID {
+				pkg.Abort(c, pkg.NewError(constant.Conflict))
+				return
+			}
+		case gorm.ErrRecordNotFound:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
+	}
+
+	department.Name = request.Name
+	rawData, err := d.DepartmentRepository.Save(&department)
+	if err != nil {
+		slog.Error("Error when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapDepartmentToDepartmentResponse(rawData)))
+}
+
+func (d DepartmentServiceImpl) RemoveDepartment(c *gin.Context) {
+	/* RemoveDepartment applies a department deleted in the planner-backend
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program remove department")
+
+	department, err := d.DepartmentRepository.FindDepartmentByPlannerID(c.Param("plannerID"))
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		// already removed
+		c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+		return
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if err := d.DepartmentRepository.DeleteDepartmentById(department.ID); err != nil {
+		slog.Error("Error when deleting data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (d DepartmentServiceImpl) ReconcileDepartments(c *gin.Context) {
+	/* ReconcileDepartments runs the reconciliation with the planner-backend on demand
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program reconcile departments")
+
+	data, err := d.Reconcile()
+	if err != nil {
+		slog.Error("Error happened: when reconcile departments", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (d DepartmentServiceImpl) Reconcile() (dco.DepartmentReconciliationResponse, error) {
+	/**
+	* Repairs the departments of both services after a failed push or report.
+	* The api-gateway wins: its departments are pushed to the planner-backend. Departments only known to the
+	* planner-backend are deleted there if they were deleted in the api-gateway and imported otherwise.
+	**/
+	result := dco.DepartmentReconciliationResponse{Pushed: []string{}, Imported: []string{}, Deleted: []string{}, Conflicts: []string{}}
+	if d.Planner == nil {
+		return result, errors.New("PLANNER_BACKEND_TARGET is not set")
+	}
+
+	plannerDepartments, err := d.Planner.GetDepartments()
+	if err != nil {
+		return result, err
+	}
+	departments, err := d.DepartmentRepository.FindAllDepartments()
+	if err != nil {
+		return result, err
+	}
+
+	remaining := map[string]dco.PlannerDepartment{}
+	for _, department := range plannerDepartments {
+		remaining[department.ID] = department
+	}
+	names := map[string]bool{}
+
+	for _, department := range departments {
+		names[department.Name] = true
+
+		if department.PlannerID == "" {
+			// link departments created before the synchronization by name
+			department.PlannerID = uuid.NewString()
+			for _, plannerDepartment := range remaining {
+				if plannerDepartment.Name == department.Name && !d.isLinked(departments, plannerDepartment.ID) {
+					department.PlannerID = plannerDepartment.ID
+					break
+				}
+			}
+			if _, err := d.DepartmentRepository.Save(&department); err != nil {
+				return result, err
+			}
+		}
+
+		plannerDepartment, exists := remaining[department.PlannerID]
+		delete(remaining, department.PlannerID)
+		if exists && plannerDepartment.Name == department.Name {
+			continue
+		}
+		if err := d.Planner.SaveDepartment(dco.PlannerDepartment{ID: department.PlannerID, Name: department.Name}); err != nil {
+			return result, err
+		}
+		result.Pushed = append(result.Pushed, department.Name)
+	}
+
+	if len(remaining) == 0 {
+		return result, nil
+	}
+
+	deletedIDs, err := d.DepartmentRepository.FindDeletedPlannerIDs()
+	if err != nil {
+		return result, err
+	}
+	deleted := map[string]bool{}
+	for _, id := range deletedIDs {
+		deleted[id] = true
+	}
+
+	for _, plannerDepartment := range remaining {
+		switch {
+		case deleted[plannerDepartment.ID]:
+			if err := d.Planner.DeleteDepartment(plannerDepartment.ID); err != nil {
+				return result, err
+			}
+			result.Deleted = append(result.Deleted, plannerDepartment.Name)
+		case names[plannerDepartment.Name]:
+			// two departments of the same name have to be resolved by an admin
+			slog.Warn("Department of planner-backend conflicts with department of api-gateway", "department", plannerDepartment.ID, "name", plannerDepartment.Name)
+			result.Conflicts = append(result.Conflicts, plannerDepartment.Name)
+		default:
+			department := dao.Department{Name: plannerDepartment.Name, PlannerID: plannerDepartment.ID}
+			if _, err := d.DepartmentRepository.Save(&department); err != nil {
+				return result, err
+			}
+			names[department.Name] = true
+			result.Imported = append(result.Imported, department.Name)
+		}
+	}
+
+	slog.Info("Reconciled departments", "pushed", len(result.Pushed), "imported", len(result.Imported), "deleted", len(result.Deleted), "conflicts", len(result.Conflicts))
+	return result, nil
+}
+
+func (d DepartmentServiceImpl) isLinked(departments []dao.Department, plannerID string) bool {
+	for _, department := range departments {
+		if department.PlannerID == plannerID {
+			return true
+		}
+	}
+	return false
+}
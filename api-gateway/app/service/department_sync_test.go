@@ -0,0 +1,255 @@
+package service
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/mock"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestDepartmentChangesArePushed(t *testing.T) {
+	/* Test that created, renamed and deleted departments are pushed to the planner-backend */
+	mockDepartmentRepository := mock.NewDepartmentRepositoryMock()
+	mockPlanner := mock.PlannerClientMock{}
+	departmentService := DepartmentServiceImpl{
+		DepartmentRepository: &mockDepartmentRepository,
+		Planner:              &mockPlanner,
+	}
+	department := dao.Department{
+		BaseModel: dao.BaseModel{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001")},
+		Name:      "Department 1",
+		PlannerID: "planner1",
+	}
+	params := gin.Params{{Key: "departmentID", Value: department.ID.String()}}
+
+	// create
+	mockDepartmentRepository.On("FindDepartmentByName").Return(nil, gorm.ErrRecordNotFound)
+	mockDepartmentRepository.On("FindDepartmentByPlannerID").Return(nil, gorm.ErrRecordNotFound)
+	mockDepartmentRepository.On("Save").Return(department, nil)
+	w := httptest.NewRecorder()
+	departmentService.AddDepartment(mock.GetGinTestContext(w, "POST", gin.Params{}, map[string]interface{}{"name": "Department 1", "planner_id": "planner1"}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, but got %d", http.StatusCreated, w.Code)
+	}
+	if mockDepartmentRepository.Saved[0].PlannerID != "planner1" {
+		t.Errorf("Expected the given planner id to be saved, got %q", mockDepartmentRepository.Saved[0].PlannerID)
+	}
+
+	// rename, the planner-backend is unavailable
+	mockPlanner.Error = errors.New("unavailable")
+	mockDepartmentRepository.On("FindDepartmentById").Return(department, nil)
+	mockDepartmentRepository.On("Save").Return(dao.Department{BaseModel: department.BaseModel, Name: "Department 2", PlannerID: "planner1"}, nil)
+	w = httptest.NewRecorder()
+	departmentService.UpdateDepartment(mock.GetGinTestContext(w, "PUT", params, map[string]interface{}{"name": "Department 2"}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the rename to succeed without planner-backend, got %d", w.Code)
+	}
+
+	// delete
+	mockPlanner.Error = nil
+	w = httptest.NewRecorder()
+	departmentService.DeleteDepartment(mock.GetGinTestContext(w, "DELETE", params, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, but got %d", http.StatusOK, w.Code)
+	}
+
+	expectedSaved := []dco.PlannerDepartment{{ID: "planner1", Name: "Department 1"}, {ID: "planner1", Name: "Department 2"}}
+	if !reflect.DeepEqual(mockPlanner.Saved, expectedSaved) {
+		t.Errorf("Expected %v to be pushed, got %v", expectedSaved, mockPlanner.Saved)
+	}
+	if !reflect.DeepEqual(mockPlanner.Deleted, []string{"planner1"}) {
+		t.Errorf("Expected planner1 to be deleted, got %v", mockPlanner.Deleted)
+	}
+}
+
+func TestSyncDepartment(t *testing.T) {
+	/* Test that departments reported by the planner-backend are applied without pushing them back */
+	linked := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "Department 1", PlannerID: "planner1"}
+	unlinked := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "Department 2"}
+	other := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "Department 2", PlannerID: "planner2"}
+
+	type syncDepartmentTest struct {
+		name               string
+		request            map[string]interface{}
+		findByPlannerID    interface{}
+		findByPlannerIDErr error
+		findByName         interface{}
+		findByNameErr      error
+		expectedStatusCode int
+		expectedSaved      *dao.Department
+	}
+
+	testSteps := []syncDepartmentTest{
+		{
+			name:               "new department is created",
+			request:            map[string]interface{}{"name": "Department 3"},
+			findByPlannerIDErr: gorm.ErrRecordNotFound,
+			findByNameErr:      gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusOK,
+			expectedSaved:      &dao.Department{Name: "Department 3", PlannerID: "planner1"},
+		},
+		{
+			name:               "linked department is renamed",
+			request:            map[string]interface{}{"name": "Department 3"},
+			findByPlannerID:    linked,
+			findByNameErr:      gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusOK,
+			expectedSaved:      &dao.Department{BaseModel: linked.BaseModel, Name: "Department 3", PlannerID: "planner1"},
+		},
+		{
+			name:               "department of the same name is linked",
+			request:            map[string]interface{}{"name": "Department 2"},
+			findByPlannerIDErr: gorm.ErrRecordNotFound,
+			findByName:         unlinked,
+			expectedStatusCode: http.StatusOK,
+			expectedSaved:      &dao.Department{BaseModel: unlinked.BaseModel, Name: "Department 2", PlannerID: "planner1"},
+		},
+		{
+			name:               "name of another linked department conflicts",
+			request:            map[string]interface{}{"name": "Department 2"},
+			findByPlannerIDErr: gorm.ErrRecordNotFound,
+			findByName:         other,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "rename to the name of another department conflicts",
+			request:            map[string]interface{}{"name": "Department 2"},
+			findByPlannerID:    linked,
+			findByName:         other,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "department without name is rejected",
+			request:            map[string]interface{}{},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			mockDepartmentRepository := mock.NewDepartmentRepositoryMock()
+			mockPlanner := mock.PlannerClientMock{}
+			departmentService := DepartmentServiceImpl{
+				DepartmentRepository: &mockDepartmentRepository,
+				Planner:              &mockPlanner,
+			}
+			mockDepartmentRepository.On("FindDepartmentByPlannerID").Return(testStep.findByPlannerID, testStep.findByPlannerIDErr)
+			mockDepartmentRepository.On("FindDepartmentByName").Return(testStep.findByName, testStep.findByNameErr)
+
+			w := httptest.NewRecorder()
+			ctx := mock.GetGinTestContext(w, "PUT", gin.Params{{Key: "plannerID", Value: "planner1"}}, testStep.request)
+			serve(ctx, departmentService.SyncDepartment)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, but got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedSaved == nil {
+				if len(mockDepartmentRepository.Saved) != 0 {
+					t.Errorf("Expected nothing to be saved, got %v", mockDepartmentRepository.Saved)
+				}
+			} else if len(mockDepartmentRepository.Saved) != 1 || !reflect.DeepEqual(mockDepartmentRepository.Saved[0], *testStep.expectedSaved) {
+				t.Errorf("Expected %v to be saved, got %v", *testStep.expectedSaved, mockDepartmentRepository.Saved)
+			}
+			if len(mockPlanner.Saved) != 0 {
+				t.Errorf("Expected the department not to be pushed back, got %v", mockPlanner.Saved)
+			}
+		})
+	}
+}
+
+func TestRemoveDepartment(t *testing.T) {
+	/* Test that departments deleted in the planner-backend are deleted without pushing them back */
+	department := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "Department 1", PlannerID: "planner1"}
+
+	for i, found := range []bool{true, false} {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			mockDepartmentRepository := mock.NewDepartmentRepositoryMock()
+			mockPlanner := mock.PlannerClientMock{}
+			departmentService := DepartmentServiceImpl{
+				DepartmentRepository: &mockDepartmentRepository,
+				Planner:              &mockPlanner,
+			}
+			if found {
+				mockDepartmentRepository.On("FindDepartmentByPlannerID").Return(department, nil)
+			} else {
+				mockDepartmentRepository.On("FindDepartmentByPlannerID").Return(nil, gorm.ErrRecordNotFound)
+			}
+
+			w := httptest.NewRecorder()
+			departmentService.RemoveDepartment(mock.GetGinTestContext(w, "DELETE", gin.Params{{Key: "plannerID", Value: "planner1"}}, nil))
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status code %d, but got %d", http.StatusOK, w.Code)
+			}
+			if found != (len(mockDepartmentRepository.Deleted) == 1) {
+				t.Errorf("Expected deleted %v, got %v", found, mockDepartmentRepository.Deleted)
+			}
+			if len(mockPlanner.Deleted) != 0 {
+				t.Errorf("Expected the department not to be pushed back, got %v", mockPlanner.Deleted)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	/* Test that the reconciliation repairs the departments of both services */
+	mockDepartmentRepository := mock.NewDepartmentRepositoryMock()
+	mockPlanner := mock.PlannerClientMock{
+		Departments: []dco.PlannerDepartment{
+			{ID: "in-sync", Name: "In Sync"},
+			{ID: "renamed", Name: "Old Name"},
+			{ID: "legacy", Name: "Legacy"},
+			{ID: "deleted", Name: "Deleted"},
+			{ID: "conflict", Name: "In Sync"},
+			{ID: "new", Name: "New"},
+		},
+	}
+	departmentService := DepartmentServiceImpl{
+		DepartmentRepository: &mockDepartmentRepository,
+		Planner:              &mockPlanner,
+	}
+	mockDepartmentRepository.On("FindAllDepartments").Return([]dao.Department{
+		{Name: "In Sync", PlannerID: "in-sync"},
+		{Name: "New Name", PlannerID: "renamed"},
+		{Name: "Legacy"},
+		{Name: "Missing", PlannerID: "missing"},
+	}, nil)
+	mockDepartmentRepository.On("FindDeletedPlannerIDs").Return([]string{"deleted"}, nil)
+
+	result, err := departmentService.Reconcile()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	sort.Strings(result.Pushed)
+	if !reflect.DeepEqual(result.Pushed, []string{"Missing", "New Name"}) {
+		t.Errorf("Expected Missing and New Name to be pushed, got %v", result.Pushed)
+	}
+	if !reflect.DeepEqual(result.Imported, []string{"New"}) || !reflect.DeepEqual(result.Deleted, []string{"Deleted"}) || !reflect.DeepEqual(result.Conflicts, []string{"In Sync"}) {
+		t.Errorf("Expected New imported, Deleted deleted and In Sync conflicting, got %+v", result)
+	}
+	if !reflect.DeepEqual(mockPlanner.Deleted, []string{"deleted"}) {
+		t.Errorf("Expected the deleted department to be deleted in the planner-backend, got %v", mockPlanner.Deleted)
+	}
+
+	// the legacy department is linked by name, the new department is imported
+	expectedSaved := []dao.Department{{Name: "Legacy", PlannerID: "legacy"}, {Name: "New", PlannerID: "new"}}
+	if !reflect.DeepEqual(mockDepartmentRepository.Saved, expectedSaved) {
+		t.Errorf("Expected %v to be saved, got %v", expectedSaved, mockDepartmentRepository.Saved)
+	}
+
+	// without the planner-backend nothing is reconciled
+	departmentService.Planner = nil
+	if _, err := departmentService.Reconcile(); err == nil {
+		t.Errorf("Expected an error without planner-backend")
+	}
+}
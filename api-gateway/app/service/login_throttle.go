@@ -0,0 +1,107 @@
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/metrics"
+	"api-gateway/app/pkg"
+	"api-gateway/app/repository"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/**
+* Failed logins are tracked per username and per client IP in the database.
+* Every failure doubles the delay until the next attempt is accepted, after too many
+* failures the username or the IP is locked for a while and an audit entry is written.
+**/
+
+func loginThrottleKeys(c *gin.Context, username string) (string, string) {
+	return dao.LoginThrottleUsernamePrefix + username, dao.LoginThrottleIPPrefix + c.ClientIP()
+}
+
+func (a AuthServiceImpl) checkLoginThrottle(c *gin.Context, step string, keys ...string) error {
+	/* Rejects the attempt of the login step with 429 if one of the keys is locked or has to wait, the Retry-After header is set */
+	for _, key := range keys {
+		throttle, err := a.LoginThrottleRepository.FindLoginThrottle(key)
+		if err != nil {
+			slog.Error("Error happened: when get data from database", "error", err)
+			return pkg.NewError(constant.UnknownError)
+		}
+
+		var retryAfter time.Duration
+		if throttle.IsLocked() {
+			retryAfter = time.Until(*throttle.LockedUntil)
+		} else if throttle.Failures > 0 {
+			retryAfter = time.Until(throttle.LastFailureAt.Add(a.LoginPolicy.Delay(throttle.Failures)))
+		}
+
+		if retryAfter > 0 {
+			slog.Warn("Login attempt rejected by throttle", "key", key, "failures", throttle.Failures, "retryAfter", retryAfter)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			metrics.Login(step, metrics.LoginThrottled)
+			return pkg.NewError(constant.TooManyRequests)
+		}
+	}
+	return nil
+}
+
+func (a AuthServiceImpl) recordLoginFailure(c *gin.Context, step string, usernameKey string, ipKey string) {
+	/* Counts the failed attempt of the login step and locks the keys which reached their limit */
+	metrics.Login(step, metrics.LoginFailure)
+
+	limits := map[string]int{
+		usernameKey: a.LoginPolicy.MaxFailures,
+		ipKey:       a.LoginPolicy.MaxFailuresPerIP,
+	}
+
+	for key, maxFailures := range limits {
+		throttle, err := a.LoginThrottleRepository.RecordFailure(key, a.LoginPolicy.FailureWindow)
+		if err != nil {
+			// the attempt is rejected anyway, so the error is not returned to the client
+			slog.Error("Error happened: when record login failure", "error", err)
+			continue
+		}
+
+		if maxFailures <= 0 || throttle.Failures < maxFailures || throttle.IsLocked() {
+			continue
+		}
+
+		lockedUntil := time.Now().Add(a.LoginPolicy.LockoutDuration)
+		if err := a.LoginThrottleRepository.Lock(key, lockedUntil); err != nil {
+			slog.Error("Error happened: when lock login", "error", err)
+			continue
+		}
+
+		slog.Warn("Login locked after too many failures", "key", key, "failures", throttle.Failures, "lockedUntil", lockedUntil)
+		a.audit(&dao.AuditEntry{
+			Event:     dao.AuditEventLoginLocked,
+			Subject:   key,
+			IPAddress: c.ClientIP(),
+			Details:   fmt.Sprintf("%d failed logins, locked until %s", throttle.Failures, lockedUntil.Format(time.RFC3339)),
+		})
+	}
+}
+
+func (a AuthServiceImpl) resetLoginThrottle(key string) {
+	/* A successful login forgets the failures of the username, the IP keeps its failures */
+	if err := a.LoginThrottleRepository.Reset(key); err != nil {
+		slog.Error("Error happened: when reset login throttle", "error", err)
+	}
+}
+
+func (a AuthServiceImpl) audit(entry *dao.AuditEntry) {
+	saveAuditEntry(a.AuditRepository, entry)
+}
+
+func saveAuditEntry(auditRepository repository.AuditRepository, entry *dao.AuditEntry) {
+	/* Audit entries must not break the request, errors are logged only */
+	if err := auditRepository.Save(entry); err != nil {
+		slog.Error("Error happened: when saving audit entry", "event", entry.Event, "subject", entry.Subject, "error", err)
+	}
+}
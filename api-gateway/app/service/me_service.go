@@ -0,0 +1,184 @@
+/**
+* This package serves the planner data of the logged in user, read from the person linked to the user.
+* - Assignments: The workdays the person is assigned to
+* - Absences: The absences of the person
+* - Hours: The planned hours of the person compared to the working hours
+* - Leave requests: The leave requests of the person, new ones are decided by the planners of the department
+* The person is taken from the link of the user, so users can only read their own data.
+* The routes of the workdays, absences and hours accept ?start_date=...&end_date=..., by default the next four weeks are returned.
+**/
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"api-gateway/app/planner"
+	"api-gateway/app/repository"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+// Range of the requests without end_date
+const defaultMeRange = 28 * 24 * time.Hour
+
+type MeService interface {
+	Assignments(c *gin.Context)
+	Absences(c *gin.Context)
+	Hours(c *gin.Context)
+	LeaveRequests(c *gin.Context)
+	RequestLeave(c *gin.Context)
+}
+
+type MeServiceImpl struct {
+	UserRepository repository.UserRepository
+	// nil if the planner-backend is not configured
+	Planner planner.Client
+}
+
+func (m MeServiceImpl) Assignments(c *gin.Context) {
+	/* Method to get the upcoming workdays of the logged in user */
+	slog.Info("start to execute program get assignments of me")
+
+	m.forward(c, "workday")
+}
+
+func (m MeServiceImpl) Absences(c *gin.Context) {
+	/* Method to get the absences of the logged in user */
+	slog.Info("start to execute program get absences of me")
+
+	m.forward(c, "absency")
+}
+
+func (m MeServiceImpl) Hours(c *gin.Context) {
+	/* Method to get the planned hours of the logged in user */
+	slog.Info("start to execute program get hours of me")
+
+	m.forward(c, "hours")
+}
+
+func (m MeServiceImpl) LeaveRequests(c *gin.Context) {
+	/* Method to get the leave requests of the logged in user */
+	slog.Info("start to execute program get leave requests of me")
+
+	_, personID, err := m.linkedPerson(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	m.send(c, gatewayIdentity(), http.MethodGet, "/internal/person/"+personID+"/leave-request", nil, nil)
+}
+
+func (m MeServiceImpl) RequestLeave(c *gin.Context) {
+	/* Method to file a leave request for the logged in user */
+	slog.Info("start to execute program request leave of me")
+
+	var request dco.LeaveRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	token, personID, err := m.linkedPerson(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	// the user filing the request is taken from the token, never from the body
+	body, err := json.Marshal(dco.PlannerLeaveRequest{LeaveRequest: request, RequestedBy: token.Username})
+	if err != nil {
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	m.send(c, gatewayIdentity(), http.MethodPost, "/internal/person/"+personID+"/leave-request", nil, body)
+}
+
+func (m MeServiceImpl) forward(c *gin.Context, resource string) {
+	/**
+	* Forwards the request to the route of the linked person at the planner-backend and returns its response.
+	* Only the range of dates is taken from the request, the person is never chosen by the client.
+	* The plan of a person is only shown to the members of its departments, the linked person is forwarded
+	* with the identity of the api-gateway since it may belong to other departments than the user.
+	**/
+	_, personID, err := m.linkedPerson(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	startDate := c.DefaultQuery("start_date", time.Now().Format("2006-01-02"))
+	endDate := c.Query("end_date")
+	if endDate == "" {
+		start, err := time.Parse("2006-01-02", startDate)
+		if err != nil {
+			pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+			return
+		}
+		endDate = start.Add(defaultMeRange - 24*time.Hour).Format("2006-01-02")
+	}
+
+	path := "/api/v1/planner/person/" + personID + "/" + resource
+	m.send(c, gatewayIdentity(), http.MethodGet, path, url.Values{"start_date": {startDate}, "end_date": {endDate}}, nil)
+}
+
+func (m MeServiceImpl) linkedPerson(c *gin.Context) (*dco.JWTClaim, string, error) {
+	/* Helper to get the token and the id of the person linked to the logged in user */
+	claim, exists := c.Get("retrievedToken")
+	if !exists {
+		return nil, "", pkg.NewError(constant.Unauthorized)
+	}
+	token := claim.(*dco.JWTClaim)
+
+	if m.Planner == nil {
+		slog.Error("Error happened: when forward request of me", "error", "PLANNER_BACKEND_TARGET is not set")
+		return nil, "", pkg.NewError(constant.UnknownError)
+	}
+
+	user, err := m.UserRepository.FindUserByUsername(token.Username)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		return nil, "", pkg.NewError(constant.Unauthorized)
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		return nil, "", pkg.NewError(constant.UnknownError)
+	}
+	if user.PersonID == nil {
+		return nil, "", pkg.NewError(constant.DataNotFound).WithMessage("No person is linked to the user")
+	}
+
+	return token, *user.PersonID, nil
+}
+
+func (m MeServiceImpl) send(c *gin.Context, identity dco.Identity, method string, path string, query url.Values, body []byte) {
+	/* Helper to send a request to the planner-backend and return its response */
+	status, response, err := m.Planner.Forward(identity, method, path, query, body)
+	if err != nil {
+		slog.Error("Error happened: when forward request of me", "path", path, "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.Data(status, "application/json; charset=utf-8", response)
+}
+
+func gatewayIdentity() dco.Identity {
+	/* The internal routes of the planner-backend only accept the identity of the api-gateway */
+	return dco.Identity{Username: dco.GatewayServiceName, IsAdmin: true}
+}
+
+var meServiceSet = wire.NewSet(
+	wire.Struct(new(MeServiceImpl), "*"),
+	wire.Bind(new(MeService), new(*MeServiceImpl)),
+)
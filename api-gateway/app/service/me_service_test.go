@@ -0,0 +1,222 @@
+package service
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/middleware"
+	"api-gateway/app/mock"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func meContext(t *testing.T, w *httptest.ResponseRecorder, query map[string]string) *gin.Context {
+	/* Helper to build the context of a logged in user */
+	t.Helper()
+	ctx, err := mock.NewTestContextBuilder().WithMethod("GET").WithQueries(query).WithResponseRecorder(w).Build()
+	if err != nil {
+		t.Fatal("Error happened: when build test context", "error", err)
+	}
+	token, _ := mock.GenerateMockToken(dao.User{Username: "test"})
+	claim, _ := middleware.DecodeToken(token)
+	ctx.Set("retrievedToken", claim)
+	return ctx
+}
+
+func TestMeService(t *testing.T) {
+	/* Test that the requests are forwarded to the linked person only */
+	mockUserRepository := mock.NewUserRepositoryMock()
+	plannerMock := mock.PlannerClientMock{Body: []byte(`{"data":[]}`)}
+	meService := MeServiceImpl{
+		UserRepository: &mockUserRepository,
+		Planner:        &plannerMock,
+	}
+
+	personID := "p1"
+	linked := dao.User{Username: "test", PersonID: &personID}
+	today := time.Now()
+
+	type meServiceTest struct {
+		query              map[string]string
+		user               dao.User
+		userError          error
+		plannerError       error
+		expectedStatusCode int
+		expectedForwarded  string
+	}
+
+	testSteps := []meServiceTest{
+		{
+			// the person cannot be chosen by the client
+			query:              map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31", "person": "p2"},
+			user:               linked,
+			expectedStatusCode: http.StatusOK,
+			expectedForwarded:  "GET /api/v1/planner/person/p1/workday?end_date=2024-01-31&start_date=2024-01-01",
+		},
+		{
+			// the next four weeks by default
+			user:               linked,
+			expectedStatusCode: http.StatusOK,
+			expectedForwarded: fmt.Sprintf("GET /api/v1/planner/person/p1/workday?end_date=%s&start_date=%s",
+				today.Add(27*24*time.Hour).Format("2006-01-02"), today.Format("2006-01-02")),
+		},
+		{
+			query:              map[string]string{"start_date": "2024-01-01"},
+			user:               linked,
+			expectedStatusCode: http.StatusOK,
+			expectedForwarded:  "GET /api/v1/planner/person/p1/workday?end_date=2024-01-28&start_date=2024-01-01",
+		},
+		{
+			query:              map[string]string{"start_date": "invalid"},
+			user:               linked,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// no person linked
+			user:               dao.User{Username: "test"},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			query:              map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31"},
+			user:               linked,
+			plannerError:       errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedForwarded:  "GET /api/v1/planner/person/p1/workday?end_date=2024-01-31&start_date=2024-01-01",
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			plannerMock.Forwarded = nil
+			plannerMock.Error = testStep.plannerError
+			mockUserRepository.On("FindUserByUsername").Return(testStep.user, testStep.userError)
+
+			w := httptest.NewRecorder()
+			serve(meContext(t, w, testStep.query), meService.Assignments)
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step %d. Expected status code %d but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedForwarded == "" && len(plannerMock.Forwarded) != 0 {
+				t.Errorf("Step %d. Expected no request to the planner-backend, got %v", i, plannerMock.Forwarded)
+			}
+			if testStep.expectedForwarded != "" && (len(plannerMock.Forwarded) != 1 || plannerMock.Forwarded[0] != testStep.expectedForwarded) {
+				t.Errorf("Step %d. Expected request %s, got %v", i, testStep.expectedForwarded, plannerMock.Forwarded)
+			}
+		})
+	}
+
+	// the other routes of the person
+	plannerMock.Forwarded = nil
+	plannerMock.Error = nil
+	mockUserRepository.On("FindUserByUsername").Return(linked, nil)
+	query := map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31"}
+
+	meService.Absences(meContext(t, httptest.NewRecorder(), query))
+	meService.Hours(meContext(t, httptest.NewRecorder(), query))
+
+	expected := []string{
+		"GET /api/v1/planner/person/p1/absency?end_date=2024-01-31&start_date=2024-01-01",
+		"GET /api/v1/planner/person/p1/hours?end_date=2024-01-31&start_date=2024-01-01",
+	}
+	if len(plannerMock.Forwarded) != 2 || plannerMock.Forwarded[0] != expected[0] || plannerMock.Forwarded[1] != expected[1] {
+		t.Errorf("Expected requests %v, got %v", expected, plannerMock.Forwarded)
+	}
+
+	// without the planner-backend
+	w := httptest.NewRecorder()
+	serve(meContext(t, w, nil), MeServiceImpl{UserRepository: &mockUserRepository}.Hours)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status code %d without planner-backend, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestMeLeaveRequests(t *testing.T) {
+	/* Test that leave requests are filed for the linked person on behalf of the user */
+	mockUserRepository := mock.NewUserRepositoryMock()
+	plannerMock := mock.PlannerClientMock{Body: []byte(`{"data":{}}`), Status: http.StatusCreated}
+	meService := MeServiceImpl{
+		UserRepository: &mockUserRepository,
+		Planner:        &plannerMock,
+	}
+
+	personID := "p1"
+	mockUserRepository.On("FindUserByUsername").Return(dao.User{Username: "test", PersonID: &personID}, nil)
+
+	type requestLeaveTest struct {
+		body               interface{}
+		expectedStatusCode int
+		expectedForwarded  bool
+	}
+
+	testSteps := []requestLeaveTest{
+		{
+			// the user filing the request cannot be chosen by the client
+			body:               map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-05", "requested_by": "someone"},
+			expectedStatusCode: http.StatusCreated,
+			expectedForwarded:  true,
+		},
+		{
+			body:               map[string]string{"start_date": "2024-01-01"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			plannerMock.Forwarded = nil
+			plannerMock.ForwardedBodies = nil
+
+			w := httptest.NewRecorder()
+			ctx, err := mock.NewTestContextBuilder().WithMethod("POST").WithBody(testStep.body).WithResponseRecorder(w).Build()
+			if err != nil {
+				t.Fatal("Error happened: when build test context", "error", err)
+			}
+			token, _ := mock.GenerateMockToken(dao.User{Username: "test"})
+			claim, _ := middleware.DecodeToken(token)
+			ctx.Set("retrievedToken", claim)
+
+			serve(ctx, meService.RequestLeave)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step %d. Expected status code %d but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+			if !testStep.expectedForwarded {
+				if len(plannerMock.Forwarded) != 0 {
+					t.Errorf("Step %d. Expected no request to the planner-backend, got %v", i, plannerMock.Forwarded)
+				}
+				return
+			}
+
+			if len(plannerMock.Forwarded) != 1 || plannerMock.Forwarded[0] != "POST /internal/person/p1/leave-request?" {
+				t.Fatalf("Step %d. Expected the leave request of the linked person, got %v", i, plannerMock.Forwarded)
+			}
+			var forwarded dco.PlannerLeaveRequest
+			if err := json.Unmarshal(plannerMock.ForwardedBodies[0], &forwarded); err != nil {
+				t.Fatalf("Step %d. Error while decoding forwarded body: %s", i, err)
+			}
+			if forwarded.RequestedBy != "test" || forwarded.StartDate != "2024-01-01" || forwarded.EndDate != "2024-01-05" {
+				t.Errorf("Step %d. Expected the request of test, got %+v", i, forwarded)
+			}
+		})
+	}
+
+	// the leave requests of the linked person
+	plannerMock.Forwarded = nil
+	plannerMock.Status = http.StatusOK
+	w := httptest.NewRecorder()
+	serve(meContext(t, w, map[string]string{"person": "p2"}), meService.LeaveRequests)
+	if w.Code != http.StatusOK || len(plannerMock.Forwarded) != 1 || plannerMock.Forwarded[0] != "GET /internal/person/p1/leave-request?" {
+		t.Errorf("Expected the leave requests of the linked person, got %d and %v", w.Code, plannerMock.Forwarded)
+	}
+}
@@ -0,0 +1,322 @@
+/**
+* This package handles the passwords of the users.
+* It has three main functions:
+* - ChangePassword: Change the password of the logged in user
+* - RequestPasswordReset: Send a one-time reset link to a user, used by admins
+* - ResetPassword: Set a new password with a reset token
+* Every new password has to satisfy the password policy.
+**/
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/domain/dto"
+	"api-gateway/app/mailer"
+	"api-gateway/app/pkg"
+	"api-gateway/app/policy"
+	"api-gateway/app/repository"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type PasswordService interface {
+	ChangePassword(c *gin.Context)
+	RequestPasswordReset(c *gin.Context)
+	ResetPassword(c *gin.Context)
+}
+
+type PasswordServiceImpl struct {
+	UserRepository          repository.UserRepository
+	SessionRepository       repository.SessionRepository
+	PasswordResetRepository repository.PasswordResetRepository
+	Mailer                  mailer.Mailer
+	PasswordPolicy          policy.PasswordPolicy
+}
+
+func (p PasswordServiceImpl) ChangePassword(c *gin.Context) {
+	/**
+	* Changes the password of the logged in user.
+	* All sessions of the user are revoked and a new session is started for the client.
+	**/
+	slog.Info("start to execute program change password")
+
+	claim, exists := c.Get("retrievedToken")
+	if !exists {
+		slog.Error("Error happened: when get token from cookie", "error", "token not found")
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	var request dco.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	user, err := p.UserRepository.FindUserByUsername(claim.(*dco.JWTClaim).Username)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// the password of external users is managed by their provider
+	if user.IsExternal() {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Password is managed by the identity provider"))
+		return
+	}
+
+	// a wrong current password is not answered with 401, the client would try to refresh its session
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword)); err != nil {
+		slog.Error("Error happened: when compare password", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Current password is incorrect"))
+		return
+	}
+	if request.CurrentPassword == request.NewPassword {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("New password must differ from the current password"))
+		return
+	}
+	if err := checkPasswordPolicy(p.PasswordPolicy, "new_password", request.NewPassword); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	hash, err := hashPassword(request.NewPassword)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if err := p.UserRepository.UpdatePassword(user.ID, hash, false); err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	user.MustChangePassword = false
+
+	// sessions on other devices might have been started with the old password
+	if err := p.SessionRepository.RevokeSessionsOfUser(user.ID, time.Now().Add(dco.JWTExpirationTime)); err != nil {
+		slog.Error("Error happened: when revoke sessions", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	session, err := startSession(c, p.SessionRepository, user)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if err := issueAccessToken(c, user, session.ID, session.CSRFToken); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapUserToUserResponse(user)))
+}
+
+func (p PasswordServiceImpl) RequestPasswordReset(c *gin.Context) {
+	/**
+	* Sends a one-time password reset link to the email address of the user.
+	* Previously requested tokens of the user become invalid.
+	**/
+	slog.Info("start to execute program request password reset")
+
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	user, err := p.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	if _, err := authorizeUserChange(c, user, "user:write"); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	if user.IsExternal() {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Password is managed by the identity provider"))
+		return
+	}
+
+	if err := p.PasswordResetRepository.InvalidatePasswordResetsOfUser(user.ID); err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	token, hash, err := generateToken()
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	passwordReset, err := p.PasswordResetRepository.Save(&dao.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(dco.PasswordResetExpirationTime),
+	})
+	if err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if err := p.Mailer.Send(buildPasswordResetMessage(user, token, passwordReset.ExpiresAt)); err != nil {
+		slog.Error("Error happened: when sending password reset email", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (p PasswordServiceImpl) ResetPassword(c *gin.Context) {
+	/**
+	* Sets a new password with a reset token. The token can only be used once.
+	* All sessions of the user are revoked, the user has to log in with the new password.
+	**/
+	slog.Info("start to execute program reset password")
+
+	var request dco.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	passwordReset, err := p.PasswordResetRepository.FindPasswordResetByTokenHash(hashToken(request.Token))
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Password reset token is invalid or expired"))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	if !passwordReset.IsValid() {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Password reset token is invalid or expired"))
+		return
+	}
+
+	if err := checkPasswordPolicy(p.PasswordPolicy, "new_password", request.NewPassword); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	// the token is used first, so concurrent requests can not use it twice
+	switch err := p.PasswordResetRepository.UsePasswordReset(passwordReset.ID); err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Password reset token is invalid or expired"))
+		return
+	default:
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	hash, err := hashPassword(request.NewPassword)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	switch err := p.UserRepository.UpdatePassword(passwordReset.UserID, hash, false); err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if err := p.SessionRepository.RevokeSessionsOfUser(passwordReset.UserID, time.Now().Add(dco.JWTExpirationTime)); err != nil {
+		slog.Error("Error happened: when revoke sessions", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+var passwordServiceSet = wire.NewSet(
+	wire.Struct(new(PasswordServiceImpl), "*"),
+	wire.Bind(new(PasswordService), new(*PasswordServiceImpl)),
+)
+
+func checkPasswordPolicy(passwordPolicy policy.PasswordPolicy, field string, password string) error {
+	/* Returns an invalid request with the violated rules as details of the field if the password does not satisfy the policy */
+	violations := passwordPolicy.Check(password)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	slog.Error("Error happened: password violates the policy", "violations", violations)
+	err := pkg.NewError(constant.InvalidRequest).WithCode("password_policy").WithMessage(strings.Join(violations, ", "))
+	for _, violation := range violations {
+		err.WithDetails(dto.ErrorDetail{Field: field, Code: "password_policy", Message: violation})
+	}
+	return err
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		slog.Error("Error happened: when hashing password", "error", err)
+		return "", pkg.NewError(constant.UnknownError)
+	}
+	return string(hash), nil
+}
+
+func buildPasswordResetMessage(user dao.User, token string, expiresAt time.Time) mailer.Message {
+	/* The token is only sent to the user, the admin requesting the reset never sees it */
+	link := token
+	if dco.PasswordResetURL != "" {
+		link = dco.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	}
+
+	return mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nan administrator requested a password reset for your account.\n"+
+				"Use the following link to set a new password, it is valid until %s:\n\n%s\n\n"+
+				"If you did not expect this email, please contact your administrator.\n",
+			user.Username, expiresAt.Format(time.RFC1123), link,
+		),
+	}
+}
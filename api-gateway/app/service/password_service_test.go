@@ -0,0 +1,320 @@
+package service
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/middleware"
+	"api-gateway/app/mock"
+	"api-gateway/app/policy"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestChangePassword(t *testing.T) {
+	/* Test Change Password */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	sessionRepoMock := mock.NewSessionRepositoryMock()
+	passwordService := PasswordServiceImpl{
+		UserRepository:    &userRepoMock,
+		SessionRepository: &sessionRepoMock,
+		PasswordPolicy:    policy.DefaultPasswordPolicy,
+	}
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("admin"), bcrypt.MinCost)
+	user := dao.User{Username: "admin", Password: string(hashedPassword), MustChangePassword: true}
+
+	type changePasswordTest struct {
+		request            map[string]interface{}
+		authProvider       string
+		userError          error
+		updateError        error
+		expectedStatusCode int
+	}
+
+	testSteps := []changePasswordTest{
+		{
+			request:            map[string]interface{}{"current_password": "admin", "new_password": "CorrectHorse42"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			// wrong current password
+			request:            map[string]interface{}{"current_password": "wrong", "new_password": "CorrectHorse42"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// violates the policy
+			request:            map[string]interface{}{"current_password": "admin", "new_password": "short"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// same password
+			request:            map[string]interface{}{"current_password": "admin", "new_password": "admin"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            map[string]interface{}{"current_password": "admin"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            map[string]interface{}{"current_password": "admin", "new_password": "CorrectHorse42"},
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			request:            map[string]interface{}{"current_password": "admin", "new_password": "CorrectHorse42"},
+			updateError:        errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// the password of external users is managed by their provider
+			request:            map[string]interface{}{"current_password": "admin", "new_password": "CorrectHorse42"},
+			authProvider:       "directory",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			user := user
+			user.AuthProvider = testStep.authProvider
+			userRepoMock.On("FindUserByUsername").Return(user, testStep.userError)
+			userRepoMock.On("UpdatePassword").Return(nil, testStep.updateError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "PUT", gin.Params{}, testStep.request)
+
+			token, err := mock.GenerateMockToken(user)
+			if err != nil {
+				t.Error("Error happened: when generate mock token", "error", err)
+			}
+			claim, _ := middleware.DecodeToken(token)
+			c.Set("retrievedToken", claim)
+
+			// Call function
+			serve(c, passwordService.ChangePassword)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+
+			// a new session is started without the forced password change
+			if testStep.expectedStatusCode == http.StatusOK {
+				var accessToken string
+				for _, cookie := range response.Cookies() {
+					if cookie.Name == dco.AccessTokenCookie {
+						accessToken = cookie.Value
+					}
+				}
+				claim, err := middleware.DecodeToken(accessToken)
+				if err != nil {
+					t.Fatalf("Step: %d. Expected a valid access token, got %v", i, err)
+				}
+				if claim.MustChangePassword {
+					t.Errorf("Step: %d. Expected the password change to be completed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	/* Test Request Password Reset */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	passwordResetRepoMock := mock.NewPasswordResetRepositoryMock()
+
+	type requestPasswordResetTest struct {
+		userID             string
+		user               dao.User
+		caller             dao.User
+		userError          error
+		mailError          error
+		expectedStatusCode int
+	}
+
+	testSteps := []requestPasswordResetTest{
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			userID:             "invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			mailError:          errors.New("connection refused"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// the user belongs to a department the caller does not administer
+			userID:             "00000000-0000-0000-0000-000000000001",
+			user:               dao.User{Username: "test", Email: "test@example.com", Department: dao.Department{PlannerID: "planner-b"}},
+			caller:             departmentAdmin("planner-a"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			user:               dao.User{Username: "test", Email: "test@example.com", Department: dao.Department{PlannerID: "planner-a"}, Roles: mock.SystemAdminRoles},
+			caller:             departmentAdmin("planner-a"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			mailerMock := mock.MailerMock{Err: testStep.mailError}
+			passwordService := PasswordServiceImpl{
+				UserRepository:          &userRepoMock,
+				PasswordResetRepository: &passwordResetRepoMock,
+				Mailer:                  &mailerMock,
+			}
+
+			// the user and the caller default to a user and a system admin
+			user, caller := testStep.user, testStep.caller
+			if user.Username == "" {
+				user = dao.User{Username: "test", Email: "test@example.com"}
+			}
+			if caller.Username == "" {
+				caller = dao.User{Username: "admin", Roles: mock.SystemAdminRoles}
+			}
+
+			// Prime mock
+			userRepoMock.On("FindUserById").Return(user, testStep.userError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, _ := mock.NewTestContextBuilder().
+				WithResponseRecorder(w).
+				WithMethod("POST").
+				WithParams(gin.Params{{Key: "userID", Value: testStep.userID}}).
+				Build()
+			token, err := mock.GenerateMockToken(caller)
+			if err != nil {
+				t.Error("Error happened: when generate mock token", "error", err)
+			}
+			claim, _ := middleware.DecodeToken(token)
+			c.Set("retrievedToken", claim)
+
+			// Call function
+			serve(c, passwordService.RequestPasswordReset)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+
+			if testStep.expectedStatusCode == http.StatusOK {
+				if len(mailerMock.Sent) != 1 || mailerMock.Sent[0].To != "test@example.com" {
+					t.Fatalf("Step: %d. Expected one email to test@example.com, got %v", i, mailerMock.Sent)
+				}
+				// the token is sent to the user only
+				if strings.Contains(w.Body.String(), "token") {
+					t.Errorf("Step: %d. Expected the token not to be returned, got %s", i, w.Body.String())
+				}
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	/* Test Reset Password */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	sessionRepoMock := mock.NewSessionRepositoryMock()
+	passwordResetRepoMock := mock.NewPasswordResetRepositoryMock()
+	passwordService := PasswordServiceImpl{
+		UserRepository:          &userRepoMock,
+		SessionRepository:       &sessionRepoMock,
+		PasswordResetRepository: &passwordResetRepoMock,
+		PasswordPolicy:          policy.DefaultPasswordPolicy,
+	}
+
+	usedAt := time.Now().Add(-time.Minute)
+
+	type resetPasswordTest struct {
+		request            map[string]interface{}
+		passwordReset      dao.PasswordReset
+		passwordResetError error
+		useError           error
+		expectedStatusCode int
+	}
+
+	testSteps := []resetPasswordTest{
+		{
+			request:            map[string]interface{}{"token": "token", "new_password": "CorrectHorse42"},
+			passwordReset:      dao.PasswordReset{ExpiresAt: time.Now().Add(time.Hour)},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			// unknown token
+			request:            map[string]interface{}{"token": "token", "new_password": "CorrectHorse42"},
+			passwordResetError: gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// expired token
+			request:            map[string]interface{}{"token": "token", "new_password": "CorrectHorse42"},
+			passwordReset:      dao.PasswordReset{ExpiresAt: time.Now().Add(-time.Hour)},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// used token
+			request:            map[string]interface{}{"token": "token", "new_password": "CorrectHorse42"},
+			passwordReset:      dao.PasswordReset{ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// token used by a concurrent request
+			request:            map[string]interface{}{"token": "token", "new_password": "CorrectHorse42"},
+			passwordReset:      dao.PasswordReset{ExpiresAt: time.Now().Add(time.Hour)},
+			useError:           gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// violates the policy
+			request:            map[string]interface{}{"token": "token", "new_password": "password"},
+			passwordReset:      dao.PasswordReset{ExpiresAt: time.Now().Add(time.Hour)},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            map[string]interface{}{"new_password": "CorrectHorse42"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			passwordResetRepoMock.On("FindPasswordResetByTokenHash").Return(testStep.passwordReset, testStep.passwordResetError)
+			passwordResetRepoMock.On("UsePasswordReset").Return(nil, testStep.useError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "POST", gin.Params{}, testStep.request)
+
+			// Call function
+			serve(c, passwordService.ResetPassword)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
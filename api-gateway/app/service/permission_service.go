@@ -33,13 +33,13 @@ func (p PermissionServiceImpl) GetAllPermissions(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all permissions")
 
 	rawData, err := p.PermissionRepository.FindAllPermissions()
 	if err != nil {
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapPermissionListToPermissionResponseList(rawData)
@@ -52,14 +52,14 @@ func (p PermissionServiceImpl) GetPermissionById(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get permission by id")
 
 	id := c.Param("permissionID")
 	permissionID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error when parsing uuid. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	rawData, err := p.PermissionRepository.FindPermissionById(permissionID)
@@ -68,10 +68,12 @@ func (p PermissionServiceImpl) GetPermissionById(c *gin.Context) {
 		break
 	case gorm.ErrRecordNotFound:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapPermissionToPermissionResponse(rawData)
@@ -84,13 +86,13 @@ func (p PermissionServiceImpl) AddPermission(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add permission")
 
 	var rawRequest dco.PermissionRequest
 	if err := c.ShouldBindJSON(&rawRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	request := mapPermissionRequestToPermission(rawRequest)
 
@@ -98,18 +100,21 @@ func (p PermissionServiceImpl) AddPermission(c *gin.Context) {
 	_, err := p.PermissionRepository.FindPermissionByName(request.Name)
 	switch err {
 	case nil:
-		pkg.PanicException(constant.Conflict)
+		pkg.Abort(c, pkg.NewError(constant.Conflict))
+		return
 	case gorm.ErrRecordNotFound:
 		break
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	rawData, err := p.PermissionRepository.Save(&request)
 	if err != nil {
 		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapPermissionToPermissionResponse(rawData)
@@ -122,20 +127,21 @@ func (p PermissionServiceImpl) UpdatePermission(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update permission")
 
 	id := c.Param("permissionID")
 	permissionID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error when parsing uuid. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var rawRequest dco.PermissionRequest
 	if err := c.ShouldBindJSON(&rawRequest); err != nil {
-		slog.Error("Error when binding json", err)
-		pkg.PanicException(constant.InvalidRequest)
+		slog.Error("Error when binding json", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
 	request := mapPermissionRequestToPermission(rawRequest)
@@ -145,10 +151,12 @@ func (p PermissionServiceImpl) UpdatePermission(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	oldData.Name = request.Name
@@ -159,10 +167,12 @@ func (p PermissionServiceImpl) UpdatePermission(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapPermissionToPermissionResponse(rawData)
@@ -175,14 +185,14 @@ func (p PermissionServiceImpl) DeletePermission(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete permission")
 
 	id := c.Param("permissionID")
 	permissionID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error when parsing uuid. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	err = p.PermissionRepository.DeletePermissionById(permissionID)
@@ -190,10 +200,12 @@ func (p PermissionServiceImpl) DeletePermission(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when deleting data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -134,8 +134,7 @@ func TestUpdatePermission(t *testing.T) {
 			c := mock.GetGinTestContext(w, "PUT", testStep.ParamsToGinParams(), testStep.mockRequestData)
 
 			// Run function
-			permissionService.UpdatePermission(c)
-
+			serve(c, permissionService.UpdatePermission)
 			// Check result
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -216,8 +215,7 @@ func TestDeletePermission(t *testing.T) {
 			}, nil)
 
 			// Run function
-			permissionService.DeletePermission(c)
-
+			serve(c, permissionService.DeletePermission)
 			// Check result
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -304,8 +302,7 @@ func TestAddPermission(t *testing.T) {
 			c := mock.GetGinTestContext(w, "POST", gin.Params{}, testStep.mockRequestData)
 
 			// Run function
-			permissionService.AddPermission(c)
-
+			serve(c, permissionService.AddPermission)
 			// Check result
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -399,8 +396,7 @@ func TestGetAllPermissions(t *testing.T) {
 			c := mock.GetGinTestContext(w, "GET", gin.Params{}, nil)
 
 			// Run function
-			permissionService.GetAllPermissions(c)
-
+			serve(c, permissionService.GetAllPermissions)
 			// Check result
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -487,8 +483,7 @@ func TestGetPermissionById(t *testing.T) {
 			}, nil)
 
 			// Run function
-			permissionService.GetPermissionById(c)
-
+			serve(c, permissionService.GetPermissionById)
 			// Check result
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
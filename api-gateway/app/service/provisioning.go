@@ -0,0 +1,160 @@
+package service
+
+import (
+	"api-gateway/app/authprovider"
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/pkg"
+	"errors"
+	"log/slog"
+
+	"gorm.io/gorm"
+)
+
+/**
+* Users of external providers are provisioned on their first login.
+* Their department is mapped from the claims or attributes of the provider, their roles from
+* the groups. The provisioned roles are replaced on every login, roles assigned through the
+* API are kept.
+**/
+
+func (a AuthServiceImpl) resolveUser(identity *authprovider.Identity) (dao.User, error) {
+	/**
+	* Finds the user of an authenticated identity, users of external providers are created if needed
+	* @param identity: The identity returned by the provider
+	* @return: The user with all relations loaded
+	**/
+	if identity.Provider == authprovider.LocalProviderName {
+		user, err := a.UserRepository.FindUserByUsername(identity.Username)
+		if err != nil {
+			slog.Error("Error happened: when get data from database", "error", err)
+			return dao.User{}, pkg.NewError(constant.UnknownError)
+		}
+		return user, nil
+	}
+
+	department, err := a.provisionedDepartment(identity)
+	if err != nil {
+		return dao.User{}, err
+	}
+
+	user, err := a.UserRepository.FindUserByExternalIdentity(identity.Provider, identity.Subject)
+	switch {
+	case err == nil:
+		// the department and email are owned by the provider
+		if user.DepartmentID != department.ID || (identity.Email != "" && user.Email != identity.Email) {
+			user.DepartmentID = department.ID
+			user.Department = department
+			if identity.Email != "" {
+				user.Email = identity.Email
+			}
+			if _, err := a.UserRepository.Save(&user); err != nil {
+				slog.Error("Error happened: when saving data to database", "error", err)
+				return dao.User{}, pkg.NewError(constant.UnknownError)
+			}
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if user, err = a.createProvisionedUser(identity, department); err != nil {
+			return dao.User{}, err
+		}
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		return dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+
+	roles, err := a.provisionedRoles(identity, department)
+	if err != nil {
+		return dao.User{}, err
+	}
+	if err := a.UserRepository.SyncProvisionedRoles(user.ID, roles); err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		return dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+
+	// reload the user to resolve the permissions of the synced roles
+	user, err = a.UserRepository.FindUserById(user.ID)
+	if err != nil {
+		slog.Error("Error happened: when get data from database", "error", err)
+		return dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+	return user, nil
+}
+
+func (a AuthServiceImpl) createProvisionedUser(identity *authprovider.Identity, department dao.Department) (dao.User, error) {
+	// usernames are unique across all providers, an existing user is never taken over
+	_, err := a.UserRepository.FindUserByUsername(identity.Username)
+	switch {
+	case err == nil:
+		slog.Error("Error happened: username is taken by another user", "provider", identity.Provider, "username", identity.Username)
+		return dao.User{}, pkg.NewError(constant.Conflict)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		slog.Error("Error happened: when get data from database", "error", err)
+		return dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+
+	subject := identity.Subject
+	user, err := a.UserRepository.Save(&dao.User{
+		Username:        identity.Username,
+		Email:           identity.Email,
+		AuthProvider:    identity.Provider,
+		ExternalSubject: &subject,
+		DepartmentID:    department.ID,
+	})
+	if err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		return dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+
+	slog.Info("Provisioned user", "provider", identity.Provider, "username", identity.Username, "department", department.Name)
+	a.audit(&dao.AuditEntry{
+		Event:   dao.AuditEventUserProvisioned,
+		Subject: identity.Username,
+		Details: "provider " + identity.Provider + ", department " + department.Name,
+	})
+	return user, nil
+}
+
+func (a AuthServiceImpl) provisionedDepartment(identity *authprovider.Identity) (dao.Department, error) {
+	/* Users without a known department are rejected, since every user belongs to a department */
+	if identity.Department == "" {
+		slog.Error("Error happened: identity has no department", "provider", identity.Provider, "username", identity.Username)
+		return dao.Department{}, pkg.NewError(constant.Forbidden)
+	}
+
+	department, err := a.DepartmentRepository.FindDepartmentByName(identity.Department)
+	switch {
+	case err == nil:
+		return department, nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		slog.Error("Error happened: unknown department", "provider", identity.Provider, "username", identity.Username, "department", identity.Department)
+		return dao.Department{}, pkg.NewError(constant.Forbidden)
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		return dao.Department{}, pkg.NewError(constant.UnknownError)
+	}
+}
+
+func (a AuthServiceImpl) provisionedRoles(identity *authprovider.Identity, department dao.Department) ([]dao.UserRole, error) {
+	/* Roles unknown to the gateway are skipped, so a typo in the mapping does not block the login */
+	roles := []dao.UserRole{}
+	for _, mapped := range identity.Roles {
+		role, err := a.RoleRepository.FindRoleByName(mapped.Name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			slog.Warn("Skipping unknown role of provider mapping", "provider", identity.Provider, "role", mapped.Name)
+			continue
+		}
+		if err != nil {
+			slog.Error("Error happened: when get data from database", "error", err)
+			return nil, pkg.NewError(constant.UnknownError)
+		}
+
+		userRole := dao.UserRole{RoleID: role.ID}
+		// the system admin role is never limited to a department
+		if mapped.DepartmentScoped && role.Name != dao.RoleSystemAdmin {
+			departmentID := department.ID
+			userRole.DepartmentID = &departmentID
+		}
+		roles = append(roles, userRole)
+	}
+	return roles, nil
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"api-gateway/app/authprovider"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/mock"
+	"api-gateway/app/policy"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type passwordProviderMock struct {
+	identity *authprovider.Identity
+	err      error
+}
+
+func (p passwordProviderMock) Name() string {
+	return "external"
+}
+
+func (p passwordProviderMock) Authenticate(ctx context.Context, username string, password string) (*authprovider.Identity, error) {
+	return p.identity, p.err
+}
+
+type redirectProviderMock struct {
+	identity *authprovider.Identity
+	err      error
+}
+
+func (p redirectProviderMock) Name() string {
+	return "external"
+}
+
+func (p redirectProviderMock) AuthCodeURL(state string, nonce string, codeVerifier string) string {
+	return "https://idp.example.org/authorize?state=" + state
+}
+
+func (p redirectProviderMock) Exchange(ctx context.Context, code string, nonce string, codeVerifier string) (*authprovider.Identity, error) {
+	return p.identity, p.err
+}
+
+func TestLoginExternalProvider(t *testing.T) {
+	// define test struct
+	type externalLoginTest struct {
+		provider             string
+		providerError        error
+		existingUser         bool
+		usernameTaken        bool
+		departmentError      error
+		expectedStatusCode   int
+		expectedAuditEntries int
+	}
+
+	identity := &authprovider.Identity{
+		Provider:   "external",
+		Subject:    "4f1c2a",
+		Username:   "jdoe",
+		Email:      "jdoe@example.org",
+		Department: "cardiology",
+		Roles:      []authprovider.MappedRole{{Name: dao.RolePlanner, DepartmentScoped: true}},
+	}
+	department := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "cardiology"}
+	subject := identity.Subject
+	user := dao.User{
+		BaseModel:       dao.BaseModel{ID: uuid.New()},
+		Username:        identity.Username,
+		Email:           identity.Email,
+		AuthProvider:    identity.Provider,
+		ExternalSubject: &subject,
+		DepartmentID:    department.ID,
+		Department:      department,
+	}
+
+	testSteps := []externalLoginTest{
+		{
+			// first login, the user is provisioned
+			provider:             "external",
+			expectedStatusCode:   http.StatusOK,
+			expectedAuditEntries: 1,
+		},
+		{
+			// later logins find the provisioned user
+			provider:           "external",
+			existingUser:       true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			provider:           "unknown",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			provider:           "external",
+			providerError:      authprovider.ErrInvalidCredentials,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			// e.g. the directory is not reachable
+			provider:           "external",
+			providerError:      errors.New("connection refused"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// a local user is never taken over by an external provider
+			provider:           "external",
+			usernameTaken:      true,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			provider:           "external",
+			departmentError:    gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test Step: %d", i), func(t *testing.T) {
+			mockUserRepository := mock.NewUserRepositoryMock()
+			mockSessionRepository := mock.NewSessionRepositoryMock()
+			mockLoginThrottleRepository := mock.NewLoginThrottleRepositoryMock()
+			mockDepartmentRepository := mock.NewDepartmentRepositoryMock()
+			mockRoleRepository := mock.NewRoleRepositoryMock()
+			mockAuditRepository := mock.AuditRepositoryMock{}
+			authService := AuthServiceImpl{
+				UserRepository:          &mockUserRepository,
+				SessionRepository:       &mockSessionRepository,
+				LoginThrottleRepository: &mockLoginThrottleRepository,
+				AuditRepository:         &mockAuditRepository,
+				LoginPolicy:             policy.DefaultLoginPolicy,
+				AuthProviders:           authprovider.NewRegistry(passwordProviderMock{identity: identity, err: testStep.providerError}),
+				DepartmentRepository:    &mockDepartmentRepository,
+				RoleRepository:          &mockRoleRepository,
+			}
+
+			// Set mock data
+			if testStep.existingUser {
+				mockUserRepository.On("FindUserByExternalIdentity").Return(user, nil)
+			} else {
+				mockUserRepository.On("FindUserByExternalIdentity").Return(nil, gorm.ErrRecordNotFound)
+			}
+			if testStep.usernameTaken {
+				mockUserRepository.On("FindUserByUsername").Return(dao.User{Username: identity.Username}, nil)
+			} else {
+				mockUserRepository.On("FindUserByUsername").Return(nil, gorm.ErrRecordNotFound)
+			}
+			mockUserRepository.On("Save").Return(user, nil)
+			mockUserRepository.On("FindUserById").Return(user, nil)
+			mockDepartmentRepository.On("FindDepartmentByName").Return(department, testStep.departmentError)
+			mockRoleRepository.On("FindRoleByName").Return(dao.Role{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: dao.RolePlanner}, nil)
+
+			w := httptest.NewRecorder()
+			ctx := mock.GetGinTestContext(w, "POST", gin.Params{}, map[string]interface{}{
+				"username": "jdoe",
+				"password": "secret",
+				"provider": testStep.provider,
+			})
+
+			serve(ctx, authService.Login)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if len(mockAuditRepository.Entries) != testStep.expectedAuditEntries {
+				t.Errorf("Expected %d audit entries but got %v", testStep.expectedAuditEntries, mockAuditRepository.Entries)
+			}
+			if testStep.expectedAuditEntries > 0 && mockAuditRepository.Entries[0].Event != dao.AuditEventUserProvisioned {
+				t.Errorf("Expected provisioning audit entry but got %s", mockAuditRepository.Entries[0].Event)
+			}
+			if cookies := strings.Join(w.Header().Values("Set-Cookie"), "\n"); (testStep.expectedStatusCode == http.StatusOK) != strings.Contains(cookies, dco.AccessTokenCookie+"=") {
+				t.Errorf("Unexpected cookies %q", cookies)
+			}
+		})
+	}
+}
+
+func TestProvisionedRoles(t *testing.T) {
+	mockRoleRepository := mock.NewRoleRepositoryMock()
+	authService := AuthServiceImpl{RoleRepository: &mockRoleRepository}
+	department := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "cardiology"}
+
+	planner := dao.Role{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: dao.RolePlanner}
+	systemAdmin := dao.Role{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: dao.RoleSystemAdmin}
+
+	testSteps := []struct {
+		mapped    authprovider.MappedRole
+		role      dao.Role
+		roleError error
+		expected  []dao.UserRole
+	}{
+		{
+			mapped:   authprovider.MappedRole{Name: dao.RolePlanner, DepartmentScoped: true},
+			role:     planner,
+			expected: []dao.UserRole{{RoleID: planner.ID, DepartmentID: &department.ID}},
+		},
+		{
+			mapped:   authprovider.MappedRole{Name: dao.RolePlanner},
+			role:     planner,
+			expected: []dao.UserRole{{RoleID: planner.ID}},
+		},
+		{
+			// the system admin role is never limited to a department
+			mapped:   authprovider.MappedRole{Name: dao.RoleSystemAdmin, DepartmentScoped: true},
+			role:     systemAdmin,
+			expected: []dao.UserRole{{RoleID: systemAdmin.ID}},
+		},
+		{
+			// unknown roles are skipped
+			mapped:    authprovider.MappedRole{Name: "unknown"},
+			roleError: gorm.ErrRecordNotFound,
+			expected:  []dao.UserRole{},
+		},
+	}
+
+	for i, testStep := range testSteps {
+		mockRoleRepository.On("FindRoleByName").Return(testStep.role, testStep.roleError)
+
+		identity := &authprovider.Identity{Provider: "external", Roles: []authprovider.MappedRole{testStep.mapped}}
+		roles, err := authService.provisionedRoles(identity, department)
+		if err != nil {
+			t.Errorf("Test Step %d: unexpected error %v", i, err)
+		}
+		if !reflect.DeepEqual(roles, testStep.expected) {
+			t.Errorf("Test Step %d: expected %+v, got %+v", i, testStep.expected, roles)
+		}
+	}
+}
+
+func TestExternalLogin(t *testing.T) {
+	authService := AuthServiceImpl{
+		AuthProviders: authprovider.NewRegistry(redirectProviderMock{}),
+	}
+
+	// unknown providers are not found
+	w := httptest.NewRecorder()
+	ctx, _ := mock.NewTestContextBuilder().WithResponseRecorder(w).WithMethod("GET").WithParams(gin.Params{{Key: "provider", Value: "unknown"}}).Build()
+	serve(ctx, authService.ExternalLogin)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d but got %d", http.StatusNotFound, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	ctx, _ = mock.NewTestContextBuilder().WithResponseRecorder(w).WithMethod("GET").WithParams(gin.Params{{Key: "provider", Value: "external"}}).Build()
+	serve(ctx, authService.ExternalLogin)
+	if w.Code != http.StatusFound {
+		t.Fatalf("Expected status code %d but got %d", http.StatusFound, w.Code)
+	}
+
+	// the state of the redirect has to match the cookie
+	location, _ := url.Parse(w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != dco.ExternalLoginCookie || !cookies[0].HttpOnly {
+		t.Fatalf("Expected the external login cookie but got %v", cookies)
+	}
+	if state := location.Query().Get("state"); state == "" || !strings.HasPrefix(cookies[0].Value, state+".") {
+		t.Errorf("Expected the state %q in the cookie %q", state, cookies[0].Value)
+	}
+}
+
+func TestExternalCallback(t *testing.T) {
+	// define test struct
+	type externalCallbackTest struct {
+		cookie             string
+		query              map[string]string
+		providerError      error
+		expectedStatusCode int
+	}
+
+	identity := &authprovider.Identity{Provider: "external", Subject: "4f1c2a", Username: "jdoe", Department: "cardiology"}
+	department := dao.Department{BaseModel: dao.BaseModel{ID: uuid.New()}, Name: "cardiology"}
+	user := dao.User{BaseModel: dao.BaseModel{ID: uuid.New()}, Username: "jdoe", DepartmentID: department.ID, Department: department}
+
+	testSteps := []externalCallbackTest{
+		{
+			cookie:             "state.nonce.verifier",
+			query:              map[string]string{"state": "state", "code": "code"},
+			expectedStatusCode: http.StatusFound,
+		},
+		{
+			// the state does not belong to this client
+			cookie:             "state.nonce.verifier",
+			query:              map[string]string{"state": "another", "code": "code"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// the login was not started by this client
+			query:              map[string]string{"state": "state", "code": "code"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// the user cancelled the login at the provider
+			cookie:             "state.nonce.verifier",
+			query:              map[string]string{"state": "state", "error": "access_denied"},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			cookie:             "state.nonce.verifier",
+			query:              map[string]string{"state": "state", "code": "code"},
+			providerError:      errors.New("invalid_grant"),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test Step: %d", i), func(t *testing.T) {
+			mockUserRepository := mock.NewUserRepositoryMock()
+			mockSessionRepository := mock.NewSessionRepositoryMock()
+			mockDepartmentRepository := mock.NewDepartmentRepositoryMock()
+			authService := AuthServiceImpl{
+				UserRepository:       &mockUserRepository,
+				SessionRepository:    &mockSessionRepository,
+				AuthProviders:        authprovider.NewRegistry(redirectProviderMock{identity: identity, err: testStep.providerError}),
+				DepartmentRepository: &mockDepartmentRepository,
+			}
+
+			// Set mock data
+			mockUserRepository.On("FindUserByExternalIdentity").Return(user, nil)
+			mockUserRepository.On("FindUserById").Return(user, nil)
+			mockDepartmentRepository.On("FindDepartmentByName").Return(department, nil)
+
+			w := httptest.NewRecorder()
+			builder := mock.NewTestContextBuilder().WithResponseRecorder(w).WithMethod("GET").WithParams(gin.Params{{Key: "provider", Value: "external"}}).WithQueries(testStep.query)
+			if testStep.cookie != "" {
+				builder = builder.WithHeader("Cookie", dco.ExternalLoginCookie+"="+testStep.cookie)
+			}
+			ctx, _ := builder.Build()
+
+			serve(ctx, authService.ExternalCallback)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedStatusCode == http.StatusFound && w.Header().Get("Location") != "/" {
+				t.Errorf("Expected redirect to / but got %q", w.Header().Get("Location"))
+			}
+		})
+	}
+}
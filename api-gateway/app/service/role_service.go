@@ -0,0 +1,322 @@
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/domain/dto"
+	"api-gateway/app/pkg"
+	"api-gateway/app/repository"
+	"database/sql"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type RoleService interface {
+	GetAllRoles(c *gin.Context)
+	GetRoleById(c *gin.Context)
+	AddRole(c *gin.Context)
+	UpdateRole(c *gin.Context)
+	DeleteRole(c *gin.Context)
+}
+
+type RoleServiceImpl struct {
+	RoleRepository       repository.RoleRepository
+	PermissionRepository repository.PermissionRepository
+}
+
+func (r RoleServiceImpl) GetAllRoles(c *gin.Context) {
+	/* GetAllRoles is a function to get all roles
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program get all roles")
+
+	rawData, err := r.RoleRepository.FindAllRoles()
+	if err != nil {
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	data := mapRoleListToRoleResponseList(rawData)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (r RoleServiceImpl) GetRoleById(c *gin.Context) {
+	/* GetRoleById is a function to get role by id
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program get role by id")
+
+	roleID, err := uuid.Parse(c.Param("roleID"))
+	if err != nil {
+		slog.Error("Error when parsing uuid. Error", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	rawData, err := r.RoleRepository.FindRoleById(roleID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	data := mapRoleToRoleResponse(rawData)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (r RoleServiceImpl) AddRole(c *gin.Context) {
+	/* AddRole is a function to add a role with its permissions
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program add role")
+
+	var rawRequest dco.RoleRequest
+	if err := c.ShouldBindJSON(&rawRequest); err != nil {
+		slog.Error("Error when binding json", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+	request := mapRoleRequestToRole(rawRequest)
+	permissions, err := r.findPermissions(rawRequest.PermissionIDs)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	request.Permissions = permissions
+
+	// Check if role name already exist
+	_, err = r.RoleRepository.FindRoleByName(request.Name)
+	switch err {
+	case nil:
+		pkg.Abort(c, pkg.NewError(constant.Conflict))
+		return
+	case gorm.ErrRecordNotFound:
+		break
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	rawData, err := r.RoleRepository.Save(&request)
+	if err != nil {
+		slog.Error("Error when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	data := mapRoleToRoleResponse(rawData)
+
+	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, data))
+}
+
+func (r RoleServiceImpl) UpdateRole(c *gin.Context) {
+	/* UpdateRole is a function to update a role by id. The permissions of the role are replaced
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program update role")
+
+	roleID, err := uuid.Parse(c.Param("roleID"))
+	if err != nil {
+		slog.Error("Error when parsing uuid. Error", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	var rawRequest dco.RoleRequest
+	if err := c.ShouldBindJSON(&rawRequest); err != nil {
+		slog.Error("Error when binding json", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+	request := mapRoleRequestToRole(rawRequest)
+
+	oldData, err := r.RoleRepository.FindRoleById(roleID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// the system-admin role is referenced by name and must not be renamed
+	if oldData.Name == dao.RoleSystemAdmin && request.Name != dao.RoleSystemAdmin {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	oldData.Name = request.Name
+	oldData.Description = request.Description
+	oldData.RequireTwoFactor = request.RequireTwoFactor
+	oldData.Permissions, err = r.findPermissions(rawRequest.PermissionIDs)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	rawData, err := r.RoleRepository.Save(&oldData)
+	if err != nil {
+		slog.Error("Error when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	data := mapRoleToRoleResponse(rawData)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (r RoleServiceImpl) DeleteRole(c *gin.Context) {
+	/* DeleteRole is a function to delete a role by id, the assignments of the role are removed as well
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program delete role")
+
+	roleID, err := uuid.Parse(c.Param("roleID"))
+	if err != nil {
+		slog.Error("Error when parsing uuid. Error", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	role, err := r.RoleRepository.FindRoleById(roleID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if role.Name == dao.RoleSystemAdmin {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	if err := r.RoleRepository.DeleteRoleById(roleID); err != nil {
+		slog.Error("Error when deleting data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (r RoleServiceImpl) findPermissions(ids []uuid.UUID) ([]dao.Permission, error) {
+	/* findPermissions loads the permissions of a role request, unknown permissions are rejected
+	 * @param ids is the list of permission ids
+	 * @return []dao.Permission
+	 */
+	permissions := []dao.Permission{}
+	for _, id := range ids {
+		permission, err := r.PermissionRepository.FindPermissionById(id)
+		switch err {
+		case nil:
+			permissions = append(permissions, permission)
+		case gorm.ErrRecordNotFound:
+			return nil, pkg.NewError(constant.InvalidRequest).WithCode("validation_failed").WithDetails(dto.ErrorDetail{
+				Field:   "permission_ids",
+				Code:    "unknown",
+				Message: "permission " + id.String() + " does not exist",
+			})
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return nil, pkg.NewError(constant.UnknownError)
+		}
+	}
+	return permissions, nil
+}
+
+var roleServiceSet = wire.NewSet(
+	wire.Struct(new(RoleServiceImpl), "*"),
+	wire.Bind(new(RoleService), new(*RoleServiceImpl)),
+)
+
+func mapRoleToRoleResponse(role dao.Role) dco.RoleResponse {
+	/* mapRoleToRoleResponse is a function to map role to role response
+	 * @param role is dao.Role
+	 * @return dco.RoleResponse
+	 */
+	var roleDescription *string
+	if role.Description.Valid {
+		roleDescription = &role.Description.String
+	}
+
+	permissions := mapPermissionListToPermissionResponseList(role.Permissions)
+	if permissions == nil {
+		permissions = []dco.PermissionResponse{}
+	}
+
+	return dco.RoleResponse{
+		BaseModel: dco.BaseModel{
+			ID:        role.ID,
+			CreatedAt: role.CreatedAt,
+			UpdatedAt: role.UpdatedAt,
+		},
+		Name:             role.Name,
+		Description:      roleDescription,
+		Permissions:      permissions,
+		RequireTwoFactor: role.RequireTwoFactor,
+	}
+}
+
+func mapRoleListToRoleResponseList(roles []dao.Role) []dco.RoleResponse {
+	/* mapRoleListToRoleResponseList is a function to map roles to role response list
+	 * @param roles is []dao.Role
+	 * @return []dco.RoleResponse
+	 */
+	result := []dco.RoleResponse{}
+	for _, role := range roles {
+		result = append(result, mapRoleToRoleResponse(role))
+	}
+	return result
+}
+
+func mapRoleRequestToRole(req dco.RoleRequest) dao.Role {
+	/* mapRoleRequestToRole is a function to map role request to role
+	 * @param req is dco.RoleRequest
+	 * @return dao.Role
+	 */
+	var roleDescription sql.NullString
+	if req.Description != nil {
+		roleDescription = sql.NullString{
+			String: *req.Description,
+			Valid:  true,
+		}
+	}
+
+	return dao.Role{
+		Name:             req.Name,
+		Description:      roleDescription,
+		RequireTwoFactor: req.RequireTwoFactor,
+	}
+}
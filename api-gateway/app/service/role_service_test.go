@@ -0,0 +1,281 @@
+package service
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/domain/dto"
+	"api-gateway/app/mock"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestAddRole(t *testing.T) {
+	// Create mock object
+	roleRepositoryMock := mock.NewRoleRepositoryMock()
+	permissionRepositoryMock := mock.NewPermissionRepositoryMock()
+	roleService := RoleServiceImpl{
+		RoleRepository:       &roleRepositoryMock,
+		PermissionRepository: &permissionRepositoryMock,
+	}
+
+	permission := dao.Permission{
+		BaseModel: dao.BaseModel{
+			ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"),
+		},
+		Name: "workday:write",
+	}
+
+	type roleServiceTestPOST struct {
+		ServiceTestPOST
+		permissionError error
+	}
+
+	// Set mock data
+	testSteps := []roleServiceTestPOST{
+		{
+			ServiceTestPOST: ServiceTestPOST{
+				mockRequestData: map[string]interface{}{
+					"name":           "planner",
+					"permission_ids": []string{"00000000-0000-0000-0000-000000000002"},
+				},
+				saveValue: dao.Role{
+					BaseModel: dao.BaseModel{
+						ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+					},
+					Name:        "planner",
+					Permissions: []dao.Permission{permission},
+				},
+				findError:          gorm.ErrRecordNotFound,
+				expectedStatusCode: http.StatusCreated,
+			},
+		},
+		{
+			ServiceTestPOST: ServiceTestPOST{
+				mockRequestData:    map[string]interface{}{},
+				expectedStatusCode: http.StatusBadRequest,
+			},
+		},
+		{
+			// unknown permission
+			ServiceTestPOST: ServiceTestPOST{
+				mockRequestData: map[string]interface{}{
+					"name":           "planner",
+					"permission_ids": []string{"00000000-0000-0000-0000-000000000003"},
+				},
+				findError:          gorm.ErrRecordNotFound,
+				expectedStatusCode: http.StatusBadRequest,
+			},
+			permissionError: gorm.ErrRecordNotFound,
+		},
+		{
+			// role name already exists
+			ServiceTestPOST: ServiceTestPOST{
+				mockRequestData: map[string]interface{}{
+					"name": "planner",
+				},
+				findValue:          dao.Role{Name: "planner"},
+				expectedStatusCode: http.StatusConflict,
+			},
+		},
+		{
+			ServiceTestPOST: ServiceTestPOST{
+				mockRequestData: map[string]interface{}{
+					"name": "planner",
+				},
+				findError:          gorm.ErrRecordNotFound,
+				saveError:          errors.New("some error"),
+				expectedStatusCode: http.StatusInternalServerError,
+			},
+		},
+	}
+
+	// Run test
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Set mock data
+			roleRepositoryMock.On("FindRoleByName").Return(testStep.findValue, testStep.findError)
+			roleRepositoryMock.On("Save").Return(testStep.saveValue, testStep.saveError)
+			permissionRepositoryMock.On("FindPermissionById").Return(permission, testStep.permissionError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "POST", gin.Params{}, testStep.mockRequestData)
+
+			// Run function
+			serve(c, roleService.AddRole)
+			// Check result
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+
+			if testStep.expectedStatusCode != http.StatusCreated {
+				return
+			}
+
+			// Check mock data
+			var responseBody dto.APIResponse[dco.RoleResponse]
+			if err := json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
+				t.Errorf("Step: %d. Error when unmarshalling response body: %s", i, err.Error())
+			}
+
+			if responseBody.Data.Name != testStep.saveValue.(dao.Role).Name {
+				t.Errorf("Step: %d. Expected name %s but got %s", i, testStep.saveValue.(dao.Role).Name, responseBody.Data.Name)
+			}
+			if len(responseBody.Data.Permissions) != len(testStep.saveValue.(dao.Role).Permissions) {
+				t.Errorf("Step: %d. Expected %d permissions but got %d", i, len(testStep.saveValue.(dao.Role).Permissions), len(responseBody.Data.Permissions))
+			}
+		})
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	// Create mock object
+	roleRepositoryMock := mock.NewRoleRepositoryMock()
+	permissionRepositoryMock := mock.NewPermissionRepositoryMock()
+	roleService := RoleServiceImpl{
+		RoleRepository:       &roleRepositoryMock,
+		PermissionRepository: &permissionRepositoryMock,
+	}
+
+	// Set mock data
+	var testSteps = []ServiceTestPUT{
+		{
+			mockRequestData: map[string]interface{}{
+				"name": "viewer",
+			},
+			findError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			params: map[string]string{
+				"roleID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			mockRequestData: map[string]interface{}{
+				"name": "reader",
+			},
+			findValue:          dao.Role{Name: "viewer"},
+			saveValue:          dao.Role{Name: "reader"},
+			expectedStatusCode: http.StatusOK,
+			params: map[string]string{
+				"roleID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			// the system-admin role can not be renamed
+			mockRequestData: map[string]interface{}{
+				"name": "admin",
+			},
+			findValue:          dao.Role{Name: dao.RoleSystemAdmin},
+			expectedStatusCode: http.StatusBadRequest,
+			params: map[string]string{
+				"roleID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			mockRequestData: map[string]interface{}{
+				"name": "viewer",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			params: map[string]string{
+				"roleID": "invalid",
+			},
+		},
+	}
+
+	// Run test
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Set mock data
+			roleRepositoryMock.On("FindRoleById").Return(testStep.findValue, testStep.findError)
+			roleRepositoryMock.On("Save").Return(testStep.saveValue, testStep.saveError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "PUT", testStep.ParamsToGinParams(), testStep.mockRequestData)
+
+			// Run function
+			serve(c, roleService.UpdateRole)
+			// Check result
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+
+			if testStep.saveValue == nil {
+				return
+			}
+
+			var responseBody dto.APIResponse[dco.RoleResponse]
+			if err := json.NewDecoder(response.Body).Decode(&responseBody); err != nil {
+				t.Errorf("Step: %d. Error when unmarshalling response body: %s", i, err.Error())
+			}
+			if responseBody.Data.Name != testStep.saveValue.(dao.Role).Name {
+				t.Errorf("Step: %d. Expected name %s but got %s", i, testStep.saveValue.(dao.Role).Name, responseBody.Data.Name)
+			}
+		})
+	}
+}
+
+func TestDeleteRole(t *testing.T) {
+	// Create mock object
+	roleRepositoryMock := mock.NewRoleRepositoryMock()
+	roleService := RoleServiceImpl{
+		RoleRepository: &roleRepositoryMock,
+	}
+
+	// Set mock data
+	testSteps := []ServiceTestDELETE{
+		{
+			mockValue:          dao.Role{Name: dao.RolePlanner},
+			expectedStatusCode: http.StatusOK,
+			params: map[string]string{
+				"roleID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			mockError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			params: map[string]string{
+				"roleID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			// the system-admin role can not be deleted
+			mockValue:          dao.Role{Name: dao.RoleSystemAdmin},
+			expectedStatusCode: http.StatusBadRequest,
+			params: map[string]string{
+				"roleID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+	}
+
+	// Run test
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Set mock data
+			roleRepositoryMock.On("FindRoleById").Return(testStep.mockValue, testStep.mockError)
+			roleRepositoryMock.On("DeleteRoleById").Return(nil, nil)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "DELETE", testStep.ParamsToGinParams(), nil)
+
+			// Run function
+			serve(c, roleService.DeleteRole)
+			// Check result
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
@@ -6,5 +6,11 @@ var ServiceSet = wire.NewSet(
 	authServiceSet,
 	userServiceSet,
 	permissionServiceSet,
+	roleServiceSet,
+	sessionServiceSet,
+	passwordServiceSet,
+	twoFactorServiceSet,
+	apiKeyServiceSet,
 	departmentServiceSet,
+	meServiceSet,
 )
@@ -1,6 +1,27 @@
 package service
 
-import "github.com/gin-gonic/gin"
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serve(c *gin.Context, handler gin.HandlerFunc) {
+	/* Calls the handler like the router, its recorded error is rendered as by the ErrorHandler middleware */
+	handler(c)
+	pkg.RenderErrors(c)
+}
+
+func departmentAdmin(plannerDepartmentID string) dao.User {
+	/* Helper to create a user who holds user:write within a single department */
+	return dao.User{
+		Username:    "department-admin",
+		Department:  dao.Department{PlannerID: plannerDepartmentID},
+		Permissions: []dao.Permission{{Name: "user:write"}},
+	}
+}
 
 /**
  * Struct to be used for testing service
@@ -67,9 +88,19 @@ type ServiceTestPUT struct {
 	findError error
 	saveError error
 
+	// the caller, a system admin if not set
+	claim *dco.JWTClaim
+
 	params map[string]string
 }
 
+func (s *ServiceTestPUT) Claim() *dco.JWTClaim {
+	if s.claim == nil {
+		return &dco.JWTClaim{Username: "admin", IsAdmin: true}
+	}
+	return s.claim
+}
+
 func (s *ServiceTestPUT) ParamsToGinParams() gin.Params {
 	var params gin.Params
 	for key, value := range s.params {
@@ -93,9 +124,19 @@ type ServiceTestDELETE struct {
 	// expected status code to be returned by service
 	expectedStatusCode int
 
+	// the caller, a system admin if not set
+	claim *dco.JWTClaim
+
 	params map[string]string
 }
 
+func (s *ServiceTestDELETE) Claim() *dco.JWTClaim {
+	if s.claim == nil {
+		return &dco.JWTClaim{Username: "admin", IsAdmin: true}
+	}
+	return s.claim
+}
+
 func (s *ServiceTestDELETE) ParamsToGinParams() gin.Params {
 	var params gin.Params
 	for key, value := range s.params {
@@ -0,0 +1,433 @@
+/**
+* This package handles the sessions of the users.
+* Every login starts a session. The client holds a short-lived access token and a refresh token,
+* which is rotated on every refresh. Only the hashes of the refresh tokens are stored.
+* Revoked sessions are put on the revocation list, which is checked by the RequiredAuth middleware.
+**/
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"api-gateway/app/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type SessionService interface {
+	Refresh(c *gin.Context)
+	GetSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+	RevokeUserSessions(c *gin.Context)
+}
+
+type SessionServiceImpl struct {
+	SessionRepository repository.SessionRepository
+	UserRepository    repository.UserRepository
+}
+
+func (s SessionServiceImpl) Refresh(c *gin.Context) {
+	/**
+	* Exchanges the refresh token for a new access token and a new refresh token.
+	* Presenting a rotated refresh token again revokes the session, since the token was likely stolen.
+	**/
+	slog.Info("start to execute program refresh session")
+
+	refreshToken, err := c.Cookie(dco.RefreshTokenCookie)
+	if err != nil || refreshToken == "" {
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+	hash := hashToken(refreshToken)
+
+	session, err := s.SessionRepository.FindSessionByRefreshTokenHash(hash)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		clearAuthCookies(c)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if !session.IsActive() {
+		clearAuthCookies(c)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	if session.RefreshTokenHash != hash {
+		// concurrent requests of the same client may present the previous token shortly after the rotation
+		if session.RotatedAt != nil && time.Since(*session.RotatedAt) < dco.RefreshTokenReuseGracePeriod {
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+
+		slog.Warn("Rotated refresh token was reused, revoking session", "session", session.ID)
+		if err := s.SessionRepository.RevokeSession(session.ID, time.Now().Add(dco.JWTExpirationTime)); err != nil {
+			slog.Error("Error happened: when revoke session", "error", err)
+		}
+		clearAuthCookies(c)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	// the user is loaded again, so changes of the roles and permissions are applied
+	user, err := s.UserRepository.FindUserById(session.UserID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		clearAuthCookies(c)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	newRefreshToken, newHash, err := generateToken()
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	now := time.Now()
+	session.PreviousRefreshTokenHash = session.RefreshTokenHash
+	session.RefreshTokenHash = newHash
+	session.RotatedAt = &now
+	session.LastUsedAt = now
+	session.ExpiresAt = now.Add(dco.RefreshTokenExpirationTime)
+	// sessions started before the CSRF protection receive their token now
+	if session.CSRFToken == "" {
+		session.CSRFToken, _, err = generateToken()
+		if err != nil {
+			pkg.Abort(c, err)
+			return
+		}
+	}
+
+	// the session is only rotated if it still holds the presented token, the loser of two
+	// concurrent refreshes with the same token is treated as a reuse
+	rotated, err := s.SessionRepository.RotateRefreshToken(&session, hash)
+	if err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	if !rotated {
+		slog.Warn("Refresh token was rotated or revoked concurrently, revoking session", "session", session.ID)
+		if err := s.SessionRepository.RevokeSession(session.ID, time.Now().Add(dco.JWTExpirationTime)); err != nil {
+			slog.Error("Error happened: when revoke session", "error", err)
+		}
+		clearAuthCookies(c)
+		pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+		return
+	}
+
+	setRefreshTokenCookie(c, newRefreshToken)
+	if err := issueAccessToken(c, user, session.ID, session.CSRFToken); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapUserToUserResponse(user)))
+}
+
+func (s SessionServiceImpl) GetSessions(c *gin.Context) {
+	/* Returns the active sessions of the user, the session of the request is marked as current */
+	slog.Info("start to execute program get sessions")
+
+	claim, user, err := s.currentUser(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	sessions, err := s.SessionRepository.FindActiveSessionsByUserId(user.ID)
+	if err != nil {
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapSessionListToSessionResponseList(sessions, claim.SessionID)))
+}
+
+func (s SessionServiceImpl) RevokeSession(c *gin.Context) {
+	/* Revokes a session of the user, e.g. a forgotten login on another device */
+	slog.Info("start to execute program revoke session")
+
+	sessionID, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	claim, user, err := s.currentUser(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	session, err := s.SessionRepository.FindSessionById(sessionID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// sessions of other users are not revealed
+	if session.UserID != user.ID {
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	}
+
+	if err := s.SessionRepository.RevokeSession(session.ID, time.Now().Add(dco.JWTExpirationTime)); err != nil {
+		slog.Error("Error happened: when revoke session", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if session.ID.String() == claim.SessionID {
+		clearAuthCookies(c)
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (s SessionServiceImpl) RevokeUserSessions(c *gin.Context) {
+	/* Revokes all sessions of a user, this is used by admins to force a logout */
+	slog.Info("start to execute program revoke sessions of user")
+
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	_, err = s.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if err := s.SessionRepository.RevokeSessionsOfUser(userID, time.Now().Add(dco.JWTExpirationTime)); err != nil {
+		slog.Error("Error happened: when revoke sessions", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (s SessionServiceImpl) currentUser(c *gin.Context) (*dco.JWTClaim, dao.User, error) {
+	/* Helper to load the user of the request */
+	claim, exists := c.Get("retrievedToken")
+	if !exists {
+		slog.Error("Error happened: when get token from cookie", "error", "token not found")
+		return nil, dao.User{}, pkg.NewError(constant.Unauthorized)
+	}
+
+	user, err := s.UserRepository.FindUserByUsername(claim.(*dco.JWTClaim).Username)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		return nil, dao.User{}, pkg.NewError(constant.Unauthorized)
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		return nil, dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+
+	return claim.(*dco.JWTClaim), user, nil
+}
+
+var sessionServiceSet = wire.NewSet(
+	wire.Struct(new(SessionServiceImpl), "*"),
+	wire.Bind(new(SessionService), new(*SessionServiceImpl)),
+)
+
+func startSession(c *gin.Context, sessionRepository repository.SessionRepository, user dao.User) (dao.Session, error) {
+	/**
+	* Creates a new session for the user and sets the refresh token cookie
+	* @param c is gin context
+	* @param sessionRepository is the repository to store the session in
+	* @param user is the user to start the session for
+	* @return the created session
+	**/
+	refreshToken, hash, err := generateToken()
+	if err != nil {
+		return dao.Session{}, err
+	}
+	csrfToken, _, err := generateToken()
+	if err != nil {
+		return dao.Session{}, err
+	}
+
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	now := time.Now()
+	session, err := sessionRepository.Save(&dao.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: hash,
+		CSRFToken:        csrfToken,
+		UserAgent:        userAgent,
+		IPAddress:        c.ClientIP(),
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(dco.RefreshTokenExpirationTime),
+	})
+	if err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		return dao.Session{}, pkg.NewError(constant.UnknownError)
+	}
+
+	setRefreshTokenCookie(c, refreshToken)
+	return session, nil
+}
+
+func issueAccessToken(c *gin.Context, user dao.User, sessionID uuid.UUID, csrfToken string) error {
+	/**
+	* Creates a short-lived access token bound to the session and sets it as cookie.
+	* The roles and permissions of the user are resolved into the token.
+	* The CSRF token of the session is set as cookie readable by the frontend and in the X-CSRF-Token header.
+	**/
+	tokenString, err := dco.JWTKeys.Sign(dco.JWTClaim{
+		SessionID:   sessionID.String(),
+		Username:    user.Username,
+		Department:  user.Department.ID.String(),
+		Roles:       user.RoleNames(),
+		Permissions: user.PermissionNames(),
+		IsAdmin:     user.IsSystemAdmin(),
+
+		PlannerDepartmentID: user.Department.PlannerID,
+		Memberships:         membershipClaims(user),
+
+		MustChangePassword:  user.MustChangePassword,
+		MustEnrollTwoFactor: user.RequiresTwoFactor() && !user.HasTwoFactor(),
+		CSRFToken:           csrfToken,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    dco.JWTIssuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(dco.JWTExpirationTime)),
+		},
+	})
+	if err != nil {
+		return pkg.NewError(constant.UnknownError).Wrap(err)
+	}
+
+	setCookie(c, dco.AccessTokenCookie, tokenString, int(dco.JWTExpirationTime.Seconds()), "/")
+
+	// the frontend reads the token to send it with mutating requests,
+	// it is never shared with other subdomains, which could send it as well
+	c.SetCookie(dco.CSRFTokenCookie, csrfToken, int(dco.RefreshTokenExpirationTime.Seconds()), "/", "", dco.Cookies.Secure, false)
+	c.Header(dco.CSRFTokenHeader, csrfToken)
+	return nil
+}
+
+func membershipClaims(user dao.User) []dco.DepartmentMembershipClaim {
+	/* Resolves the roles and permissions of the user within each of its departments */
+	memberships := []dco.DepartmentMembershipClaim{}
+	for _, department := range user.Departments() {
+		memberships = append(memberships, dco.DepartmentMembershipClaim{
+			Department:          department.ID.String(),
+			PlannerDepartmentID: department.PlannerID,
+			Roles:               user.RoleNamesIn(department.ID),
+			Permissions:         user.PermissionNamesIn(department.ID),
+		})
+	}
+	return memberships
+}
+
+func setRefreshTokenCookie(c *gin.Context, refreshToken string) {
+	/* The refresh token cookie is only sent to the auth routes */
+	setCookie(c, dco.RefreshTokenCookie, refreshToken, int(dco.RefreshTokenExpirationTime.Seconds()), dco.RefreshTokenCookiePath)
+}
+
+func clearAuthCookies(c *gin.Context) {
+	setCookie(c, dco.AccessTokenCookie, "", -1, "/")
+	setCookie(c, dco.RefreshTokenCookie, "", -1, dco.RefreshTokenCookiePath)
+	c.SetCookie(dco.CSRFTokenCookie, "", -1, "/", "", dco.Cookies.Secure, false)
+}
+
+func setCookie(c *gin.Context, name string, value string, maxAge int, path string) {
+	/* Sets an HttpOnly cookie with the Secure, Domain and SameSite attributes of dco.Cookies */
+	c.SetSameSite(dco.Cookies.SameSite)
+	c.SetCookie(name, value, maxAge, path, dco.Cookies.Domain, dco.Cookies.Secure, true)
+}
+
+func generateToken() (string, string, error) {
+	/**
+	* Generates a random token, e.g. a refresh token or a password reset token
+	* @return the token for the client and its hash for the database
+	**/
+	buffer := make([]byte, 32)
+	if _, err := rand.Read(buffer); err != nil {
+		slog.Error("Error happened: when generate token", "error", err)
+		return "", "", pkg.NewError(constant.UnknownError)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buffer)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func mapSessionListToSessionResponseList(sessions []dao.Session, currentSessionID string) []dco.SessionResponse {
+	/* mapSessionListToSessionResponseList is a function to map sessions to session responses
+	 * @param sessions is []dao.Session
+	 * @param currentSessionID is the session of the request
+	 * @return []dco.SessionResponse
+	 */
+	result := []dco.SessionResponse{}
+	for _, session := range sessions {
+		result = append(result, dco.SessionResponse{
+			ID:         session.ID,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    session.ID.String() == currentSessionID,
+		})
+	}
+	return result
+}
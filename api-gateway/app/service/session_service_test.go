@@ -0,0 +1,337 @@
+package service
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/middleware"
+	"api-gateway/app/mock"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestRefreshSession(t *testing.T) {
+	/* Test Refresh */
+	// Create Mock Repo
+	sessionRepoMock := mock.NewSessionRepositoryMock()
+	userRepoMock := mock.NewUserRepositoryMock()
+	sessionService := SessionServiceImpl{
+		SessionRepository: &sessionRepoMock,
+		UserRepository:    &userRepoMock,
+	}
+
+	refreshToken := "refresh-token"
+	rotatedJustNow := time.Now()
+	rotatedLongAgo := time.Now().Add(-time.Hour)
+	revokedAt := time.Now().Add(-time.Minute)
+
+	type refreshTest struct {
+		refreshToken string
+		session      dao.Session
+		sessionError error
+		userError    error
+		// another refresh or a revocation changed the session first
+		concurrentlyChanged bool
+		expectedStatusCode  int
+		expectedCookies     bool
+		// the CSRF token of the session is kept, empty if a new one is expected
+		expectedCSRFToken string
+	}
+
+	testSteps := []refreshTest{
+		{
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash: hashToken(refreshToken),
+				ExpiresAt:        time.Now().Add(time.Hour),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCookies:    true,
+		},
+		{
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash: hashToken(refreshToken),
+				CSRFToken:        "session-csrf",
+				ExpiresAt:        time.Now().Add(time.Hour),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedCookies:    true,
+			expectedCSRFToken:  "session-csrf",
+		},
+		{
+			// no refresh token
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			refreshToken:       refreshToken,
+			sessionError:       gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			refreshToken:       refreshToken,
+			sessionError:       errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// expired session
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash: hashToken(refreshToken),
+				ExpiresAt:        time.Now().Add(-time.Hour),
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			// revoked session
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash: hashToken(refreshToken),
+				ExpiresAt:        time.Now().Add(time.Hour),
+				RevokedAt:        &revokedAt,
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			// previous token within the grace period
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash:         "rotated",
+				PreviousRefreshTokenHash: hashToken(refreshToken),
+				RotatedAt:                &rotatedJustNow,
+				ExpiresAt:                time.Now().Add(time.Hour),
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			// reused token
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash:         "rotated",
+				PreviousRefreshTokenHash: hashToken(refreshToken),
+				RotatedAt:                &rotatedLongAgo,
+				ExpiresAt:                time.Now().Add(time.Hour),
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			// user was deleted
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash: hashToken(refreshToken),
+				ExpiresAt:        time.Now().Add(time.Hour),
+			},
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			// concurrent refresh with the same token or revocation after the session was read
+			refreshToken: refreshToken,
+			session: dao.Session{
+				RefreshTokenHash: hashToken(refreshToken),
+				ExpiresAt:        time.Now().Add(time.Hour),
+			},
+			concurrentlyChanged: true,
+			expectedStatusCode:  http.StatusUnauthorized,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			sessionRepoMock.On("FindSessionByRefreshTokenHash").Return(testStep.session, testStep.sessionError)
+			sessionRepoMock.On("RotateRefreshToken").Return(!testStep.concurrentlyChanged, nil)
+			userRepoMock.On("FindUserById").Return(dao.User{Username: "test"}, testStep.userError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			builder := mock.NewTestContextBuilder().
+				WithResponseRecorder(w).
+				WithMethod("POST")
+			if testStep.refreshToken != "" {
+				builder = builder.WithHeader("Cookie", dco.RefreshTokenCookie+"="+testStep.refreshToken)
+			}
+			c, _ := builder.Build()
+
+			// Call function
+			serve(c, sessionService.Refresh)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+
+			cookies := strings.Join(response.Header.Values("Set-Cookie"), ";")
+			if testStep.expectedCookies {
+				if !strings.Contains(cookies, dco.AccessTokenCookie+"=") || !strings.Contains(cookies, dco.RefreshTokenCookie+"=") {
+					t.Errorf("Step: %d. Expected access and refresh token cookies but got %s", i, cookies)
+				}
+				if strings.Contains(cookies, dco.RefreshTokenCookie+"="+refreshToken) {
+					t.Errorf("Step: %d. Expected refresh token to be rotated", i)
+				}
+
+				// the CSRF token is sent as cookie and header, sessions without a token receive one
+				csrfToken := response.Header.Get(dco.CSRFTokenHeader)
+				if csrfToken == "" || !strings.Contains(cookies, dco.CSRFTokenCookie+"="+csrfToken) {
+					t.Errorf("Step: %d. Expected the CSRF token as cookie and header but got %q and %s", i, csrfToken, cookies)
+				}
+				if testStep.expectedCSRFToken != "" && csrfToken != testStep.expectedCSRFToken {
+					t.Errorf("Step: %d. Expected the CSRF token of the session %q but got %q", i, testStep.expectedCSRFToken, csrfToken)
+				}
+			}
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	/* Test Revoke Session */
+	// Create Mock Repo
+	sessionRepoMock := mock.NewSessionRepositoryMock()
+	userRepoMock := mock.NewUserRepositoryMock()
+	sessionService := SessionServiceImpl{
+		SessionRepository: &sessionRepoMock,
+		UserRepository:    &userRepoMock,
+	}
+
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	otherUserID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	sessionID := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+
+	type revokeSessionTest struct {
+		sessionID          string
+		session            dao.Session
+		sessionError       error
+		revokeError        error
+		expectedStatusCode int
+	}
+
+	testSteps := []revokeSessionTest{
+		{
+			sessionID:          sessionID.String(),
+			session:            dao.Session{BaseModel: dao.BaseModel{ID: sessionID}, UserID: userID},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			// sessions of other users are not found
+			sessionID:          sessionID.String(),
+			session:            dao.Session{BaseModel: dao.BaseModel{ID: sessionID}, UserID: otherUserID},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			sessionID:          sessionID.String(),
+			sessionError:       gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			sessionID:          "invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			sessionID:          sessionID.String(),
+			session:            dao.Session{BaseModel: dao.BaseModel{ID: sessionID}, UserID: userID},
+			revokeError:        errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	user := dao.User{BaseModel: dao.BaseModel{ID: userID}, Username: "test"}
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			userRepoMock.On("FindUserByUsername").Return(user, nil)
+			sessionRepoMock.On("FindSessionById").Return(testStep.session, testStep.sessionError)
+			sessionRepoMock.On("RevokeSession").Return(nil, testStep.revokeError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, _ := mock.NewTestContextBuilder().
+				WithResponseRecorder(w).
+				WithMethod("DELETE").
+				WithParams(gin.Params{{Key: "sessionID", Value: testStep.sessionID}}).
+				Build()
+
+			token, err := mock.GenerateMockToken(user)
+			if err != nil {
+				t.Error("Error happened: when generate mock token", "error", err)
+			}
+			claim, _ := middleware.DecodeToken(token)
+			c.Set("retrievedToken", claim)
+
+			// Call function
+			serve(c, sessionService.RevokeSession)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	/* Test Revoke Sessions of User */
+	// Create Mock Repo
+	sessionRepoMock := mock.NewSessionRepositoryMock()
+	userRepoMock := mock.NewUserRepositoryMock()
+	sessionService := SessionServiceImpl{
+		SessionRepository: &sessionRepoMock,
+		UserRepository:    &userRepoMock,
+	}
+
+	type revokeUserSessionsTest struct {
+		userID             string
+		userError          error
+		revokeError        error
+		expectedStatusCode int
+	}
+
+	testSteps := []revokeUserSessionsTest{
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			userID:             "invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			userID:             "00000000-0000-0000-0000-000000000001",
+			revokeError:        errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			userRepoMock.On("FindUserById").Return(dao.User{}, testStep.userError)
+			sessionRepoMock.On("RevokeSessionsOfUser").Return(nil, testStep.revokeError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, _ := mock.NewTestContextBuilder().
+				WithResponseRecorder(w).
+				WithMethod("DELETE").
+				WithParams(gin.Params{{Key: "userID", Value: testStep.userID}}).
+				Build()
+
+			// Call function
+			serve(c, sessionService.RevokeUserSessions)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+/**
+* This package handles the two-factor authentication of the users.
+* - EnrollTwoFactor: Create a TOTP secret for the authenticator app
+* - ConfirmTwoFactor: Enable two-factor authentication with a first code and return the recovery codes
+* - DisableTwoFactor: Disable two-factor authentication, unless a role of the user requires it
+* - RegenerateRecoveryCodes: Replace the recovery codes
+* - ResetTwoFactor: Remove the second factor of a user who lost the device, for admins
+**/
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"api-gateway/app/repository"
+	"api-gateway/app/totp"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+type TwoFactorService interface {
+	EnrollTwoFactor(c *gin.Context)
+	ConfirmTwoFactor(c *gin.Context)
+	DisableTwoFactor(c *gin.Context)
+	RegenerateRecoveryCodes(c *gin.Context)
+	ResetTwoFactor(c *gin.Context)
+}
+
+type TwoFactorServiceImpl struct {
+	UserRepository      repository.UserRepository
+	TwoFactorRepository repository.TwoFactorRepository
+	AuditRepository     repository.AuditRepository
+}
+
+func (t TwoFactorServiceImpl) EnrollTwoFactor(c *gin.Context) {
+	/**
+	* Creates a new secret for the user. Two-factor authentication is enabled once the user
+	* confirmed a code of the authenticator app, until then the enrollment can be restarted.
+	**/
+	slog.Info("start to execute program enroll two factor")
+
+	_, user, err := t.currentUser(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if user.HasTwoFactor() {
+		pkg.Abort(c, pkg.NewError(constant.Conflict))
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		slog.Error("Error happened: when generate secret", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	if _, err := t.TwoFactorRepository.Save(&dao.TwoFactor{UserID: user.ID, Secret: secret}); err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	issuer := dco.TOTPIssuer
+	if issuer == "" {
+		issuer = "Planner"
+	}
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, dco.TwoFactorEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(issuer, user.Username, secret),
+	}))
+}
+
+func (t TwoFactorServiceImpl) ConfirmTwoFactor(c *gin.Context) {
+	/**
+	* Enables two-factor authentication if the code matches the secret of the enrollment.
+	* The recovery codes are returned once. The access token is reissued, since the
+	* enrollment might have been required by a role of the user.
+	**/
+	slog.Info("start to execute program confirm two factor")
+
+	var request dco.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	claim, user, err := t.currentUser(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if user.TwoFactor == nil {
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	}
+	if user.HasTwoFactor() {
+		pkg.Abort(c, pkg.NewError(constant.Conflict))
+		return
+	}
+
+	step, ok := totp.Validate(user.TwoFactor.Secret, request.Code, time.Now(), 0)
+	if !ok {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Invalid code"))
+		return
+	}
+
+	switch err := t.TwoFactorRepository.Enable(user.ID, step); err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		// enabled by a concurrent request
+		pkg.Abort(c, pkg.NewError(constant.Conflict))
+		return
+	default:
+		slog.Error("Error happened: when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	codes, err := t.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	user, err = t.UserRepository.FindUserById(user.ID)
+	if err != nil {
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	sessionID, _ := uuid.Parse(claim.SessionID)
+	if err := issueAccessToken(c, user, sessionID, claim.CSRFToken); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, dco.RecoveryCodesResponse{RecoveryCodes: codes}))
+}
+
+func (t TwoFactorServiceImpl) DisableTwoFactor(c *gin.Context) {
+	/**
+	* Disables two-factor authentication after checking a code or a recovery code.
+	* Users holding a role which requires two-factor authentication cannot disable it.
+	**/
+	slog.Info("start to execute program disable two factor")
+
+	var request dco.TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	_, user, err := t.currentUser(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if !user.HasTwoFactor() {
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	}
+	if user.RequiresTwoFactor() {
+		pkg.Abort(c, pkg.NewError(constant.Forbidden).WithMessage("Two-factor authentication is required by a role of the user"))
+		return
+	}
+
+	valid, err := verifySecondFactor(t.TwoFactorRepository, user, request.Code, request.RecoveryCode)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if !valid {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Invalid code"))
+		return
+	}
+
+	if err := t.TwoFactorRepository.DeleteTwoFactor(user.ID); err != nil {
+		slog.Error("Error happened: when deleting data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (t TwoFactorServiceImpl) RegenerateRecoveryCodes(c *gin.Context) {
+	/* Replaces the recovery codes after checking a code of the authenticator app */
+	slog.Info("start to execute program regenerate recovery codes")
+
+	var request dco.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	_, user, err := t.currentUser(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if !user.HasTwoFactor() {
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	}
+
+	valid, err := verifySecondFactor(t.TwoFactorRepository, user, request.Code, "")
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	if !valid {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithMessage("Invalid code"))
+		return
+	}
+
+	codes, err := t.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, dco.RecoveryCodesResponse{RecoveryCodes: codes}))
+}
+
+func (t TwoFactorServiceImpl) ResetTwoFactor(c *gin.Context) {
+	/**
+	* Removes the second factor of a user, e.g. after the device was lost.
+	* The user has to enroll again if a role requires two-factor authentication.
+	**/
+	slog.Info("start to execute program reset two factor")
+
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	user, err := t.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	claim, err := authorizeUserChange(c, user, "user:write")
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	if err := t.TwoFactorRepository.DeleteTwoFactor(user.ID); err != nil {
+		slog.Error("Error happened: when deleting data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	saveAuditEntry(t.AuditRepository, &dao.AuditEntry{
+		Event:     dao.AuditEventTwoFactorReset,
+		Subject:   user.Username,
+		Actor:     claim.Username,
+		IPAddress: c.ClientIP(),
+	})
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (t TwoFactorServiceImpl) currentUser(c *gin.Context) (*dco.JWTClaim, dao.User, error) {
+	/* Helper to load the user of the request */
+	claim, exists := c.Get("retrievedToken")
+	if !exists {
+		slog.Error("Error happened: when get token from cookie", "error", "token not found")
+		return nil, dao.User{}, pkg.NewError(constant.Unauthorized)
+	}
+
+	user, err := t.UserRepository.FindUserByUsername(claim.(*dco.JWTClaim).Username)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		return nil, dao.User{}, pkg.NewError(constant.Unauthorized)
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		return nil, dao.User{}, pkg.NewError(constant.UnknownError)
+	}
+
+	return claim.(*dco.JWTClaim), user, nil
+}
+
+func (t TwoFactorServiceImpl) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.TwoFactorRepository.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		slog.Error("Error happened: when saving data to database", "error", err)
+		return nil, pkg.NewError(constant.UnknownError)
+	}
+	return codes, nil
+}
+
+var twoFactorServiceSet = wire.NewSet(
+	wire.Struct(new(TwoFactorServiceImpl), "*"),
+	wire.Bind(new(TwoFactorService), new(*TwoFactorServiceImpl)),
+)
+
+func verifySecondFactor(twoFactorRepository repository.TwoFactorRepository, user dao.User, code string, recoveryCode string) (bool, error) {
+	/**
+	* Checks a code of the authenticator app or a recovery code, both can only be used once
+	* @param twoFactorRepository is the repository to mark the code as used
+	* @param user is the user with the second factor loaded
+	* @param code is the code of the authenticator app
+	* @param recoveryCode is a recovery code, it is checked instead of the code if it is set
+	* @return true if the code is valid
+	**/
+	if !user.HasTwoFactor() {
+		return false, nil
+	}
+
+	var err error
+	if recoveryCode != "" {
+		err = twoFactorRepository.UseRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(recoveryCode)))
+	} else {
+		step, ok := totp.Validate(user.TwoFactor.Secret, code, time.Now(), user.TwoFactor.LastUsedStep)
+		if !ok {
+			return false, nil
+		}
+		err = twoFactorRepository.UseStep(user.ID, step)
+	}
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return false, nil
+	default:
+		slog.Error("Error happened: when saving data to database", "error", err)
+		return false, pkg.NewError(constant.UnknownError)
+	}
+}
+
+func generateRecoveryCodes() ([]string, []string, error) {
+	/**
+	* Generates the recovery codes, e.g. "k3jd-9x2a"
+	* @return the codes for the user and their hashes for the database
+	**/
+	codes := []string{}
+	hashes := []string{}
+	for i := 0; i < dco.RecoveryCodeCount; i++ {
+		buffer := make([]byte, 5)
+		if _, err := rand.Read(buffer); err != nil {
+			slog.Error("Error happened: when generate recovery code", "error", err)
+			return nil, nil, pkg.NewError(constant.UnknownError)
+		}
+
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(buffer))
+		code = code[:4] + "-" + code[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(code)))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	/* Recovery codes are accepted regardless of case, dashes and spaces */
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+}
@@ -0,0 +1,433 @@
+package service
+
+import (
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/middleware"
+	"api-gateway/app/mock"
+	"api-gateway/app/totp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const twoFactorTestSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func twoFactorTestContext(t *testing.T, w *httptest.ResponseRecorder, method string, params gin.Params, body interface{}, user dao.User) *gin.Context {
+	/* Helper to create a context of a logged in user */
+	c := mock.GetGinTestContext(w, method, params, body)
+
+	token, err := mock.GenerateMockToken(user)
+	if err != nil {
+		t.Error("Error happened: when generate mock token", "error", err)
+	}
+	claim, _ := middleware.DecodeToken(token)
+	c.Set("retrievedToken", claim)
+	return c
+}
+
+func currentTOTPCode(t *testing.T) string {
+	/* Helper to create a valid code of the test secret */
+	code, err := totp.Code(twoFactorTestSecret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal("Error happened: when generate code", "error", err)
+	}
+	return code
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	/* Test Enroll Two Factor */
+	enabledAt := time.Now()
+
+	type enrollTwoFactorTest struct {
+		twoFactor          *dao.TwoFactor
+		saveError          error
+		expectedStatusCode int
+	}
+
+	testSteps := []enrollTwoFactorTest{
+		{
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			// an unconfirmed enrollment can be restarted
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			saveError:          errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock := mock.NewUserRepositoryMock()
+			twoFactorRepoMock := mock.NewTwoFactorRepositoryMock()
+			twoFactorService := TwoFactorServiceImpl{
+				UserRepository:      &userRepoMock,
+				TwoFactorRepository: &twoFactorRepoMock,
+			}
+
+			user := dao.User{Username: "test", TwoFactor: testStep.twoFactor}
+			userRepoMock.On("FindUserByUsername").Return(user, nil)
+			twoFactorRepoMock.On("Save").Return(nil, testStep.saveError)
+
+			w := httptest.NewRecorder()
+			c := twoFactorTestContext(t, w, "POST", gin.Params{}, nil, user)
+
+			serve(c, twoFactorService.EnrollTwoFactor)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+
+			if testStep.expectedStatusCode == http.StatusOK {
+				var response struct {
+					Data dco.TwoFactorEnrollmentResponse `json:"data"`
+				}
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("Step: %d. Error happened: when parse response %v", i, err)
+				}
+				if response.Data.Secret == "" || response.Data.ProvisioningURI == "" {
+					t.Errorf("Step: %d. Expected a secret and a provisioning uri, got %+v", i, response.Data)
+				}
+			}
+		})
+	}
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	/* Test Confirm Two Factor */
+	enabledAt := time.Now()
+	validCode := currentTOTPCode(t)
+	requiredRoles := []dao.UserRole{{Role: dao.Role{Name: "planner", RequireTwoFactor: true}}}
+
+	type confirmTwoFactorTest struct {
+		request            map[string]interface{}
+		twoFactor          *dao.TwoFactor
+		enableError        error
+		expectedStatusCode int
+	}
+
+	testSteps := []confirmTwoFactorTest{
+		{
+			request:            map[string]interface{}{"code": validCode},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			request:            map[string]interface{}{},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            map[string]interface{}{"code": "000000"},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// not enrolled
+			request:            map[string]interface{}{"code": validCode},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			request:            map[string]interface{}{"code": validCode},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			// enabled by a concurrent request
+			request:            map[string]interface{}{"code": validCode},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret},
+			enableError:        gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusConflict,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock := mock.NewUserRepositoryMock()
+			twoFactorRepoMock := mock.NewTwoFactorRepositoryMock()
+			twoFactorService := TwoFactorServiceImpl{
+				UserRepository:      &userRepoMock,
+				TwoFactorRepository: &twoFactorRepoMock,
+			}
+
+			user := dao.User{Username: "test", Roles: requiredRoles, TwoFactor: testStep.twoFactor}
+			userRepoMock.On("FindUserByUsername").Return(user, nil)
+			userRepoMock.On("FindUserById").Return(dao.User{
+				Username:  "test",
+				Roles:     requiredRoles,
+				TwoFactor: &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			}, nil)
+			twoFactorRepoMock.On("Enable").Return(nil, testStep.enableError)
+
+			w := httptest.NewRecorder()
+			c := twoFactorTestContext(t, w, "POST", gin.Params{}, testStep.request, user)
+
+			serve(c, twoFactorService.ConfirmTwoFactor)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+
+			if testStep.expectedStatusCode == http.StatusOK {
+				if len(twoFactorRepoMock.RecoveryCodeHashes) != dco.RecoveryCodeCount {
+					t.Errorf("Step: %d. Expected %d recovery codes, got %d", i, dco.RecoveryCodeCount, len(twoFactorRepoMock.RecoveryCodeHashes))
+				}
+
+				// the reissued token no longer requires the enrollment
+				var accessToken string
+				for _, cookie := range w.Result().Cookies() {
+					if cookie.Name == dco.AccessTokenCookie {
+						accessToken = cookie.Value
+					}
+				}
+				claim, err := middleware.DecodeToken(accessToken)
+				if err != nil {
+					t.Fatalf("Step: %d. Expected a valid access token, got %v", i, err)
+				}
+				if claim.MustEnrollTwoFactor {
+					t.Errorf("Step: %d. Expected the enrollment to be completed", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	/* Test Disable Two Factor */
+	enabledAt := time.Now()
+	validCode := currentTOTPCode(t)
+
+	type disableTwoFactorTest struct {
+		request            map[string]interface{}
+		roles              []dao.UserRole
+		twoFactor          *dao.TwoFactor
+		useError           error
+		expectedStatusCode int
+	}
+
+	testSteps := []disableTwoFactorTest{
+		{
+			request:            map[string]interface{}{"code": validCode},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			request:            map[string]interface{}{"recovery_code": "ABCD-EFGH"},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			// unknown or used recovery code
+			request:            map[string]interface{}{"recovery_code": "abcd-efgh"},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			useError:           gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            map[string]interface{}{"code": "000000"},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            map[string]interface{}{"code": validCode},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			// a role of the user requires two-factor authentication
+			request:            map[string]interface{}{"code": validCode},
+			roles:              []dao.UserRole{{Role: dao.Role{Name: "planner", RequireTwoFactor: true}}},
+			twoFactor:          &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt},
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock := mock.NewUserRepositoryMock()
+			twoFactorRepoMock := mock.NewTwoFactorRepositoryMock()
+			twoFactorService := TwoFactorServiceImpl{
+				UserRepository:      &userRepoMock,
+				TwoFactorRepository: &twoFactorRepoMock,
+			}
+
+			user := dao.User{Username: "test", Roles: testStep.roles, TwoFactor: testStep.twoFactor}
+			userRepoMock.On("FindUserByUsername").Return(user, nil)
+			twoFactorRepoMock.On("UseRecoveryCode").Return(nil, testStep.useError)
+
+			w := httptest.NewRecorder()
+			c := twoFactorTestContext(t, w, "DELETE", gin.Params{}, testStep.request, user)
+
+			serve(c, twoFactorService.DisableTwoFactor)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestRegenerateRecoveryCodes(t *testing.T) {
+	/* Test Regenerate Recovery Codes */
+	enabledAt := time.Now()
+	validCode := currentTOTPCode(t)
+
+	type regenerateRecoveryCodesTest struct {
+		request            map[string]interface{}
+		useError           error
+		expectedStatusCode int
+	}
+
+	testSteps := []regenerateRecoveryCodesTest{
+		{
+			request:            map[string]interface{}{"code": validCode},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			// the code was already used
+			request:            map[string]interface{}{"code": validCode},
+			useError:           gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// recovery codes cannot replace themselves
+			request:            map[string]interface{}{"recovery_code": "abcd-efgh"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			request:            map[string]interface{}{"code": validCode},
+			useError:           errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock := mock.NewUserRepositoryMock()
+			twoFactorRepoMock := mock.NewTwoFactorRepositoryMock()
+			twoFactorService := TwoFactorServiceImpl{
+				UserRepository:      &userRepoMock,
+				TwoFactorRepository: &twoFactorRepoMock,
+			}
+
+			user := dao.User{Username: "test", TwoFactor: &dao.TwoFactor{Secret: twoFactorTestSecret, EnabledAt: &enabledAt}}
+			userRepoMock.On("FindUserByUsername").Return(user, nil)
+			twoFactorRepoMock.On("UseStep").Return(nil, testStep.useError)
+
+			w := httptest.NewRecorder()
+			c := twoFactorTestContext(t, w, "POST", gin.Params{}, testStep.request, user)
+
+			serve(c, twoFactorService.RegenerateRecoveryCodes)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedStatusCode == http.StatusOK && len(twoFactorRepoMock.RecoveryCodeHashes) != dco.RecoveryCodeCount {
+				t.Errorf("Step: %d. Expected %d recovery codes, got %d", i, dco.RecoveryCodeCount, len(twoFactorRepoMock.RecoveryCodeHashes))
+			}
+		})
+	}
+}
+
+func TestResetTwoFactor(t *testing.T) {
+	/* Test Reset Two Factor */
+	type resetTwoFactorTest struct {
+		userID             string
+		user               dao.User
+		caller             dao.User
+		userError          error
+		deleteError        error
+		expectedStatusCode int
+		expectedAudit      int
+	}
+
+	testSteps := []resetTwoFactorTest{
+		{
+			userID:             uuid.NewString(),
+			expectedStatusCode: http.StatusOK,
+			expectedAudit:      1,
+		},
+		{
+			userID:             "invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			userID:             uuid.NewString(),
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			userID:             uuid.NewString(),
+			deleteError:        errors.New("some error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// the user belongs to a department the caller does not administer
+			userID:             uuid.NewString(),
+			user:               dao.User{Username: "test", Department: dao.Department{PlannerID: "planner-b"}},
+			caller:             departmentAdmin("planner-a"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			userID:             uuid.NewString(),
+			user:               dao.User{Username: "test", Department: dao.Department{PlannerID: "planner-a"}, Roles: mock.SystemAdminRoles},
+			caller:             departmentAdmin("planner-a"),
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			userID:             uuid.NewString(),
+			user:               dao.User{Username: "test", Department: dao.Department{PlannerID: "planner-a"}},
+			caller:             departmentAdmin("planner-a"),
+			expectedStatusCode: http.StatusOK,
+			expectedAudit:      1,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			userRepoMock := mock.NewUserRepositoryMock()
+			twoFactorRepoMock := mock.NewTwoFactorRepositoryMock()
+			auditRepoMock := mock.AuditRepositoryMock{}
+			twoFactorService := TwoFactorServiceImpl{
+				UserRepository:      &userRepoMock,
+				TwoFactorRepository: &twoFactorRepoMock,
+				AuditRepository:     &auditRepoMock,
+			}
+
+			// the user and the caller default to a user and a system admin
+			user, caller := testStep.user, testStep.caller
+			if user.Username == "" {
+				user = dao.User{Username: "test"}
+			}
+			if caller.Username == "" {
+				caller = dao.User{Username: "admin", Roles: mock.SystemAdminRoles}
+			}
+
+			userRepoMock.On("FindUserById").Return(user, testStep.userError)
+			twoFactorRepoMock.On("DeleteTwoFactor").Return(nil, testStep.deleteError)
+
+			w := httptest.NewRecorder()
+			c := twoFactorTestContext(t, w, "DELETE", gin.Params{{Key: "userID", Value: testStep.userID}}, nil, caller)
+
+			serve(c, twoFactorService.ResetTwoFactor)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, w.Code)
+			}
+			if len(auditRepoMock.Entries) != testStep.expectedAudit {
+				t.Errorf("Step: %d. Expected %d audit entries, got %d", i, testStep.expectedAudit, len(auditRepoMock.Entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"api-gateway/app/constant"
+	"api-gateway/app/domain/dao"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/pkg"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+func authorizeUserChange(c *gin.Context, user dao.User, permission string) (*dco.JWTClaim, error) {
+	/**
+	* Checks that the caller may change a user: the permission has to be held within one of the departments
+	* of the user, the permission of the department of the caller is not enough.
+	* System admins can only be changed by system admins.
+	* @param user: The user to change, loaded with its memberships and roles
+	* @param permission: The permission required for the change, e.g. user:write
+	* @return: The claim of the caller
+	**/
+	token, exists := c.Get("retrievedToken")
+	if !exists {
+		slog.Error("Error happened: when get token from context", "error", "token not found")
+		return nil, pkg.NewError(constant.Unauthorized)
+	}
+	claim := token.(*dco.JWTClaim)
+
+	if claim.IsAdmin {
+		return claim, nil
+	}
+	if user.IsSystemAdmin() {
+		slog.Error("Error happened: when check permission", "username", claim.Username, "user", user.Username, "error", "system admins can only be changed by system admins")
+		return nil, pkg.NewError(constant.Forbidden)
+	}
+	if !holdsPermissionIn(claim, user.Departments(), permission) {
+		slog.Error("Error happened: when check permission", "username", claim.Username, "user", user.Username, "permission", permission)
+		return nil, pkg.NewError(constant.Forbidden)
+	}
+	return claim, nil
+}
+
+func holdsPermissionIn(claim *dco.JWTClaim, departments []dao.Department, permission string) bool {
+	/* Checks whether the claim holds the permission within one of the departments */
+	for _, department := range departments {
+		// departments which were never synchronized have no planner id and cannot be matched
+		if department.PlannerID == "" {
+			continue
+		}
+		if scoped, member := claim.InDepartment(department.PlannerID); member && scoped.HasPermission(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func authorizeMembershipChange(c *gin.Context, user dao.User, department dao.Department, permissions ...string) error {
+	/**
+	* Checks that the caller may change the membership of a user in a department: the permissions have to be
+	* held within that department. System admins can only be changed by system admins.
+	* @param user: The user of the membership, loaded with its roles
+	* @param department: The department of the membership
+	* @param permissions: The permissions required for the change, e.g. user:write and role:write
+	**/
+	token, exists := c.Get("retrievedToken")
+	if !exists {
+		slog.Error("Error happened: when get token from context", "error", "token not found")
+		return pkg.NewError(constant.Unauthorized)
+	}
+	claim := token.(*dco.JWTClaim)
+
+	if claim.IsAdmin {
+		return nil
+	}
+	if user.IsSystemAdmin() {
+		slog.Error("Error happened: when check permission", "username", claim.Username, "user", user.Username, "error", "system admins can only be changed by system admins")
+		return pkg.NewError(constant.Forbidden)
+	}
+	for _, permission := range permissions {
+		if !holdsPermissionIn(claim, []dao.Department{department}, permission) {
+			slog.Error("Error happened: when check permission", "username", claim.Username, "department", department.ID, "permission", permission)
+			return pkg.NewError(constant.Forbidden)
+		}
+	}
+	return nil
+}
@@ -5,7 +5,10 @@ import (
 	"api-gateway/app/domain/dao"
 	"api-gateway/app/domain/dco"
 	"api-gateway/app/pkg"
+	"api-gateway/app/planner"
+	"api-gateway/app/policy"
 	"api-gateway/app/repository"
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -26,28 +29,43 @@ type UserService interface {
 
 	AddPermission(c *gin.Context)
 	DeletePermission(c *gin.Context)
+
+	AddRole(c *gin.Context)
+	DeleteRole(c *gin.Context)
+
+	SaveMembership(c *gin.Context)
+	DeleteMembership(c *gin.Context)
+
+	LinkPerson(c *gin.Context)
+	UnlinkPerson(c *gin.Context)
 }
 
 type UserServiceImpl struct {
-	UserRepository repository.UserRepository
+	UserRepository       repository.UserRepository
+	RoleRepository       repository.RoleRepository
+	DepartmentRepository repository.DepartmentRepository
+	PasswordPolicy       policy.PasswordPolicy
+	// The linked persons are checked against the planner-backend, nil if it is not configured
+	Planner planner.Client
 }
 
 func (u UserServiceImpl) UpdateUser(c *gin.Context) {
 	/* Method to update user data by id */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update user data by id")
 
 	id := c.Param("userID")
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error when parsing uuid. Error", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var rawRequest dco.UserRequest
 	if err := c.ShouldBindJSON(&rawRequest); err != nil {
 		slog.Error("Error happened: when mapping request", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	request := mapUserRequestToUser(rawRequest)
 
@@ -56,10 +74,33 @@ func (u UserServiceImpl) UpdateUser(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// user:write is checked within the departments of the user, not within the department of the caller
+	claim, err := authorizeUserChange(c, oldData, "user:write")
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+	// a user can only be moved to a department the caller administers as well
+	if request.DepartmentID != oldData.DepartmentID && !claim.IsAdmin {
+		department, err := u.DepartmentRepository.FindDepartmentById(request.DepartmentID)
+		if err != nil {
+			slog.Error("Error happened: when get data from database", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+			return
+		}
+		if !holdsPermissionIn(claim, []dao.Department{department}, "user:write") {
+			slog.Error("Error happened: when check permission", "username", claim.Username, "department", department.ID, "permission", "user:write")
+			pkg.Abort(c, pkg.NewError(constant.Forbidden))
+			return
+		}
 	}
 
 	// Foreign keys
@@ -75,10 +116,12 @@ func (u UserServiceImpl) UpdateUser(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error happened: when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 	data := mapUserToUserResponse(rawData)
 
@@ -87,12 +130,12 @@ func (u UserServiceImpl) UpdateUser(c *gin.Context) {
 
 func (u UserServiceImpl) GetUserByUsername(c *gin.Context) {
 	/* Method to get user data by username */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get user by username")
 
 	username := c.Query("username")
 	if username == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	rawData, err := u.UserRepository.FindUserByUsername(username)
@@ -100,10 +143,12 @@ func (u UserServiceImpl) GetUserByUsername(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 	data := mapUserToUserResponse(rawData)
 
@@ -112,14 +157,14 @@ func (u UserServiceImpl) GetUserByUsername(c *gin.Context) {
 
 func (u UserServiceImpl) GetUserById(c *gin.Context) {
 	/* Method to get user data by id */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get user by id")
 
 	id := c.Param("userID")
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error happened when parsing uuid", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	rawData, err := u.UserRepository.FindUserById(userID)
@@ -127,10 +172,12 @@ func (u UserServiceImpl) GetUserById(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 	data := mapUserToUserResponse(rawData)
 
@@ -138,13 +185,13 @@ func (u UserServiceImpl) GetUserById(c *gin.Context) {
 }
 
 func (u UserServiceImpl) AddUser(c *gin.Context) {
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add data user")
 
 	var rawRequest dco.UserRequest
 	if err := c.ShouldBindJSON(&rawRequest); err != nil {
 		slog.Error("Error happened: when mapping request", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	request := mapUserRequestToUser(rawRequest)
 
@@ -152,20 +199,34 @@ func (u UserServiceImpl) AddUser(c *gin.Context) {
 	_, err := u.UserRepository.FindUserByUsername(request.Username)
 	switch err {
 	case nil:
-		pkg.PanicException(constant.Conflict)
+		pkg.Abort(c, pkg.NewError(constant.Conflict))
+		return
 	case gorm.ErrRecordNotFound:
 		break
 	default:
 		slog.Error("Error happened: when get data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
-	// Hash password
-	if hash, err := bcrypt.GenerateFromPassword([]byte(rawRequest.Password), 15); err != nil {
-		slog.Error("Error happened: when hashing password", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if rawRequest.ServiceAccount {
+		// service accounts cannot log in, their requests are authenticated with API keys
+		request.AuthProvider = dao.AuthProviderServiceAccount
+		request.Password = ""
 	} else {
-		request.Password = string(hash)
+		if err := checkPasswordPolicy(u.PasswordPolicy, "password", rawRequest.Password); err != nil {
+			pkg.Abort(c, err)
+			return
+		}
+
+		// Hash password
+		if hash, err := bcrypt.GenerateFromPassword([]byte(rawRequest.Password), 15); err != nil {
+			slog.Error("Error happened: when hashing password", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		} else {
+			request.Password = string(hash)
+		}
 	}
 
 	rawData, err := u.UserRepository.Save(&request)
@@ -174,7 +235,8 @@ func (u UserServiceImpl) AddUser(c *gin.Context) {
 		break
 	default:
 		slog.Error("Error happened: when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 	data := mapUserToUserResponse(rawData)
 
@@ -183,13 +245,13 @@ func (u UserServiceImpl) AddUser(c *gin.Context) {
 
 func (u UserServiceImpl) GetAllUsers(c *gin.Context) {
 	/* Method to get all user data */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute get all data user")
 
 	rawData, err := u.UserRepository.FindAllUsers()
 	if err != nil {
 		slog.Error("Error happened: when find all user data", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 	data := mapUserListToUserResponseList(rawData)
 
@@ -197,14 +259,31 @@ func (u UserServiceImpl) GetAllUsers(c *gin.Context) {
 }
 
 func (u UserServiceImpl) DeleteUser(c *gin.Context) {
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute delete data user by id")
 
 	id := c.Param("userID")
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error happened: when parsing string to int", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	user, err := u.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+	if _, err := authorizeUserChange(c, user, "user:write"); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	err = u.UserRepository.DeleteUser(userID)
@@ -212,10 +291,12 @@ func (u UserServiceImpl) DeleteUser(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error happened: when delete data user from DB", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -223,21 +304,22 @@ func (u UserServiceImpl) DeleteUser(c *gin.Context) {
 
 func (u UserServiceImpl) AddPermission(c *gin.Context) {
 	/* Method to add permission to user */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add permission to user")
 
 	id := c.Param("userID")
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error happened: when parsing uuid", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	pId := c.Param("permissionID")
 	permissionID, err := uuid.Parse(pId)
 	if err != nil {
 		slog.Error("Error happened: when parsing uuid", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	err = u.UserRepository.AddPermissionToUser(userID, permissionID)
@@ -245,10 +327,12 @@ func (u UserServiceImpl) AddPermission(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	default:
 		slog.Error("Error happened: when add permission to user", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -256,21 +340,22 @@ func (u UserServiceImpl) AddPermission(c *gin.Context) {
 
 func (u UserServiceImpl) DeletePermission(c *gin.Context) {
 	/* Method to delete permission to user */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete permission to user")
 
 	id := c.Param("userID")
 	userID, err := uuid.Parse(id)
 	if err != nil {
 		slog.Error("Error happened: when parsing uuid", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	pId := c.Param("permissionID")
 	permissionID, err := uuid.Parse(pId)
 	if err != nil {
 		slog.Error("Error happened: when parsing uuid", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	err = u.UserRepository.DeletePermissionFromUser(userID, permissionID)
@@ -278,15 +363,395 @@ func (u UserServiceImpl) DeletePermission(c *gin.Context) {
 	case nil:
 		break
 	case gorm.ErrRecordNotFound:
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	default:
 		slog.Error("Error happened: when delete permission to user", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
 }
 
+func (u UserServiceImpl) AddRole(c *gin.Context) {
+	/* Method to assign a role to user, the role is limited to a department if ?department_id= is set */
+	slog.Info("start to execute program add role to user")
+
+	userID, roleID, departmentID, err := parseUserRoleParams(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	role, err := u.RoleRepository.FindRoleById(roleID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// system admins are not bound to a department
+	if role.Name == dao.RoleSystemAdmin && departmentID != nil {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	user, err := u.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	for _, userRole := range user.Roles {
+		if userRole.RoleID == roleID && equalDepartment(userRole.DepartmentID, departmentID) {
+			pkg.Abort(c, pkg.NewError(constant.Conflict))
+			return
+		}
+	}
+
+	err = u.UserRepository.AddRoleToUser(&dao.UserRole{
+		UserID:       userID,
+		RoleID:       roleID,
+		DepartmentID: departmentID,
+	})
+	if err != nil {
+		slog.Error("Error happened: when add role to user", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (u UserServiceImpl) DeleteRole(c *gin.Context) {
+	/* Method to remove a role from user, ?department_id= selects a department specific assignment */
+	slog.Info("start to execute program delete role from user")
+
+	userID, roleID, departmentID, err := parseUserRoleParams(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	err = u.UserRepository.DeleteRoleFromUser(userID, roleID, departmentID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when delete role from user", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (u UserServiceImpl) SaveMembership(c *gin.Context) {
+	/* Method to add a user to a department or to change the role of the membership */
+	slog.Info("start to execute program save membership of user")
+
+	userID, departmentID, err := parseMembershipParams(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	var request dco.MembershipRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request from FE. Error", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	if request.RoleID != nil {
+		role, err := u.RoleRepository.FindRoleById(*request.RoleID)
+		switch err {
+		case nil:
+			break
+		case gorm.ErrRecordNotFound:
+			pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+			return
+		default:
+			slog.Error("Error happened: when get data from database", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
+
+		// system admins are not bound to a department
+		if role.Name == dao.RoleSystemAdmin {
+			pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+			return
+		}
+	}
+
+	department, err := u.DepartmentRepository.FindDepartmentById(departmentID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	user, err := u.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// the caller has to administer the department, a role can only be granted with role:write in it
+	permissions := []string{"user:write"}
+	if request.RoleID != nil {
+		permissions = append(permissions, "role:write")
+	}
+	if err := authorizeMembershipChange(c, user, department, permissions...); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	err = u.UserRepository.SaveMembership(&dao.DepartmentMembership{
+		UserID:       userID,
+		DepartmentID: departmentID,
+		RoleID:       request.RoleID,
+	})
+	if err != nil {
+		slog.Error("Error happened: when save membership of user", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (u UserServiceImpl) DeleteMembership(c *gin.Context) {
+	/* Method to remove a user from a department, the department of the user is changed with an update of the user */
+	slog.Info("start to execute program delete membership of user")
+
+	userID, departmentID, err := parseMembershipParams(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	department, err := u.DepartmentRepository.FindDepartmentById(departmentID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	user, err := u.UserRepository.FindUserById(userID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// only the admins of the department can remove users from it
+	if err := authorizeMembershipChange(c, user, department, "user:write"); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	err = u.UserRepository.DeleteMembership(userID, departmentID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when delete membership of user", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (u UserServiceImpl) LinkPerson(c *gin.Context) {
+	/* Method to link a user to a person of the planner-backend, a person can be linked to one user only */
+	slog.Info("start to execute program link person to user")
+
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	var request dco.PersonLinkRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error happened: when mapping request from FE. Error", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	linked, err := u.UserRepository.FindUserByPersonID(request.PersonID)
+	switch err {
+	case nil:
+		if linked.ID != userID {
+			pkg.Abort(c, pkg.NewError(constant.Conflict))
+			return
+		}
+	case gorm.ErrRecordNotFound:
+		break
+	default:
+		slog.Error("Error happened: when get data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	// without the planner-backend the person cannot be checked, the link is stored nevertheless
+	if u.Planner != nil {
+		_, err := u.Planner.GetPerson(request.PersonID)
+		switch {
+		case err == nil:
+			break
+		case errors.Is(err, planner.ErrNotFound):
+			pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+			return
+		default:
+			slog.Error("Error happened: when get person from planner-backend", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.UnknownError))
+			return
+		}
+	}
+
+	err = u.UserRepository.SetPersonID(userID, &request.PersonID)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when link person to user", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (u UserServiceImpl) UnlinkPerson(c *gin.Context) {
+	/* Method to remove the link between a user and a person of the planner-backend */
+	slog.Info("start to execute program unlink person from user")
+
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	err = u.UserRepository.SetPersonID(userID, nil)
+	switch err {
+	case nil:
+		break
+	case gorm.ErrRecordNotFound:
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
+	default:
+		slog.Error("Error happened: when unlink person from user", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func parseMembershipParams(c *gin.Context) (uuid.UUID, uuid.UUID, error) {
+	/* Helper to parse the user and the department of a membership */
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		return uuid.Nil, uuid.Nil, pkg.NewError(constant.InvalidRequest)
+	}
+
+	departmentID, err := uuid.Parse(c.Param("departmentID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		return uuid.Nil, uuid.Nil, pkg.NewError(constant.InvalidRequest)
+	}
+	return userID, departmentID, nil
+}
+
+func parseUserRoleParams(c *gin.Context) (uuid.UUID, uuid.UUID, *uuid.UUID, error) {
+	/* Helper to parse the user, the role and the optional department of a role assignment */
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		return uuid.Nil, uuid.Nil, nil, pkg.NewError(constant.InvalidRequest)
+	}
+
+	roleID, err := uuid.Parse(c.Param("roleID"))
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		return uuid.Nil, uuid.Nil, nil, pkg.NewError(constant.InvalidRequest)
+	}
+
+	department := c.Query("department_id")
+	if department == "" {
+		return userID, roleID, nil, nil
+	}
+
+	departmentID, err := uuid.Parse(department)
+	if err != nil {
+		slog.Error("Error happened: when parsing uuid", "error", err)
+		return uuid.Nil, uuid.Nil, nil, pkg.NewError(constant.InvalidRequest)
+	}
+	return userID, roleID, &departmentID, nil
+}
+
+func equalDepartment(a *uuid.UUID, b *uuid.UUID) bool {
+	/* Helper to compare optional departments */
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
 var userServiceSet = wire.NewSet(
 	wire.Struct(new(UserServiceImpl), "*"),
 	wire.Bind(new(UserService), new(*UserServiceImpl)),
@@ -303,9 +768,12 @@ func mapUserToUserResponse(user dao.User) dco.UserResponse {
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
 		},
-		Username: user.Username,
-		Email:    user.Email,
-		IsAdmin:  user.IsAdmin,
+		Username:    user.Username,
+		Email:       user.Email,
+		IsAdmin:     user.IsSystemAdmin(),
+		Roles:       mapUserRoleListToUserRoleResponseList(user.Roles),
+		Memberships: mapUserToMembershipResponseList(user),
+		PersonID:    user.PersonID,
 		Department: dco.DepartmentResponse{
 			BaseModel: dco.BaseModel{
 				ID:        user.Department.BaseModel.ID,
@@ -314,7 +782,49 @@ func mapUserToUserResponse(user dao.User) dco.UserResponse {
 			},
 			Name: user.Department.Name,
 		},
+		MustChangePassword: user.MustChangePassword,
+		TwoFactorEnabled:   user.HasTwoFactor(),
+		ServiceAccount:     user.IsServiceAccount(),
+	}
+}
+
+func mapUserRoleListToUserRoleResponseList(roles []dao.UserRole) []dco.UserRoleResponse {
+	/* mapUserRoleListToUserRoleResponseList is a function to map the role assignments of a user
+	 * @param roles is []dao.UserRole
+	 * @return []dco.UserRoleResponse
+	 */
+	result := []dco.UserRoleResponse{}
+	for _, role := range roles {
+		result = append(result, dco.UserRoleResponse{
+			Name:         role.Role.Name,
+			RoleID:       role.RoleID,
+			DepartmentID: role.DepartmentID,
+		})
+	}
+	return result
+}
+
+func mapUserToMembershipResponseList(user dao.User) []dco.DepartmentMembershipResponse {
+	/* mapUserToMembershipResponseList is a function to map the departments of a user with the roles and permissions within them
+	 * @param user is dao.User
+	 * @return []dco.DepartmentMembershipResponse
+	 */
+	result := []dco.DepartmentMembershipResponse{}
+	for _, department := range user.Departments() {
+		var role *string
+		if membershipRole := user.MembershipRole(department.ID); membershipRole != nil {
+			role = &membershipRole.Name
+		}
+		result = append(result, dco.DepartmentMembershipResponse{
+			DepartmentID:   department.ID,
+			DepartmentName: department.Name,
+			PlannerID:      department.PlannerID,
+			Role:           role,
+			Roles:          user.RoleNamesIn(department.ID),
+			Permissions:    user.PermissionNamesIn(department.ID),
+		})
 	}
+	return result
 }
 
 func mapUserListToUserResponseList(users []dao.User) []dco.UserResponse {
@@ -339,6 +849,5 @@ func mapUserRequestToUser(req dco.UserRequest) dao.User {
 		Email:        req.Email,
 		Password:     req.Password,
 		DepartmentID: req.DepartmentID,
-		IsAdmin:      req.IsAdmin,
 	}
 }
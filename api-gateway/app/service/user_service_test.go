@@ -5,6 +5,7 @@ import (
 	"api-gateway/app/domain/dco"
 	"api-gateway/app/domain/dto"
 	"api-gateway/app/mock"
+	"api-gateway/app/planner"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -21,8 +22,29 @@ func TestUpdateUser(t *testing.T) {
 	/* Test Update User */
 	// Create Mock Repo
 	userRepoMock := mock.NewUserRepositoryMock()
+	departmentRepoMock := mock.NewDepartmentRepositoryMock()
 	userService := UserServiceImpl{
-		UserRepository: &userRepoMock,
+		UserRepository:       &userRepoMock,
+		DepartmentRepository: &departmentRepoMock,
+	}
+
+	departmentA := dao.Department{
+		BaseModel: dao.BaseModel{ID: uuid.MustParse("00000000-0000-0000-0000-00000000000a")},
+		PlannerID: "planner-a",
+	}
+	departmentB := dao.Department{
+		BaseModel: dao.BaseModel{ID: uuid.MustParse("00000000-0000-0000-0000-00000000000b")},
+		PlannerID: "planner-b",
+	}
+	// administers department A only
+	departmentAdmin := &dco.JWTClaim{
+		Username:            "department-admin",
+		PlannerDepartmentID: departmentB.PlannerID,
+		Permissions:         []string{"user:write"},
+		Memberships: []dco.DepartmentMembershipClaim{
+			{PlannerDepartmentID: departmentA.PlannerID, Permissions: []string{"user:write"}},
+			{PlannerDepartmentID: departmentB.PlannerID},
+		},
 	}
 
 	testSteps := []ServiceTestPUT{
@@ -90,13 +112,12 @@ func TestUpdateUser(t *testing.T) {
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			saveValue: dao.User{
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      true,
+				Roles:        mock.SystemAdminRoles,
 			},
 			findError:          nil,
 			saveError:          nil,
@@ -118,13 +139,11 @@ func TestUpdateUser(t *testing.T) {
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			saveValue: dao.User{
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			findError:          nil,
 			saveError:          nil,
@@ -145,13 +164,11 @@ func TestUpdateUser(t *testing.T) {
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			saveValue: dao.User{
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			findError:          nil,
 			saveError:          nil,
@@ -173,13 +190,12 @@ func TestUpdateUser(t *testing.T) {
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      true,
+				Roles:        mock.SystemAdminRoles,
 			},
 			saveValue: dao.User{
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			findError:          nil,
 			saveError:          nil,
@@ -225,6 +241,92 @@ func TestUpdateUser(t *testing.T) {
 			saveValue:          dao.User{},
 			expectedStatusCode: http.StatusBadRequest,
 		},
+		{
+			// Test Update User by the admin of the department of the user
+			mockRequestData: map[string]interface{}{
+				"username":      "TEST",
+				"email":         "test@example.com",
+				"password":      "testpassword",
+				"department_id": departmentA.ID.String(),
+			},
+			findValue: dao.User{
+				Username:     "TEST",
+				Email:        "test@example.com",
+				DepartmentID: departmentA.ID,
+				Department:   departmentA,
+			},
+			saveValue: dao.User{
+				Username:     "TEST",
+				Email:        "test@example.com",
+				DepartmentID: departmentA.ID,
+			},
+			claim:              departmentAdmin,
+			expectedStatusCode: http.StatusOK,
+			params: map[string]string{
+				"userID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			// Test Update User of a department the caller is only a member of
+			mockRequestData: map[string]interface{}{
+				"username":      "TEST",
+				"email":         "test@example.com",
+				"password":      "testpassword",
+				"department_id": departmentB.ID.String(),
+			},
+			findValue: dao.User{
+				Username:     "TEST",
+				Email:        "test@example.com",
+				DepartmentID: departmentB.ID,
+				Department:   departmentB,
+			},
+			claim:              departmentAdmin,
+			expectedStatusCode: http.StatusForbidden,
+			params: map[string]string{
+				"userID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			// Test Update System Admin by the admin of the department
+			mockRequestData: map[string]interface{}{
+				"username":      "TEST",
+				"email":         "test@example.com",
+				"password":      "testpassword",
+				"department_id": departmentA.ID.String(),
+			},
+			findValue: dao.User{
+				Username:     "TEST",
+				Email:        "test@example.com",
+				DepartmentID: departmentA.ID,
+				Department:   departmentA,
+				Roles:        mock.SystemAdminRoles,
+			},
+			claim:              departmentAdmin,
+			expectedStatusCode: http.StatusForbidden,
+			params: map[string]string{
+				"userID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
+		{
+			// Test Move User into a department the caller does not administer
+			mockRequestData: map[string]interface{}{
+				"username":      "TEST",
+				"email":         "test@example.com",
+				"password":      "testpassword",
+				"department_id": departmentB.ID.String(),
+			},
+			findValue: dao.User{
+				Username:     "TEST",
+				Email:        "test@example.com",
+				DepartmentID: departmentA.ID,
+				Department:   departmentA,
+			},
+			claim:              departmentAdmin,
+			expectedStatusCode: http.StatusForbidden,
+			params: map[string]string{
+				"userID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
 	}
 
 	for i, testStep := range testSteps {
@@ -232,14 +334,15 @@ func TestUpdateUser(t *testing.T) {
 			// Set mock data
 			userRepoMock.On("FindUserById").Return(testStep.findValue, testStep.findError)
 			userRepoMock.On("Save").Return(testStep.saveValue, testStep.saveError)
+			departmentRepoMock.On("FindDepartmentById").Return(departmentB, nil)
 
 			// get GIN context
 			w := httptest.NewRecorder()
 			c := mock.GetGinTestContext(w, "PUT", testStep.ParamsToGinParams(), testStep.mockRequestData)
+			c.Set("retrievedToken", testStep.Claim())
 
 			// Call function
-			userService.UpdateUser(c)
-
+			serve(c, userService.UpdateUser)
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
 				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
@@ -264,8 +367,8 @@ func TestUpdateUser(t *testing.T) {
 				t.Errorf("Step: %d. Expected email %s, but got %s", i, testStep.saveValue.(dao.User).Email, responseBody.Data.Email)
 			}
 
-			if responseBody.Data.IsAdmin != testStep.saveValue.(dao.User).IsAdmin {
-				t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.saveValue.(dao.User).IsAdmin, responseBody.Data.IsAdmin)
+			if responseBody.Data.IsAdmin != testStep.saveValue.(dao.User).IsSystemAdmin() {
+				t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.saveValue.(dao.User).IsSystemAdmin(), responseBody.Data.IsAdmin)
 			}
 		})
 	}
@@ -302,20 +405,41 @@ func TestDeleteUser(t *testing.T) {
 				"userID": "00000000-0000-0000-0000-000000000001",
 			},
 		},
+		{
+			// Test Delete User of a department the caller does not administer
+			mockValue: dao.User{
+				Username: "testuser",
+				Email:    "test@example.com",
+				Department: dao.Department{
+					PlannerID: "planner-b",
+				},
+			},
+			claim: &dco.JWTClaim{
+				Username: "department-admin",
+				Memberships: []dco.DepartmentMembershipClaim{
+					{PlannerDepartmentID: "planner-a", Permissions: []string{"user:write"}},
+				},
+			},
+			expectedStatusCode: http.StatusForbidden,
+			params: map[string]string{
+				"userID": "00000000-0000-0000-0000-000000000001",
+			},
+		},
 	}
 
 	for i, testStep := range testSteps {
 		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
 			// Prime mock
+			userRepoMock.On("FindUserById").Return(testStep.mockValue, testStep.mockError)
 			userRepoMock.On("DeleteUser").Return(nil, testStep.mockError)
 
 			// get GIN context
 			w := httptest.NewRecorder()
 			c := mock.GetGinTestContext(w, "DELETE", testStep.ParamsToGinParams(), nil)
+			c.Set("retrievedToken", testStep.Claim())
 
 			// Call function
-			userService.DeleteUser(c)
-
+			serve(c, userService.DeleteUser)
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
 				t.Errorf("Expected status code %d, but got %d", testStep.expectedStatusCode, response.StatusCode)
@@ -347,7 +471,7 @@ func TestAddUser(t *testing.T) {
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      true,
+				Roles:        mock.SystemAdminRoles,
 			},
 			findError:          gorm.ErrRecordNotFound,
 			saveError:          nil,
@@ -366,7 +490,6 @@ func TestAddUser(t *testing.T) {
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			findError:          gorm.ErrRecordNotFound,
 			saveError:          nil,
@@ -386,12 +509,43 @@ func TestAddUser(t *testing.T) {
 				Username:     "TEST",
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-				IsAdmin:      false,
 			},
 			findError:          gorm.ErrRecordNotFound,
 			saveError:          nil,
 			expectedStatusCode: http.StatusCreated,
 		},
+		{
+			// Test Add User, service accounts have no password
+			mockRequestData: map[string]interface{}{
+				"username":        "TEST",
+				"email":           "test@example.com",
+				"department_id":   "00000000-0000-0000-0000-000000000001",
+				"service_account": true,
+			},
+			findValue: nil,
+			saveValue: dao.User{
+				Username:     "TEST",
+				Email:        "test@example.com",
+				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+				AuthProvider: dao.AuthProviderServiceAccount,
+			},
+			findError:          gorm.ErrRecordNotFound,
+			saveError:          nil,
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			// Test Add User, invalid request without password
+			mockRequestData: map[string]interface{}{
+				"username":      "TEST",
+				"email":         "test@example.com",
+				"department_id": "00000000-0000-0000-0000-000000000001",
+			},
+			findValue:          nil,
+			saveValue:          nil,
+			findError:          gorm.ErrRecordNotFound,
+			saveError:          nil,
+			expectedStatusCode: http.StatusBadRequest,
+		},
 		{
 			// Test Add User, invalid request email
 			mockRequestData: map[string]interface{}{
@@ -434,8 +588,7 @@ func TestAddUser(t *testing.T) {
 			c := mock.GetGinTestContext(w, "POST", gin.Params{}, testStep.mockRequestData)
 
 			// Call function
-			userService.AddUser(c)
-
+			serve(c, userService.AddUser)
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
 				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
@@ -460,8 +613,8 @@ func TestAddUser(t *testing.T) {
 				t.Errorf("Step: %d. Expected email %s, but got %s", i, testStep.saveValue.(dao.User).Email, responseBody.Data.Email)
 			}
 
-			if responseBody.Data.IsAdmin != testStep.saveValue.(dao.User).IsAdmin {
-				t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.saveValue.(dao.User).IsAdmin, responseBody.Data.IsAdmin)
+			if responseBody.Data.IsAdmin != testStep.saveValue.(dao.User).IsSystemAdmin() {
+				t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.saveValue.(dao.User).IsSystemAdmin(), responseBody.Data.IsAdmin)
 			}
 
 			if responseBody.Data.Department.ID != testStep.saveValue.(dao.User).DepartmentID {
@@ -491,7 +644,7 @@ func TestGetAllUsers(t *testing.T) {
 					Email:        "test@example.com",
 					DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
 					Password:     "asdasd",
-					IsAdmin:      true,
+					Roles:        mock.SystemAdminRoles,
 				},
 				{
 					BaseModel: dao.BaseModel{
@@ -519,7 +672,7 @@ func TestGetAllUsers(t *testing.T) {
 					Username:     "testuser",
 					Email:        "test@example.com",
 					DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-					IsAdmin:      true,
+					Roles:        mock.SystemAdminRoles,
 				},
 				{
 					BaseModel: dao.BaseModel{
@@ -529,7 +682,6 @@ func TestGetAllUsers(t *testing.T) {
 					Email:        "test@example.com",
 					DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
 					Password:     "",
-					IsAdmin:      false,
 				},
 				{
 					BaseModel: dao.BaseModel{
@@ -538,7 +690,6 @@ func TestGetAllUsers(t *testing.T) {
 					Username:     "testuser2",
 					Email:        "test2@example.com",
 					DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
-					IsAdmin:      false,
 				},
 			},
 			mockError:          nil,
@@ -556,8 +707,7 @@ func TestGetAllUsers(t *testing.T) {
 			c := mock.GetGinTestContext(w, "GET", gin.Params{}, nil)
 
 			// Call function
-			userService.GetAllUsers(c)
-
+			serve(c, userService.GetAllUsers)
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
 				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
@@ -582,8 +732,8 @@ func TestGetAllUsers(t *testing.T) {
 					t.Errorf("Step: %d. Expected email %s, but got %s", i, testStep.expectedResponse.([]dao.User)[j].Email, user.Email)
 				}
 
-				if user.IsAdmin != testStep.expectedResponse.([]dao.User)[j].IsAdmin {
-					t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.expectedResponse.([]dao.User)[j].IsAdmin, user.IsAdmin)
+				if user.IsAdmin != testStep.expectedResponse.([]dao.User)[j].IsSystemAdmin() {
+					t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.expectedResponse.([]dao.User)[j].IsSystemAdmin(), user.IsAdmin)
 				}
 
 				if user.Department.ID != testStep.expectedResponse.([]dao.User)[j].DepartmentID {
@@ -613,7 +763,7 @@ func TestGetUser(t *testing.T) {
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
 				Password:     "asdasd",
-				IsAdmin:      true,
+				Roles:        mock.SystemAdminRoles,
 			},
 			expectedResponse: dao.User{
 				BaseModel: dao.BaseModel{
@@ -623,7 +773,7 @@ func TestGetUser(t *testing.T) {
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
 				Password:     "",
-				IsAdmin:      true,
+				Roles:        mock.SystemAdminRoles,
 			},
 			mockError:          nil,
 			expectedStatusCode: http.StatusOK,
@@ -647,7 +797,6 @@ func TestGetUser(t *testing.T) {
 				Email:        "test@example.com",
 				DepartmentID: uuid.MustParse("00000000-0000-0000-0000-000000000001"),
 				Password:     "",
-				IsAdmin:      false,
 			},
 			mockError:          nil,
 			expectedStatusCode: http.StatusOK,
@@ -706,8 +855,7 @@ func TestGetUser(t *testing.T) {
 			}, nil)
 
 			// Call function
-			userService.GetUserById(c)
-
+			serve(c, userService.GetUserById)
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
 				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
@@ -732,8 +880,8 @@ func TestGetUser(t *testing.T) {
 				t.Errorf("Step: %d. Expected email %s, but got %s", i, testStep.expectedResponse.(dao.User).Email, responseBody.Data.Email)
 			}
 
-			if responseBody.Data.IsAdmin != testStep.expectedResponse.(dao.User).IsAdmin {
-				t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.expectedResponse.(dao.User).IsAdmin, responseBody.Data.IsAdmin)
+			if responseBody.Data.IsAdmin != testStep.expectedResponse.(dao.User).IsSystemAdmin() {
+				t.Errorf("Step: %d. Expected is_admin %t, but got %t", i, testStep.expectedResponse.(dao.User).IsSystemAdmin(), responseBody.Data.IsAdmin)
 			}
 
 			if responseBody.Data.Department.ID != testStep.expectedResponse.(dao.User).DepartmentID {
@@ -788,8 +936,7 @@ func TestAddPermissionToUser(t *testing.T) {
 			}, testStep.mockRequestData)
 
 			// Call function
-			userService.AddPermission(c)
-
+			serve(c, userService.AddPermission)
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
 				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
@@ -836,12 +983,506 @@ func TestDeletePermissionFromUser(t *testing.T) {
 			}, nil)
 
 			// Call function
-			userService.DeletePermission(c)
+			serve(c, userService.DeletePermission)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestAddRoleToUser(t *testing.T) {
+	/* Test Add Role */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	roleRepoMock := mock.NewRoleRepositoryMock()
+	userService := UserServiceImpl{
+		UserRepository: &userRepoMock,
+		RoleRepository: &roleRepoMock,
+	}
+
+	roleID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	departmentID := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+
+	type addRoleTest struct {
+		department         string
+		role               dao.Role
+		roleError          error
+		user               dao.User
+		userError          error
+		expectedStatusCode int
+	}
+
+	testSteps := []addRoleTest{
+		{
+			// global assignment
+			role:               dao.Role{Name: dao.RolePlanner},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			// department assignment
+			department:         departmentID.String(),
+			role:               dao.Role{Name: dao.RolePlanner},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			// same role in another department
+			department: departmentID.String(),
+			role:       dao.Role{Name: dao.RolePlanner},
+			user: dao.User{Roles: []dao.UserRole{
+				{RoleID: roleID},
+			}},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			// already assigned
+			department: departmentID.String(),
+			role:       dao.Role{Name: dao.RolePlanner},
+			user: dao.User{Roles: []dao.UserRole{
+				{RoleID: roleID, DepartmentID: &departmentID},
+			}},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			// system admins can not be limited to a department
+			department:         departmentID.String(),
+			role:               dao.Role{Name: dao.RoleSystemAdmin},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			department:         "invalid",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			roleError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			role:               dao.Role{Name: dao.RolePlanner},
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			roleRepoMock.On("FindRoleById").Return(testStep.role, testStep.roleError)
+			userRepoMock.On("FindUserById").Return(testStep.user, testStep.userError)
+			userRepoMock.On("AddRoleToUser").Return(nil, nil)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			builder := mock.NewTestContextBuilder().
+				WithResponseRecorder(w).
+				WithMethod("POST").
+				WithParams(gin.Params{
+					{Key: "userID", Value: "00000000-0000-0000-0000-000000000001"},
+					{Key: "roleID", Value: roleID.String()},
+				})
+			if testStep.department != "" {
+				builder = builder.WithQuery("department_id", testStep.department)
+			}
+			c, _ := builder.Build()
+
+			// Call function
+			serve(c, userService.AddRole)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestDeleteRoleFromUser(t *testing.T) {
+	/* Test Delete Role */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	userService := UserServiceImpl{
+		UserRepository: &userRepoMock,
+	}
+
+	testSteps := []ServiceTestDELETE{
+		{
+			mockError:          nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			mockError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			userRepoMock.On("DeleteRoleFromUser").Return(nil, testStep.mockError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "DELETE", gin.Params{
+				{Key: "userID", Value: "00000000-0000-0000-0000-000000000001"},
+				{Key: "roleID", Value: "00000000-0000-0000-0000-000000000002"},
+			}, nil)
+
+			// Call function
+			serve(c, userService.DeleteRole)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
 
+func TestSaveMembership(t *testing.T) {
+	/* Test Save Membership */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	roleRepoMock := mock.NewRoleRepositoryMock()
+	departmentRepoMock := mock.NewDepartmentRepositoryMock()
+	userService := UserServiceImpl{
+		UserRepository:       &userRepoMock,
+		RoleRepository:       &roleRepoMock,
+		DepartmentRepository: &departmentRepoMock,
+	}
+
+	roleID := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	type saveMembershipTest struct {
+		department      string
+		body            interface{}
+		role            dao.Role
+		roleError       error
+		departmentError error
+		userError       error
+		// the caller, a system admin if not set
+		caller             *dco.JWTClaim
+		expectedStatusCode int
+		expectedSaved      bool
+	}
+
+	// administers the department of the membership, without managing roles
+	departmentAdmin := &dco.JWTClaim{
+		Username:    "department-admin",
+		Memberships: []dco.DepartmentMembershipClaim{{PlannerDepartmentID: "planner-a", Permissions: []string{"user:write"}}},
+	}
+	roleAdmin := &dco.JWTClaim{
+		Username:    "role-admin",
+		Memberships: []dco.DepartmentMembershipClaim{{PlannerDepartmentID: "planner-a", Permissions: []string{"user:write", "role:write"}}},
+	}
+	otherDepartmentAdmin := &dco.JWTClaim{
+		Username:    "other-department-admin",
+		Memberships: []dco.DepartmentMembershipClaim{{PlannerDepartmentID: "planner-b", Permissions: []string{"user:write", "role:write"}}},
+	}
+
+	testSteps := []saveMembershipTest{
+		{
+			// membership with role
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{"role_id": roleID},
+			role:               dao.Role{Name: dao.RolePlanner},
+			expectedStatusCode: http.StatusOK,
+			expectedSaved:      true,
+		},
+		{
+			// membership without role
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{},
+			expectedStatusCode: http.StatusOK,
+			expectedSaved:      true,
+		},
+		{
+			// system admins are not bound to a department
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{"role_id": roleID},
+			role:               dao.Role{Name: dao.RoleSystemAdmin},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{"role_id": roleID},
+			roleError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{},
+			departmentError:    gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{},
+			userError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			department:         "invalid",
+			body:               map[string]interface{}{},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// membership added by the admin of the department
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{},
+			caller:             departmentAdmin,
+			expectedStatusCode: http.StatusOK,
+			expectedSaved:      true,
+		},
+		{
+			// a role needs role:write within the department
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{"role_id": roleID},
+			role:               dao.Role{Name: dao.RolePlanner},
+			caller:             departmentAdmin,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{"role_id": roleID},
+			role:               dao.Role{Name: dao.RolePlanner},
+			caller:             roleAdmin,
+			expectedStatusCode: http.StatusOK,
+			expectedSaved:      true,
+		},
+		{
+			// the caller does not administer the department
+			department:         "00000000-0000-0000-0000-000000000003",
+			body:               map[string]interface{}{},
+			caller:             otherDepartmentAdmin,
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			caller := testStep.caller
+			if caller == nil {
+				caller = &dco.JWTClaim{Username: "admin", IsAdmin: true}
+			}
+
+			// Prime mock
+			userRepoMock.SavedMemberships = nil
+			roleRepoMock.On("FindRoleById").Return(testStep.role, testStep.roleError)
+			departmentRepoMock.On("FindDepartmentById").Return(dao.Department{PlannerID: "planner-a"}, testStep.departmentError)
+			userRepoMock.On("FindUserById").Return(dao.User{}, testStep.userError)
+			userRepoMock.On("SaveMembership").Return(nil, nil)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "PUT", gin.Params{
+				{Key: "userID", Value: "00000000-0000-0000-0000-000000000001"},
+				{Key: "departmentID", Value: testStep.department},
+			}, testStep.body)
+			c.Set("retrievedToken", caller)
+
+			// Call function
+			serve(c, userService.SaveMembership)
 			response := w.Result()
 			if response.StatusCode != testStep.expectedStatusCode {
 				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
 			}
+			if saved := len(userRepoMock.SavedMemberships) == 1; saved != testStep.expectedSaved {
+				t.Errorf("Step: %d. Expected saved %v, got %v", i, testStep.expectedSaved, userRepoMock.SavedMemberships)
+			}
+		})
+	}
+}
+
+func TestDeleteMembership(t *testing.T) {
+	/* Test Delete Membership */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	departmentRepoMock := mock.NewDepartmentRepositoryMock()
+	userService := UserServiceImpl{
+		UserRepository:       &userRepoMock,
+		DepartmentRepository: &departmentRepoMock,
+	}
+
+	testSteps := []ServiceTestDELETE{
+		{
+			mockError:          nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			mockError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			// the caller does not administer the department
+			claim: &dco.JWTClaim{
+				Username:    "department-admin",
+				Memberships: []dco.DepartmentMembershipClaim{{PlannerDepartmentID: "planner-b", Permissions: []string{"user:write"}}},
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			claim: &dco.JWTClaim{
+				Username:    "department-admin",
+				Memberships: []dco.DepartmentMembershipClaim{{PlannerDepartmentID: "planner-a", Permissions: []string{"user:write"}}},
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			departmentRepoMock.On("FindDepartmentById").Return(dao.Department{PlannerID: "planner-a"}, nil)
+			userRepoMock.On("FindUserById").Return(dao.User{Username: "test"}, nil)
+			userRepoMock.On("DeleteMembership").Return(nil, testStep.mockError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "DELETE", gin.Params{
+				{Key: "userID", Value: "00000000-0000-0000-0000-000000000001"},
+				{Key: "departmentID", Value: "00000000-0000-0000-0000-000000000003"},
+			}, nil)
+			c.Set("retrievedToken", testStep.Claim())
+
+			// Call function
+			serve(c, userService.DeleteMembership)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestLinkPerson(t *testing.T) {
+	/* Test Link Person */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	plannerMock := mock.PlannerClientMock{}
+	userService := UserServiceImpl{
+		UserRepository: &userRepoMock,
+		Planner:        &plannerMock,
+	}
+
+	userID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+	type linkPersonTest struct {
+		body               interface{}
+		linked             interface{}
+		linkedError        error
+		plannerError       error
+		setError           error
+		expectedStatusCode int
+		expectedLinked     bool
+	}
+
+	testSteps := []linkPersonTest{
+		{
+			body:               map[string]interface{}{"person_id": "p1"},
+			linkedError:        gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusOK,
+			expectedLinked:     true,
+		},
+		{
+			// linked to the same user already
+			body:               map[string]interface{}{"person_id": "p1"},
+			linked:             dao.User{BaseModel: dao.BaseModel{ID: userID}},
+			expectedStatusCode: http.StatusOK,
+			expectedLinked:     true,
+		},
+		{
+			// linked to another user
+			body:               map[string]interface{}{"person_id": "p1"},
+			linked:             dao.User{BaseModel: dao.BaseModel{ID: uuid.New()}},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			// unknown person
+			body:               map[string]interface{}{"person_id": "p1"},
+			linkedError:        gorm.ErrRecordNotFound,
+			plannerError:       fmt.Errorf("GET /api/v1/planner/person/p1: %w", planner.ErrNotFound),
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// unknown user
+			body:               map[string]interface{}{"person_id": "p1"},
+			linkedError:        gorm.ErrRecordNotFound,
+			setError:           gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedLinked:     true,
+		},
+		{
+			body:               map[string]interface{}{},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			userRepoMock.PersonIDs = nil
+			plannerMock.Error = testStep.plannerError
+			userRepoMock.On("FindUserByPersonID").Return(testStep.linked, testStep.linkedError)
+			userRepoMock.On("SetPersonID").Return(nil, testStep.setError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "PUT", gin.Params{
+				{Key: "userID", Value: userID.String()},
+			}, testStep.body)
+
+			// Call function
+			serve(c, userService.LinkPerson)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+			if linked := len(userRepoMock.PersonIDs) == 1 && *userRepoMock.PersonIDs[0] == "p1"; linked != testStep.expectedLinked {
+				t.Errorf("Step: %d. Expected linked %v, got %v", i, testStep.expectedLinked, userRepoMock.PersonIDs)
+			}
+		})
+	}
+}
+
+func TestUnlinkPerson(t *testing.T) {
+	/* Test Unlink Person */
+	// Create Mock Repo
+	userRepoMock := mock.NewUserRepositoryMock()
+	userService := UserServiceImpl{
+		UserRepository: &userRepoMock,
+	}
+
+	testSteps := []ServiceTestDELETE{
+		{
+			mockError:          nil,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			mockError:          gorm.ErrRecordNotFound,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		t.Run(fmt.Sprintf("Test step %d", i), func(t *testing.T) {
+			// Prime mock
+			userRepoMock.PersonIDs = nil
+			userRepoMock.On("SetPersonID").Return(nil, testStep.mockError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c := mock.GetGinTestContext(w, "DELETE", gin.Params{
+				{Key: "userID", Value: "00000000-0000-0000-0000-000000000001"},
+			}, nil)
+
+			// Call function
+			serve(c, userService.UnlinkPerson)
+			response := w.Result()
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Step: %d. Expected status code %d, but got %d", i, testStep.expectedStatusCode, response.StatusCode)
+			}
+			if len(userRepoMock.PersonIDs) != 1 || userRepoMock.PersonIDs[0] != nil {
+				t.Errorf("Step: %d. Expected the link to be removed, got %v", i, userRepoMock.PersonIDs)
+			}
 		})
 	}
 }
@@ -0,0 +1,40 @@
+/* Here there are functions that are used to synchronize the departments with the planner-backend periodically */
+package app
+
+import (
+	"api-gateway/app/metrics"
+	"api-gateway/config"
+	"log/slog"
+	"time"
+)
+
+func InitializeDepartmentSynchronization(injector *config.Injector) {
+	if injector.Config.PlannerBackendTarget == "" {
+		slog.Info("PLANNER_BACKEND_TARGET is not set, departments are not synchronized")
+		return
+	}
+
+	// every hour by default, GATEWAY_DEPARTMENT_SYNC_INTERVAL
+	interval := injector.Config.DepartmentSyncInterval
+	slog.Info("Initializing department synchronization", "interval", interval)
+
+	// the planner-backend may start after the api-gateway, so the first run does not block the start
+	go func() {
+		reconcile(injector)
+		ticker := time.NewTicker(interval)
+		for range ticker.C {
+			reconcile(injector)
+		}
+	}()
+}
+
+func reconcile(injector *config.Injector) {
+	/* Runs the reconciliation once, its duration is reported to /metrics */
+	slog.Info("Synchronizing departments")
+	start := time.Now()
+	_, err := injector.DepartmentService.Reconcile()
+	metrics.ObserveSyncJob(metrics.JobDepartments, start, err)
+	if err != nil {
+		slog.Error("Error synchronizing departments", "error", err)
+	}
+}
@@ -0,0 +1,122 @@
+/**
+* This package holds the TLS certificate of the gateway.
+* The certificate is read from PEM files, e.g. issued by cert-manager or certbot, and read again
+* when the files change, so a renewed certificate is used without a restart.
+**/
+package tlscert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+type Reloader struct {
+	certFile string
+	keyFile  string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	// the latest modification time of the files of the current certificate
+	modified time.Time
+}
+
+func Load(certFile string, keyFile string) (*Reloader, error) {
+	/**
+	* Loads the certificate and its private key
+	* @param certFile: The certificate chain, the certificate of the gateway first
+	* @param keyFile: The private key of the certificate
+	**/
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	/* Returns the current certificate, used as GetCertificate of the tls.Config */
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *Reloader) Reload() error {
+	/**
+	* Reads the certificate files again.
+	* The current certificate stays in place if the files are invalid, e.g. while they are being replaced.
+	**/
+	modified, err := r.modifiedAt()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.modified = modified
+	r.mu.Unlock()
+
+	slog.Info("Loaded TLS certificate", "cert", r.certFile, "not_after", cert.Leaf.NotAfter)
+	return nil
+}
+
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	/* Reloads the certificate when its files were modified, they are checked every interval until the context is done */
+	slog.Info("Watching TLS certificate", "cert", r.certFile, "key", r.keyFile, "interval", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !r.modifiedSince() {
+					continue
+				}
+				if err := r.Reload(); err != nil {
+					slog.Error("Failed to reload TLS certificate, the current certificate stays in place", "cert", r.certFile, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (r *Reloader) modifiedSince() bool {
+	modified, err := r.modifiedAt()
+	if err != nil {
+		slog.Warn("Failed to check TLS certificate", "cert", r.certFile, "error", err)
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return !modified.Equal(r.modified)
+}
+
+func (r *Reloader) modifiedAt() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,99 @@
+package tlscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeCertificate(t *testing.T, dir string, name string, modified time.Time) (string, string) {
+	/* Helper to write a self-signed certificate for the name */
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error while generating key: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Error while creating certificate: %s", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Error while encoding key: %s", err)
+	}
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+	os.Chtimes(certFile, modified, modified)
+	os.Chtimes(keyFile, modified, modified)
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil || cert == nil {
+		t.Fatalf("Expected a certificate, got %v", err)
+	}
+	return cert.Leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	certFile, keyFile := writeCertificate(t, dir, "first.example.com", now)
+
+	r, err := Load(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("Error while loading certificate: %s", err)
+	}
+	if name := commonName(t, r); name != "first.example.com" {
+		t.Errorf("Expected the first certificate, got %s", name)
+	}
+	if r.modifiedSince() {
+		t.Errorf("Expected the loaded files to be unmodified")
+	}
+
+	// a renewed certificate is picked up
+	writeCertificate(t, dir, "second.example.com", now.Add(time.Minute))
+	if !r.modifiedSince() {
+		t.Errorf("Expected the files to be modified")
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Error while reloading certificate: %s", err)
+	}
+	if name := commonName(t, r); name != "second.example.com" {
+		t.Errorf("Expected the renewed certificate, got %s", name)
+	}
+
+	// an invalid certificate keeps the current one
+	os.WriteFile(certFile, []byte("invalid"), 0o600)
+	if err := r.Reload(); err == nil {
+		t.Errorf("Expected an error for an invalid certificate")
+	}
+	if name := commonName(t, r); name != "second.example.com" {
+		t.Errorf("Expected the current certificate to stay in place, got %s", name)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.crt"), keyFile); err == nil {
+		t.Errorf("Expected an error for a missing certificate")
+	}
+}
@@ -0,0 +1,119 @@
+/**
+* This package implements time-based one-time passwords (RFC 6238) as used by authenticator apps.
+* Codes have 6 digits, are derived with HMAC-SHA1 and change every 30 seconds.
+**/
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Period = 30 * time.Second
+	Digits = 6
+	// Number of periods a code is accepted before and after the current one, to tolerate clock drift
+	Skew = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func GenerateSecret() (string, error) {
+	/* Generates a random 160 bit secret, encoded as base32 for the authenticator app */
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+func Code(secret string, step int64) (string, error) {
+	/**
+	* Computes the code of a time step
+	* @param secret: The base32 encoded secret
+	* @param step: The time step, see Step
+	* @return: The code with leading zeros
+	**/
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("decode secret: %w", err)
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, see RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", Digits, value%1_000_000), nil
+}
+
+func Validate(secret string, code string, t time.Time, lastStep int64) (int64, bool) {
+	/**
+	* Checks a code against the periods around t. Codes of steps up to lastStep are rejected,
+	* so every code can only be used once.
+	* @param secret: The base32 encoded secret
+	* @param code: The code entered by the user
+	* @param t: The current time
+	* @param lastStep: The step of the last accepted code
+	* @return: The step of the code and if the code is valid
+	**/
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(t)
+	for step := current - Skew; step <= current+Skew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := Code(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func ProvisioningURI(issuer string, account string, secret string) string {
+	/**
+	* Builds the otpauth URI shown as QR code to the authenticator app
+	* @param issuer: The name of the application
+	* @param account: The name of the user
+	* @param secret: The base32 encoded secret
+	* @return: The URI
+	**/
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
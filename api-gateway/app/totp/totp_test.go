@@ -0,0 +1,94 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// The SHA1 secret of the test vectors in RFC 6238 appendix B, "12345678901234567890" as base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// the RFC lists 8 digit codes, the last 6 digits are the 6 digit codes
+	testSteps := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range testSteps {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != expected {
+			t.Errorf("Time %d: expected %s, got %s", unix, expected, code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := Step(now)
+
+	testSteps := []struct {
+		code     string
+		lastStep int64
+		valid    bool
+	}{
+		{code: "081804", valid: true},
+		// the code of the previous period is accepted for clock drift
+		{code: mustCode(t, step-1), valid: true},
+		{code: mustCode(t, step+1), valid: true},
+		{code: mustCode(t, step-2), valid: false},
+		// a code can only be used once
+		{code: "081804", lastStep: step, valid: false},
+		{code: "81804", valid: false},
+		{code: "000000", valid: false},
+	}
+
+	for i, testStep := range testSteps {
+		if _, valid := Validate(rfcSecret, testStep.code, now, testStep.lastStep); valid != testStep.valid {
+			t.Errorf("Test Step %d: expected valid %v, got %v", i, testStep.valid, valid)
+		}
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("Expected a 32 character secret, got %s", secret)
+	}
+	if _, err := Code(secret, 1); err != nil {
+		t.Errorf("Expected the secret to be usable, got %v", err)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri, err := url.Parse(ProvisioningURI("Planner", "jdoe", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Planner:jdoe" {
+		t.Errorf("Unexpected URI %s", uri)
+	}
+	if query := uri.Query(); query.Get("secret") != rfcSecret || query.Get("issuer") != "Planner" || query.Get("digits") != "6" {
+		t.Errorf("Unexpected parameters %s", uri.RawQuery)
+	}
+}
+
+func mustCode(t *testing.T, step int64) string {
+	code, err := Code(rfcSecret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
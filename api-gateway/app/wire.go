@@ -14,12 +14,15 @@ import (
 
 var db = wire.NewSet(config.ConnectToDB)
 
+var policies = wire.NewSet(newPasswordPolicy, newLoginPolicy, newMailer, newAuthProviders, newPlannerClient, newRouteTable, config.NewServer, newRateLimiter)
+
 var InjectorSet = wire.NewSet(
 	wire.Struct(new(config.Injector), "*"))
 
-func BuildInjector() (*config.Injector, func(), error) {
+func BuildInjector(cfg config.Config) (*config.Injector, func(), error) {
 	wire.Build(
 		db,
+		policies,
 		repository.RepositorySet,
 		service.ServiceSet,
 		controller.ControllerSet,
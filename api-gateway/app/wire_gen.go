@@ -16,40 +16,132 @@ import (
 
 // Injectors from wire.go:
 
-func BuildInjector() (*config.Injector, func(), error) {
-	gormDB := config.ConnectToDB()
-	systemControllerImpl := &controller.SystemControllerImpl{}
+func BuildInjector(cfg config.Config) (*config.Injector, func(), error) {
+	gormDB := config.ConnectToDB(cfg)
+	table, err := newRouteTable(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	limiter, err := newRateLimiter(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	systemControllerImpl := &controller.SystemControllerImpl{
+		Routes:      table,
+		RateLimiter: limiter,
+	}
 	userRepositoryImpl := repository.UserRepositoryInit(gormDB)
+	permissionRepositoryImpl := repository.PermissionRepositoryInit(gormDB)
+	sessionRepositoryImpl := repository.SessionRepositoryInit(gormDB)
+	loginThrottleRepositoryImpl := repository.LoginThrottleRepositoryInit(gormDB)
+	auditRepositoryImpl := repository.AuditRepositoryInit(gormDB)
+	loginPolicy := newLoginPolicy(cfg)
+	registry, err := newAuthProviders(cfg, userRepositoryImpl)
+	if err != nil {
+		return nil, nil, err
+	}
+	departmentRepositoryImpl := repository.DepartmentRepositoryInit(gormDB)
+	roleRepositoryImpl := repository.RoleRepositoryInit(gormDB)
+	twoFactorRepositoryImpl := repository.TwoFactorRepositoryInit(gormDB)
+	client := newPlannerClient(cfg)
 	authServiceImpl := &service.AuthServiceImpl{
-		UserRepository: userRepositoryImpl,
+		UserRepository:          userRepositoryImpl,
+		PermissionRepository:    permissionRepositoryImpl,
+		SessionRepository:       sessionRepositoryImpl,
+		LoginThrottleRepository: loginThrottleRepositoryImpl,
+		AuditRepository:         auditRepositoryImpl,
+		LoginPolicy:             loginPolicy,
+		AuthProviders:           registry,
+		DepartmentRepository:    departmentRepositoryImpl,
+		RoleRepository:          roleRepositoryImpl,
+		TwoFactorRepository:     twoFactorRepositoryImpl,
+		Planner:                 client,
 	}
+	passwordPolicy := newPasswordPolicy(cfg)
 	userServiceImpl := &service.UserServiceImpl{
-		UserRepository: userRepositoryImpl,
+		UserRepository:       userRepositoryImpl,
+		RoleRepository:       roleRepositoryImpl,
+		DepartmentRepository: departmentRepositoryImpl,
+		PasswordPolicy:       passwordPolicy,
+		Planner:              client,
+	}
+	sessionServiceImpl := &service.SessionServiceImpl{
+		SessionRepository: sessionRepositoryImpl,
+		UserRepository:    userRepositoryImpl,
+	}
+	passwordResetRepositoryImpl := repository.PasswordResetRepositoryInit(gormDB)
+	mailer := newMailer(cfg)
+	passwordServiceImpl := &service.PasswordServiceImpl{
+		UserRepository:          userRepositoryImpl,
+		SessionRepository:       sessionRepositoryImpl,
+		PasswordResetRepository: passwordResetRepositoryImpl,
+		Mailer:                  mailer,
+		PasswordPolicy:          passwordPolicy,
+	}
+	twoFactorServiceImpl := &service.TwoFactorServiceImpl{
+		UserRepository:      userRepositoryImpl,
+		TwoFactorRepository: twoFactorRepositoryImpl,
+		AuditRepository:     auditRepositoryImpl,
+	}
+	apiKeyRepositoryImpl := repository.APIKeyRepositoryInit(gormDB)
+	apiKeyServiceImpl := &service.APIKeyServiceImpl{
+		UserRepository:       userRepositoryImpl,
+		PermissionRepository: permissionRepositoryImpl,
+		APIKeyRepository:     apiKeyRepositoryImpl,
+		AuditRepository:      auditRepositoryImpl,
 	}
 	userControllerImpl := &controller.UserControllerImpl{
-		AuthService: authServiceImpl,
-		UserService: userServiceImpl,
+		AuthService:      authServiceImpl,
+		UserService:      userServiceImpl,
+		SessionService:   sessionServiceImpl,
+		PasswordService:  passwordServiceImpl,
+		TwoFactorService: twoFactorServiceImpl,
+		APIKeyService:    apiKeyServiceImpl,
 	}
-	departmentRepositoryImpl := repository.DepartmentRepositoryInit(gormDB)
 	departmentServiceImpl := &service.DepartmentServiceImpl{
 		DepartmentRepository: departmentRepositoryImpl,
+		Planner:              client,
 	}
 	departmentControllerImpl := &controller.DepartmentControllerImpl{
 		DepartmentService: departmentServiceImpl,
 	}
-	permissionRepositoryImpl := repository.PermissionRepositoryInit(gormDB)
 	permissionServiceImpl := &service.PermissionServiceImpl{
 		PermissionRepository: permissionRepositoryImpl,
 	}
 	permissionControllerImpl := &controller.PermissionControllerImpl{
 		PermissionService: permissionServiceImpl,
 	}
+	roleServiceImpl := &service.RoleServiceImpl{
+		RoleRepository:       roleRepositoryImpl,
+		PermissionRepository: permissionRepositoryImpl,
+	}
+	roleControllerImpl := &controller.RoleControllerImpl{
+		RoleService: roleServiceImpl,
+	}
+	meServiceImpl := &service.MeServiceImpl{
+		UserRepository: userRepositoryImpl,
+		Planner:        client,
+	}
+	meControllerImpl := &controller.MeControllerImpl{
+		MeService: meServiceImpl,
+	}
+	server := config.NewServer(cfg)
 	injector := &config.Injector{
-		DB:             gormDB,
-		SystemCtrl:     systemControllerImpl,
-		UserCtrl:       userControllerImpl,
-		DepartmentCtrl: departmentControllerImpl,
-		PermissionCtrl: permissionControllerImpl,
+		Config:            cfg,
+		DB:                gormDB,
+		SystemCtrl:        systemControllerImpl,
+		UserCtrl:          userControllerImpl,
+		DepartmentCtrl:    departmentControllerImpl,
+		PermissionCtrl:    permissionControllerImpl,
+		RoleCtrl:          roleControllerImpl,
+		MeCtrl:            meControllerImpl,
+		SessionRepository: sessionRepositoryImpl,
+		APIKeyRepository:  apiKeyRepositoryImpl,
+		DepartmentService: departmentServiceImpl,
+		Planner:           client,
+		Routes:            table,
+		RateLimiter:       limiter,
+		Server:            server,
 	}
 	return injector, func() {
 	}, nil
@@ -59,4 +151,6 @@ func BuildInjector() (*config.Injector, func(), error) {
 
 var db = wire.NewSet(config.ConnectToDB)
 
+var policies = wire.NewSet(newPasswordPolicy, newLoginPolicy, newMailer, newAuthProviders, newPlannerClient, newRouteTable, config.NewServer, newRateLimiter)
+
 var InjectorSet = wire.NewSet(wire.Struct(new(config.Injector), "*"))
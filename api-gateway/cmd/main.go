@@ -2,24 +2,71 @@ package main
 
 import (
 	"api-gateway/app"
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/jwtkeys"
 	"api-gateway/app/router"
 	"api-gateway/config"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 )
 
 func main() {
-	port := os.Getenv("GATEWAY_BACKEND_PORT")
-	if port == "" {
-		port = "8081"
+	// `config print` shows the effective config with the secrets redacted
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		cfg, err := config.Load(os.Args[3:])
+		cfg.Print(os.Stdout)
+		exitOnInvalidConfig(err)
+		return
 	}
 
-	config.InitLogger()
+	// every invalid or missing setting is reported at once, before anything is started
+	cfg, err := config.Load(os.Args[1:])
+	exitOnInvalidConfig(err)
 
-	init, _, _ := app.BuildInjector()
+	config.InitLogger(cfg.LogLevel)
+
+	// refuses to start without keys to sign the access tokens
+	dco.JWTKeys = jwtkeys.Load(cfg.JWT.KeysDir, cfg.JWT.SigningKeyID)
+	dco.IdentitySigningKey = []byte(cfg.IdentitySigningKey)
+	dco.PasswordResetURL = cfg.PasswordResetURL
+	dco.PostLoginURL = cfg.PostLoginURL
+	dco.TOTPIssuer = cfg.TOTPIssuer
+
+	init, _, err := app.BuildInjector(cfg)
+	if err != nil {
+		slog.Error("Failed to start the gateway", "error", err)
+		os.Exit(1)
+	}
+	dco.Cookies = init.Server.Cookies
 	router := router.Init(init)
 
 	// run migration
-	config.Migrate(init.DB)
+	config.Migrate(init.DB, cfg.Admin)
+
+	// repair the departments of the planner-backend after failed pushes
+	app.InitializeDepartmentSynchronization(init)
+
+	// the health of the upstreams is shown by /api/v1/ping and opens their circuits, the route file is watched for changes
+	init.Routes.Start(context.Background())
 
-	router.Run(":" + port)
+	// serves HTTPS if GATEWAY_TLS_CERT_FILE and GATEWAY_TLS_KEY_FILE are set
+	if err := init.Server.ListenAndServe(router, cfg.Port); err != nil {
+		slog.Error("Server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+func exitOnInvalidConfig(err error) {
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n  - %s\n", strings.ReplaceAll(err.Error(), "\n", "\n  - "))
+		os.Exit(2)
+	}
 }
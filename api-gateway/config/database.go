@@ -2,18 +2,17 @@ package config
 
 import (
 	"api-gateway/app/domain/dao"
+	"api-gateway/app/repository"
 	"log/slog"
-	"os"
 
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectToDB() *gorm.DB {
-	dsn := os.Getenv("GATEWAY_DB_DSN")
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+func ConnectToDB(c Config) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(c.Database.DSN), &gorm.Config{
 		// Do not use transaction for each writes
 		SkipDefaultTransaction: true,
 
@@ -25,6 +24,12 @@ func ConnectToDB() *gorm.DB {
 		panic(err)
 	}
 
+	// the queries of the repositories are reported to /metrics
+	if err := db.Use(repository.QueryMetrics{}); err != nil {
+		slog.Error("Failed to register the query metrics", "error", err)
+		panic(err)
+	}
+
 	// Ping to database
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -52,9 +57,10 @@ func checkIfValueExists(db *gorm.DB, model interface{}, field string, value inte
 	return result.Error == nil && count > 0
 }
 
-func Migrate(db *gorm.DB) {
+func Migrate(db *gorm.DB, admin Admin) {
 	/**
 	 * Aside from the migrations of the models, we also need to migrate the user and initial database configuration
+	 * The admin user is seeded with the settings of the config
 	 */
 
 	// setup department
@@ -68,12 +74,9 @@ func Migrate(db *gorm.DB) {
 	}
 
 	// create user
-	username := os.Getenv("GATEWAY_ADMIN_USERNAME")
-	if username == "" {
-		username = "admin"
-	}
-	password := os.Getenv("GATEWAY_ADMIN_PASSWORD")
+	password := admin.Password
 	if password == "" {
+		slog.Warn("GATEWAY_ADMIN_PASSWORD is not set, the admin user is seeded with the default password")
 		password = "admin"
 	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -82,24 +85,85 @@ func Migrate(db *gorm.DB) {
 		panic(err)
 	}
 
-	email := os.Getenv("GATEWAY_ADMIN_EMAIL")
-	if email == "" {
-		email = "admin@example.com"
-	}
-
+	// the seeded password is known from the deployment, so it has to be changed at the first login
 	user := dao.User{
-		Username:   username,
-		Password:   string(hash),
-		Email:      email,
-		Department: department,
-		IsAdmin:    true,
+		Username:           admin.Username,
+		Password:           string(hash),
+		Email:              admin.Email,
+		Department:         department,
+		MustChangePassword: true,
 	}
 
+	// setup roles
+	systemAdmin := migrateRoles(db)
+
 	// create instances
 	if !checkIfValueExists(db, user, "username", user.Username) {
 		db.Create(&user)
+		db.Create(&dao.UserRole{UserID: user.ID, RoleID: systemAdmin.ID})
 		slog.Info("Created admin user", "username", user.Username)
 	} else {
 		slog.Info("Admin user already exists", "username", user.Username)
 	}
 }
+
+// Permissions bundled by the default roles. The system-admin role holds every permission.
+var defaultRoles = map[string][]string{
+	dao.RoleViewer:          {},
+	dao.RolePlanner:         {"person:write", "absency:write", "workday:write", "workday:assign"},
+	dao.RoleDepartmentAdmin: {"person:write", "absency:write", "workday:write", "workday:assign", "department:write", "user:write"},
+	dao.RoleSystemAdmin:     {"person:write", "absency:write", "workday:write", "workday:assign", "department:write", "user:write", "role:write", "session:revoke"},
+}
+
+func migrateRoles(db *gorm.DB) dao.Role {
+	/**
+	 * This function creates the default roles and their permissions if they do not exist.
+	 * Existing roles are left untouched, so changes made through the API are kept.
+	 * Users flagged with the former is_admin column are migrated to the system-admin role.
+	 */
+	for name, permissionNames := range defaultRoles {
+		if checkIfValueExists(db, dao.Role{}, "name", name) {
+			continue
+		}
+
+		role := dao.Role{Name: name}
+		for _, permissionName := range permissionNames {
+			permission := dao.Permission{Name: permissionName}
+			db.Where("name = ?", permissionName).FirstOrCreate(&permission)
+			role.Permissions = append(role.Permissions, permission)
+		}
+
+		if err := db.Create(&role).Error; err != nil {
+			slog.Error("Failed to create role", "role", name, "error", err)
+			panic(err)
+		}
+		slog.Info("Created role", "role", name)
+	}
+
+	var systemAdmin dao.Role
+	if err := db.Where("name = ?", dao.RoleSystemAdmin).First(&systemAdmin).Error; err != nil {
+		slog.Error("Failed to find system-admin role", "error", err)
+		panic(err)
+	}
+
+	if db.Migrator().HasColumn(&dao.User{}, "is_admin") {
+		var adminIDs []uuid.UUID
+		if err := db.Model(&dao.User{}).Where("is_admin = ?", true).Pluck("id", &adminIDs).Error; err != nil {
+			slog.Error("Failed to find admin users", "error", err)
+			panic(err)
+		}
+
+		for _, id := range adminIDs {
+			userRole := dao.UserRole{UserID: id, RoleID: systemAdmin.ID}
+			db.Where("user_id = ? AND role_id = ? AND department_id IS NULL", id, systemAdmin.ID).FirstOrCreate(&userRole)
+		}
+
+		if err := db.Migrator().DropColumn(&dao.User{}, "is_admin"); err != nil {
+			slog.Error("Failed to drop is_admin column", "error", err)
+			panic(err)
+		}
+		slog.Info("Migrated admin users to the system-admin role", "users", len(adminIDs))
+	}
+
+	return systemAdmin
+}
@@ -2,14 +2,37 @@ package config
 
 import (
 	"api-gateway/app/controller"
+	"api-gateway/app/planner"
+	"api-gateway/app/proxy"
+	"api-gateway/app/ratelimit"
+	"api-gateway/app/repository"
+	"api-gateway/app/service"
 
 	"gorm.io/gorm"
 )
 
 type Injector struct {
+	Config         Config
 	DB             *gorm.DB
 	SystemCtrl     controller.SystemController
 	UserCtrl       controller.UserController
 	DepartmentCtrl controller.DepartmentController
 	PermissionCtrl controller.PermissionController
+	RoleCtrl       controller.RoleController
+	MeCtrl         controller.MeController
+
+	// The sessions are checked against the revocation list by the auth middlewares
+	SessionRepository repository.SessionRepository
+	// API keys are resolved by the auth middlewares as well
+	APIKeyRepository repository.APIKeyRepository
+	// The departments are reconciled with the planner-backend periodically
+	DepartmentService service.DepartmentService
+	// Finds the departments of the persons changed through the planner API, nil without PLANNER_BACKEND_TARGET
+	Planner planner.Client
+	// Forwards the planner API and further services to their upstreams
+	Routes *proxy.Table
+	// Limits the request rates of the clients
+	RateLimiter *ratelimit.Limiter
+	// TLS, cookies, security headers and trusted proxies of the HTTP server
+	Server Server
 }
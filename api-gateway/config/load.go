@@ -0,0 +1,271 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Secrets are replaced by this value when the config is printed
+const redacted = "[redacted]"
+
+// setting is a field of the config, described by the tags of the struct:
+// json is its key in the config file, env its environment variable and flag its command line flag
+type setting struct {
+	key    string
+	env    string
+	flag   string
+	usage  string
+	secret bool
+	value  reflect.Value
+}
+
+func settings(config *Config) []setting {
+	return collectSettings(reflect.ValueOf(config).Elem(), "")
+}
+
+func collectSettings(v reflect.Value, prefix string) []setting {
+	result := []setting{}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := prefix + strings.Split(field.Tag.Get("json"), ",")[0]
+
+		// sections are nested objects in the config file
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
+			result = append(result, collectSettings(v.Field(i), key+".")...)
+			continue
+		}
+
+		result = append(result, setting{
+			key:    key,
+			env:    field.Tag.Get("env"),
+			flag:   field.Tag.Get("flag"),
+			usage:  field.Tag.Get("usage"),
+			secret: field.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+	return result
+}
+
+func (s setting) set(raw string) error {
+	// optional settings are pointers, which stay nil unless they are set
+	if s.value.Kind() == reflect.Pointer {
+		value := reflect.New(s.value.Type().Elem())
+		if err := (setting{value: value.Elem()}).set(raw); err != nil {
+			return err
+		}
+		s.value.Set(value)
+		return nil
+	}
+
+	switch s.value.Interface().(type) {
+	case string:
+		s.value.SetString(raw)
+	case bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		s.value.SetBool(parsed)
+	case int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		s.value.SetInt(int64(parsed))
+	case time.Duration:
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, expected e.g. 30m", raw)
+		}
+		s.value.SetInt(int64(parsed))
+	case []string:
+		values := []string{}
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		s.value.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s", s.value.Type())
+	}
+	return nil
+}
+
+func (s setting) name() string {
+	// the variables are the usual way to configure the services, so they name the settings in errors
+	if s.env != "" {
+		return s.env
+	}
+	return s.key
+}
+
+func Load(args []string) (Config, error) {
+	/**
+	* Loads the config from its sources, each overriding the former:
+	* the defaults, the JSON file in --config or GATEWAY_CONFIG_FILE, the environment and the flags.
+	* Empty variables count as unset. The config is validated afterwards.
+	* @param args: The command line arguments without the name of the program
+	* @return The config and all invalid or missing settings at once
+	**/
+	config := Default()
+
+	flags := flag.NewFlagSet("api-gateway", flag.ContinueOnError)
+	path := flags.String("config", os.Getenv("GATEWAY_CONFIG_FILE"), "JSON file with the config")
+	production := flags.Bool("prod", false, "shorthand for --mode="+ModeProduction)
+	flagValues := map[string]string{}
+	for _, s := range settings(&config) {
+		if s.flag == "" {
+			continue
+		}
+		name := s.flag
+		flags.Func(name, s.usage, func(value string) error {
+			flagValues[name] = value
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return config, err
+	}
+	if flags.NArg() > 0 {
+		return config, fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+
+	errs := []error{}
+	if *path != "" {
+		errs = append(errs, loadFile(&config, *path)...)
+	}
+
+	for _, s := range settings(&config) {
+		if value := os.Getenv(s.env); s.env != "" && value != "" {
+			if err := s.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.env, err))
+			}
+		}
+	}
+
+	for _, s := range settings(&config) {
+		if value, found := flagValues[s.flag]; s.flag != "" && found {
+			if err := s.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("--%s: %w", s.flag, err))
+			}
+		}
+	}
+	if *production {
+		config.Mode = ModeProduction
+	}
+
+	errs = append(errs, config.Validate()...)
+	return config, errors.Join(errs...)
+}
+
+func loadFile(config *Config, path string) []error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []error{fmt.Errorf("config file: %w", err)}
+	}
+
+	var values map[string]any
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return []error{fmt.Errorf("config file %s: %w", path, err)}
+	}
+
+	byKey := map[string]setting{}
+	for _, s := range settings(config) {
+		byKey[s.key] = s
+	}
+	return applyFile(byKey, values, "")
+}
+
+func applyFile(byKey map[string]setting, values map[string]any, prefix string) []error {
+	errs := []error{}
+	for key, value := range values {
+		key = prefix + key
+
+		if section, ok := value.(map[string]any); ok {
+			errs = append(errs, applyFile(byKey, section, key+".")...)
+			continue
+		}
+
+		s, found := byKey[key]
+		if !found {
+			errs = append(errs, fmt.Errorf("config file: unknown setting %s", key))
+			continue
+		}
+
+		raw := ""
+		switch value := value.(type) {
+		case nil:
+			// null keeps the default, e.g. of the optional settings printed by config print
+			continue
+		case string:
+			raw = value
+		case json.Number:
+			raw = value.String()
+		case bool:
+			raw = strconv.FormatBool(value)
+		case []any:
+			items := []string{}
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+			raw = strings.Join(items, ",")
+		default:
+			errs = append(errs, fmt.Errorf("config file: invalid value of %s", key))
+			continue
+		}
+
+		if err := s.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("config file: %s: %w", key, err))
+		}
+	}
+	return errs
+}
+
+func (c Config) Print(w io.Writer) error {
+	/* Writes the config as JSON in the format of the config file, secrets are redacted */
+	output := map[string]any{}
+	for _, s := range settings(&c) {
+		section := output
+		parts := strings.Split(s.key, ".")
+		for _, part := range parts[:len(parts)-1] {
+			if _, found := section[part]; !found {
+				section[part] = map[string]any{}
+			}
+			section = section[part].(map[string]any)
+		}
+
+		var value any = s.value.Interface()
+		if s.value.Kind() == reflect.Pointer {
+			value = nil
+			if !s.value.IsNil() {
+				value = s.value.Elem().Interface()
+			}
+		}
+		if duration, ok := value.(time.Duration); ok {
+			value = duration.String()
+		}
+		if s.secret && !s.value.IsZero() {
+			value = redacted
+		}
+		section[parts[len(parts)-1]] = value
+	}
+
+	encoded, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(encoded))
+	return err
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"api-gateway/app/domain/dco"
+	"api-gateway/app/tlscert"
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Server holds the settings of the HTTP server of the gateway
+type Server struct {
+	// Certificate and private key to terminate TLS, plain HTTP is served without them
+	TLSCertFile string
+	TLSKeyFile  string
+	// The certificate files are checked for changes in this interval, 0 disables the reload
+	TLSReloadInterval time.Duration
+	// Addresses or CIDRs of the proxies whose X-Forwarded-For header reveals the client IP
+	TrustedProxies []string
+	// Max age of the Strict-Transport-Security header, 0 disables the header
+	HSTSMaxAge time.Duration
+	Cookies    dco.CookieConfig
+}
+
+func NewServer(c Config) Server {
+	/* Resolves the settings of the server, they were checked by Validate */
+	server := Server{
+		TLSCertFile:       c.TLS.CertFile,
+		TLSKeyFile:        c.TLS.KeyFile,
+		TLSReloadInterval: c.TLS.ReloadInterval,
+		TrustedProxies:    c.TrustedProxies,
+		HSTSMaxAge:        c.HSTS(),
+		Cookies: dco.CookieConfig{
+			Domain:   c.Cookies.Domain,
+			Secure:   c.SecureCookies(),
+			SameSite: http.SameSiteLaxMode,
+		},
+	}
+
+	switch strings.ToLower(c.Cookies.SameSite) {
+	case "strict":
+		server.Cookies.SameSite = http.SameSiteStrictMode
+	case "none":
+		server.Cookies.SameSite = http.SameSiteNoneMode
+	}
+
+	return server
+}
+
+func (s Server) TLS() bool {
+	return s.TLSCertFile != ""
+}
+
+func (s Server) ListenAndServe(handler http.Handler, port string) error {
+	/**
+	* Serves the handler on the port, over HTTPS if a certificate is configured.
+	* The certificate is reloaded when its files change if GATEWAY_TLS_RELOAD_INTERVAL is set.
+	**/
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if !s.TLS() {
+		slog.Info("Serving HTTP", "port", port)
+		return server.ListenAndServe()
+	}
+
+	certificate, err := tlscert.Load(s.TLSCertFile, s.TLSKeyFile)
+	if err != nil {
+		slog.Error("Failed to load TLS certificate", "cert", s.TLSCertFile, "key", s.TLSKeyFile, "error", err)
+		return err
+	}
+	if s.TLSReloadInterval > 0 {
+		certificate.Watch(context.Background(), s.TLSReloadInterval)
+	}
+
+	server.TLSConfig = &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certificate.GetCertificate,
+	}
+
+	slog.Info("Serving HTTPS", "port", port)
+	return server.ListenAndServeTLS("", "")
+}
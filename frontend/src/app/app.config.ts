@@ -8,12 +8,14 @@ import { NotificationService } from '@core/services/notification.service';
 import { routes } from './app.routes';
 import { MAT_DATE_LOCALE, provideNativeDateAdapter } from '@angular/material/core';
 import { timeoutInterceptor } from './core/interceptors/timeout-interceptor';
+import { refreshInterceptor } from './core/interceptors/refresh-interceptor';
+import { csrfInterceptor } from './core/interceptors/csrf-interceptor';
 
 export const appConfig: ApplicationConfig = {
   providers: [
     provideRouter(routes),
     provideAnimations(),
-    provideHttpClient(withInterceptors([httpErrorInterceptor, timeoutInterceptor])),
+    provideHttpClient(withInterceptors([httpErrorInterceptor, refreshInterceptor, timeoutInterceptor, csrfInterceptor])),
     importProvidersFrom(MatSnackBarModule),
     NotificationService,
     provideNativeDateAdapter(),
@@ -0,0 +1,27 @@
+import { HttpEvent, HttpHandlerFn, HttpInterceptorFn, HttpRequest } from '@angular/common/http';
+import { Observable } from 'rxjs';
+
+// The gateway rejects mutating requests of a session without its CSRF token.
+// The token is issued at login as cookie and repeated in a header, which other sites cannot set.
+const CSRF_COOKIE = 'csrf_token';
+const CSRF_HEADER = 'X-CSRF-Token';
+const SAFE_METHODS = ['GET', 'HEAD', 'OPTIONS'];
+
+function readCookie(name: string): string | null {
+  const cookie = document.cookie.split('; ').find((entry) => entry.startsWith(`${name}=`));
+  return cookie ? decodeURIComponent(cookie.substring(name.length + 1)) : null;
+}
+
+export const csrfInterceptor: HttpInterceptorFn = (req: HttpRequest<unknown>, next: HttpHandlerFn): Observable<HttpEvent<unknown>> => {
+  if (SAFE_METHODS.includes(req.method)) {
+    return next(req);
+  }
+
+  // read on every request, so requests retried after a login carry the current token
+  const token = readCookie(CSRF_COOKIE);
+  if (!token || req.headers.has(CSRF_HEADER)) {
+    return next(req);
+  }
+
+  return next(req.clone({ headers: req.headers.set(CSRF_HEADER, token) }));
+};
@@ -0,0 +1,38 @@
+import { HttpClient, HttpErrorResponse, HttpEvent, HttpHandlerFn, HttpInterceptorFn, HttpRequest } from '@angular/common/http';
+import { inject } from '@angular/core';
+import { Observable, catchError, finalize, shareReplay, switchMap, throwError } from 'rxjs';
+import { constants } from '../constants/constants';
+
+// Access tokens are short-lived. A request failing with 401 refreshes the session once and is retried.
+// Concurrent requests share a single refresh, since every refresh rotates the refresh token.
+let refreshInFlight: Observable<unknown> | null = null;
+
+const SKIPPED_URLS = ['login', 'logout', 'refresh'].map((path) => `${constants.APIS.AUTH}/${path}`);
+
+export const refreshInterceptor: HttpInterceptorFn = (req: HttpRequest<unknown>, next: HttpHandlerFn): Observable<HttpEvent<unknown>> => {
+  const http = inject(HttpClient);
+
+  if (SKIPPED_URLS.includes(req.url)) {
+    return next(req);
+  }
+
+  return next(req).pipe(
+    catchError((error: HttpErrorResponse) => {
+      if (error.status !== 401) {
+        return throwError(() => error);
+      }
+
+      if (!refreshInFlight) {
+        refreshInFlight = http.post(`${constants.APIS.AUTH}/refresh`, {}, { withCredentials: true }).pipe(
+          finalize(() => (refreshInFlight = null)),
+          shareReplay(1),
+        );
+      }
+
+      return refreshInFlight.pipe(
+        catchError(() => throwError(() => error)),
+        switchMap(() => next(req)),
+      );
+    }),
+  );
+};
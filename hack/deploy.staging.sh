@@ -8,6 +8,11 @@ kubectl delete secret secrets -n $NAMESPACE --kubeconfig=$KUBECONFIG
 kubectl create secret generic secrets -n $NAMESPACE \
     --from-env-file=.staging.env \
     --kubeconfig=$KUBECONFIG
+# the keys to sign the access tokens, <kid>.pem files in $JWT_KEYS_DIR
+kubectl delete secret jwt-keys -n $NAMESPACE --kubeconfig=$KUBECONFIG
+kubectl create secret generic jwt-keys -n $NAMESPACE \
+    --from-file=$JWT_KEYS_DIR \
+    --kubeconfig=$KUBECONFIG
 kubectl delete secret regcred -n $NAMESPACE --kubeconfig=$KUBECONFIG
 kubectl create secret docker-registry -n $NAMESPACE \
     regcred \
@@ -1,5 +1,7 @@
 package constant
 
+import "net/http"
+
 type ResponseStatus int
 type Headers int
 type General int
@@ -17,6 +19,7 @@ const (
 	DataNotFound
 	Conflict
 	UnknownError
+	Forbidden
 )
 
 func (r ResponseStatus) GetResponseStatus() string {
@@ -27,6 +30,7 @@ func (r ResponseStatus) GetResponseStatus() string {
 		"Data Not Found",
 		"Conflict",
 		"Unknown Error",
+		"Forbidden",
 	}[r-1]
 }
 
@@ -38,5 +42,31 @@ func (r ResponseStatus) GetResponseMessage() string {
 		"Data Not Found: Data not found",
 		"Conflict: Data already exist",
 		"Unknown Error: Unknown error",
+		"Forbidden: Missing permission",
+	}[r-1]
+}
+
+func (r ResponseStatus) GetHTTPStatus() int {
+	return [...]int{
+		http.StatusOK,
+		http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusNotFound,
+		http.StatusConflict,
+		http.StatusInternalServerError,
+		http.StatusForbidden,
+	}[r-1]
+}
+
+// GetErrorCode returns the machine-readable code of the status, which clients can rely on
+func (r ResponseStatus) GetErrorCode() string {
+	return [...]string{
+		"success",
+		"invalid_request",
+		"unauthorized",
+		"not_found",
+		"conflict",
+		"unknown_error",
+		"forbidden",
 	}[r-1]
 }
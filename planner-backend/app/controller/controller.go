@@ -12,4 +12,5 @@ var ControllerSet = wire.NewSet(
 	personRelControllerSet,
 	workdayControllerSet,
 	absenceControllerSet,
+	leaveRequestControllerSet,
 )
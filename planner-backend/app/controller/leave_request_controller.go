@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"planner-backend/app/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+)
+
+type LeaveRequestController interface {
+	Create(ctx *gin.Context)
+	GetAllForPerson(ctx *gin.Context)
+	GetAllForDepartment(ctx *gin.Context)
+	Approve(ctx *gin.Context)
+	Reject(ctx *gin.Context)
+}
+
+type LeaveRequestControllerImpl struct {
+	LeaveRequestService service.LeaveRequestService
+}
+
+func (u LeaveRequestControllerImpl) Create(ctx *gin.Context) {
+	u.LeaveRequestService.CreateLeaveRequest(ctx)
+}
+
+func (u LeaveRequestControllerImpl) GetAllForPerson(ctx *gin.Context) {
+	u.LeaveRequestService.GetLeaveRequestsForPerson(ctx)
+}
+
+func (u LeaveRequestControllerImpl) GetAllForDepartment(ctx *gin.Context) {
+	u.LeaveRequestService.GetLeaveRequestsForDepartment(ctx)
+}
+
+func (u LeaveRequestControllerImpl) Approve(ctx *gin.Context) {
+	u.LeaveRequestService.ApproveLeaveRequest(ctx)
+}
+
+func (u LeaveRequestControllerImpl) Reject(ctx *gin.Context) {
+	u.LeaveRequestService.RejectLeaveRequest(ctx)
+}
+
+var leaveRequestControllerSet = wire.NewSet(
+	wire.Struct(new(LeaveRequestControllerImpl), "*"),
+	wire.Bind(new(LeaveRequestController), new(*LeaveRequestControllerImpl)),
+)
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"planner-backend/app/constant"
+	"planner-backend/app/domain/dto"
 	"planner-backend/app/pkg"
 	"planner-backend/app/service"
 
@@ -51,7 +52,11 @@ func (u PersonRelControllerImpl) FindAbsencyForPerson(ctx *gin.Context) {
 		return
 	}
 
-	pkg.SendResponse(ctx, constant.InvalidRequest, pkg.Null())
+	pkg.Abort(ctx, pkg.NewError(constant.InvalidRequest).WithCode("validation_failed").WithDetails(dto.ErrorDetail{
+		Field:   "date",
+		Code:    "required",
+		Message: "is required unless start_date and end_date are set",
+	}))
 }
 
 func (u PersonRelControllerImpl) AddDepartment(ctx *gin.Context) {
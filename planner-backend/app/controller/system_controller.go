@@ -16,8 +16,6 @@ type SystemController interface {
 type SystemControllerImpl struct{}
 
 func (s SystemControllerImpl) Ping(c *gin.Context) {
-	defer pkg.PanicHandler(c)
-
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
 }
 
@@ -14,6 +14,9 @@ type WorkdayController interface {
 
 	AssignPersonToWorkday(ctx *gin.Context)
 	UnassignPersonFromWorkday(ctx *gin.Context)
+
+	GetWorkdaysForPerson(ctx *gin.Context)
+	GetHoursForPerson(ctx *gin.Context)
 }
 
 type WorkdayControllerImpl struct {
@@ -40,6 +43,14 @@ func (w WorkdayControllerImpl) UnassignPersonFromWorkday(ctx *gin.Context) {
 	w.WorkdayService.UnassignPersonFromWorkday(ctx)
 }
 
+func (w WorkdayControllerImpl) GetWorkdaysForPerson(ctx *gin.Context) {
+	w.WorkdayService.GetWorkdaysForPerson(ctx)
+}
+
+func (w WorkdayControllerImpl) GetHoursForPerson(ctx *gin.Context) {
+	w.WorkdayService.GetHoursForPerson(ctx)
+}
+
 var workdayControllerSet = wire.NewSet(
 	wire.Struct(new(WorkdayControllerImpl), "*"),
 	wire.Bind(new(WorkdayController), new(*WorkdayControllerImpl)),
@@ -0,0 +1,114 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// States of a leave request, a pending request is decided by a planner of the department
+const (
+	LeaveRequestPending  = "pending"
+	LeaveRequestApproved = "approved"
+	LeaveRequestRejected = "rejected"
+)
+
+type LeaveRequest struct {
+	ID        string
+	PersonID  string
+	StartDate string // Dates as string since we only need the date
+	EndDate   string
+	Reason    string
+	Status    string
+
+	// Username of the employee who filed the request
+	RequestedBy string
+	// Username of the planner who decided the request, empty while pending
+	DecidedBy string
+	DecidedAt *time.Time
+	Comment   string
+
+	CreatedAt time.Time
+}
+
+func (l *LeaveRequest) ParseFromDBRecord(record *neo4j.Record) error {
+	/**
+	 * Parses a leave request from a neo4j record (node lr and the id of the person) and sets the values on this leave request
+	 */
+
+	node, _, err := neo4j.GetRecordValue[neo4j.Node](record, "lr")
+	if err != nil {
+		return err
+	}
+	personID, _, err := neo4j.GetRecordValue[string](record, "personID")
+	if err != nil {
+		return err
+	}
+
+	id, err := neo4j.GetProperty[string](node, "id")
+	if err != nil {
+		return err
+	}
+	startDate, err := neo4j.GetProperty[neo4j.Date](node, "start_date")
+	if err != nil {
+		return err
+	}
+	endDate, err := neo4j.GetProperty[neo4j.Date](node, "end_date")
+	if err != nil {
+		return err
+	}
+	status, err := neo4j.GetProperty[string](node, "status")
+	if err != nil {
+		return err
+	}
+	requestedBy, err := neo4j.GetProperty[string](node, "requested_by")
+	if err != nil {
+		return err
+	}
+	createdAt, err := neo4j.GetProperty[time.Time](node, "created_at")
+	if err != nil {
+		return err
+	}
+
+	// optional properties
+	reason, _ := neo4j.GetProperty[string](node, "reason")
+	decidedBy, _ := neo4j.GetProperty[string](node, "decided_by")
+	comment, _ := neo4j.GetProperty[string](node, "comment")
+	if decidedAt, err := neo4j.GetProperty[time.Time](node, "decided_at"); err == nil {
+		l.DecidedAt = &decidedAt
+	}
+
+	l.ID = id
+	l.PersonID = personID
+	l.StartDate = startDate.Time().Format("2006-01-02")
+	l.EndDate = endDate.Time().Format("2006-01-02")
+	l.Reason = reason
+	l.Status = status
+	l.RequestedBy = requestedBy
+	l.DecidedBy = decidedBy
+	l.Comment = comment
+	l.CreatedAt = createdAt
+
+	return nil
+}
+
+func (l *LeaveRequest) Dates() []string {
+	/**
+	 * Returns every date from the start to the end date of the leave request
+	 */
+	start, err := time.Parse("2006-01-02", l.StartDate)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse("2006-01-02", l.EndDate)
+	if err != nil {
+		return nil
+	}
+
+	dates := make([]string, 0)
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date.Format("2006-01-02"))
+	}
+
+	return dates
+}
@@ -0,0 +1,37 @@
+package dao
+
+import (
+	"testing"
+)
+
+func TestLeaveRequestDates(t *testing.T) {
+	tests := []struct {
+		request LeaveRequest
+		want    []string
+	}{
+		{
+			request: LeaveRequest{StartDate: "2024-02-28", EndDate: "2024-03-01"},
+			want:    []string{"2024-02-28", "2024-02-29", "2024-03-01"},
+		},
+		{
+			request: LeaveRequest{StartDate: "2024-01-01", EndDate: "2024-01-01"},
+			want:    []string{"2024-01-01"},
+		},
+		{
+			request: LeaveRequest{StartDate: "2024-01-01", EndDate: "invalid"},
+			want:    nil,
+		},
+	}
+
+	for _, test := range tests {
+		got := test.request.Dates()
+		if len(got) != len(test.want) {
+			t.Fatalf("Expected %v, got %v", test.want, got)
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("Expected %v, got %v", test.want, got)
+			}
+		}
+	}
+}
@@ -10,3 +10,8 @@ type DepartmentRequest struct {
 	ID   string `json:"id" binding:"required"`
 	Name string `json:"name" binding:"required"`
 }
+
+// The api-gateway creates or renames a department with its id in the path
+type DepartmentSyncRequest struct {
+	Name string `json:"name" binding:"required"`
+}
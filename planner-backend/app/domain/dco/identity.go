@@ -0,0 +1,66 @@
+package dco
+
+import "github.com/golang-jwt/jwt/v5"
+
+/**
+* The api-gateway forwards the identity of an authenticated user via these headers.
+* The headers are signed with a key shared between the api-gateway and the planner-backend.
+**/
+const (
+	IdentityUserHeader        = "X-Identity-User"
+	IdentityDepartmentHeader  = "X-Identity-Department"
+	IdentityAdminHeader       = "X-Identity-Admin"
+	IdentityPermissionsHeader = "X-Identity-Permissions"
+	IdentityTimestampHeader   = "X-Identity-Timestamp"
+	IdentitySignatureHeader   = "X-Identity-Signature"
+)
+
+// Shared with the api-gateway, loaded at startup
+var IdentitySigningKey []byte
+
+// The services call the internal routes of each other with a signed identity of these names
+const (
+	GatewayServiceName = "api-gateway"
+	PlannerServiceName = "planner-backend"
+)
+
+// Clients may call the planner-backend directly with an access token of the api-gateway.
+// The token is verified with the public keys of the api-gateway.
+const AccessTokenIssuer = "api-gateway"
+
+type AccessTokenClaim struct {
+	// Field names match the claims of the api-gateway
+	Username    string
+	Department  string
+	Permissions []string
+	IsAdmin     bool
+	// The id of the department in the planner-backend, Department holds its name
+	PlannerDepartmentID string
+	MustChangePassword  bool
+	MustEnrollTwoFactor bool
+	jwt.RegisteredClaims
+}
+
+type Identity struct {
+	Username    string
+	Department  string
+	IsAdmin     bool
+	Permissions []string
+}
+
+func (i *Identity) HasPermission(permission string) bool {
+	/**
+	* Checks if the identity holds a given permission. Admins hold every permission.
+	**/
+	if i.IsAdmin {
+		return true
+	}
+
+	for _, p := range i.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package dco
+
+import (
+	"errors"
+	"time"
+)
+
+// Longest leave that can be requested at once
+const maxLeaveRequestDays = 366
+
+/* Requests */
+type LeaveRequestRequest struct {
+	StartDate string  `json:"start_date" binding:"required"`
+	EndDate   string  `json:"end_date" binding:"required"`
+	Reason    *string `json:"reason" binding:"omitempty"`
+	// Username of the employee, set by the api-gateway
+	RequestedBy string `json:"requested_by" binding:"required"`
+}
+
+func (r *LeaveRequestRequest) Validate() error {
+	/* Validate the range of dates of the leave request */
+	start, err := time.Parse("2006-01-02", r.StartDate)
+	if err != nil {
+		return err
+	}
+
+	end, err := time.Parse("2006-01-02", r.EndDate)
+	if err != nil {
+		return err
+	}
+
+	if end.Before(start) {
+		return errors.New("end date must not be before start date")
+	}
+	if end.Sub(start) >= maxLeaveRequestDays*24*time.Hour {
+		return errors.New("leave request is too long")
+	}
+
+	return nil
+}
+
+type LeaveRequestDecisionRequest struct {
+	Comment *string `json:"comment" binding:"omitempty"`
+}
+
+/* Responses */
+type LeaveRequestResponse struct {
+	ID          string     `json:"id"`
+	PersonID    string     `json:"person_id"`
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	Reason      string     `json:"reason,omitempty"`
+	Status      string     `json:"status"`
+	RequestedBy string     `json:"requested_by"`
+	DecidedBy   string     `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type LeaveRequestDecisionResponse struct {
+	LeaveRequest LeaveRequestResponse `json:"leave_request"`
+	// Assignments of the person during the leave, released by an approval
+	Conflicts []WorkdayResponse `json:"conflicts"`
+}
@@ -91,3 +91,16 @@ type WorkdayResponse struct {
 	// Assigned Person can be nil
 	Persons []PersonResponse `json:"persons"`
 }
+
+type PersonHoursResponse struct {
+	PersonID  string `json:"person_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+
+	// Number of workdays the person is assigned to within the range
+	Assignments    int     `json:"assignments"`
+	PlannedMinutes int64   `json:"planned_minutes"`
+	PlannedHours   float64 `json:"planned_hours"`
+	// The weekly working hours of the person, scaled to the days of the range
+	ContractedHours float64 `json:"contracted_hours"`
+}
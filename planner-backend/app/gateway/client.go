@@ -0,0 +1,87 @@
+/**
+* This package calls the internal API of the api-gateway, which holds the registry of the departments.
+* Departments created, renamed or deleted in the planner-backend are reported to the api-gateway,
+* the reconciliation of the api-gateway repairs the registry if a call fails.
+**/
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"planner-backend/app/domain/dco"
+	"planner-backend/app/middleware"
+	"strings"
+	"time"
+)
+
+type Department struct {
+	ID   string
+	Name string
+}
+
+type Client interface {
+	// Creates or renames the department with the given planner id
+	SaveDepartment(department Department) error
+	DeleteDepartment(id string) error
+}
+
+type HTTPClient struct {
+	target string
+	client *http.Client
+}
+
+func NewClient(target string) *HTTPClient {
+	return &HTTPClient{
+		target: strings.TrimSuffix(target, "/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func NewClientForTarget(target string) Client {
+	/**
+	* Creates the client of the api-gateway in PLANNER_GATEWAY_TARGET.
+	* Without a target the changes are not reported and only picked up by the reconciliation.
+	**/
+	if target == "" {
+		slog.Info("PLANNER_GATEWAY_TARGET is not set, departments are not reported to the api-gateway")
+		return nil
+	}
+	return NewClient(target)
+}
+
+func (g *HTTPClient) SaveDepartment(department Department) error {
+	body, err := json.Marshal(map[string]string{"name": department.Name})
+	if err != nil {
+		return err
+	}
+	return g.do(http.MethodPut, "/internal/department/"+department.ID, body)
+}
+
+func (g *HTTPClient) DeleteDepartment(id string) error {
+	return g.do(http.MethodDelete, "/internal/department/"+id, nil)
+}
+
+func (g *HTTPClient) do(method string, path string, body []byte) error {
+	/* Sends a request signed with the identity of the planner-backend */
+	req, err := http.NewRequest(method, g.target+(&url.URL{Path: path}).EscapedPath(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	middleware.SetIdentityHeaders(req.Header, dco.Identity{Username: dco.PlannerServiceName, IsAdmin: true}, method, path, "")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: unexpected status code %d", method, path, resp.StatusCode)
+	}
+	return nil
+}
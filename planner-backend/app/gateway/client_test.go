@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"planner-backend/app/domain/dco"
+	"planner-backend/app/middleware"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	/* Test that the changes are sent signed with the identity of the planner-backend */
+	dco.IdentitySigningKey = []byte("secret")
+
+	type request struct {
+		method string
+		path   string
+		name   string
+	}
+	requests := []request{}
+	status := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := middleware.VerifyIdentity(r.Header, r.Method, r.URL.Path, r.URL.RawQuery)
+		if err != nil || identity.Username != dco.PlannerServiceName {
+			t.Errorf("Expected a signed request of the planner-backend, got %v", err)
+		}
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, request{method: r.Method, path: r.URL.Path, name: body["name"]})
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL + "/")
+	if err := client.SaveDepartment(Department{ID: "department 1", Name: "Department 1"}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := client.DeleteDepartment("department 1"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	expected := []request{
+		{method: http.MethodPut, path: "/internal/department/department 1", name: "Department 1"},
+		{method: http.MethodDelete, path: "/internal/department/department 1"},
+	}
+	if len(requests) != len(expected) || requests[0] != expected[0] || requests[1] != expected[1] {
+		t.Errorf("Expected requests %v, got %v", expected, requests)
+	}
+
+	status = http.StatusUnauthorized
+	if err := client.DeleteDepartment("department1"); err == nil {
+		t.Errorf("Expected an error for a rejected request")
+	}
+}
@@ -0,0 +1,223 @@
+/**
+* This package verifies the access tokens of the api-gateway with the public keys of its JWKS endpoint.
+* The keys are cached and fetched again when a token names an unknown key, so rotated keys are picked up
+* without a restart. Supported are RSA keys (RS256) and Ed25519 keys (EdDSA).
+**/
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// The keys are fetched at most once per interval, so tokens with made up key ids cannot flood the api-gateway
+var MinRefreshInterval = time.Minute
+
+// Cached keys are fetched again after this period to drop retired keys
+var MaxCacheAge = time.Hour
+
+type key struct {
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+type KeySet struct {
+	url    string
+	client *http.Client
+
+	// guards the fields below, it is not held while the keys are fetched
+	mu        sync.Mutex
+	keys      map[string]key
+	fetchedAt time.Time
+	// closed when the running fetch is done, nil if no fetch is running
+	fetching chan struct{}
+}
+
+func NewKeySet(url string) *KeySet {
+	return &KeySet{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   map[string]key{},
+	}
+}
+
+func NewKeySetForURL(url string) *KeySet {
+	/**
+	* Creates the key set of the JWKS endpoint in PLANNER_JWKS_URL.
+	* Without a url no key set is created and access tokens are not accepted.
+	**/
+	if url == "" {
+		slog.Info("PLANNER_JWKS_URL is not set, access tokens are not accepted")
+		return nil
+	}
+	return NewKeySet(url)
+}
+
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	/**
+	* Resolves the key of a token by its kid header, to be passed to jwt.Parse.
+	* The algorithm of the token has to match the key, so a public key cannot be used as HMAC secret.
+	**/
+	id, _ := token.Header["kid"].(string)
+	if id == "" {
+		return nil, errors.New("token without key id")
+	}
+
+	k, err := s.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), id)
+	}
+	return k.public, nil
+}
+
+func (s *KeySet) lookup(id string) (key, error) {
+	/**
+	* Returns a cached key, the keys are fetched again if the key is unknown or the cache is old.
+	* A single lookup fetches the keys, the lookups of an unknown key wait for it meanwhile
+	* and the lookups of cached keys are not blocked by it.
+	**/
+	s.mu.Lock()
+	k, exists := s.keys[id]
+	stale := time.Since(s.fetchedAt) > MaxCacheAge
+	start := (!exists || stale) && s.fetching == nil && time.Since(s.fetchedAt) > MinRefreshInterval
+	if start {
+		s.fetchedAt = time.Now()
+		s.fetching = make(chan struct{})
+	}
+	fetching := s.fetching
+	s.mu.Unlock()
+
+	switch {
+	case start:
+		if err := s.refresh(); err != nil {
+			slog.Error("Error happened: when fetch JWKS", "url", s.url, "error", err)
+			// an unreachable api-gateway does not invalidate the cached keys
+			if !exists {
+				return key{}, err
+			}
+		}
+	case !exists && fetching != nil:
+		<-fetching
+	}
+
+	if start || !exists {
+		s.mu.Lock()
+		k, exists = s.keys[id]
+		s.mu.Unlock()
+	}
+
+	if !exists {
+		return key{}, fmt.Errorf("unknown key id %q", id)
+	}
+	return k, nil
+}
+
+func (s *KeySet) refresh() error {
+	/* Fetches the keys of the JWKS endpoint and swaps them, the lock is only held for the swap */
+	keys, err := s.fetch()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		s.keys = keys
+	}
+	close(s.fetching)
+	s.fetching = nil
+	return err
+}
+
+func (s *KeySet) fetch() (map[string]key, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var document struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]key{}
+	for _, raw := range document.Keys {
+		id, k, err := parseKey(raw)
+		if err != nil {
+			// keys of other types are skipped, the remaining keys can still be used
+			slog.Warn("Skipped key of JWKS", "url", s.url, "error", err)
+			continue
+		}
+		keys[id] = k
+	}
+
+	slog.Info("Fetched JWKS", "url", s.url, "keys", len(keys))
+	return keys, nil
+}
+
+func parseKey(raw json.RawMessage) (string, key, error) {
+	/* Parses a JSON Web Key (RFC 7517) with an RSA or Ed25519 public key */
+	var jwk struct {
+		KeyType   string `json:"kty"`
+		KeyID     string `json:"kid"`
+		Use       string `json:"use"`
+		Algorithm string `json:"alg"`
+		N         string `json:"n"`
+		E         string `json:"e"`
+		Curve     string `json:"crv"`
+		X         string `json:"x"`
+	}
+	if err := json.Unmarshal(raw, &jwk); err != nil {
+		return "", key{}, err
+	}
+	if jwk.KeyID == "" {
+		return "", key{}, errors.New("key without key id")
+	}
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return "", key{}, fmt.Errorf("key %s: unsupported use %s", jwk.KeyID, jwk.Use)
+	}
+
+	switch {
+	case jwk.KeyType == "RSA" && (jwk.Algorithm == "" || jwk.Algorithm == "RS256"):
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return "", key{}, fmt.Errorf("key %s: %w", jwk.KeyID, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return "", key{}, fmt.Errorf("key %s: invalid exponent", jwk.KeyID)
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if public.N.BitLen() < 2048 {
+			return "", key{}, fmt.Errorf("key %s: RSA keys need at least 2048 bits", jwk.KeyID)
+		}
+		return jwk.KeyID, key{method: jwt.SigningMethodRS256, public: public}, nil
+	case jwk.KeyType == "OKP" && jwk.Curve == "Ed25519" && (jwk.Algorithm == "" || jwk.Algorithm == "EdDSA"):
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return "", key{}, fmt.Errorf("key %s: invalid Ed25519 key", jwk.KeyID)
+		}
+		return jwk.KeyID, key{method: jwt.SigningMethodEdDSA, public: ed25519.PublicKey(x)}, nil
+	default:
+		return "", key{}, fmt.Errorf("key %s: unsupported key type %s", jwk.KeyID, jwk.KeyType)
+	}
+}
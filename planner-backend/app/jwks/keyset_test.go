@@ -0,0 +1,216 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func edJWK(id string, public ed25519.PublicKey) map[string]string {
+	return map[string]string{"kty": "OKP", "kid": id, "use": "sig", "alg": "EdDSA", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(public)}
+}
+
+func rsaJWK(id string, public *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": id, "use": "sig", "alg": "RS256",
+		"n": base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}
+}
+
+func jwksServer(t *testing.T, keys *[]map[string]string, requests *int32) *httptest.Server {
+	/* Helper to serve a JWKS as the api-gateway does, the keys can be changed by the test */
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": *keys})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, id string, private interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))})
+	token.Header["kid"] = id
+	tokenString, err := token.SignedString(private)
+	if err != nil {
+		t.Fatalf("Error happened: when sign token %v", err)
+	}
+	return tokenString
+}
+
+func TestKeySet(t *testing.T) {
+	/* Test that tokens are verified with the keys of the JWKS */
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	rsaPrivate, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, otherPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	var requests int32
+	keys := []map[string]string{
+		edJWK("ed", edPublic),
+		rsaJWK("rsa", &rsaPrivate.PublicKey),
+		{"kty": "EC", "kid": "ec", "crv": "P-256"},
+	}
+	server := jwksServer(t, &keys, &requests)
+	keySet := NewKeySet(server.URL)
+
+	// the public key must not be usable as HMAC secret
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{})
+	hmacToken.Header["kid"] = "rsa"
+	hmacString, _ := hmacToken.SignedString([]byte("secret"))
+
+	type keySetTest struct {
+		name          string
+		token         string
+		expectedValid bool
+	}
+
+	testSteps := []keySetTest{
+		{name: "Ed25519 key", token: sign(t, jwt.SigningMethodEdDSA, "ed", edPrivate), expectedValid: true},
+		{name: "RSA key", token: sign(t, jwt.SigningMethodRS256, "rsa", rsaPrivate), expectedValid: true},
+		{name: "wrong key", token: sign(t, jwt.SigningMethodEdDSA, "ed", otherPrivate)},
+		{name: "wrong algorithm", token: sign(t, jwt.SigningMethodEdDSA, "rsa", edPrivate)},
+		{name: "HMAC token", token: hmacString},
+		{name: "unknown key", token: sign(t, jwt.SigningMethodEdDSA, "unknown", edPrivate)},
+		{name: "unsupported key", token: sign(t, jwt.SigningMethodEdDSA, "ec", edPrivate)},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			_, err := jwt.Parse(testStep.token, keySet.Keyfunc)
+			if (err == nil) != testStep.expectedValid {
+				t.Errorf("Expected valid %v, got %v", testStep.expectedValid, err)
+			}
+		})
+	}
+
+	// unknown keys do not trigger a fetch within the refresh interval
+	if requests != 1 {
+		t.Errorf("Expected the keys to be fetched once, got %d", requests)
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	/* Test that a new key of the api-gateway is fetched when a token names it */
+	oldPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	newPublic, newPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	var requests int32
+	keys := []map[string]string{edJWK("old", oldPublic)}
+	server := jwksServer(t, &keys, &requests)
+	keySet := NewKeySet(server.URL)
+
+	if _, err := keySet.lookup("old"); err != nil {
+		t.Fatalf("Expected the old key, got %v", err)
+	}
+
+	// the api-gateway rotates its key
+	keys = []map[string]string{edJWK("old", oldPublic), edJWK("new", newPublic)}
+	token := sign(t, jwt.SigningMethodEdDSA, "new", newPrivate)
+
+	if _, err := jwt.Parse(token, keySet.Keyfunc); err == nil {
+		t.Errorf("Expected the new key not to be fetched within the refresh interval")
+	}
+
+	keySet.fetchedAt = time.Now().Add(-MinRefreshInterval - time.Second)
+	if _, err := jwt.Parse(token, keySet.Keyfunc); err != nil {
+		t.Errorf("Expected the new key to be fetched, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected the keys to be fetched twice, got %d", requests)
+	}
+}
+
+func TestKeySetUnavailable(t *testing.T) {
+	/* Test that cached keys are kept if the api-gateway cannot be reached */
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+
+	var requests int32
+	keys := []map[string]string{edJWK("ed", public)}
+	server := jwksServer(t, &keys, &requests)
+	keySet := NewKeySet(server.URL)
+
+	token := sign(t, jwt.SigningMethodEdDSA, "ed", private)
+	if _, err := jwt.Parse(token, keySet.Keyfunc); err != nil {
+		t.Fatalf("Expected the token to be valid, got %v", err)
+	}
+
+	server.Close()
+	keySet.fetchedAt = time.Now().Add(-MaxCacheAge - time.Second)
+	if _, err := jwt.Parse(token, keySet.Keyfunc); err != nil {
+		t.Errorf("Expected the cached key to be used, got %v", err)
+	}
+}
+
+func TestKeySetConcurrentFetch(t *testing.T) {
+	/* Test that a slow fetch does not block the cached keys and is shared by the lookups of an unknown key */
+	oldPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	newPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	var requests int32
+	release := make(chan struct{})
+	keys := []map[string]string{edJWK("old", oldPublic), edJWK("new", newPublic)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first fetch is answered right away, the following ones wait for the test
+		if atomic.AddInt32(&requests, 1) > 1 {
+			<-release
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	t.Cleanup(server.Close)
+	keySet := NewKeySet(server.URL)
+
+	if _, err := keySet.lookup("old"); err != nil {
+		t.Fatalf("Expected the old key, got %v", err)
+	}
+	keySet.mu.Lock()
+	delete(keySet.keys, "new")
+	keySet.fetchedAt = time.Now().Add(-MinRefreshInterval - time.Second)
+	keySet.mu.Unlock()
+
+	results := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := keySet.lookup("new")
+			results <- err
+		}()
+	}
+
+	// the cached key is returned while the fetch hangs
+	for atomic.LoadInt32(&requests) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cached := make(chan error, 1)
+	go func() {
+		_, err := keySet.lookup("old")
+		cached <- err
+	}()
+	select {
+	case err := <-cached:
+		if err != nil {
+			t.Errorf("Expected the cached key, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the cached key not to wait for the fetch")
+	}
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("Expected the new key, got %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected a single fetch for the concurrent lookups, got %d", requests)
+	}
+}
@@ -0,0 +1,72 @@
+/**
+* This package holds the Prometheus metrics of the planner-backend, which are served by /metrics.
+* The collectors are registered with the default registry, which also reports the Go runtime and the process.
+**/
+package metrics
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Outcomes of queries and jobs
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Jobs which run periodically
+const (
+	// Creates the workdays of the next weeks
+	JobWorkdays = "workdays"
+)
+
+var (
+	// Requests by route template, e.g. /api/v1/planner/person/:personID, method and status code
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by route template, method and status code.",
+	}, []string{"route", "method", "status"})
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the HTTP requests by route template, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// Queries by the repository method which ran them, e.g. WorkdayRepository and GetWorkday
+	Neo4jQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "neo4j_query_duration_seconds",
+		Help:    "Duration of the Neo4j queries by repository, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"repository", "method", "status"})
+
+	SyncJobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "sync_job_duration_seconds",
+		Help:    "Duration of the synchronization jobs by job and status.",
+		Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"job", "status"})
+	SyncWorkdaysCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sync_workdays_created_total",
+		Help: "Workdays created by the synchronization.",
+	})
+)
+
+func Handler() gin.HandlerFunc {
+	/* Serves the metrics of the default registry in the Prometheus text format */
+	return gin.WrapH(promhttp.Handler())
+}
+
+func Status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusOK
+}
+
+func ObserveSyncJob(job string, start time.Time, err error) {
+	SyncJobDuration.WithLabelValues(job, Status(err)).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"planner-backend/app/pkg"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ErrorHandler() gin.HandlerFunc {
+	/**
+	* This middleware renders the errors which the handlers record with pkg.Abort,
+	* so every failed request is answered in the same format. It must be the first middleware,
+	* panics of the following handlers are recovered and answered as unknown errors.
+	**/
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				slog.Error("Panic when handling request", "path", c.Request.URL.Path, "panic", recovered, "stack", string(debug.Stack()))
+				pkg.Abort(c, fmt.Errorf("panic: %v", recovered))
+				pkg.RenderErrors(c)
+			}
+		}()
+
+		c.Next()
+		pkg.RenderErrors(c)
+	}
+}
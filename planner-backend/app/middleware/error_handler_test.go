@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"planner-backend/app/constant"
+	"planner-backend/app/domain/dto"
+	"planner-backend/app/pkg"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		Name  string `json:"name" binding:"required"`
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/conflict", func(c *gin.Context) {
+		pkg.Abort(c, pkg.NewError(constant.Conflict).WithMessage("Name: already taken"))
+	})
+	router.POST("/validate", func(c *gin.Context) {
+		var body request
+		if err := c.ShouldBindJSON(&body); err != nil {
+			pkg.Abort(c, pkg.ValidationError(err))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/unknown", func(c *gin.Context) {
+		pkg.Abort(c, errors.New("connection refused"))
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("something went wrong")
+	})
+	router.GET("/written", func(c *gin.Context) {
+		c.JSON(http.StatusAccepted, pkg.BuildResponse(constant.Success, pkg.Null()))
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+	})
+
+	type errorHandlerTest struct {
+		method           string
+		path             string
+		body             string
+		expectedStatus   int
+		expectedResponse dto.APIErrorResponse
+	}
+
+	testSteps := []errorHandlerTest{
+		{
+			// messages may contain colons
+			method:         "GET",
+			path:           "/conflict",
+			expectedStatus: http.StatusConflict,
+			expectedResponse: dto.APIErrorResponse{
+				ResponseKey:     "Conflict",
+				ResponseMessage: "Name: already taken",
+				Code:            "conflict",
+				Errors:          []dto.ErrorDetail{},
+			},
+		},
+		{
+			method:         "POST",
+			path:           "/validate",
+			body:           `{"email": "not an email"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: dto.APIErrorResponse{
+				ResponseKey:     "Invalid Request",
+				ResponseMessage: "Invalid Request: Some fields are invalid",
+				Code:            "validation_failed",
+				Errors: []dto.ErrorDetail{
+					{Field: "name", Code: "required", Message: "is required"},
+					{Field: "email", Code: "email", Message: "must be an email address"},
+				},
+			},
+		},
+		{
+			method:         "POST",
+			path:           "/validate",
+			body:           `{"name": 1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: dto.APIErrorResponse{
+				ResponseKey:     "Invalid Request",
+				ResponseMessage: "Invalid Request: Some fields are invalid",
+				Code:            "validation_failed",
+				Errors:          []dto.ErrorDetail{{Field: "name", Code: "type", Message: "must be of type string"}},
+			},
+		},
+		{
+			method:         "POST",
+			path:           "/validate",
+			body:           `{"name": `,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: dto.APIErrorResponse{
+				ResponseKey:     "Invalid Request",
+				ResponseMessage: "Invalid Request: The body is not valid JSON",
+				Code:            "invalid_request",
+				Errors:          []dto.ErrorDetail{},
+			},
+		},
+		{
+			// the cause is not sent to the client
+			method:         "GET",
+			path:           "/unknown",
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: dto.APIErrorResponse{
+				ResponseKey:     "Unknown Error",
+				ResponseMessage: "Unknown Error: Unknown error",
+				Code:            "unknown_error",
+				Errors:          []dto.ErrorDetail{},
+			},
+		},
+		{
+			method:         "GET",
+			path:           "/panic",
+			expectedStatus: http.StatusInternalServerError,
+			expectedResponse: dto.APIErrorResponse{
+				ResponseKey:     "Unknown Error",
+				ResponseMessage: "Unknown Error: Unknown error",
+				Code:            "unknown_error",
+				Errors:          []dto.ErrorDetail{},
+			},
+		},
+	}
+
+	for i, testStep := range testSteps {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(testStep.method, testStep.path, strings.NewReader(testStep.body))
+		router.ServeHTTP(w, req)
+
+		if w.Code != testStep.expectedStatus {
+			t.Errorf("Step %d: expected status code %d, got %d", i, testStep.expectedStatus, w.Code)
+		}
+		var response dto.APIErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Step %d: invalid body %q: %v", i, w.Body.String(), err)
+		}
+		if !reflect.DeepEqual(response, testStep.expectedResponse) {
+			t.Errorf("Step %d: expected %+v, got %+v", i, testStep.expectedResponse, response)
+		}
+	}
+
+	// a response of the handler is kept
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/written", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected the response of the handler, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestErrorHandlerProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler())
+	router.GET("/person/:id", func(c *gin.Context) {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest).WithCode("validation_failed").WithDetails(dto.ErrorDetail{
+			Field:   "id",
+			Code:    "uuid",
+			Message: "must be a UUID",
+		}))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/person/1", nil)
+	req.Header.Set("Accept", "application/problem+json, application/json")
+	router.ServeHTTP(w, req)
+
+	if contentType := w.Header().Get("Content-Type"); contentType != pkg.ProblemContentType {
+		t.Errorf("Expected content type %s, got %s", pkg.ProblemContentType, contentType)
+	}
+
+	var problem dto.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Invalid body %q: %v", w.Body.String(), err)
+	}
+	expected := dto.Problem{
+		Type:     "about:blank",
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "Invalid Request: Please check your request",
+		Instance: "/person/1",
+		Code:     "validation_failed",
+		Errors:   []dto.ErrorDetail{{Field: "id", Code: "uuid", Message: "must be a UUID"}},
+	}
+	if w.Code != http.StatusBadRequest || !reflect.DeepEqual(problem, expected) {
+		t.Errorf("Expected %d %+v, got %d %+v", http.StatusBadRequest, expected, w.Code, problem)
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"planner-backend/app/constant"
+	"planner-backend/app/metrics"
+	"planner-backend/app/pkg"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Metrics() gin.HandlerFunc {
+	/**
+	* This middleware counts the requests and measures their latency by the route template,
+	* so the paths with ids do not create a series each. Requests without a route are reported as "unmatched".
+	**/
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+func RequiredMetricsToken(token string) gin.HandlerFunc {
+	/**
+	* This middleware protects /metrics with the bearer token of the scraper.
+	* The metrics are public if no token is configured.
+	**/
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(bearerToken(c.Request.Header)), []byte(token)) != 1 {
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+		c.Next()
+	}
+}
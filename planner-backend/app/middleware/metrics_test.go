@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"planner-backend/app/metrics"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ErrorHandler(), Metrics())
+	router.GET("/person/:personID", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedRoute string
+		expectedCode  string
+	}{
+		// the ids of the path are replaced by the route template
+		{name: "route", path: "/person/42", expectedRoute: "/person/:personID", expectedCode: "204"},
+		{name: "unmatched", path: "/unknown/42", expectedRoute: "unmatched", expectedCode: "404"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(test.expectedRoute, http.MethodGet, test.expectedCode)
+			before := testutil.ToFloat64(counter)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, test.path, nil)
+			router.ServeHTTP(w, req)
+
+			if count := testutil.ToFloat64(counter); count != before+1 {
+				t.Errorf("Expected %v requests, got %v", before+1, count)
+			}
+		})
+	}
+}
+
+func TestRequiredMetricsToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name               string
+		token              string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{name: "public without token", expectedStatusCode: http.StatusOK},
+		{name: "missing token", token: "scraper", expectedStatusCode: http.StatusUnauthorized},
+		{name: "wrong token", token: "scraper", authorization: "Bearer other", expectedStatusCode: http.StatusUnauthorized},
+		{name: "token", token: "scraper", authorization: "Bearer scraper", expectedStatusCode: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.GET("/metrics", RequiredMetricsToken(test.token), metrics.Handler())
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			router.ServeHTTP(w, req)
+
+			if w.Code != test.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", test.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"planner-backend/app/constant"
+	"planner-backend/app/domain/dco"
+	"planner-backend/app/jwks"
+	"planner-backend/app/pkg"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type identityContextKey struct{}
+
+// IdentityMaxAge is the maximum age of a forwarded identity before it is rejected
+var IdentityMaxAge = 5 * time.Minute
+
+func Identity(keys *jwks.KeySet) gin.HandlerFunc {
+	/**
+	* This middleware verifies the identity forwarded by the api-gateway, or the access token
+	* of a client calling the planner-backend directly. The forwarded identity takes precedence.
+	* Requests without identity are treated as anonymous, requests with an invalid signature
+	* are rejected. A verified identity is stored in the gin context and in the request context.
+	* Access tokens are only accepted for reading: the policy of the api-gateway, which decides on
+	* the permissions and departments of a write, is not evaluated here.
+	**/
+	return func(c *gin.Context) {
+		var identity *dco.Identity
+		var err error
+		if c.Request.Header.Get(dco.IdentitySignatureHeader) != "" {
+			identity, err = VerifyIdentity(c.Request.Header, c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery)
+		} else if token := bearerToken(c.Request.Header); token != "" {
+			identity, err = VerifyAccessToken(keys, token)
+		} else {
+			c.Next()
+			return
+		}
+		if err != nil {
+			slog.Error("Error happened: when verify identity", "error", err)
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+		if c.Request.Header.Get(dco.IdentitySignatureHeader) == "" && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			slog.Error("Error happened: when verify identity", "username", identity.Username, "error", "access token used for a write")
+			pkg.Abort(c, pkg.NewError(constant.Forbidden).WithMessage("Forbidden: Changes have to be sent through the api-gateway"))
+			return
+		}
+
+		c.Set("identity", identity)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), identityContextKey{}, identity))
+		c.Next()
+	}
+}
+
+func RequiredAuth() gin.HandlerFunc {
+	/**
+	* This middleware is used for routes that require an authenticated user.
+	* It must be used after the Identity middleware.
+	**/
+	return func(c *gin.Context) {
+		if _, exists := GetIdentity(c); !exists {
+			slog.Error("Error happened: when get identity", "error", "identity not found")
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func RequiredDepartmentMember(param string) gin.HandlerFunc {
+	/**
+	* This middleware is used for routes that are only visible to the members of a department.
+	* The department of the identity must be the one of the path, admins may see every department.
+	* It must be used after the RequiredAuth middleware.
+	* @param param: The name of the path parameter holding the department id
+	**/
+	return func(c *gin.Context) {
+		identity, exists := GetIdentity(c)
+		if !exists {
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+		if !identity.IsAdmin && identity.Department != c.Param(param) {
+			slog.Error("Error happened: when check department", "username", identity.Username, "department", c.Param(param))
+			pkg.Abort(c, pkg.NewError(constant.Forbidden))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func RequiredService(service string) gin.HandlerFunc {
+	/**
+	* This middleware is used for the internal routes which are only called by another service.
+	* The request must carry identity headers signed for the service, access tokens are not accepted.
+	**/
+	return func(c *gin.Context) {
+		identity, err := VerifyIdentity(c.Request.Header, c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery)
+		if err != nil || identity.Username != service || !identity.IsAdmin {
+			slog.Error("Error happened: when verify service identity", "service", service, "error", err)
+			pkg.Abort(c, pkg.NewError(constant.Unauthorized))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func GetIdentity(c *gin.Context) (*dco.Identity, bool) {
+	/* Returns the identity stored by the Identity middleware */
+	value, exists := c.Get("identity")
+	if !exists {
+		return nil, false
+	}
+
+	identity, ok := value.(*dco.Identity)
+	return identity, ok
+}
+
+func IdentityFromContext(ctx context.Context) (*dco.Identity, bool) {
+	/* Returns the identity stored in a request context */
+	identity, ok := ctx.Value(identityContextKey{}).(*dco.Identity)
+	return identity, ok
+}
+
+func VerifyIdentity(header http.Header, method string, path string, query string) (*dco.Identity, error) {
+	/**
+	* Verifies the signed identity headers of a request
+	* @param header: The header of the request
+	* @param method: The HTTP method of the request
+	* @param path: The URL path of the request
+	* @param query: The raw query of the request
+	* @return: The identity if the signature is valid
+	**/
+	if len(dco.IdentitySigningKey) == 0 {
+		return nil, errors.New("identity signing key is not configured")
+	}
+
+	timestamp := header.Get(dco.IdentityTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid identity timestamp")
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age > IdentityMaxAge || age < -IdentityMaxAge {
+		return nil, errors.New("identity timestamp is out of range")
+	}
+
+	isAdmin, err := strconv.ParseBool(header.Get(dco.IdentityAdminHeader))
+	if err != nil {
+		return nil, errors.New("invalid identity admin flag")
+	}
+
+	permissions := []string{}
+	if raw := header.Get(dco.IdentityPermissionsHeader); raw != "" {
+		permissions = strings.Split(raw, ",")
+	}
+
+	identity := dco.Identity{
+		Username:    header.Get(dco.IdentityUserHeader),
+		Department:  header.Get(dco.IdentityDepartmentHeader),
+		IsAdmin:     isAdmin,
+		Permissions: permissions,
+	}
+	if identity.Username == "" {
+		return nil, errors.New("identity without username")
+	}
+
+	expected, err := hex.DecodeString(SignIdentity(identity, timestamp, method, path, query))
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hex.DecodeString(header.Get(dco.IdentitySignatureHeader))
+	if err != nil {
+		return nil, errors.New("invalid identity signature")
+	}
+	if !hmac.Equal(expected, signature) {
+		return nil, errors.New("identity signature does not match")
+	}
+
+	return &identity, nil
+}
+
+func VerifyAccessToken(keys *jwks.KeySet, tokenString string) (*dco.Identity, error) {
+	/**
+	* Verifies an access token of the api-gateway with the keys of its JWKS.
+	* Sessions are not checked here, revoked sessions are accepted until their short-lived token expires.
+	* @param keys: The public keys of the api-gateway, nil if access tokens are not accepted
+	* @param tokenString: The access token
+	* @return: The identity of the token
+	**/
+	if keys == nil {
+		return nil, errors.New("access tokens are not accepted")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &dco.AccessTokenClaim{}, keys.Keyfunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(dco.AccessTokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, ok := token.Claims.(*dco.AccessTokenClaim)
+	if !ok || !token.Valid || claim.Username == "" {
+		return nil, errors.New("invalid token")
+	}
+	// the api-gateway only allows its auth routes for these tokens
+	if claim.MustChangePassword || claim.MustEnrollTwoFactor {
+		return nil, errors.New("token is restricted to the auth routes")
+	}
+
+	permissions := claim.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return &dco.Identity{
+		Username:    claim.Username,
+		Department:  claim.PlannerDepartmentID,
+		IsAdmin:     claim.IsAdmin,
+		Permissions: permissions,
+	}, nil
+}
+
+func bearerToken(header http.Header) string {
+	scheme, token, found := strings.Cut(header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func SetIdentityHeaders(header http.Header, identity dco.Identity, method string, path string, query string) {
+	/* Sets the signed identity headers on a request to the given method, path and raw query */
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	header.Set(dco.IdentityUserHeader, identity.Username)
+	header.Set(dco.IdentityDepartmentHeader, identity.Department)
+	header.Set(dco.IdentityAdminHeader, strconv.FormatBool(identity.IsAdmin))
+	header.Set(dco.IdentityPermissionsHeader, strings.Join(identity.Permissions, ","))
+	header.Set(dco.IdentityTimestampHeader, timestamp)
+	header.Set(dco.IdentitySignatureHeader, SignIdentity(identity, timestamp, method, path, query))
+}
+
+func SignIdentity(identity dco.Identity, timestamp string, method string, path string, query string) string {
+	/**
+	* Creates the HMAC-SHA256 signature of an identity and the request it belongs to, including its raw query.
+	* Must match the implementation of the api-gateway.
+	**/
+	payload := strings.Join([]string{
+		identity.Username,
+		identity.Department,
+		strconv.FormatBool(identity.IsAdmin),
+		strings.Join(identity.Permissions, ","),
+		timestamp,
+		method,
+		path,
+		query,
+	}, "\n")
+
+	mac := hmac.New(sha256.New, dco.IdentitySigningKey)
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
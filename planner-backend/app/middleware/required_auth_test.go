@@ -0,0 +1,393 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"planner-backend/app/domain/dco"
+	"planner-backend/app/jwks"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signedRequest(method string, target string, identity dco.Identity, timestamp time.Time) *http.Request {
+	/* Creates a request carrying the identity headers as the api-gateway would, the target may carry a query */
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	req, _ := http.NewRequest(method, target, nil)
+	req.Header.Set(dco.IdentityUserHeader, identity.Username)
+	req.Header.Set(dco.IdentityDepartmentHeader, identity.Department)
+	req.Header.Set(dco.IdentityAdminHeader, strconv.FormatBool(identity.IsAdmin))
+	req.Header.Set(dco.IdentityTimestampHeader, ts)
+	req.Header.Set(dco.IdentitySignatureHeader, SignIdentity(identity, ts, method, req.URL.Path, req.URL.RawQuery))
+
+	return req
+}
+
+func TestRequiredAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	identity := dco.Identity{Username: "test", Department: "department1", Permissions: []string{}}
+
+	type requiredAuthTest struct {
+		name               string
+		request            func() *http.Request
+		expectedStatusCode int
+		expectedUsername   string
+	}
+
+	testSteps := []requiredAuthTest{
+		{
+			name: "anonymous GET is allowed",
+			request: func() *http.Request {
+				req, _ := http.NewRequest("GET", "/open", nil)
+				return req
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "anonymous POST is rejected",
+			request: func() *http.Request {
+				req, _ := http.NewRequest("POST", "/secured", nil)
+				return req
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "signed POST is allowed",
+			request: func() *http.Request {
+				return signedRequest("POST", "/secured", identity, time.Now())
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedUsername:   "test",
+		},
+		{
+			name: "signed GET exposes the identity",
+			request: func() *http.Request {
+				return signedRequest("GET", "/open", identity, time.Now())
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedUsername:   "test",
+		},
+		{
+			name: "tampered identity is rejected",
+			request: func() *http.Request {
+				req := signedRequest("POST", "/secured", identity, time.Now())
+				req.Header.Set(dco.IdentityAdminHeader, "true")
+				return req
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "signature for a different route is rejected",
+			request: func() *http.Request {
+				req := signedRequest("GET", "/open", identity, time.Now())
+				req.Method = "POST"
+				req.URL.Path = "/secured"
+				return req
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "signed query is allowed",
+			request: func() *http.Request {
+				return signedRequest("GET", "/open?department_id=department1", identity, time.Now())
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedUsername:   "test",
+		},
+		{
+			name: "signature for a different query is rejected",
+			request: func() *http.Request {
+				req := signedRequest("GET", "/open?department_id=department1", identity, time.Now())
+				req.URL.RawQuery = "department_id=department2"
+				return req
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "expired identity is rejected",
+			request: func() *http.Request {
+				return signedRequest("POST", "/secured", identity, time.Now().Add(-2*IdentityMaxAge))
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			var username string
+			handler := func(c *gin.Context) {
+				if identity, ok := IdentityFromContext(c.Request.Context()); ok {
+					username = identity.Username
+				}
+				c.Status(http.StatusOK)
+			}
+
+			router := gin.New()
+
+			router.Use(ErrorHandler())
+			router.Use(Identity(nil))
+			router.GET("/open", handler)
+			router.POST("/secured", RequiredAuth(), handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, testStep.request())
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if username != testStep.expectedUsername {
+				t.Errorf("Expected username %q, got %q", testStep.expectedUsername, username)
+			}
+		})
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	/* Test clients calling the planner-backend directly with an access token of the api-gateway */
+	gin.SetMode(gin.TestMode)
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "OKP", "kid": "gateway", "use": "sig", "alg": "EdDSA", "crv": "Ed25519",
+			"x": base64.RawURLEncoding.EncodeToString(public),
+		}}})
+	}))
+	defer server.Close()
+	keys := jwks.NewKeySet(server.URL)
+
+	accessToken := func(modify func(claim *dco.AccessTokenClaim)) string {
+		claim := dco.AccessTokenClaim{
+			Username:            "test",
+			Department:          "Department 1",
+			PlannerDepartmentID: "department1",
+			Permissions:         []string{"person:write"},
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    dco.AccessTokenIssuer,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}
+		modify(&claim)
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claim)
+		token.Header["kid"] = "gateway"
+		tokenString, _ := token.SignedString(private)
+		return tokenString
+	}
+
+	type accessTokenTest struct {
+		name               string
+		method             string
+		token              string
+		keys               *jwks.KeySet
+		expectedStatusCode int
+		expectedUsername   string
+	}
+
+	testSteps := []accessTokenTest{
+		{
+			name:               "valid token is allowed",
+			token:              accessToken(func(claim *dco.AccessTokenClaim) {}),
+			keys:               keys,
+			expectedStatusCode: http.StatusOK,
+			expectedUsername:   "test",
+		},
+		{
+			// the policy of the api-gateway is not evaluated for tokens
+			name:               "valid token is rejected for writes",
+			method:             "POST",
+			token:              accessToken(func(claim *dco.AccessTokenClaim) {}),
+			keys:               keys,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "tokens are rejected without key set",
+			token:              accessToken(func(claim *dco.AccessTokenClaim) {}),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "expired token is rejected",
+			token:              accessToken(func(claim *dco.AccessTokenClaim) { claim.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }),
+			keys:               keys,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "token of another issuer is rejected",
+			token:              accessToken(func(claim *dco.AccessTokenClaim) { claim.Issuer = "someone" }),
+			keys:               keys,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "token restricted to the auth routes is rejected",
+			token:              accessToken(func(claim *dco.AccessTokenClaim) { claim.MustChangePassword = true }),
+			keys:               keys,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:               "tampered token is rejected",
+			token:              accessToken(func(claim *dco.AccessTokenClaim) {}) + "x",
+			keys:               keys,
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			var identity *dco.Identity
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.Use(Identity(testStep.keys))
+			handler := func(c *gin.Context) {
+				identity, _ = GetIdentity(c)
+				c.Status(http.StatusOK)
+			}
+			router.GET("/secured", RequiredAuth(), handler)
+			router.POST("/secured", RequiredAuth(), handler)
+
+			method := testStep.method
+			if method == "" {
+				method = "GET"
+			}
+			req, _ := http.NewRequest(method, "/secured", nil)
+			req.Header.Set("Authorization", "Bearer "+testStep.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+			if testStep.expectedUsername != "" && (identity == nil || identity.Username != testStep.expectedUsername || !identity.HasPermission("person:write")) {
+				t.Errorf("Expected the identity of the token, got %+v", identity)
+			}
+		})
+	}
+}
+
+func TestRequiredService(t *testing.T) {
+	/* Internal routes only accept the signed identity of the service */
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	service := dco.Identity{Username: dco.GatewayServiceName, IsAdmin: true}
+	user := dco.Identity{Username: "test", IsAdmin: true}
+
+	type requiredServiceTest struct {
+		name               string
+		request            func() *http.Request
+		expectedStatusCode int
+	}
+
+	testSteps := []requiredServiceTest{
+		{
+			name: "service identity is allowed",
+			request: func() *http.Request {
+				req, _ := http.NewRequest("PUT", "/internal/department/test", nil)
+				SetIdentityHeaders(req.Header, service, "PUT", "/internal/department/test", "")
+				return req
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name: "user identity is rejected",
+			request: func() *http.Request {
+				return signedRequest("PUT", "/internal/department/test", user, time.Now())
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "identity signed for another route is rejected",
+			request: func() *http.Request {
+				req, _ := http.NewRequest("PUT", "/internal/department/test", nil)
+				SetIdentityHeaders(req.Header, service, "PUT", "/internal/department/other", "")
+				return req
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name: "anonymous request is rejected",
+			request: func() *http.Request {
+				req, _ := http.NewRequest("PUT", "/internal/department/test", nil)
+				return req
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.PUT("/internal/department/:departmentID", RequiredService(dco.GatewayServiceName), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, testStep.request())
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestRequiredDepartmentMember(t *testing.T) {
+	/* The leave requests of a department are only visible to its members */
+	gin.SetMode(gin.TestMode)
+	dco.IdentitySigningKey = []byte("secret")
+
+	type requiredDepartmentMemberTest struct {
+		name               string
+		identity           dco.Identity
+		path               string
+		expectedStatusCode int
+	}
+
+	testSteps := []requiredDepartmentMemberTest{
+		{name: "member is allowed", identity: dco.Identity{Username: "test", Department: "department1"}, path: "/department/department1/leave-request/", expectedStatusCode: http.StatusOK},
+		{name: "other department is rejected", identity: dco.Identity{Username: "test", Department: "department1"}, path: "/department/department2/leave-request/", expectedStatusCode: http.StatusForbidden},
+		{name: "admin is allowed", identity: dco.Identity{Username: "admin", IsAdmin: true}, path: "/department/department2/leave-request/", expectedStatusCode: http.StatusOK},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ErrorHandler())
+			router.Use(Identity(nil))
+			router.GET("/department/:departmentID/leave-request/", RequiredAuth(), RequiredDepartmentMember("departmentID"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, signedRequest("GET", testStep.path, testStep.identity, time.Now()))
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	identity := dco.Identity{Permissions: []string{"write"}}
+	if !identity.HasPermission("write") {
+		t.Errorf("Expected identity to hold permission write")
+	}
+	if identity.HasPermission("delete") {
+		t.Errorf("Expected identity not to hold permission delete")
+	}
+
+	identity.IsAdmin = true
+	if !identity.HasPermission("delete") {
+		t.Errorf("Expected admin to hold every permission")
+	}
+}
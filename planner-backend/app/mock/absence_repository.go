@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"planner-backend/app/domain/dao"
 )
 
@@ -30,7 +31,7 @@ func (r *AbsenceRepositoryMock) Return(mockData interface{}, errorData error) Mo
 }
 
 /* Repostory interface implementations */
-func (r *AbsenceRepositoryMock) FindAllAbsencies(departmentID string, date string) ([]dao.Absence, error) {
+func (r *AbsenceRepositoryMock) FindAllAbsencies(ctx context.Context, departmentID string, date string) ([]dao.Absence, error) {
 	if r.dataContainer["FindAllAbsencies"] == nil {
 		return nil, r.errorContainer["FindAllAbsencies"]
 	}
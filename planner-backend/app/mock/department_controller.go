@@ -28,3 +28,11 @@ func (m *DepartmentControllerMock) Update(ctx *gin.Context) {
 func (m *DepartmentControllerMock) Delete(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Delete"})
 }
+
+func (m *DepartmentControllerMock) Sync(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Sync"})
+}
+
+func (m *DepartmentControllerMock) Remove(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Remove"})
+}
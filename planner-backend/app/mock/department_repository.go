@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"planner-backend/app/domain/dao"
 )
 
@@ -30,14 +31,14 @@ func (r *DepartmentRepositoryMock) Return(mockData interface{}, errorData error)
 }
 
 /* Repostory interface implementations */
-func (r *DepartmentRepositoryMock) FindAllDepartments() ([]dao.Department, error) {
+func (r *DepartmentRepositoryMock) FindAllDepartments(ctx context.Context) ([]dao.Department, error) {
 	if r.dataContainer["FindAllDepartments"] == nil {
 		return nil, r.errorContainer["FindAllDepartments"]
 	}
 	return r.dataContainer["FindAllDepartments"].([]dao.Department), r.errorContainer["FindAllDepartments"]
 }
 
-func (r *DepartmentRepositoryMock) FindDepartmentByID(id string) (dao.Department, error) {
+func (r *DepartmentRepositoryMock) FindDepartmentByID(ctx context.Context, id string) (dao.Department, error) {
 	if r.dataContainer["FindDepartmentByID"] == nil {
 		return dao.Department{}, r.errorContainer["FindDepartmentByID"]
 	}
@@ -45,14 +46,14 @@ func (r *DepartmentRepositoryMock) FindDepartmentByID(id string) (dao.Department
 	return r.dataContainer["FindDepartmentByID"].(dao.Department), r.errorContainer["FindDepartmentByID"]
 }
 
-func (r *DepartmentRepositoryMock) Save(Department *dao.Department) (dao.Department, error) {
+func (r *DepartmentRepositoryMock) Save(ctx context.Context, Department *dao.Department) (dao.Department, error) {
 	if r.dataContainer["Save"] == nil {
 		return dao.Department{}, r.errorContainer["Save"]
 	}
 	return r.dataContainer["Save"].(dao.Department), r.errorContainer["Save"]
 }
 
-func (r *DepartmentRepositoryMock) Delete(Department *dao.Department) error {
+func (r *DepartmentRepositoryMock) Delete(ctx context.Context, Department *dao.Department) error {
 	return r.errorContainer["Delete"]
 }
 
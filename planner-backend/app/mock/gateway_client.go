@@ -0,0 +1,24 @@
+/* Mock file for the client of the api-gateway */
+package mock
+
+import "planner-backend/app/gateway"
+
+type GatewayClientMock struct {
+	// Error is returned by every call
+	Error error
+
+	// Saved contains the departments passed to SaveDepartment
+	Saved []gateway.Department
+	// Deleted contains the ids passed to DeleteDepartment
+	Deleted []string
+}
+
+func (g *GatewayClientMock) SaveDepartment(department gateway.Department) error {
+	g.Saved = append(g.Saved, department)
+	return g.Error
+}
+
+func (g *GatewayClientMock) DeleteDepartment(id string) error {
+	g.Deleted = append(g.Deleted, id)
+	return g.Error
+}
@@ -0,0 +1,30 @@
+package mock
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveRequestControllerMock struct {
+}
+
+func (m *LeaveRequestControllerMock) Create(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Create"})
+}
+
+func (m *LeaveRequestControllerMock) GetAllForPerson(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "GetAllForPerson"})
+}
+
+func (m *LeaveRequestControllerMock) GetAllForDepartment(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "GetAllForDepartment"})
+}
+
+func (m *LeaveRequestControllerMock) Approve(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Approve"})
+}
+
+func (m *LeaveRequestControllerMock) Reject(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "Reject"})
+}
@@ -0,0 +1,80 @@
+package mock
+
+import (
+	"context"
+	"planner-backend/app/domain/dao"
+)
+
+type LeaveRequestRepositoryMock struct {
+	dataContainer      map[string]interface{}
+	errorContainer     map[string]error
+	primedFunctionName string
+
+	// Decided contains the leave requests passed to Decide
+	Decided []dao.LeaveRequest
+}
+
+/* Mock interface implementations */
+func (r *LeaveRequestRepositoryMock) On(functionName string) Mock {
+	// set default value
+	r.dataContainer[functionName] = nil
+	r.errorContainer[functionName] = nil
+
+	// Set primed function name
+	r.primedFunctionName = functionName
+
+	return r
+}
+
+func (r *LeaveRequestRepositoryMock) Return(mockData interface{}, errorData error) Mock {
+	r.dataContainer[r.primedFunctionName] = mockData
+	r.errorContainer[r.primedFunctionName] = errorData
+
+	return r
+}
+
+/* Repostory interface implementations */
+func (r *LeaveRequestRepositoryMock) Save(ctx context.Context, request *dao.LeaveRequest) (dao.LeaveRequest, error) {
+	if r.dataContainer["Save"] == nil {
+		return dao.LeaveRequest{}, r.errorContainer["Save"]
+	}
+	return r.dataContainer["Save"].(dao.LeaveRequest), r.errorContainer["Save"]
+}
+
+func (r *LeaveRequestRepositoryMock) FindLeaveRequestByID(ctx context.Context, departmentID string, requestID string) (dao.LeaveRequest, error) {
+	if r.dataContainer["FindLeaveRequestByID"] == nil {
+		return dao.LeaveRequest{}, r.errorContainer["FindLeaveRequestByID"]
+	}
+	return r.dataContainer["FindLeaveRequestByID"].(dao.LeaveRequest), r.errorContainer["FindLeaveRequestByID"]
+}
+
+func (r *LeaveRequestRepositoryMock) FindLeaveRequestsForPerson(ctx context.Context, personID string) ([]dao.LeaveRequest, error) {
+	if r.dataContainer["FindLeaveRequestsForPerson"] == nil {
+		return nil, r.errorContainer["FindLeaveRequestsForPerson"]
+	}
+	return r.dataContainer["FindLeaveRequestsForPerson"].([]dao.LeaveRequest), r.errorContainer["FindLeaveRequestsForPerson"]
+}
+
+func (r *LeaveRequestRepositoryMock) FindLeaveRequestsForDepartment(ctx context.Context, departmentID string, status string) ([]dao.LeaveRequest, error) {
+	if r.dataContainer["FindLeaveRequestsForDepartment"] == nil {
+		return nil, r.errorContainer["FindLeaveRequestsForDepartment"]
+	}
+	return r.dataContainer["FindLeaveRequestsForDepartment"].([]dao.LeaveRequest), r.errorContainer["FindLeaveRequestsForDepartment"]
+}
+
+func (r *LeaveRequestRepositoryMock) Decide(ctx context.Context, request dao.LeaveRequest) error {
+	r.Decided = append(r.Decided, request)
+	return r.errorContainer["Decide"]
+}
+
+/**
+* Function to create new LeaveRequestRepositoryMock
+* @return LeaveRequestRepositoryMock
+ */
+
+func NewLeaveRequestRepositoryMock() *LeaveRequestRepositoryMock {
+	return &LeaveRequestRepositoryMock{
+		dataContainer:  make(map[string]interface{}),
+		errorContainer: make(map[string]error),
+	}
+}
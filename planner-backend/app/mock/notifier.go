@@ -0,0 +1,17 @@
+/* Mock file for the notifier */
+package mock
+
+import "planner-backend/app/notification"
+
+type NotifierMock struct {
+	// Error is returned by every call
+	Error error
+
+	// Events contains the events passed to Notify
+	Events []notification.Event
+}
+
+func (n *NotifierMock) Notify(event notification.Event) error {
+	n.Events = append(n.Events, event)
+	return n.Error
+}
@@ -1,6 +1,9 @@
 package mock
 
-import "planner-backend/app/domain/dao"
+import (
+	"context"
+	"planner-backend/app/domain/dao"
+)
 
 type PersonRelRepositoryMock struct {
 	dataContainer      map[string]interface{}
@@ -28,42 +31,42 @@ func (r *PersonRelRepositoryMock) Return(mockData interface{}, errorData error)
 }
 
 /* Repository interface implementations */
-func (r *PersonRelRepositoryMock) AddAbsencyToPerson(person dao.Person, absence dao.Absence) error {
+func (r *PersonRelRepositoryMock) AddAbsencyToPerson(ctx context.Context, person dao.Person, absence dao.Absence) error {
 	return r.errorContainer["AddAbsencyToPerson"]
 }
-func (r *PersonRelRepositoryMock) RemoveAbsencyFromPerson(person dao.Person, absency dao.Absence) error {
+func (r *PersonRelRepositoryMock) RemoveAbsencyFromPerson(ctx context.Context, person dao.Person, absency dao.Absence) error {
 	return r.errorContainer["RemoveAbsencyFromPerson"]
 }
-func (r *PersonRelRepositoryMock) FindAbsencyForPerson(personID string, date string) (dao.Absence, error) {
+func (r *PersonRelRepositoryMock) FindAbsencyForPerson(ctx context.Context, personID string, date string) (dao.Absence, error) {
 	if r.dataContainer["FindAbsencyForPerson"] == nil {
 		return dao.Absence{}, r.errorContainer["FindAbsencyForPerson"]
 	}
 	return r.dataContainer["FindAbsencyForPerson"].(dao.Absence), r.errorContainer["FindAbsencyForPerson"]
 }
 
-func (r *PersonRelRepositoryMock) FindAbsencyForPersonInRange(personID string, startDate string, endDate string) ([]dao.Absence, error) {
+func (r *PersonRelRepositoryMock) FindAbsencyForPersonInRange(ctx context.Context, personID string, startDate string, endDate string) ([]dao.Absence, error) {
 	if r.dataContainer["FindAbsencyForPersonInRange"] == nil {
 		return nil, r.errorContainer["FindAbsencyForPersonInRange"]
 	}
 	return r.dataContainer["FindAbsencyForPersonInRange"].([]dao.Absence), r.errorContainer["FindAbsencyForPersonInRange"]
 }
 
-func (r *PersonRelRepositoryMock) AddDepartmentToPerson(person dao.Person, departmentID string) error {
+func (r *PersonRelRepositoryMock) AddDepartmentToPerson(ctx context.Context, person dao.Person, departmentID string) error {
 	return r.errorContainer["AddDepartmentToPerson"]
 }
-func (r *PersonRelRepositoryMock) RemoveDepartmentFromPerson(person dao.Person, departmentID string) error {
+func (r *PersonRelRepositoryMock) RemoveDepartmentFromPerson(ctx context.Context, person dao.Person, departmentID string) error {
 	return r.errorContainer["RemoveDepartmentFromPerson"]
 }
-func (r *PersonRelRepositoryMock) AddWorkplaceToPerson(person dao.Person, departmentID string, workplaceID string) error {
+func (r *PersonRelRepositoryMock) AddWorkplaceToPerson(ctx context.Context, person dao.Person, departmentID string, workplaceID string) error {
 	return r.errorContainer["AddWorkplaceToPerson"]
 }
-func (r *PersonRelRepositoryMock) RemoveWorkplaceFromPerson(person dao.Person, departmentID string, workplaceID string) error {
+func (r *PersonRelRepositoryMock) RemoveWorkplaceFromPerson(ctx context.Context, person dao.Person, departmentID string, workplaceID string) error {
 	return r.errorContainer["RemoveWorkplaceFromPerson"]
 }
-func (r *PersonRelRepositoryMock) AddWeekdayToPerson(person dao.Person, weekdayID int64) error {
+func (r *PersonRelRepositoryMock) AddWeekdayToPerson(ctx context.Context, person dao.Person, weekdayID int64) error {
 	return r.errorContainer["AddWeekdayToPerson"]
 }
-func (r *PersonRelRepositoryMock) RemoveWeekdayFromPerson(person dao.Person, weekdayID int64) error {
+func (r *PersonRelRepositoryMock) RemoveWeekdayFromPerson(ctx context.Context, person dao.Person, weekdayID int64) error {
 	return r.errorContainer["RemoveWeekdayFromPerson"]
 }
 
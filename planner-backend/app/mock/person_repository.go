@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"planner-backend/app/domain/dao"
 )
 
@@ -30,21 +31,21 @@ func (r *PersonRepositoryMock) Return(mockData interface{}, errorData error) Moc
 }
 
 /* Repository interface implementations */
-func (r *PersonRepositoryMock) FindAllPersons(departmentID string) ([]dao.Person, error) {
+func (r *PersonRepositoryMock) FindAllPersons(ctx context.Context, departmentID string) ([]dao.Person, error) {
 	if r.dataContainer["FindAllPersons"] == nil {
 		return nil, r.errorContainer["FindAllPersons"]
 	}
 	return r.dataContainer["FindAllPersons"].([]dao.Person), r.errorContainer["FindAllPersons"]
 }
 
-func (r *PersonRepositoryMock) FindAllPersonsBy(departmentID string, workplaceID string, weekdayID string, notAbsentOn string) ([]dao.Person, error) {
+func (r *PersonRepositoryMock) FindAllPersonsBy(ctx context.Context, departmentID string, workplaceID string, weekdayID string, notAbsentOn string) ([]dao.Person, error) {
 	if r.dataContainer["FindAllPersonsBy"] == nil {
 		return nil, r.errorContainer["FindAllPersonsBy"]
 	}
 	return r.dataContainer["FindAllPersonsBy"].([]dao.Person), r.errorContainer["FindAllPersonsBy"]
 }
 
-func (r *PersonRepositoryMock) FindPersonByID(id string) (dao.Person, error) {
+func (r *PersonRepositoryMock) FindPersonByID(ctx context.Context, id string) (dao.Person, error) {
 	if r.dataContainer["FindPersonByID"] == nil {
 		return dao.Person{}, r.errorContainer["FindPersonByID"]
 	}
@@ -52,14 +53,14 @@ func (r *PersonRepositoryMock) FindPersonByID(id string) (dao.Person, error) {
 	return r.dataContainer["FindPersonByID"].(dao.Person), r.errorContainer["FindPersonByID"]
 }
 
-func (r *PersonRepositoryMock) Save(person *dao.Person) (dao.Person, error) {
+func (r *PersonRepositoryMock) Save(ctx context.Context, person *dao.Person) (dao.Person, error) {
 	if r.dataContainer["Save"] == nil {
 		return dao.Person{}, r.errorContainer["Save"]
 	}
 	return r.dataContainer["Save"].(dao.Person), r.errorContainer["Save"]
 }
 
-func (r *PersonRepositoryMock) Delete(person *dao.Person) error {
+func (r *PersonRepositoryMock) Delete(ctx context.Context, person *dao.Person) error {
 	return r.errorContainer["Delete"]
 }
 
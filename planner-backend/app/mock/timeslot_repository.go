@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"planner-backend/app/domain/dao"
 )
 
@@ -30,14 +31,14 @@ func (r *TimeslotRepositoryMock) Return(mockData interface{}, errorData error) M
 }
 
 /* Repository interface implementations */
-func (r *TimeslotRepositoryMock) FindAllTimeslots(departmentID string, workplaceID string) ([]dao.Timeslot, error) {
+func (r *TimeslotRepositoryMock) FindAllTimeslots(ctx context.Context, departmentID string, workplaceID string) ([]dao.Timeslot, error) {
 	if r.dataContainer["FindAllTimeslots"] == nil {
 		return nil, r.errorContainer["FindAllTimeslots"]
 	}
 	return r.dataContainer["FindAllTimeslots"].([]dao.Timeslot), r.errorContainer["FindAllTimeslots"]
 }
 
-func (r *TimeslotRepositoryMock) FindTimeslotByID(departmentID string, workplaceID string, timeslotID string) (dao.Timeslot, error) {
+func (r *TimeslotRepositoryMock) FindTimeslotByID(ctx context.Context, departmentID string, workplaceID string, timeslotID string) (dao.Timeslot, error) {
 	if r.dataContainer["FindTimeslotByID"] == nil {
 		return dao.Timeslot{}, r.errorContainer["FindTimeslotByID"]
 	}
@@ -45,14 +46,14 @@ func (r *TimeslotRepositoryMock) FindTimeslotByID(departmentID string, workplace
 	return r.dataContainer["FindTimeslotByID"].(dao.Timeslot), r.errorContainer["FindTimeslotByID"]
 }
 
-func (r *TimeslotRepositoryMock) Save(departmentID string, workplaceID string, timeslot *dao.Timeslot) (dao.Timeslot, error) {
+func (r *TimeslotRepositoryMock) Save(ctx context.Context, departmentID string, workplaceID string, timeslot *dao.Timeslot) (dao.Timeslot, error) {
 	if r.dataContainer["Save"] == nil {
 		return dao.Timeslot{}, r.errorContainer["Save"]
 	}
 	return r.dataContainer["Save"].(dao.Timeslot), r.errorContainer["Save"]
 }
 
-func (r *TimeslotRepositoryMock) Delete(departmentID string, workplaceID string, timeslot *dao.Timeslot) error {
+func (r *TimeslotRepositoryMock) Delete(ctx context.Context, departmentID string, workplaceID string, timeslot *dao.Timeslot) error {
 	return r.errorContainer["Delete"]
 }
 
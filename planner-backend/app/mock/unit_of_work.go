@@ -0,0 +1,20 @@
+/* Mock file for the unit of work */
+package mock
+
+import "context"
+
+type UnitOfWorkMock struct {
+	// Reads and Writes count the units of work run in each mode
+	Reads  int
+	Writes int
+}
+
+func (u *UnitOfWorkMock) Read(ctx context.Context, work func(ctx context.Context) error) error {
+	u.Reads++
+	return work(ctx)
+}
+
+func (u *UnitOfWorkMock) Write(ctx context.Context, work func(ctx context.Context) error) error {
+	u.Writes++
+	return work(ctx)
+}
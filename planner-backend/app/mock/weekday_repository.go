@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"planner-backend/app/domain/dao"
 )
 
@@ -30,29 +31,29 @@ func (r *WeekdayRepositoryMock) Return(mockData interface{}, errorData error) Mo
 }
 
 /* Repository interface implementations */
-func (r *WeekdayRepositoryMock) AddWeekdaysToTimeslot(timeslot *dao.Timeslot, weekdays []dao.OnWeekday) ([]dao.OnWeekday, error) {
+func (r *WeekdayRepositoryMock) AddWeekdaysToTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekdays []dao.OnWeekday) ([]dao.OnWeekday, error) {
 	if r.dataContainer["AddWeekdaysToTimeslot"] == nil {
 		return nil, r.errorContainer["AddWeekdaysToTimeslot"]
 	}
 	return r.dataContainer["AddWeekdaysToTimeslot"].([]dao.OnWeekday), r.errorContainer["AddWeekdaysToTimeslot"]
 }
 
-func (r *WeekdayRepositoryMock) DeleteAllWeekdaysFromTimeslot(timeslot *dao.Timeslot) error {
+func (r *WeekdayRepositoryMock) DeleteAllWeekdaysFromTimeslot(ctx context.Context, timeslot *dao.Timeslot) error {
 	return r.errorContainer["DeleteAllWeekdaysFromTimeslot"]
 }
 
-func (r *WeekdayRepositoryMock) AddWeekdayToTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
+func (r *WeekdayRepositoryMock) AddWeekdayToTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
 	if r.dataContainer["AddWeekdayToTimeslot"] == nil {
 		return nil, r.errorContainer["AddWeekdayToTimeslot"]
 	}
 	return r.dataContainer["AddWeekdayToTimeslot"].([]dao.OnWeekday), r.errorContainer["AddWeekdayToTimeslot"]
 }
 
-func (r *WeekdayRepositoryMock) DeleteWeekdayFromTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) error {
+func (r *WeekdayRepositoryMock) DeleteWeekdayFromTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) error {
 	return r.errorContainer["DeleteWeekdayFromTimeslot"]
 }
 
-func (r *WeekdayRepositoryMock) UpdateWeekdayForTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
+func (r *WeekdayRepositoryMock) UpdateWeekdayForTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
 	if r.dataContainer["UpdateWeekdayForTimeslot"] == nil {
 		return nil, r.errorContainer["UpdateWeekdayForTimeslot"]
 	}
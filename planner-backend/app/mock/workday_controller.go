@@ -28,3 +28,11 @@ func (m *WorkdayControllerMock) AssignPersonToWorkday(ctx *gin.Context) {
 func (m *WorkdayControllerMock) UnassignPersonFromWorkday(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "UnassignPersonFromWorkday"})
 }
+
+func (m *WorkdayControllerMock) GetWorkdaysForPerson(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "GetWorkdaysForPerson"})
+}
+
+func (m *WorkdayControllerMock) GetHoursForPerson(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"message": "GetHoursForPerson"})
+}
@@ -1,6 +1,9 @@
 package mock
 
-import "planner-backend/app/domain/dao"
+import (
+	"context"
+	"planner-backend/app/domain/dao"
+)
 
 type WorkdayRepositoryMock struct {
 	dataContainer      map[string]interface{}
@@ -28,29 +31,36 @@ func (r *WorkdayRepositoryMock) Return(mockData interface{}, errorData error) Mo
 }
 
 /* Repostory interface implementations */
-func (r *WorkdayRepositoryMock) GetWorkdaysForDepartmentAndDate(departmentID string, date string, isActive bool) ([]dao.Workday, error) {
+func (r *WorkdayRepositoryMock) GetWorkdaysForDepartmentAndDate(ctx context.Context, departmentID string, date string, isActive bool) ([]dao.Workday, error) {
 	if r.dataContainer["GetWorkdaysForDepartmentAndDate"] == nil {
 		return nil, r.errorContainer["GetWorkdaysForDepartmentAndDate"]
 	}
 	return r.dataContainer["GetWorkdaysForDepartmentAndDate"].([]dao.Workday), r.errorContainer["GetWorkdaysForDepartmentAndDate"]
 }
 
-func (r *WorkdayRepositoryMock) GetWorkday(departmentID string, workplaceID string, timeslotID string, date string) (dao.Workday, error) {
+func (r *WorkdayRepositoryMock) GetWorkday(ctx context.Context, departmentID string, workplaceID string, timeslotID string, date string) (dao.Workday, error) {
 	if r.dataContainer["GetWorkday"] == nil {
 		return dao.Workday{}, r.errorContainer["GetWorkday"]
 	}
 	return r.dataContainer["GetWorkday"].(dao.Workday), r.errorContainer["GetWorkday"]
 }
 
-func (r *WorkdayRepositoryMock) Save(wd *dao.Workday) error {
+func (r *WorkdayRepositoryMock) GetWorkdaysForPerson(ctx context.Context, personID string, startDate string, endDate string) ([]dao.Workday, error) {
+	if r.dataContainer["GetWorkdaysForPerson"] == nil {
+		return nil, r.errorContainer["GetWorkdaysForPerson"]
+	}
+	return r.dataContainer["GetWorkdaysForPerson"].([]dao.Workday), r.errorContainer["GetWorkdaysForPerson"]
+}
+
+func (r *WorkdayRepositoryMock) Save(ctx context.Context, wd *dao.Workday) error {
 	return r.errorContainer["Save"]
 }
 
-func (r *WorkdayRepositoryMock) AssignPersonToWorkday(personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
+func (r *WorkdayRepositoryMock) AssignPersonToWorkday(ctx context.Context, personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
 	return r.errorContainer["AssignPersonToWorkday"]
 }
 
-func (r *WorkdayRepositoryMock) UnassignPersonFromWorkday(personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
+func (r *WorkdayRepositoryMock) UnassignPersonFromWorkday(ctx context.Context, personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
 	return r.errorContainer["UnassignPersonFromWorkday"]
 }
 
@@ -1,6 +1,7 @@
 package mock
 
 import (
+	"context"
 	"planner-backend/app/domain/dao"
 )
 
@@ -30,14 +31,14 @@ func (r *WorkplaceRepositoryMock) Return(mockData interface{}, errorData error)
 }
 
 /* Repostory interface implementations */
-func (r *WorkplaceRepositoryMock) FindAllWorkplaces(departmentID string) ([]dao.Workplace, error) {
+func (r *WorkplaceRepositoryMock) FindAllWorkplaces(ctx context.Context, departmentID string) ([]dao.Workplace, error) {
 	if r.dataContainer["FindAllWorkplaces"] == nil {
 		return nil, r.errorContainer["FindAllWorkplaces"]
 	}
 	return r.dataContainer["FindAllWorkplaces"].([]dao.Workplace), r.errorContainer["FindAllWorkplaces"]
 }
 
-func (r *WorkplaceRepositoryMock) FindWorkplaceByID(departmentID string, workplaceID string) (dao.Workplace, error) {
+func (r *WorkplaceRepositoryMock) FindWorkplaceByID(ctx context.Context, departmentID string, workplaceID string) (dao.Workplace, error) {
 	if r.dataContainer["FindWorkplaceByID"] == nil {
 		return dao.Workplace{}, r.errorContainer["FindWorkplaceByID"]
 	}
@@ -45,14 +46,14 @@ func (r *WorkplaceRepositoryMock) FindWorkplaceByID(departmentID string, workpla
 	return r.dataContainer["FindWorkplaceByID"].(dao.Workplace), r.errorContainer["FindWorkplaceByID"]
 }
 
-func (r *WorkplaceRepositoryMock) Save(departmentID string, Workplace *dao.Workplace) (dao.Workplace, error) {
+func (r *WorkplaceRepositoryMock) Save(ctx context.Context, departmentID string, Workplace *dao.Workplace) (dao.Workplace, error) {
 	if r.dataContainer["Save"] == nil {
 		return dao.Workplace{}, r.errorContainer["Save"]
 	}
 	return r.dataContainer["Save"].(dao.Workplace), r.errorContainer["Save"]
 }
 
-func (r *WorkplaceRepositoryMock) Delete(departmentID string, Workplace *dao.Workplace) error {
+func (r *WorkplaceRepositoryMock) Delete(ctx context.Context, departmentID string, Workplace *dao.Workplace) error {
 	return r.errorContainer["Delete"]
 }
 
@@ -0,0 +1,81 @@
+/**
+* This package notifies other systems about decisions taken in the planner-backend, e.g. on leave requests.
+* The events are posted as JSON to the webhook in PLANNER_NOTIFICATION_WEBHOOK_URL,
+* without the variable they are only logged.
+**/
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Events of a leave request
+const (
+	LeaveRequestApproved = "leave_request.approved"
+	LeaveRequestRejected = "leave_request.rejected"
+)
+
+type Event struct {
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type Notifier interface {
+	Notify(event Event) error
+}
+
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func NewNotifierForURL(url string) Notifier {
+	/**
+	* Creates the notifier for the webhook in PLANNER_NOTIFICATION_WEBHOOK_URL.
+	* Without a url the events are only logged.
+	**/
+	if url == "" {
+		slog.Info("PLANNER_NOTIFICATION_WEBHOOK_URL is not set, notifications are only logged")
+		return LogNotifier{}
+	}
+	return NewWebhookNotifier(url)
+}
+
+func (w *WebhookNotifier) Notify(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify %s: unexpected status code %d", event.Type, resp.StatusCode)
+	}
+	return nil
+}
+
+// LogNotifier only logs the events, used when no webhook is configured
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(event Event) error {
+	slog.Info("Notification", "type", event.Type, "data", event.Data)
+	return nil
+}
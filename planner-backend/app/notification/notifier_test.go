@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	/* Test that the events are posted as JSON to the webhook */
+	received := []map[string]interface{}{}
+	status := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected a JSON post, got %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = append(received, body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	event := Event{Type: LeaveRequestApproved, OccurredAt: time.Now(), Data: map[string]string{"id": "1"}}
+	if err := notifier.Notify(event); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(received) != 1 || received[0]["type"] != LeaveRequestApproved {
+		t.Fatalf("Expected the event to be posted, got %v", received)
+	}
+	if data, ok := received[0]["data"].(map[string]interface{}); !ok || data["id"] != "1" {
+		t.Errorf("Expected the data of the event, got %v", received[0]["data"])
+	}
+
+	status = http.StatusInternalServerError
+	if err := notifier.Notify(event); err == nil {
+		t.Errorf("Expected an error for a rejected notification")
+	}
+}
@@ -1,62 +1,98 @@
 package pkg
 
 import (
-	"errors"
-	"fmt"
+	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
-	"planner-backend/app/constant"
+	"planner-backend/app/domain/dto"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func PanicException_(key string, message string) {
-	err := errors.New(message)
-	err = fmt.Errorf("%s: %w", key, err)
+const ProblemContentType = "application/problem+json"
+
+func Abort(c *gin.Context, err error) {
+	/**
+	* Records the error of the request and stops the following handlers.
+	* The caller has to return afterwards, the ErrorHandler middleware renders the error.
+	**/
+	_ = c.Error(err)
+	c.Abort()
+}
+
+func RenderErrors(c *gin.Context) {
+	/* Renders the last recorded error of the request, unless a response was written already */
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+	WriteError(c.Writer, c.Request, c.Errors.Last().Err)
+}
+
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	/**
+	* Answers a request with the error. Clients which accept application/problem+json receive
+	* a problem (RFC 7807), all others the envelope of the API with the details in errors.
+	* Errors which are no application errors are answered as unknown errors, their cause is only logged.
+	**/
+	appErr := AsError(err)
+	logError(r, appErr)
+
+	details := appErr.Details
+	if details == nil {
+		details = []dto.ErrorDetail{}
+	}
+
+	var body any
+	if acceptsProblem(r) {
+		w.Header().Set("Content-Type", ProblemContentType)
+		body = dto.Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(appErr.Status()),
+			Status:   appErr.Status(),
+			Detail:   appErr.Message,
+			Instance: requestPath(r),
+			Code:     appErr.Code,
+			Errors:   details,
+		}
+	} else {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		body = dto.APIErrorResponse{
+			ResponseKey:     appErr.Key.GetResponseStatus(),
+			ResponseMessage: appErr.Message,
+			Code:            appErr.Code,
+			Data:            Null(),
+			Errors:          details,
+		}
+	}
+
+	encoded, err := json.Marshal(body)
 	if err != nil {
-		panic(err)
+		slog.Error("Could not encode the error response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
+	w.WriteHeader(appErr.Status())
+	_, _ = w.Write(encoded)
 }
 
-func PanicException(responseKey constant.ResponseStatus) {
-	PanicException_(responseKey.GetResponseStatus(), responseKey.GetResponseMessage())
+func logError(r *http.Request, appErr *Error) {
+	// errors of the client are expected, only failures of the service are logged as errors
+	level := slog.LevelInfo
+	if appErr.Status() >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	slog.Log(context.Background(), level, "Request failed", "path", requestPath(r), "status", appErr.Status(), "code", appErr.Code, "message", appErr.Message, "error", appErr.Err)
 }
 
-func PanicHandler(ctx *gin.Context) {
-	if err := recover(); err != nil {
-		// get error message
-		str := fmt.Sprint(err)
-		strArr := strings.Split(str, ":")
-		key := strArr[0]
-		msg := strings.Trim(strArr[1], " ")
-
-		// log error
-		slog.Error("Error when executing program", "error", err)
-
-		// handle error
-		switch key {
-		case
-			constant.DataNotFound.GetResponseStatus():
-			ctx.JSON(http.StatusNotFound, BuildResponse_(key, msg, Null()))
-			ctx.Abort()
-		case
-			constant.Unauthorized.GetResponseStatus():
-			ctx.JSON(http.StatusUnauthorized, BuildResponse_(key, msg, Null()))
-			ctx.Abort()
-		case
-			constant.InvalidRequest.GetResponseStatus():
-			ctx.JSON(http.StatusBadRequest, BuildResponse_(key, msg, Null()))
-			ctx.Abort()
-		case constant.Conflict.GetResponseStatus():
-			ctx.JSON(http.StatusConflict, BuildResponse_(key, msg, Null()))
-			ctx.Abort()
-		case constant.UnknownError.GetResponseStatus():
-			ctx.JSON(http.StatusInternalServerError, BuildResponse_(key, msg, Null()))
-			ctx.Abort()
-		default:
-			ctx.JSON(http.StatusInternalServerError, BuildResponse_(key, msg, Null()))
-			ctx.Abort()
-		}
+func acceptsProblem(r *http.Request) bool {
+	return r != nil && strings.Contains(r.Header.Get("Accept"), ProblemContentType)
+}
+
+func requestPath(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
 	}
+	return r.URL.Path
 }
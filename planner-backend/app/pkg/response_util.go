@@ -1,11 +1,8 @@
 package pkg
 
 import (
-	"net/http"
 	"planner-backend/app/constant"
 	"planner-backend/app/domain/dto"
-
-	"github.com/gin-gonic/gin"
 )
 
 func Null() interface{} {
@@ -30,14 +27,3 @@ func BuildResponse_[T any](status string, message string, data T) dto.APIRespons
 		Data:            data,
 	}
 }
-
-func SendResponse[T any](ctx *gin.Context, responseStatus constant.ResponseStatus, data T) {
-	/** This function is used to send response */
-
-	switch responseStatus.GetResponseStatus() {
-	case constant.InvalidRequest.GetResponseStatus():
-		ctx.JSON(http.StatusBadRequest, BuildResponse(responseStatus, data))
-	default:
-		ctx.JSON(http.StatusInternalServerError, BuildResponse(responseStatus, data))
-	}
-}
@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"planner-backend/app/constant"
+	"planner-backend/app/domain/dto"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// the fields in the details are named like the keys of the request
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+func ValidationError(err error) *Error {
+	/**
+	* Translates an error of the binding of a request into an invalid request.
+	* Failed validations and values of the wrong type are reported per field.
+	**/
+	appErr := NewError(constant.InvalidRequest).Wrap(err)
+
+	var validationErrors validator.ValidationErrors
+	var typeError *json.UnmarshalTypeError
+	var syntaxError *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrors):
+		appErr.WithCode("validation_failed").WithMessage("Invalid Request: Some fields are invalid")
+		for _, fieldError := range validationErrors {
+			appErr.WithDetails(dto.ErrorDetail{
+				Field:   fieldName(fieldError),
+				Code:    fieldError.Tag(),
+				Message: validationMessage(fieldError),
+			})
+		}
+	case errors.As(err, &typeError):
+		appErr.WithCode("validation_failed").WithMessage("Invalid Request: Some fields are invalid")
+		appErr.WithDetails(dto.ErrorDetail{
+			Field:   typeError.Field,
+			Code:    "type",
+			Message: "must be of type " + typeError.Type.String(),
+		})
+	case errors.As(err, &syntaxError), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		appErr.WithMessage("Invalid Request: The body is not valid JSON")
+	}
+
+	return appErr
+}
+
+func fieldName(fieldError validator.FieldError) string {
+	// the namespace starts with the name of the struct, e.g. UserRequest.email
+	_, name, found := strings.Cut(fieldError.Namespace(), ".")
+	if !found {
+		return fieldError.Field()
+	}
+	return name
+}
+
+func validationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return "is required"
+	case "email":
+		return "must be an email address"
+	case "min", "gte":
+		return "must be at least " + fieldError.Param()
+	case "max", "lte":
+		return "must be at most " + fieldError.Param()
+	case "len":
+		return "must have a length of " + fieldError.Param()
+	case "oneof":
+		return "must be one of " + fieldError.Param()
+	default:
+		return fmt.Sprintf("failed the %s validation", fieldError.Tag())
+	}
+}
@@ -0,0 +1,26 @@
+/* Here there are the providers of the components configured by the config of the planner-backend */
+package app
+
+import (
+	"planner-backend/app/gateway"
+	"planner-backend/app/jwks"
+	"planner-backend/app/notification"
+	"planner-backend/app/repository"
+	"planner-backend/config"
+)
+
+func newTokenKeySet(c config.Config) *jwks.KeySet {
+	return jwks.NewKeySetForURL(c.JWKSURL)
+}
+
+func newGatewayClient(c config.Config) gateway.Client {
+	return gateway.NewClientForTarget(c.GatewayTarget)
+}
+
+func newNotifier(c config.Config) notification.Notifier {
+	return notification.NewNotifierForURL(c.NotificationWebhookURL)
+}
+
+func newRepositoryTimeouts(c config.Config) repository.Timeouts {
+	return repository.Timeouts{Read: c.Database.ReadTimeout, Write: c.Database.WriteTimeout}
+}
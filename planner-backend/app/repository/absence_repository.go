@@ -10,21 +10,24 @@ import (
 )
 
 type AbsenceRepository interface {
-	FindAllAbsencies(departmentID string, date string) ([]dao.Absence, error)
+	FindAllAbsencies(ctx context.Context, departmentID string, date string) ([]dao.Absence, error)
 }
 
 type AbsenceRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (a AbsenceRepositoryImpl) FindAllAbsencies(departmentID string, date string) ([]dao.Absence, error) {
+func (a AbsenceRepositoryImpl) FindAllAbsencies(ctx context.Context, departmentID string, date string) ([]dao.Absence, error) {
 	/* FindAllAbsencies is a function to get all absencies for a given department and date
 	 * @param departmentID is the department id
 	 * @param date is the date
 	 * @return []dao.Absence, error
 	 */
 
+	ctx, cancel := a.timeouts.read(ctx)
+	defer cancel()
+
 	query := `
     MATCH (d: Department {id: $departmentID}) <-[:WORKS_AT]- (p: Person) -[r:ABSENT_ON]-> (date: Date {date: date($date)})
     RETURN p.id, date.date, r`
@@ -33,13 +36,7 @@ func (a AbsenceRepositoryImpl) FindAllAbsencies(departmentID string, date string
 		"date":         date,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		a.ctx,
-		*a.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, a.db, "AbsenceRepository", "FindAllAbsencies", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -72,10 +69,10 @@ func (a AbsenceRepositoryImpl) FindAllAbsencies(departmentID string, date string
 	return absences, nil
 }
 
-func AbsenceRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) AbsenceRepositoryImpl {
+func AbsenceRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) AbsenceRepositoryImpl {
 	return AbsenceRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
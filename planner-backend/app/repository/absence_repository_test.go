@@ -92,12 +92,10 @@ func TestGetAllAbsenciesFromDepartment(t *testing.T) {
 	departmentCreator.Create(db, ctx)
 
 	p := PersonRelRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	a := AbsenceRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 
 	type AbsencyToCreate struct {
@@ -226,7 +224,7 @@ func TestGetAllAbsenciesFromDepartment(t *testing.T) {
 				person := dao.Person{
 					ID: absency.PersonID,
 				}
-				if err := p.AddAbsencyToPerson(person, dao.Absence{
+				if err := p.AddAbsencyToPerson(ctx, person, dao.Absence{
 					Date:   absency.Date,
 					Reason: absency.Reason,
 				}); err != nil {
@@ -234,7 +232,7 @@ func TestGetAllAbsenciesFromDepartment(t *testing.T) {
 				}
 			}
 
-			absencies, err := a.FindAllAbsencies(test.departmentID, test.date)
+			absencies, err := a.FindAllAbsencies(ctx, test.departmentID, test.date)
 			if err != nil && !test.expectedError {
 				t.Errorf("Expected no error, got %v", err)
 			}
@@ -11,20 +11,23 @@ import (
 
 type DepartmentRepository interface {
 	// Function Used by the service
-	FindAllDepartments() ([]dao.Department, error)
-	FindDepartmentByID(id string) (dao.Department, error)
-	Save(department *dao.Department) (dao.Department, error)
-	Delete(department *dao.Department) error
+	FindAllDepartments(ctx context.Context) ([]dao.Department, error)
+	FindDepartmentByID(ctx context.Context, id string) (dao.Department, error)
+	Save(ctx context.Context, department *dao.Department) (dao.Department, error)
+	Delete(ctx context.Context, department *dao.Department) error
 }
 
 type DepartmentRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (d DepartmentRepositoryImpl) FindAllDepartments() ([]dao.Department, error) {
+func (d DepartmentRepositoryImpl) FindAllDepartments(ctx context.Context) ([]dao.Department, error) {
 	/* Returns all departments */
 
+	ctx, cancel := d.timeouts.read(ctx)
+	defer cancel()
+
 	/*
 	* Query to fetch additional data from the database:
 	*MATCH (d:Department) -[:HAS_WORKPLACE]-> (w:Workplace) -[:HAS_TIMESLOT]-> (t:Timeslot) -[r:OFFERED_ON]-> (wd:Weekday)
@@ -55,13 +58,7 @@ func (d DepartmentRepositoryImpl) FindAllDepartments() ([]dao.Department, error)
 	WHERE d.deleted_at IS NULL
 	RETURN d`
 
-	result, err := neo4j.ExecuteQuery(
-		d.ctx,
-		*d.db,
-		query,
-		nil,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, d.db, "DepartmentRepository", "FindAllDepartments", query, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -78,9 +75,12 @@ func (d DepartmentRepositoryImpl) FindAllDepartments() ([]dao.Department, error)
 	return departments, nil
 }
 
-func (d DepartmentRepositoryImpl) FindDepartmentByID(id string) (dao.Department, error) {
+func (d DepartmentRepositoryImpl) FindDepartmentByID(ctx context.Context, id string) (dao.Department, error) {
 	/* Returns a department by name */
 
+	ctx, cancel := d.timeouts.read(ctx)
+	defer cancel()
+
 	department := dao.Department{}
 	query := `
 	MATCH (d:Department {id: $id})
@@ -90,13 +90,7 @@ func (d DepartmentRepositoryImpl) FindDepartmentByID(id string) (dao.Department,
 		"id": id,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		d.ctx,
-		*d.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, d.db, "DepartmentRepository", "FindDepartmentByID", query, params)
 	if err != nil {
 		return department, err
 	}
@@ -111,9 +105,12 @@ func (d DepartmentRepositoryImpl) FindDepartmentByID(id string) (dao.Department,
 	return department, nil
 }
 
-func (d DepartmentRepositoryImpl) Save(department *dao.Department) (dao.Department, error) {
+func (d DepartmentRepositoryImpl) Save(ctx context.Context, department *dao.Department) (dao.Department, error) {
 	/* Creates a department */
 
+	ctx, cancel := d.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MERGE (d:Department {id: $id})
 	ON CREATE SET
@@ -131,13 +128,7 @@ func (d DepartmentRepositoryImpl) Save(department *dao.Department) (dao.Departme
 		"name": department.Name,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		d.ctx,
-		*d.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, d.db, "DepartmentRepository", "Save", query, params)
 	if err != nil {
 		return *department, err
 	}
@@ -152,9 +143,12 @@ func (d DepartmentRepositoryImpl) Save(department *dao.Department) (dao.Departme
 	return *department, nil
 }
 
-func (d DepartmentRepositoryImpl) Delete(department *dao.Department) error {
+func (d DepartmentRepositoryImpl) Delete(ctx context.Context, department *dao.Department) error {
 	/* Deletes a department */
 
+	ctx, cancel := d.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (d:Department)
 	WHERE d.id = $id
@@ -163,13 +157,7 @@ func (d DepartmentRepositoryImpl) Delete(department *dao.Department) error {
 		"id": department.ID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		d.ctx,
-		*d.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, d.db, "DepartmentRepository", "Delete", query, params)
 	if err != nil {
 		return err
 	}
@@ -177,10 +165,10 @@ func (d DepartmentRepositoryImpl) Delete(department *dao.Department) error {
 	return nil
 }
 
-func DepartmentRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *DepartmentRepositoryImpl {
+func DepartmentRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *DepartmentRepositoryImpl {
 	return &DepartmentRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
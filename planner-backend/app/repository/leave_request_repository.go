@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"planner-backend/app/domain/dao"
+	"planner-backend/app/pkg"
+
+	"github.com/google/wire"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+type LeaveRequestRepository interface {
+	Save(ctx context.Context, request *dao.LeaveRequest) (dao.LeaveRequest, error)
+	FindLeaveRequestByID(ctx context.Context, departmentID string, requestID string) (dao.LeaveRequest, error)
+	FindLeaveRequestsForPerson(ctx context.Context, personID string) ([]dao.LeaveRequest, error)
+	FindLeaveRequestsForDepartment(ctx context.Context, departmentID string, status string) ([]dao.LeaveRequest, error)
+	Decide(ctx context.Context, request dao.LeaveRequest) error
+}
+
+type LeaveRequestRepositoryImpl struct {
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
+}
+
+func (l LeaveRequestRepositoryImpl) Save(ctx context.Context, request *dao.LeaveRequest) (dao.LeaveRequest, error) {
+	/* Files a new pending leave request for a person
+	   @param request: The leave request, the id is generated
+	*/
+
+	ctx, cancel := l.timeouts.write(ctx)
+	defer cancel()
+
+	query := `
+	MATCH (p: Person {id: $personID})
+	CREATE (p) -[:REQUESTED]-> (lr: LeaveRequest {
+		id: randomUUID(),
+		start_date: date($startDate),
+		end_date: date($endDate),
+		reason: $reason,
+		status: $status,
+		requested_by: $requestedBy,
+		created_at: datetime()
+	})
+	RETURN lr, p.id AS personID`
+	params := map[string]interface{}{
+		"personID":    request.PersonID,
+		"startDate":   request.StartDate,
+		"endDate":     request.EndDate,
+		"reason":      request.Reason,
+		"status":      dao.LeaveRequestPending,
+		"requestedBy": request.RequestedBy,
+	}
+
+	result, err := executeWrite(ctx, l.db, "LeaveRequestRepository", "Save", query, params)
+	if err != nil {
+		return dao.LeaveRequest{}, err
+	}
+
+	if len(result.Records) == 0 {
+		return dao.LeaveRequest{}, pkg.ErrNoRows
+	}
+
+	if err := request.ParseFromDBRecord(result.Records[0]); err != nil {
+		return dao.LeaveRequest{}, err
+	}
+
+	return *request, nil
+}
+
+func (l LeaveRequestRepositoryImpl) FindLeaveRequestByID(ctx context.Context, departmentID string, requestID string) (dao.LeaveRequest, error) {
+	/* Finds a leave request of a person working at the department
+	   @param departmentID: The department the request is decided in
+	   @param requestID: The ID of the leave request
+	*/
+
+	ctx, cancel := l.timeouts.read(ctx)
+	defer cancel()
+
+	query := `
+	MATCH (d: Department {id: $departmentID}) <-[:WORKS_AT]- (p: Person) -[:REQUESTED]-> (lr: LeaveRequest {id: $requestID})
+	RETURN lr, p.id AS personID`
+	params := map[string]interface{}{
+		"departmentID": departmentID,
+		"requestID":    requestID,
+	}
+
+	result, err := executeRead(ctx, l.db, "LeaveRequestRepository", "FindLeaveRequestByID", query, params)
+	if err != nil {
+		return dao.LeaveRequest{}, err
+	}
+
+	if len(result.Records) == 0 {
+		return dao.LeaveRequest{}, pkg.ErrNoRows
+	}
+
+	request := dao.LeaveRequest{}
+	if err := request.ParseFromDBRecord(result.Records[0]); err != nil {
+		return dao.LeaveRequest{}, err
+	}
+
+	return request, nil
+}
+
+func (l LeaveRequestRepositoryImpl) FindLeaveRequestsForPerson(ctx context.Context, personID string) ([]dao.LeaveRequest, error) {
+	/* Finds all leave requests of a person, the newest first
+	   @param personID: The ID of the person
+	*/
+
+	ctx, cancel := l.timeouts.read(ctx)
+	defer cancel()
+
+	query := `
+	MATCH (p: Person {id: $personID}) -[:REQUESTED]-> (lr: LeaveRequest)
+	RETURN lr, p.id AS personID
+	ORDER BY lr.created_at DESC`
+	params := map[string]interface{}{
+		"personID": personID,
+	}
+
+	return l.findAll(ctx, "FindLeaveRequestsForPerson", query, params)
+}
+
+func (l LeaveRequestRepositoryImpl) FindLeaveRequestsForDepartment(ctx context.Context, departmentID string, status string) ([]dao.LeaveRequest, error) {
+	/* Finds the leave requests of the persons working at a department, the oldest first
+	   @param departmentID: The ID of the department
+	   @param status: Only requests in this status, all requests if empty
+	*/
+
+	ctx, cancel := l.timeouts.read(ctx)
+	defer cancel()
+
+	query := `
+	MATCH (d: Department {id: $departmentID}) <-[:WORKS_AT]- (p: Person) -[:REQUESTED]-> (lr: LeaveRequest)
+	WHERE $status = "" OR lr.status = $status
+	RETURN lr, p.id AS personID
+	ORDER BY lr.start_date, lr.created_at`
+	params := map[string]interface{}{
+		"departmentID": departmentID,
+		"status":       status,
+	}
+
+	return l.findAll(ctx, "FindLeaveRequestsForDepartment", query, params)
+}
+
+func (l LeaveRequestRepositoryImpl) Decide(ctx context.Context, request dao.LeaveRequest) error {
+	/* Stores the decision on a leave request
+	   An approved request marks the person absent on every date of the request and releases the assignments on these dates,
+	   absences entered before keep their reason, a rejected request removes the absences created by an earlier approval
+	   @param request: The leave request with the status, decided_by and comment of the decision
+	*/
+
+	ctx, cancel := l.timeouts.write(ctx)
+	defer cancel()
+
+	dates := request.Dates()
+	query := `
+	MATCH (p: Person {id: $personID}) -[:REQUESTED]-> (lr: LeaveRequest {id: $requestID})
+	SET lr.status = $status, lr.decided_by = $decidedBy, lr.decided_at = datetime(), lr.comment = $comment
+	WITH p, lr
+	OPTIONAL MATCH (p) -[r:ABSENT_ON {leave_request: lr.id}]-> (:Date)
+	DELETE r
+	`
+	if request.Status == dao.LeaveRequestApproved {
+		// Ensure that the dates exist
+		for _, date := range dates {
+			if err := EnsureDateExists(l.db, ctx, date, "LeaveRequestRepository", "Decide"); err != nil {
+				return err
+			}
+		}
+
+		query = `
+		MATCH (p: Person {id: $personID}) -[:REQUESTED]-> (lr: LeaveRequest {id: $requestID})
+		SET lr.status = $status, lr.decided_by = $decidedBy, lr.decided_at = datetime(), lr.comment = $comment
+		WITH p, lr
+		UNWIND $dates AS absentDate
+		MATCH (d: Date {date: date(absentDate)})
+		MERGE (p) -[r:ABSENT_ON]-> (d)
+		ON CREATE SET r.created_at = datetime(), r.leave_request = lr.id, r.reason = $reason
+		WITH d, p
+		OPTIONAL MATCH (d) <-[:IS_DATE]- (wkd: Workday) <-[a:ASSIGNED_TO]- (p)
+		DELETE a
+		`
+	}
+
+	params := map[string]interface{}{
+		"personID":  request.PersonID,
+		"requestID": request.ID,
+		"status":    request.Status,
+		"decidedBy": request.DecidedBy,
+		"comment":   request.Comment,
+		"reason":    request.Reason,
+		"dates":     dates,
+	}
+
+	_, err := executeWrite(ctx, l.db, "LeaveRequestRepository", "Decide", query, params)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (l LeaveRequestRepositoryImpl) findAll(ctx context.Context, method string, query string, params map[string]interface{}) ([]dao.LeaveRequest, error) {
+	/* Helper to run a query returning leave requests */
+	result, err := executeRead(ctx, l.db, "LeaveRequestRepository", method, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]dao.LeaveRequest, 0)
+	for _, record := range result.Records {
+		request := dao.LeaveRequest{}
+		if err := request.ParseFromDBRecord(record); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func LeaveRequestRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) LeaveRequestRepositoryImpl {
+	return LeaveRequestRepositoryImpl{
+		db:       db,
+		timeouts: timeouts,
+	}
+}
+
+var leaveRequestRepositorySet = wire.NewSet(
+	LeaveRequestRepositoryInit,
+	wire.Bind(new(LeaveRequestRepository), new(LeaveRequestRepositoryImpl)),
+)
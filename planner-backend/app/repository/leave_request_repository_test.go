@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"planner-backend/app/domain/dao"
+	"planner-backend/app/pkg"
+	"testing"
+)
+
+func TestDecideLeaveRequest(t *testing.T) {
+	// Create a new test database instance
+	ctx, cancel := context.WithCancel(context.Background())
+	db, err := NewTestDBInstance(ctx)
+	if err != nil {
+		t.Fatalf("Error creating test database: %v", err)
+	}
+	defer cancel()
+	Migrate(ctx, db)
+
+	// setup the initial state
+	personCreator := PersonCreatorImpl{
+		departments: []struct {
+			id   string
+			name string
+		}{
+			{id: "dept1", name: "Department 1"},
+		},
+		workplaces: []struct {
+			id           string
+			name         string
+			departmentID string
+		}{
+			{id: "wp1", name: "Workplace 1", departmentID: "dept1"},
+		},
+		weekdayIDs: []int64{1, 2, 3, 4, 5, 6, 7},
+		person: struct {
+			id           string
+			email        string
+			active       bool
+			lastName     string
+			firstName    string
+			workingHours float64
+		}{
+			id:           "person1",
+			email:        "person1@example.com",
+			active:       true,
+			lastName:     "Doe",
+			firstName:    "John",
+			workingHours: 8.0,
+		},
+	}
+	personCreator.Create(db, ctx)
+
+	l := LeaveRequestRepositoryImpl{
+		db: db,
+	}
+	p := PersonRelRepositoryImpl{
+		db: db,
+	}
+
+	request, err := l.Save(ctx, &dao.LeaveRequest{
+		PersonID:    "person1",
+		StartDate:   "2021-01-04",
+		EndDate:     "2021-01-06",
+		Reason:      "Vacation",
+		RequestedBy: "john",
+	})
+	if err != nil {
+		t.Fatalf("Error saving leave request: %v", err)
+	}
+	if request.ID == "" || request.Status != dao.LeaveRequestPending {
+		t.Errorf("Expected a pending leave request with an id, got %+v", request)
+	}
+
+	pending, err := l.FindLeaveRequestsForDepartment(ctx, "dept1", dao.LeaveRequestPending)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("Expected one pending leave request, got %v, %v", pending, err)
+	}
+
+	if _, err := l.FindLeaveRequestByID(ctx, "dept2", request.ID); err != pkg.ErrNoRows {
+		t.Errorf("Expected the leave request not to be found in another department, got %v", err)
+	}
+
+	// approving marks the person absent on every date
+	request.Status = dao.LeaveRequestApproved
+	request.DecidedBy = "planner"
+	if err := l.Decide(ctx, request); err != nil {
+		t.Fatalf("Error approving leave request: %v", err)
+	}
+	absences, err := p.FindAbsencyForPersonInRange(ctx, "person1", "2021-01-01", "2021-01-31")
+	if err != nil || len(absences) != 3 {
+		t.Errorf("Expected 3 absences, got %v, %v", absences, err)
+	}
+
+	decided, err := l.FindLeaveRequestByID(ctx, "dept1", request.ID)
+	if err != nil {
+		t.Fatalf("Error finding leave request: %v", err)
+	}
+	if decided.Status != dao.LeaveRequestApproved || decided.DecidedBy != "planner" || decided.DecidedAt == nil {
+		t.Errorf("Expected the decision to be stored, got %+v", decided)
+	}
+
+	// rejecting removes the absences of the approval
+	request.Status = dao.LeaveRequestRejected
+	if err := l.Decide(ctx, request); err != nil {
+		t.Fatalf("Error rejecting leave request: %v", err)
+	}
+	if _, err := p.FindAbsencyForPersonInRange(ctx, "person1", "2021-01-01", "2021-01-31"); err != pkg.ErrNoRows {
+		t.Errorf("Expected the absences to be removed, got %v", err)
+	}
+}
@@ -12,35 +12,38 @@ import (
 
 type PersonRelRepository interface {
 	// Function Used by the service
-	AddAbsencyToPerson(person dao.Person, absence dao.Absence) error
-	RemoveAbsencyFromPerson(person dao.Person, absence dao.Absence) error
-	FindAbsencyForPerson(personID string, date string) (dao.Absence, error)
-	FindAbsencyForPersonInRange(personID string, startDate string, endDate string) ([]dao.Absence, error)
+	AddAbsencyToPerson(ctx context.Context, person dao.Person, absence dao.Absence) error
+	RemoveAbsencyFromPerson(ctx context.Context, person dao.Person, absence dao.Absence) error
+	FindAbsencyForPerson(ctx context.Context, personID string, date string) (dao.Absence, error)
+	FindAbsencyForPersonInRange(ctx context.Context, personID string, startDate string, endDate string) ([]dao.Absence, error)
 
-	AddDepartmentToPerson(person dao.Person, departmentID string) error
-	RemoveDepartmentFromPerson(person dao.Person, departmentID string) error
+	AddDepartmentToPerson(ctx context.Context, person dao.Person, departmentID string) error
+	RemoveDepartmentFromPerson(ctx context.Context, person dao.Person, departmentID string) error
 
-	AddWorkplaceToPerson(person dao.Person, departmentID string, workplaceID string) error
-	RemoveWorkplaceFromPerson(person dao.Person, departmentID string, workplaceID string) error
+	AddWorkplaceToPerson(ctx context.Context, person dao.Person, departmentID string, workplaceID string) error
+	RemoveWorkplaceFromPerson(ctx context.Context, person dao.Person, departmentID string, workplaceID string) error
 
-	AddWeekdayToPerson(person dao.Person, weekdayID int64) error
-	RemoveWeekdayFromPerson(person dao.Person, weekdayID int64) error
+	AddWeekdayToPerson(ctx context.Context, person dao.Person, weekdayID int64) error
+	RemoveWeekdayFromPerson(ctx context.Context, person dao.Person, weekdayID int64) error
 }
 
 type PersonRelRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
 // Function Used by the service
-func (p PersonRelRepositoryImpl) AddAbsencyToPerson(person dao.Person, absence dao.Absence) error {
+func (p PersonRelRepositoryImpl) AddAbsencyToPerson(ctx context.Context, person dao.Person, absence dao.Absence) error {
 	/* Adds an absency to a person
 	   @param person: The person to add the absency to
 	   @param date: The date of the absency
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	// Ensure that the date exists
-	if err := EnsureDateExists(p.db, context.Background(), absence.Date); err != nil {
+	if err := EnsureDateExists(p.db, ctx, absence.Date, "PersonRelRepository", "AddAbsencyToPerson"); err != nil {
 		return err
 	}
 
@@ -61,13 +64,7 @@ func (p PersonRelRepositoryImpl) AddAbsencyToPerson(person dao.Person, absence d
 		"reason":   absence.Reason,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "AddAbsencyToPerson", query, params)
 	if err != nil {
 		return err
 	}
@@ -75,12 +72,15 @@ func (p PersonRelRepositoryImpl) AddAbsencyToPerson(person dao.Person, absence d
 	return nil
 }
 
-func (p PersonRelRepositoryImpl) RemoveAbsencyFromPerson(person dao.Person, absence dao.Absence) error {
+func (p PersonRelRepositoryImpl) RemoveAbsencyFromPerson(ctx context.Context, person dao.Person, absence dao.Absence) error {
 	/* Removes an absency from a person
 	   @param person: The person to remove the absency from
 	   @param date: The date of the absency
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID}) -[r:ABSENT_ON]-> (d: Date {date: date($date)})
 	DELETE r
@@ -90,13 +90,7 @@ func (p PersonRelRepositoryImpl) RemoveAbsencyFromPerson(person dao.Person, abse
 		"date":     absence.Date,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "RemoveAbsencyFromPerson", query, params)
 	if err != nil {
 		return err
 	}
@@ -104,12 +98,15 @@ func (p PersonRelRepositoryImpl) RemoveAbsencyFromPerson(person dao.Person, abse
 	return nil
 }
 
-func (p PersonRelRepositoryImpl) FindAbsencyForPerson(personID string, date string) (dao.Absence, error) {
+func (p PersonRelRepositoryImpl) FindAbsencyForPerson(ctx context.Context, personID string, date string) (dao.Absence, error) {
 	/* Finds an absency for a person
 	   @param personID: The ID of the person to find the absency for
 	   @param date: The date of the absency
 	*/
 
+	ctx, cancel := p.timeouts.read(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID}) -[r:ABSENT_ON]-> (d: Date {date: date($date)})
 	RETURN r`
@@ -118,13 +115,7 @@ func (p PersonRelRepositoryImpl) FindAbsencyForPerson(personID string, date stri
 		"date":     date,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, p.db, "PersonRelRepository", "FindAbsencyForPerson", query, params)
 	if err != nil {
 		return dao.Absence{}, err
 	}
@@ -142,13 +133,16 @@ func (p PersonRelRepositoryImpl) FindAbsencyForPerson(personID string, date stri
 	return absence, nil
 }
 
-func (p PersonRelRepositoryImpl) FindAbsencyForPersonInRange(personID string, startDate string, endDate string) ([]dao.Absence, error) {
+func (p PersonRelRepositoryImpl) FindAbsencyForPersonInRange(ctx context.Context, personID string, startDate string, endDate string) ([]dao.Absence, error) {
 	/* Finds an absency for a person in a range of dates
 	   @param person: The person to find the absency for
 	   @param startDate: The start date of the range
 	   @param endDate: The end date of the range
 	*/
 
+	ctx, cancel := p.timeouts.read(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID}) -[r:ABSENT_ON]-> (d: Date)
 	WHERE d.date >= date($startDate) AND d.date <= date($endDate)
@@ -159,13 +153,7 @@ func (p PersonRelRepositoryImpl) FindAbsencyForPersonInRange(personID string, st
 		"endDate":   endDate,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, p.db, "PersonRelRepository", "FindAbsencyForPersonInRange", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -194,12 +182,15 @@ func (p PersonRelRepositoryImpl) FindAbsencyForPersonInRange(personID string, st
 	return absences, nil
 }
 
-func (p PersonRelRepositoryImpl) AddDepartmentToPerson(person dao.Person, departmentID string) error {
+func (p PersonRelRepositoryImpl) AddDepartmentToPerson(ctx context.Context, person dao.Person, departmentID string) error {
 	/* Adds a department to a person
 	   @param person: The person to add the department to
 	   @param departmentID: The name of the department to add
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID})
 	MATCH (d: Department {id: $departmentID})
@@ -211,13 +202,7 @@ func (p PersonRelRepositoryImpl) AddDepartmentToPerson(person dao.Person, depart
 		"departmentID": departmentID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "AddDepartmentToPerson", query, params)
 	if err != nil {
 		return err
 	}
@@ -225,12 +210,15 @@ func (p PersonRelRepositoryImpl) AddDepartmentToPerson(person dao.Person, depart
 	return nil
 }
 
-func (p PersonRelRepositoryImpl) RemoveDepartmentFromPerson(person dao.Person, departmentID string) error {
+func (p PersonRelRepositoryImpl) RemoveDepartmentFromPerson(ctx context.Context, person dao.Person, departmentID string) error {
 	/* Removes a department from a person
 	   @param person: The person to remove the department from
 	   @param departmentID: The name of the department to remove
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID}) -[r:WORKS_AT]-> (d: Department {id: $departmentID})
 	DELETE r
@@ -240,13 +228,7 @@ func (p PersonRelRepositoryImpl) RemoveDepartmentFromPerson(person dao.Person, d
 		"departmentID": departmentID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "RemoveDepartmentFromPerson", query, params)
 	if err != nil {
 		return err
 	}
@@ -254,12 +236,15 @@ func (p PersonRelRepositoryImpl) RemoveDepartmentFromPerson(person dao.Person, d
 	return nil
 }
 
-func (p PersonRelRepositoryImpl) AddWorkplaceToPerson(person dao.Person, departmentID string, workplaceID string) error {
+func (p PersonRelRepositoryImpl) AddWorkplaceToPerson(ctx context.Context, person dao.Person, departmentID string, workplaceID string) error {
 	/* Adds a workplace to a person
 	   @param person: The person to add the workplace to
 	   @param workplaceID: The name of the workplace to add
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID})
 	MATCH (d: Department {id: $departmentID}) -[:HAS_WORKPLACE]-> (w: Workplace {id: $workplaceID})
@@ -274,13 +259,7 @@ func (p PersonRelRepositoryImpl) AddWorkplaceToPerson(person dao.Person, departm
 		"workplaceID":  workplaceID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "AddWorkplaceToPerson", query, params)
 	if err != nil {
 		return err
 	}
@@ -288,12 +267,15 @@ func (p PersonRelRepositoryImpl) AddWorkplaceToPerson(person dao.Person, departm
 	return nil
 }
 
-func (p PersonRelRepositoryImpl) RemoveWorkplaceFromPerson(person dao.Person, departmentID string, workplaceID string) error {
+func (p PersonRelRepositoryImpl) RemoveWorkplaceFromPerson(ctx context.Context, person dao.Person, departmentID string, workplaceID string) error {
 	/* Removes a workplace from a person
 	   @param person: The person to remove the workplace from
 	   @param workplaceID: The name of the workplace to remove
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID}) -[r:QUALIFIED_FOR]-> (w: Workplace {id: $workplaceID}) <-[:HAS_WORKPLACE]- (d: Department {id: $departmentID})
 	DELETE r
@@ -304,13 +286,7 @@ func (p PersonRelRepositoryImpl) RemoveWorkplaceFromPerson(person dao.Person, de
 		"workplaceID":  workplaceID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "RemoveWorkplaceFromPerson", query, params)
 	if err != nil {
 		return err
 	}
@@ -318,12 +294,15 @@ func (p PersonRelRepositoryImpl) RemoveWorkplaceFromPerson(person dao.Person, de
 	return nil
 }
 
-func (p PersonRelRepositoryImpl) AddWeekdayToPerson(person dao.Person, weekdayID int64) error {
+func (p PersonRelRepositoryImpl) AddWeekdayToPerson(ctx context.Context, person dao.Person, weekdayID int64) error {
 	/* Adds a weekday to a person
 	   @param person: The person to add the weekday to
 	   @param weekdayID: The ID of the weekday to add
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID})
 	MATCH (wd: Weekday {id: $weekdayID})
@@ -335,13 +314,7 @@ func (p PersonRelRepositoryImpl) AddWeekdayToPerson(person dao.Person, weekdayID
 		"weekdayID": weekdayID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "AddWeekdayToPerson", query, params)
 
 	if err != nil {
 		return err
@@ -350,12 +323,15 @@ func (p PersonRelRepositoryImpl) AddWeekdayToPerson(person dao.Person, weekdayID
 	return nil
 }
 
-func (p PersonRelRepositoryImpl) RemoveWeekdayFromPerson(person dao.Person, weekdayID int64) error {
+func (p PersonRelRepositoryImpl) RemoveWeekdayFromPerson(ctx context.Context, person dao.Person, weekdayID int64) error {
 	/* Removes a weekday from a person
 	   @param person: The person to remove the weekday from
 	   @param weekdayID: The ID of the weekday to remove
 	*/
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (p: Person {id: $personID}) -[r:AVAILABLE_ON]-> (wd: Weekday {id: $weekdayID})
 	DELETE r
@@ -365,13 +341,7 @@ func (p PersonRelRepositoryImpl) RemoveWeekdayFromPerson(person dao.Person, week
 		"weekdayID": weekdayID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRelRepository", "RemoveWeekdayFromPerson", query, params)
 
 	if err != nil {
 		return err
@@ -380,10 +350,10 @@ func (p PersonRelRepositoryImpl) RemoveWeekdayFromPerson(person dao.Person, week
 	return nil
 }
 
-func PersonRelRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *PersonRelRepositoryImpl {
+func PersonRelRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *PersonRelRepositoryImpl {
 	return &PersonRelRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
@@ -53,8 +53,7 @@ func TestAddAndRemoveAbsencyToPerson(t *testing.T) {
 	personCreator.Create(db, ctx)
 
 	p := PersonRelRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 
 	tests := []struct {
@@ -117,7 +116,7 @@ func TestAddAndRemoveAbsencyToPerson(t *testing.T) {
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("Test %s: %d", test.name, i), func(t *testing.T) {
 
-			err := p.AddAbsencyToPerson(test.person, test.absency)
+			err := p.AddAbsencyToPerson(ctx, test.person, test.absency)
 			if err != nil && !test.expectedError {
 				t.Errorf("Expected no error, got %v", err)
 			}
@@ -131,7 +130,7 @@ func TestAddAndRemoveAbsencyToPerson(t *testing.T) {
 			}
 
 			// check if the absency was added
-			absency, err := p.FindAbsencyForPerson(test.person.ID, test.absency.Date)
+			absency, err := p.FindAbsencyForPerson(ctx, test.person.ID, test.absency.Date)
 			if err != nil {
 				t.Errorf("Error finding absencies: %v", err)
 			}
@@ -157,8 +156,7 @@ func TestRemoveAbsencyFromPerson(t *testing.T) {
 	Migrate(ctx, db)
 
 	p := PersonRelRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 
 	// tests
@@ -223,13 +221,13 @@ func TestRemoveAbsencyFromPerson(t *testing.T) {
 		t.Run(fmt.Sprintf("Test %s: %d", test.name, i), func(t *testing.T) {
 			// Add the absency
 			if test.addAbsency {
-				err := p.AddAbsencyToPerson(test.person, test.absency)
+				err := p.AddAbsencyToPerson(ctx, test.person, test.absency)
 				if err != nil {
 					t.Errorf("Error adding absency: %v", err)
 				}
 			}
 
-			err := p.RemoveAbsencyFromPerson(test.person, test.absency)
+			err := p.RemoveAbsencyFromPerson(ctx, test.person, test.absency)
 			if err != nil && !test.expectedError {
 				t.Errorf("Expected no error, got %v", err)
 			}
@@ -243,7 +241,7 @@ func TestRemoveAbsencyFromPerson(t *testing.T) {
 			}
 
 			// check if the absency was removed
-			absency, err := p.FindAbsencyForPerson(test.person.ID, test.absency.Date)
+			absency, err := p.FindAbsencyForPerson(ctx, test.person.ID, test.absency.Date)
 			if err == nil {
 				t.Errorf("Expected error, got no error")
 			}
@@ -11,19 +11,19 @@ import (
 
 type PersonRepository interface {
 	// Function Used by the service
-	FindAllPersons(departmentID string) ([]dao.Person, error)
-	FindAllPersonsBy(departmentID string, workplaceID string, weekdayID string, notAbsentOn string) ([]dao.Person, error)
-	FindPersonByID(personID string) (dao.Person, error)
-	Save(person *dao.Person) (dao.Person, error)
-	Delete(person *dao.Person) error
+	FindAllPersons(ctx context.Context, departmentID string) ([]dao.Person, error)
+	FindAllPersonsBy(ctx context.Context, departmentID string, workplaceID string, weekdayID string, notAbsentOn string) ([]dao.Person, error)
+	FindPersonByID(ctx context.Context, personID string) (dao.Person, error)
+	Save(ctx context.Context, person *dao.Person) (dao.Person, error)
+	Delete(ctx context.Context, person *dao.Person) error
 }
 
 type PersonRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (p PersonRepositoryImpl) FindAllPersonsBy(departmentID string, workplaceID string, weekdayID string, notAbsentDate string) ([]dao.Person, error) {
+func (p PersonRepositoryImpl) FindAllPersonsBy(ctx context.Context, departmentID string, workplaceID string, weekdayID string, notAbsentDate string) ([]dao.Person, error) {
 	/* Returns all persons in a department, qualified for a workplace that are present on a weekday and not absent on a date
 	   @param departmentID: The name of the department the persons should be in
 	   @param presentOnWeekdayID: The ID of the weekday the persons should be present on
@@ -31,6 +31,9 @@ func (p PersonRepositoryImpl) FindAllPersonsBy(departmentID string, workplaceID
 	   @param notAbsentOn: The date the person should not be absent on
 	*/
 
+	ctx, cancel := p.timeouts.read(ctx)
+	defer cancel()
+
 	persons := []dao.Person{}
 
 	// Build dynamic query depending on which param was given
@@ -58,13 +61,7 @@ func (p PersonRepositoryImpl) FindAllPersonsBy(departmentID string, workplaceID
 
 	query += ` RETURN p`
 
-	result, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, p.db, "PersonRepository", "FindAllPersonsBy", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -81,11 +78,14 @@ func (p PersonRepositoryImpl) FindAllPersonsBy(departmentID string, workplaceID
 	return persons, nil
 }
 
-func (p PersonRepositoryImpl) FindAllPersons(departmentID string) ([]dao.Person, error) {
+func (p PersonRepositoryImpl) FindAllPersons(ctx context.Context, departmentID string) ([]dao.Person, error) {
 	/* Returns all persons
 	   @param departmentID: The name of the department the persons should be in
 	*/
 
+	ctx, cancel := p.timeouts.read(ctx)
+	defer cancel()
+
 	persons := []dao.Person{}
 	params := map[string]interface{}{
 		"departmentID": departmentID,
@@ -111,13 +111,7 @@ func (p PersonRepositoryImpl) FindAllPersons(departmentID string) ([]dao.Person,
 			name: wd.name
 		}) AS weekdays`
 
-	result, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, p.db, "PersonRepository", "FindAllPersons", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -138,9 +132,12 @@ func (p PersonRepositoryImpl) FindAllPersons(departmentID string) ([]dao.Person,
 	return persons, nil
 }
 
-func (p PersonRepositoryImpl) FindPersonByID(personID string) (dao.Person, error) {
+func (p PersonRepositoryImpl) FindPersonByID(ctx context.Context, personID string) (dao.Person, error) {
 	/* Returns a person by name */
 
+	ctx, cancel := p.timeouts.read(ctx)
+	defer cancel()
+
 	person := dao.Person{}
 	query := `
 	MATCH (p:Person {id: $personID})
@@ -166,13 +163,7 @@ func (p PersonRepositoryImpl) FindPersonByID(personID string) (dao.Person, error
 		"personID": personID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, p.db, "PersonRepository", "FindPersonByID", query, params)
 	if err != nil {
 		return dao.Person{}, err
 	}
@@ -191,9 +182,12 @@ func (p PersonRepositoryImpl) FindPersonByID(personID string) (dao.Person, error
 	return person, nil
 }
 
-func (p PersonRepositoryImpl) Save(person *dao.Person) (dao.Person, error) {
+func (p PersonRepositoryImpl) Save(ctx context.Context, person *dao.Person) (dao.Person, error) {
 	/* Saves a person */
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
     MERGE (p:Person {id: $personID})
     ON CREATE SET
@@ -222,13 +216,7 @@ func (p PersonRepositoryImpl) Save(person *dao.Person) (dao.Person, error) {
 		"workingHours": person.WorkingHours,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, p.db, "PersonRepository", "Save", query, params)
 	if err != nil {
 		return dao.Person{}, err
 	}
@@ -244,9 +232,12 @@ func (p PersonRepositoryImpl) Save(person *dao.Person) (dao.Person, error) {
 	return *person, nil
 }
 
-func (p PersonRepositoryImpl) Delete(person *dao.Person) error {
+func (p PersonRepositoryImpl) Delete(ctx context.Context, person *dao.Person) error {
 	/* Deletes a person */
 
+	ctx, cancel := p.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
     MATCH  (p:Person {id: $personID})
     SET p.deleted_at = datetime()`
@@ -254,13 +245,7 @@ func (p PersonRepositoryImpl) Delete(person *dao.Person) error {
 		"personID": person.ID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		p.ctx,
-		*p.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, p.db, "PersonRepository", "Delete", query, params)
 	if err != nil {
 		return err
 	}
@@ -268,10 +253,10 @@ func (p PersonRepositoryImpl) Delete(person *dao.Person) error {
 	return nil
 }
 
-func PersonRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *PersonRepositoryImpl {
+func PersonRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *PersonRepositoryImpl {
 	return &PersonRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/wire"
 )
 
@@ -14,4 +17,28 @@ var RepositorySet = wire.NewSet(
 	synchronizeRepositorySet,
 	workdayRepositorySet,
 	absenceRepositorySet,
+	leaveRequestRepositorySet,
+	unitOfWorkSet,
 )
+
+// Timeouts limits every operation of the repositories in addition to the deadline of the request.
+// Operations without a timeout are only limited by their context.
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
+}
+
+func (t Timeouts) read(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, t.Read)
+}
+
+func (t Timeouts) write(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, t.Write)
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
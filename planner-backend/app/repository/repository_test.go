@@ -72,11 +72,10 @@ type DeparmentCreatorImpl struct {
 
 func (c *DeparmentCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Context) error {
 	d := DepartmentRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 
-	if _, err := d.Save(&dao.Department{
+	if _, err := d.Save(ctx, &dao.Department{
 		Name: c.name,
 		ID:   c.id,
 	}); err != nil {
@@ -95,15 +94,13 @@ type WorkplaceCreatorImpl struct {
 
 func (c *WorkplaceCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Context) error {
 	d := DepartmentRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	wp := WorkplaceRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 
-	if _, err := d.Save(&dao.Department{
+	if _, err := d.Save(ctx, &dao.Department{
 		Name: c.departmentName,
 		ID:   c.departmentID,
 	}); err != nil {
@@ -111,6 +108,7 @@ func (c *WorkplaceCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.C
 	}
 
 	if _, err := wp.Save(
+		ctx,
 		c.departmentID,
 		&dao.Workplace{
 			Name: c.name,
@@ -140,23 +138,19 @@ type TimeslotCreatorImpl struct {
 
 func (c *TimeslotCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Context) error {
 	d := DepartmentRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	wp := WorkplaceRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	tr := TimeslotRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	wd := WeekdayRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 
-	if _, err := d.Save(&dao.Department{
+	if _, err := d.Save(ctx, &dao.Department{
 		ID:   c.departmentID,
 		Name: c.departmentName,
 	}); err != nil {
@@ -164,6 +158,7 @@ func (c *TimeslotCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Co
 	}
 
 	if _, err := wp.Save(
+		ctx,
 		c.departmentID,
 		&dao.Workplace{
 			ID:   c.workplaceID,
@@ -173,7 +168,7 @@ func (c *TimeslotCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Co
 		return err
 	}
 
-	if _, err := tr.Save(c.departmentID, c.workplaceID, &dao.Timeslot{
+	if _, err := tr.Save(ctx, c.departmentID, c.workplaceID, &dao.Timeslot{
 		ID:   c.id,
 		Name: c.name,
 	}); err != nil {
@@ -182,6 +177,7 @@ func (c *TimeslotCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Co
 
 	for _, weekday := range c.weekdays {
 		if _, err := wd.AddWeekdayToTimeslot(
+			ctx,
 			&dao.Timeslot{
 				ID:           c.id,
 				DepartmentID: c.departmentID,
@@ -221,24 +217,20 @@ type PersonCreatorImpl struct {
 
 func (c *PersonCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Context) error {
 	d := DepartmentRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	wp := WorkplaceRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	pr := PersonRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 	prl := PersonRelRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db: db,
 	}
 
 	for _, department := range c.departments {
-		if _, err := d.Save(&dao.Department{
+		if _, err := d.Save(ctx, &dao.Department{
 			ID:   department.id,
 			Name: department.name,
 		}); err != nil {
@@ -248,6 +240,7 @@ func (c *PersonCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Cont
 
 	for _, workplace := range c.workplaces {
 		if _, err := wp.Save(
+			ctx,
 			workplace.departmentID,
 			&dao.Workplace{
 				ID:   workplace.id,
@@ -266,24 +259,24 @@ func (c *PersonCreatorImpl) Create(db *neo4j.DriverWithContext, ctx context.Cont
 		Active:       c.person.active,
 		WorkingHours: c.person.workingHours,
 	}
-	if _, err := pr.Save(person); err != nil {
+	if _, err := pr.Save(ctx, person); err != nil {
 		return err
 	}
 
 	for _, weekdayID := range c.weekdayIDs {
-		if err := prl.AddWeekdayToPerson(*person, weekdayID); err != nil {
+		if err := prl.AddWeekdayToPerson(ctx, *person, weekdayID); err != nil {
 			return err
 		}
 	}
 
 	for _, department := range c.departments {
-		if err := prl.AddDepartmentToPerson(*person, department.id); err != nil {
+		if err := prl.AddDepartmentToPerson(ctx, *person, department.id); err != nil {
 			return err
 		}
 	}
 
 	for _, workplace := range c.workplaces {
-		if err := prl.AddWorkplaceToPerson(*person, workplace.departmentID, workplace.id); err != nil {
+		if err := prl.AddWorkplaceToPerson(ctx, *person, workplace.departmentID, workplace.id); err != nil {
 			return err
 		}
 	}
@@ -12,18 +12,19 @@ import (
 )
 
 type SynchronizeRepository interface {
-	Synchronize(weeksInAdvance int) error
+	// Returns the number of created workdays
+	Synchronize(ctx context.Context, weeksInAdvance int) (int, error)
 
-	createWorkday(tx neo4j.ManagedTransaction, date string, weekday int64) error
-	ensureDateExists(tx neo4j.ManagedTransaction, date string, weekdayID int64) error
+	createWorkday(ctx context.Context, tx neo4j.ManagedTransaction, date string, weekday int64) (int, error)
+	ensureDateExists(ctx context.Context, tx neo4j.ManagedTransaction, date string, weekdayID int64) error
 }
 
 type SynchronizeRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (d SynchronizeRepositoryImpl) ensureDateExists(tx neo4j.ManagedTransaction, date string, weekdayID int64) error {
+func (d SynchronizeRepositoryImpl) ensureDateExists(ctx context.Context, tx neo4j.ManagedTransaction, date string, weekdayID int64) (err error) {
 	/*
 		* Ensures that a date exists in the database
 		This function is used during the synchronization process to ensure that a date exists
@@ -32,6 +33,8 @@ func (d SynchronizeRepositoryImpl) ensureDateExists(tx neo4j.ManagedTransaction,
 		* @param weekday: The weekday to ensure, Format: 1-7
 		* @return: An error if the date could not be created
 	*/
+	defer observeQuery(time.Now(), "SynchronizeRepository", "ensureDateExists", &err)
+
 	slog.Info(fmt.Sprintf("Ensuring date %s exists", date))
 	query := `
 	// The weekday nodes are already created, so we can just match on them
@@ -47,7 +50,7 @@ func (d SynchronizeRepositoryImpl) ensureDateExists(tx neo4j.ManagedTransaction,
 	}
 
 	res, err := tx.Run(
-		d.ctx,
+		ctx,
 		query,
 		params,
 	)
@@ -56,26 +59,31 @@ func (d SynchronizeRepositoryImpl) ensureDateExists(tx neo4j.ManagedTransaction,
 	}
 
 	// Check if the date was created
-	if !res.Next(d.ctx) {
+	if !res.Next(ctx) {
 		return pkg.ErrNoRows
 	}
 
 	return nil
 }
 
-func (d SynchronizeRepositoryImpl) Synchronize(weeksInAdvance int) error {
+func (d SynchronizeRepositoryImpl) Synchronize(ctx context.Context, weeksInAdvance int) (int, error) {
 	/*
 	*	Synchronize:
 	*	- Get monday of the current week
 	*	- calculate all dates from monday to sunday * weeksInAdvance
+	*	- the workdays are counted once the transaction is committed, as it may be retried
 	 */
 
+	ctx, cancel := d.timeouts.write(ctx)
+	defer cancel()
+
 	// Create Workday nodes for each date and weekday
-	session := (*d.db).NewSession(d.ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(d.ctx)
+	session := (*d.db).NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
 
 	// Start a new transaction
-	if _, err := session.ExecuteWrite(d.ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+	created, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		created := 0
 		// Get the current date
 		now := time.Now()
 
@@ -89,25 +97,28 @@ func (d SynchronizeRepositoryImpl) Synchronize(weeksInAdvance int) error {
 				weekdayID := TimeDateToWeekdayID(date)
 
 				// Create the date node
-				if err := d.ensureDateExists(tx, dateStr, weekdayID); err != nil {
+				if err := d.ensureDateExists(ctx, tx, dateStr, weekdayID); err != nil {
 					return nil, err
 				}
 
-				if err := d.createWorkday(tx, dateStr, weekdayID); err != nil {
+				workdays, err := d.createWorkday(ctx, tx, dateStr, weekdayID)
+				if err != nil {
 					return nil, err
 				}
+				created += workdays
 			}
 		}
 
-		return nil, nil
-	}); err != nil {
-		return err
+		return created, nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	return created.(int), nil
 }
 
-func (d SynchronizeRepositoryImpl) createWorkday(tx neo4j.ManagedTransaction, date string, weekdayID int64) error {
+func (d SynchronizeRepositoryImpl) createWorkday(ctx context.Context, tx neo4j.ManagedTransaction, date string, weekdayID int64) (created int, err error) {
 	/**
 	 * Create Workday Nodes for Given Weekday and Date
 	 *
@@ -128,7 +139,11 @@ func (d SynchronizeRepositoryImpl) createWorkday(tx neo4j.ManagedTransaction, da
 	 *
 	 * Example Usage:
 	 * CALL yourProcedureName($weekdayID, $date)
+	 *
+	 * @return {int} The number of created Workday nodes
 	 */
+	defer observeQuery(time.Now(), "SynchronizeRepository", "createWorkday", &err)
+
 	slog.Info(fmt.Sprintf("Creating workday nodes for date %s and weekday %d", date, weekdayID))
 	query := `
 	// Get all timeslots offered on the given weekday, loop through them and create a Workday node for each of them.
@@ -171,27 +186,28 @@ func (d SynchronizeRepositoryImpl) createWorkday(tx neo4j.ManagedTransaction, da
 	}
 
 	result, err := tx.Run(
-		d.ctx,
+		ctx,
 		query,
 		params,
 	)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// Check if the result is empty
-	if !result.Next(d.ctx) || result.Err() != nil {
+	// Check if the result is empty, only the Workday nodes are created by the query
+	summary, err := result.Consume(ctx)
+	if err != nil || summary.Counters().NodesCreated() == 0 {
 		slog.Warn(fmt.Sprintf("no workday nodes were created for date %s and weekday %d", date, weekdayID))
-		return nil
+		return 0, nil
 	}
 
-	return err
+	return summary.Counters().NodesCreated(), nil
 }
 
-func SynchronizeRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *SynchronizeRepositoryImpl {
+func SynchronizeRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *SynchronizeRepositoryImpl {
 	return &SynchronizeRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
@@ -104,13 +104,16 @@ func TestSynchronize(t *testing.T) {
 
 			// begin the test
 			s := SynchronizeRepositoryImpl{
-				db:  db,
-				ctx: ctx,
+				db: db,
 			}
 
-			if err := s.Synchronize(test.weeksInAdvance); err != nil {
+			created, err := s.Synchronize(ctx, test.weeksInAdvance)
+			if err != nil {
 				t.Errorf("Error synchronizing: %v", err)
 			}
+			if created != test.expectedWorkdaysCount {
+				t.Errorf("Expected %d created workdays, got %d", test.expectedWorkdaysCount, created)
+			}
 
 			// check if the workdays were created
 			results, err := neo4j.ExecuteQuery(
@@ -156,8 +159,7 @@ func TestEnsureDateExistsInService(t *testing.T) {
 
 			// begin the test
 			s := SynchronizeRepositoryImpl{
-				db:  db,
-				ctx: ctx,
+				db: db,
 			}
 
 			session := (*db).NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
@@ -166,7 +168,7 @@ func TestEnsureDateExistsInService(t *testing.T) {
 			// Start a new transaction
 			_, err = session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
 				// Create the date node
-				if err := s.ensureDateExists(tx, test.date.Format("2006-01-02"), TimeDateToWeekdayID(test.date)); err != nil {
+				if err := s.ensureDateExists(ctx, tx, test.date.Format("2006-01-02"), TimeDateToWeekdayID(test.date)); err != nil {
 					return nil, err
 				}
 				return nil, nil
@@ -336,8 +338,7 @@ func TestCreateWorkDay(t *testing.T) {
 
 			// begin the test
 			s := SynchronizeRepositoryImpl{
-				db:  db,
-				ctx: ctx,
+				db: db,
 			}
 
 			if test.create {
@@ -350,11 +351,11 @@ func TestCreateWorkDay(t *testing.T) {
 					// Create Workday nodes for each date and weekday
 					for date := test.startDate; date.Before(test.endDate.AddDate(0, 0, 1)); date = date.AddDate(0, 0, 1) {
 						// Create the date node
-						if err := s.ensureDateExists(tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
+						if err := s.ensureDateExists(ctx, tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
 							return nil, err
 						}
 						// Create the workday node
-						if err := s.createWorkday(tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
+						if _, err := s.createWorkday(ctx, tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
 							return nil, err
 						}
 					}
@@ -513,8 +514,7 @@ func TestEnsureSynchronizationRunsOncePerDateAndDepartment(t *testing.T) {
 
 			// begin the test
 			s := SynchronizeRepositoryImpl{
-				db:  db,
-				ctx: ctx,
+				db: db,
 			}
 
 			session := (*db).NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
@@ -526,19 +526,19 @@ func TestEnsureSynchronizationRunsOncePerDateAndDepartment(t *testing.T) {
 				// Create Workday nodes for each date and weekday
 				for date := test.startDate; date.Before(test.endDate.AddDate(0, 0, 1)); date = date.AddDate(0, 0, 1) {
 					// Create the date node as we would normally do
-					if err := s.ensureDateExists(tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
+					if err := s.ensureDateExists(ctx, tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
 						return nil, err
 					}
 					// Create the workday node as we would normally do
-					if err := s.createWorkday(tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
+					if _, err := s.createWorkday(ctx, tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil {
 						return nil, err
 					}
 
 					// Run again to ensure that the synchronization runs only once
-					if err := s.createWorkday(tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err == nil {
-						// here we expect an error since no workday should be created
-						t.Errorf("Expected error, got nil")
-						return nil, errors.New("Expected error, got nil")
+					if created, err := s.createWorkday(ctx, tx, date.Format("2006-01-02"), TimeDateToWeekdayID(date)); err != nil || created != 0 {
+						// here we expect that no workday is created
+						t.Errorf("Expected no workdays, got %d: %v", created, err)
+						return nil, errors.New("Expected no workdays")
 					}
 				}
 				return nil, nil
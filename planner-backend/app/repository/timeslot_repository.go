@@ -10,20 +10,23 @@ import (
 )
 
 type TimeslotRepository interface {
-	FindAllTimeslots(departmentID string, workplaceID string) ([]dao.Timeslot, error)
-	FindTimeslotByID(departmentID string, workplaceID string, timeslotID string) (dao.Timeslot, error)
-	Save(departmentID string, workplaceID string, timeslot *dao.Timeslot) (dao.Timeslot, error)
-	Delete(departmentID string, workplaceID string, timeslot *dao.Timeslot) error
+	FindAllTimeslots(ctx context.Context, departmentID string, workplaceID string) ([]dao.Timeslot, error)
+	FindTimeslotByID(ctx context.Context, departmentID string, workplaceID string, timeslotID string) (dao.Timeslot, error)
+	Save(ctx context.Context, departmentID string, workplaceID string, timeslot *dao.Timeslot) (dao.Timeslot, error)
+	Delete(ctx context.Context, departmentID string, workplaceID string, timeslot *dao.Timeslot) error
 }
 
 type TimeslotRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (t TimeslotRepositoryImpl) FindAllTimeslots(departmentID string, workplaceID string) ([]dao.Timeslot, error) {
+func (t TimeslotRepositoryImpl) FindAllTimeslots(ctx context.Context, departmentID string, workplaceID string) ([]dao.Timeslot, error) {
 	/* Returns all timeslots */
 
+	ctx, cancel := t.timeouts.read(ctx)
+	defer cancel()
+
 	timeslots := []dao.Timeslot{}
 	query := `
     MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})-[:HAS_TIMESLOT]->(t:Timeslot)
@@ -42,13 +45,7 @@ func (t TimeslotRepositoryImpl) FindAllTimeslots(departmentID string, workplaceI
 		"workplaceID":  workplaceID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		t.ctx,
-		*t.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, t.db, "TimeslotRepository", "FindAllTimeslots", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -65,9 +62,12 @@ func (t TimeslotRepositoryImpl) FindAllTimeslots(departmentID string, workplaceI
 	return timeslots, nil
 }
 
-func (t TimeslotRepositoryImpl) FindTimeslotByID(departmentID string, workplaceID string, timeslotID string) (dao.Timeslot, error) {
+func (t TimeslotRepositoryImpl) FindTimeslotByID(ctx context.Context, departmentID string, workplaceID string, timeslotID string) (dao.Timeslot, error) {
 	/* Returns a timeslot by name */
 
+	ctx, cancel := t.timeouts.read(ctx)
+	defer cancel()
+
 	timeslot := dao.Timeslot{}
 	query := `
     MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})-[:HAS_TIMESLOT]->(t:Timeslot {id: $timeslotID})
@@ -86,13 +86,7 @@ func (t TimeslotRepositoryImpl) FindTimeslotByID(departmentID string, workplaceI
 		"timeslotID":   timeslotID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		t.ctx,
-		*t.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, t.db, "TimeslotRepository", "FindTimeslotByID", query, params)
 	if err != nil {
 		return dao.Timeslot{}, err
 	}
@@ -108,9 +102,12 @@ func (t TimeslotRepositoryImpl) FindTimeslotByID(departmentID string, workplaceI
 	return timeslot, nil
 }
 
-func (t TimeslotRepositoryImpl) Save(departmentID string, workplaceID string, timeslot *dao.Timeslot) (dao.Timeslot, error) {
+func (t TimeslotRepositoryImpl) Save(ctx context.Context, departmentID string, workplaceID string, timeslot *dao.Timeslot) (dao.Timeslot, error) {
 	/* Saves a timeslot */
 
+	ctx, cancel := t.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})
 	WHERE d.deleted_at IS NULL AND wp.deleted_at IS NULL
@@ -140,13 +137,7 @@ func (t TimeslotRepositoryImpl) Save(departmentID string, workplaceID string, ti
 		"timeslotName": timeslot.Name,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		t.ctx,
-		*t.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, t.db, "TimeslotRepository", "Save", query, params)
 	if err != nil {
 		return dao.Timeslot{}, err
 	}
@@ -162,9 +153,12 @@ func (t TimeslotRepositoryImpl) Save(departmentID string, workplaceID string, ti
 	return *timeslot, nil
 }
 
-func (t TimeslotRepositoryImpl) Delete(departmentID string, workplaceID string, timeslot *dao.Timeslot) error {
+func (t TimeslotRepositoryImpl) Delete(ctx context.Context, departmentID string, workplaceID string, timeslot *dao.Timeslot) error {
 	/* Deletes a timeslot */
 
+	ctx, cancel := t.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
     MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})-[:HAS_TIMESLOT]->(t:Timeslot {id: $timeslotID})
     SET t.deleted_at = datetime()
@@ -175,13 +169,7 @@ func (t TimeslotRepositoryImpl) Delete(departmentID string, workplaceID string,
 		"timeslotID":   timeslot.ID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		t.ctx,
-		*t.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, t.db, "TimeslotRepository", "Delete", query, params)
 	if err != nil {
 		return err
 	}
@@ -189,10 +177,10 @@ func (t TimeslotRepositoryImpl) Delete(departmentID string, workplaceID string,
 	return nil
 }
 
-func TimeslotRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *TimeslotRepositoryImpl {
+func TimeslotRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *TimeslotRepositoryImpl {
 	return &TimeslotRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"planner-backend/app/metrics"
+	"time"
+
+	"github.com/google/wire"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var ErrReadOnlyTransaction = errors.New("write in a read transaction")
+
+// UnitOfWork runs several operations of the repositories in one managed transaction.
+// The operations take the transaction from the context passed to the work, the work may be retried
+// on transient errors of the database, so it must not have other side effects.
+type UnitOfWork interface {
+	// Read runs the work in a read transaction, which is routed to the readers of a cluster
+	Read(ctx context.Context, work func(ctx context.Context) error) error
+	// Write runs the work in a write transaction, which is committed if the work succeeds
+	Write(ctx context.Context, work func(ctx context.Context) error) error
+}
+
+type UnitOfWorkImpl struct {
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
+}
+
+type transactionKey struct{}
+
+type transaction struct {
+	tx   neo4j.ManagedTransaction
+	mode neo4j.AccessMode
+}
+
+func (u UnitOfWorkImpl) Read(ctx context.Context, work func(ctx context.Context) error) error {
+	ctx, cancel := u.timeouts.read(ctx)
+	defer cancel()
+
+	return u.execute(ctx, neo4j.AccessModeRead, work)
+}
+
+func (u UnitOfWorkImpl) Write(ctx context.Context, work func(ctx context.Context) error) error {
+	ctx, cancel := u.timeouts.write(ctx)
+	defer cancel()
+
+	return u.execute(ctx, neo4j.AccessModeWrite, work)
+}
+
+func (u UnitOfWorkImpl) execute(ctx context.Context, mode neo4j.AccessMode, work func(ctx context.Context) error) error {
+	/**
+	* Runs the work in a transaction of the mode.
+	* A unit of work inside another one joins its transaction, a write can not join a read transaction.
+	**/
+	if current, ok := ctx.Value(transactionKey{}).(transaction); ok {
+		if mode == neo4j.AccessModeWrite && current.mode == neo4j.AccessModeRead {
+			return ErrReadOnlyTransaction
+		}
+		return work(ctx)
+	}
+
+	session := (*u.db).NewSession(ctx, neo4j.SessionConfig{AccessMode: mode})
+	defer session.Close(ctx)
+
+	run := session.ExecuteWrite
+	if mode == neo4j.AccessModeRead {
+		run = session.ExecuteRead
+	}
+	_, err := run(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
+		return nil, work(context.WithValue(ctx, transactionKey{}, transaction{tx: tx, mode: mode}))
+	})
+	return err
+}
+
+func executeRead(ctx context.Context, db *neo4j.DriverWithContext, repository string, method string, query string, params map[string]interface{}) (result *neo4j.EagerResult, err error) {
+	/**
+	* Runs a reading query in the transaction of the context, or on its own routed to the readers
+	* @param repository, method: The repository method which runs the query, e.g. WorkdayRepository and GetWorkday
+	**/
+	defer observeQuery(time.Now(), repository, method, &err)
+
+	if current, ok := ctx.Value(transactionKey{}).(transaction); ok {
+		return runInTransaction(ctx, current, query, params)
+	}
+	return neo4j.ExecuteQuery(ctx, *db, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithReadersRouting())
+}
+
+func executeWrite(ctx context.Context, db *neo4j.DriverWithContext, repository string, method string, query string, params map[string]interface{}) (result *neo4j.EagerResult, err error) {
+	/**
+	* Runs a writing query in the transaction of the context, or on its own routed to the writers
+	* @param repository, method: The repository method which runs the query, e.g. WorkdayRepository and Save
+	**/
+	defer observeQuery(time.Now(), repository, method, &err)
+
+	if current, ok := ctx.Value(transactionKey{}).(transaction); ok {
+		if current.mode == neo4j.AccessModeRead {
+			return nil, ErrReadOnlyTransaction
+		}
+		return runInTransaction(ctx, current, query, params)
+	}
+	return neo4j.ExecuteQuery(ctx, *db, query, params, neo4j.EagerResultTransformer, neo4j.ExecuteQueryWithWritersRouting())
+}
+
+func runInTransaction(ctx context.Context, current transaction, query string, params map[string]interface{}) (*neo4j.EagerResult, error) {
+	result, err := current.tx.Run(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := result.Keys()
+	if err != nil {
+		return nil, err
+	}
+	records, err := result.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &neo4j.EagerResult{Keys: keys, Records: records, Summary: summary}, nil
+}
+
+func observeQuery(start time.Time, repository string, method string, err *error) {
+	/* Records the duration of a query by the repository method which ran it */
+	metrics.Neo4jQueryDuration.WithLabelValues(repository, method, metrics.Status(*err)).Observe(time.Since(start).Seconds())
+}
+
+func UnitOfWorkInit(db *neo4j.DriverWithContext, timeouts Timeouts) *UnitOfWorkImpl {
+	return &UnitOfWorkImpl{
+		db:       db,
+		timeouts: timeouts,
+	}
+}
+
+var unitOfWorkSet = wire.NewSet(
+	UnitOfWorkInit,
+	wire.Bind(new(UnitOfWork), new(*UnitOfWorkImpl)),
+)
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"planner-backend/app/metrics"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/prometheus/client_golang/prometheus"
+	prometheusdto "github.com/prometheus/client_model/go"
+)
+
+func TestUnitOfWorkJoinsTransaction(t *testing.T) {
+	// A unit of work inside another one runs in its transaction, a write can not join a read transaction
+	u := UnitOfWorkImpl{}
+
+	read := context.WithValue(context.Background(), transactionKey{}, transaction{mode: neo4j.AccessModeRead})
+	write := context.WithValue(context.Background(), transactionKey{}, transaction{mode: neo4j.AccessModeWrite})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		write    bool
+		expected error
+		runs     bool
+	}{
+		{name: "read in read", ctx: read, write: false, runs: true},
+		{name: "read in write", ctx: write, write: false, runs: true},
+		{name: "write in write", ctx: write, write: true, runs: true},
+		{name: "write in read", ctx: read, write: true, expected: ErrReadOnlyTransaction},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runs := false
+			work := func(ctx context.Context) error {
+				runs = true
+				return nil
+			}
+
+			var err error
+			if test.write {
+				err = u.Write(test.ctx, work)
+			} else {
+				err = u.Read(test.ctx, work)
+			}
+
+			if err != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, err)
+			}
+			if runs != test.runs {
+				t.Errorf("Expected the work to run: %v", test.runs)
+			}
+		})
+	}
+}
+
+func TestExecuteWriteInReadTransaction(t *testing.T) {
+	// Writing queries are refused in a read transaction, before they reach the database
+	ctx := context.WithValue(context.Background(), transactionKey{}, transaction{mode: neo4j.AccessModeRead})
+
+	if _, err := executeWrite(ctx, nil, "TestRepository", "Save", "CREATE (n)", nil); err != ErrReadOnlyTransaction {
+		t.Errorf("Expected %v, got %v", ErrReadOnlyTransaction, err)
+	}
+}
+
+func TestQueryMetrics(t *testing.T) {
+	// Queries are reported by the repository method which ran them
+	ctx := context.WithValue(context.Background(), transactionKey{}, transaction{mode: neo4j.AccessModeRead})
+	histogram := metrics.Neo4jQueryDuration.WithLabelValues("QueryMetricsRepository", "Save", metrics.StatusError)
+
+	before := sampleCount(t, histogram)
+	if _, err := executeWrite(ctx, nil, "QueryMetricsRepository", "Save", "CREATE (n)", nil); err != ErrReadOnlyTransaction {
+		t.Errorf("Expected %v, got %v", ErrReadOnlyTransaction, err)
+	}
+	if count := sampleCount(t, histogram); count != before+1 {
+		t.Errorf("Expected %d queries, got %d", before+1, count)
+	}
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	var metric prometheusdto.Metric
+	if err := observer.(prometheus.Metric).Write(&metric); err != nil {
+		t.Fatalf("Error while reading metric: %s", err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
@@ -14,6 +14,7 @@ var queries = map[string]string{
 	// TODO: Implement a better way to handle these queries
 	"unique_department":        `CREATE CONSTRAINT unique_department_id IF NOT EXISTS FOR (d:Department) REQUIRE d.id IS UNIQUE;`,
 	"unique_person":            `CREATE CONSTRAINT unique_person_id IF NOT EXISTS FOR (p:Person) REQUIRE p.id IS UNIQUE;`,
+	"unique_leave_request":     `CREATE CONSTRAINT unique_leave_request_id IF NOT EXISTS FOR (lr:LeaveRequest) REQUIRE lr.id IS UNIQUE;`,
 	"create_monday":            `MERGE (:Weekday {name: 'Montag', id: 1});`,
 	"create_tuesday":           `MERGE (:Weekday {name: 'Dienstag', id: 2});`,
 	"create_wednesday":         `MERGE (:Weekday {name: 'Mittwoch', id: 3});`,
@@ -94,11 +95,12 @@ func TimeDateToWeekdayID(t time.Time) int64 {
 	}
 }
 
-func EnsureDateExists(db *neo4j.DriverWithContext, ctx context.Context, date string) error {
+func EnsureDateExists(db *neo4j.DriverWithContext, ctx context.Context, date string, repository string, method string) error {
 	/**
 	* Ensures that a date exists in the database
 	* Is idempotent to ensure that the date is only created once
 	* @param date: The date to ensure, Format: YYYY-MM-DD
+	* @param repository, method: The repository method which needs the date, the query is recorded for it
 	* @return: An error if the date could not be created
 	 */
 
@@ -122,13 +124,7 @@ func EnsureDateExists(db *neo4j.DriverWithContext, ctx context.Context, date str
 		"date":      date,
 	}
 
-	res, err := neo4j.ExecuteQuery(
-		ctx,
-		*db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	res, err := executeWrite(ctx, db, repository, method, query, params)
 	if err != nil {
 		return err
 	}
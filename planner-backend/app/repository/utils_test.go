@@ -81,7 +81,7 @@ func TestEnsureDateExists(t *testing.T) {
 
 	for i, test := range tests {
 		t.Run(fmt.Sprintf("%s: %d", test.name, i), func(t *testing.T) {
-			err := EnsureDateExists(db, ctx, test.date)
+			err := EnsureDateExists(db, ctx, test.date, "TestRepository", "EnsureDateExists")
 			if err != nil {
 				t.Errorf("Error ensuring date exists: %s", err)
 			}
@@ -175,3 +175,29 @@ func TestEnsureWeekdaysExist(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeouts(t *testing.T) {
+	// The timeouts limit the operations, a cancelled request cancels them regardless
+	timeouts := Timeouts{Read: time.Minute, Write: time.Hour}
+
+	ctx, cancel := timeouts.read(context.Background())
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Minute {
+		t.Errorf("Expected a deadline within a minute, got %v", deadline)
+	}
+
+	request, cancelRequest := context.WithCancel(context.Background())
+	ctx, cancel = timeouts.write(request)
+	defer cancel()
+	cancelRequest()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Expected the operation to be cancelled with the request, got %v", ctx.Err())
+	}
+
+	// without a timeout only the context limits the operation
+	ctx, cancel = Timeouts{}.read(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok || ctx.Err() != nil {
+		t.Errorf("Expected no deadline, got %v", ctx.Err())
+	}
+}
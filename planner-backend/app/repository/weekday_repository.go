@@ -15,21 +15,24 @@ import (
 **/
 
 type WeekdayRepository interface {
-	DeleteAllWeekdaysFromTimeslot(timeslot *dao.Timeslot) error
-	AddWeekdaysToTimeslot(timeslot *dao.Timeslot, weekdays []dao.OnWeekday) ([]dao.OnWeekday, error)
+	DeleteAllWeekdaysFromTimeslot(ctx context.Context, timeslot *dao.Timeslot) error
+	AddWeekdaysToTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekdays []dao.OnWeekday) ([]dao.OnWeekday, error)
 
-	AddWeekdayToTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error)
-	DeleteWeekdayFromTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) error
-	UpdateWeekdayForTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error)
+	AddWeekdayToTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error)
+	DeleteWeekdayFromTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) error
+	UpdateWeekdayForTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error)
 }
 
 type WeekdayRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (w WeekdayRepositoryImpl) AddWeekdaysToTimeslot(timeslot *dao.Timeslot, weekdays []dao.OnWeekday) ([]dao.OnWeekday, error) {
+func (w WeekdayRepositoryImpl) AddWeekdaysToTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekdays []dao.OnWeekday) ([]dao.OnWeekday, error) {
 	/* Adds a list of weekdays to a timeslot */
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (d:Department {id: $departmentID}) -[:HAS_WORKPLACE]-> (wp:Workplace {id: $workplaceID}) -[:HAS_TIMESLOT]-> (t:Timeslot {id: $timeslotID})
 	UNWIND $weekdays AS weekday
@@ -61,13 +64,7 @@ func (w WeekdayRepositoryImpl) AddWeekdaysToTimeslot(timeslot *dao.Timeslot, wee
 		"weekdays":     weekdaysMap,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, w.db, "WeekdayRepository", "AddWeekdaysToTimeslot", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -100,8 +97,11 @@ func (w WeekdayRepositoryImpl) AddWeekdaysToTimeslot(timeslot *dao.Timeslot, wee
 	return weekdaysResponse, nil
 }
 
-func (w WeekdayRepositoryImpl) DeleteAllWeekdaysFromTimeslot(timeslot *dao.Timeslot) error {
+func (w WeekdayRepositoryImpl) DeleteAllWeekdaysFromTimeslot(ctx context.Context, timeslot *dao.Timeslot) error {
 	/* Deletes all weekdays from a timeslot */
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})-[:HAS_TIMESLOT]->(t:Timeslot {id: $timeslotID})
 	MATCH (t) -[r:OFFERED_ON]-> (wd:Weekday)
@@ -114,13 +114,7 @@ func (w WeekdayRepositoryImpl) DeleteAllWeekdaysFromTimeslot(timeslot *dao.Times
 		"timeslotID":   timeslot.ID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, w.db, "WeekdayRepository", "DeleteAllWeekdaysFromTimeslot", query, params)
 
 	if err != nil {
 		return err
@@ -129,9 +123,12 @@ func (w WeekdayRepositoryImpl) DeleteAllWeekdaysFromTimeslot(timeslot *dao.Times
 	return nil
 }
 
-func (w WeekdayRepositoryImpl) AddWeekdayToTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
+func (w WeekdayRepositoryImpl) AddWeekdayToTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
 	/* Adds a weekday to a timeslot */
 
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
     MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})-[:HAS_TIMESLOT]->(t:Timeslot {id: $timeslotID})
     MATCH (wd:Weekday {id: $weekdayID})
@@ -159,13 +156,7 @@ func (w WeekdayRepositoryImpl) AddWeekdayToTimeslot(timeslot *dao.Timeslot, week
 		"endTime":      weekday.EndTime,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, w.db, "WeekdayRepository", "AddWeekdayToTimeslot", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -198,9 +189,12 @@ func (w WeekdayRepositoryImpl) AddWeekdayToTimeslot(timeslot *dao.Timeslot, week
 	return weekdays, nil
 }
 
-func (w WeekdayRepositoryImpl) DeleteWeekdayFromTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) error {
+func (w WeekdayRepositoryImpl) DeleteWeekdayFromTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) error {
 	/* Deletes a weekday from a timeslot */
 
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
     MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})-[:HAS_TIMESLOT]->(t:Timeslot {id: $timeslotID})
     MATCH (wd:Weekday {id: $weekdayID})
@@ -214,13 +208,7 @@ func (w WeekdayRepositoryImpl) DeleteWeekdayFromTimeslot(timeslot *dao.Timeslot,
 		"weekdayID":    weekday.ID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, w.db, "WeekdayRepository", "DeleteWeekdayFromTimeslot", query, params)
 	if err != nil {
 		return err
 	}
@@ -228,7 +216,9 @@ func (w WeekdayRepositoryImpl) DeleteWeekdayFromTimeslot(timeslot *dao.Timeslot,
 	return nil
 }
 
-func (w WeekdayRepositoryImpl) UpdateWeekdayForTimeslot(timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
+func (w WeekdayRepositoryImpl) UpdateWeekdayForTimeslot(ctx context.Context, timeslot *dao.Timeslot, weekday *dao.OnWeekday) ([]dao.OnWeekday, error) {
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
 
 	query := `
 	    MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(wp:Workplace {id: $workplaceID})-[:HAS_TIMESLOT]->(t:Timeslot {id: $timeslotID})
@@ -253,13 +243,7 @@ func (w WeekdayRepositoryImpl) UpdateWeekdayForTimeslot(timeslot *dao.Timeslot,
 		"endTime":      weekday.EndTime,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, w.db, "WeekdayRepository", "UpdateWeekdayForTimeslot", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -292,10 +276,10 @@ func (w WeekdayRepositoryImpl) UpdateWeekdayForTimeslot(timeslot *dao.Timeslot,
 	return weekdays, nil
 }
 
-func WeekdayRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *WeekdayRepositoryImpl {
+func WeekdayRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *WeekdayRepositoryImpl {
 	return &WeekdayRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
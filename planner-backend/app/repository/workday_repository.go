@@ -14,23 +14,27 @@ type WorkdayRepository interface {
 	 * Gets all Workdays along with the (if present) assigned user
 	 * for a given date
 	 */
-	GetWorkdaysForDepartmentAndDate(departmentID string, date string, active bool) ([]dao.Workday, error)
-	GetWorkday(departmentID string, workplaceID string, timeslotID string, date string) (dao.Workday, error)
-	Save(workday *dao.Workday) error
+	GetWorkdaysForDepartmentAndDate(ctx context.Context, departmentID string, date string, active bool) ([]dao.Workday, error)
+	GetWorkday(ctx context.Context, departmentID string, workplaceID string, timeslotID string, date string) (dao.Workday, error)
+	// Gets the active Workdays a person is assigned to within a range of dates
+	GetWorkdaysForPerson(ctx context.Context, personID string, startDate string, endDate string) ([]dao.Workday, error)
+	Save(ctx context.Context, workday *dao.Workday) error
 	// UpdateWorkday()
 	// DeleteWorkday()
 
 	// Main interface to Assign people to a given workday
-	AssignPersonToWorkday(personID string, departmentID string, workplaceID string, timeslotID string, date string) error
-	UnassignPersonFromWorkday(personID string, departmentID string, workplaceID string, timeslotID string, date string) error
+	AssignPersonToWorkday(ctx context.Context, personID string, departmentID string, workplaceID string, timeslotID string, date string) error
+	UnassignPersonFromWorkday(ctx context.Context, personID string, departmentID string, workplaceID string, timeslotID string, date string) error
 }
 
 type WorkdayRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (w WorkdayRepositoryImpl) GetWorkdaysForDepartmentAndDate(departmentID string, date string, active bool) ([]dao.Workday, error) {
+func (w WorkdayRepositoryImpl) GetWorkdaysForDepartmentAndDate(ctx context.Context, departmentID string, date string, active bool) ([]dao.Workday, error) {
+	ctx, cancel := w.timeouts.read(ctx)
+	defer cancel()
 
 	query := `
 	// fetch the department
@@ -54,13 +58,7 @@ func (w WorkdayRepositoryImpl) GetWorkdaysForDepartmentAndDate(departmentID stri
 		"departmentID": departmentID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, w.db, "WorkdayRepository", "GetWorkdaysForDepartmentAndDate", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +76,10 @@ func (w WorkdayRepositoryImpl) GetWorkdaysForDepartmentAndDate(departmentID stri
 	return workdays, nil
 }
 
-func (w WorkdayRepositoryImpl) GetWorkday(departmentID string, workplaceID string, timeslotID string, date string) (dao.Workday, error) {
+func (w WorkdayRepositoryImpl) GetWorkday(ctx context.Context, departmentID string, workplaceID string, timeslotID string, date string) (dao.Workday, error) {
+	ctx, cancel := w.timeouts.read(ctx)
+	defer cancel()
+
 	query := `
 	// fetch the department, workplace, timeslot, and the workday
 	MATCH (d:Department {id: $departmentID}) -[:HAS_WORKPLACE]-> (w:Workplace {id: $workplaceID}) -[:HAS_TIMESLOT]-> (t:Timeslot {id: $timeslotID}) <-[:IS_TIMESLOT]- (wkd:Workday {date: date($date)})
@@ -96,13 +97,7 @@ func (w WorkdayRepositoryImpl) GetWorkday(departmentID string, workplaceID strin
 		"timeslotID":   timeslotID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, w.db, "WorkdayRepository", "GetWorkday", query, params)
 	if err != nil {
 		return dao.Workday{}, err
 	}
@@ -119,7 +114,53 @@ func (w WorkdayRepositoryImpl) GetWorkday(departmentID string, workplaceID strin
 	return workday, nil
 }
 
-func (w WorkdayRepositoryImpl) Save(workday *dao.Workday) error {
+func (w WorkdayRepositoryImpl) GetWorkdaysForPerson(ctx context.Context, personID string, startDate string, endDate string) ([]dao.Workday, error) {
+	ctx, cancel := w.timeouts.read(ctx)
+	defer cancel()
+
+	query := `
+	// fetch the workdays the person is assigned to
+	MATCH (:Person {id: $personID}) -[:ASSIGNED_TO]-> (wkd:Workday) -[:IS_TIMESLOT]-> (t:Timeslot) <-[:HAS_TIMESLOT]- (w:Workplace) <-[:HAS_WORKPLACE]- (d:Department)
+	WHERE wkd.active = true AND wkd.date >= date($startDate) AND wkd.date <= date($endDate)
+	// fetch all persons assigned to the workday
+	OPTIONAL MATCH (wkd)<-[:ASSIGNED_TO]-(p:Person)
+	RETURN wkd, collect(p) as persons, t, w, d, wkd.date as date
+	ORDER BY wkd.date, wkd.start_time, w.id, t.name
+	`
+
+	params := map[string]interface{}{
+		"personID":  personID,
+		"startDate": startDate,
+		"endDate":   endDate,
+	}
+
+	result, err := executeRead(ctx, w.db, "WorkdayRepository", "GetWorkdaysForPerson", query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	workdays := []dao.Workday{}
+	for _, record := range result.Records {
+		dbDate, _, err := neo4j.GetRecordValue[neo4j.Date](record, "date")
+		if err != nil {
+			return nil, err
+		}
+
+		workday := dao.Workday{}
+		if err := workday.ParseFromDBRecord(record, dbDate.Time().Format("2006-01-02")); err != nil {
+			return nil, err
+		}
+
+		workdays = append(workdays, workday)
+	}
+
+	return workdays, nil
+}
+
+func (w WorkdayRepositoryImpl) Save(ctx context.Context, workday *dao.Workday) error {
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH (d:Department {id: $departmentID}) -[:HAS_WORKPLACE]-> (w:Workplace {id: $workplaceID}) -[:HAS_TIMESLOT]-> (t:Timeslot {id: $timeslotID})
 	MATCH (dt:Date {date: date($date)})
@@ -147,13 +188,7 @@ func (w WorkdayRepositoryImpl) Save(workday *dao.Workday) error {
 		"comment":      workday.Comment,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, w.db, "WorkdayRepository", "Save", query, params)
 	if err != nil {
 		return err
 	}
@@ -169,7 +204,9 @@ func (w WorkdayRepositoryImpl) Save(workday *dao.Workday) error {
 	return nil
 }
 
-func (w WorkdayRepositoryImpl) AssignPersonToWorkday(personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
+func (w WorkdayRepositoryImpl) AssignPersonToWorkday(ctx context.Context, personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
 
 	query := `
 	// delete the relationship between the person and the workday
@@ -189,13 +226,7 @@ func (w WorkdayRepositoryImpl) AssignPersonToWorkday(personID string, department
 		"timeslotID":   timeslotID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, w.db, "WorkdayRepository", "AssignPersonToWorkday", query, params)
 	if err != nil {
 		return err
 	}
@@ -207,7 +238,9 @@ func (w WorkdayRepositoryImpl) AssignPersonToWorkday(personID string, department
 	return nil
 }
 
-func (w WorkdayRepositoryImpl) UnassignPersonFromWorkday(personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
+func (w WorkdayRepositoryImpl) UnassignPersonFromWorkday(ctx context.Context, personID string, departmentID string, workplaceID string, timeslotID string, date string) error {
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
 
 	query := `
 	MATCH (wkd:Workday {date: date($date), department: $departmentID, workplace: $workplaceID, timeslot: $timeslotID, active: true})
@@ -223,13 +256,7 @@ func (w WorkdayRepositoryImpl) UnassignPersonFromWorkday(personID string, depart
 		"timeslotID":   timeslotID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, w.db, "WorkdayRepository", "UnassignPersonFromWorkday", query, params)
 	if err != nil {
 		return err
 	}
@@ -237,10 +264,10 @@ func (w WorkdayRepositoryImpl) UnassignPersonFromWorkday(personID string, depart
 	return nil
 }
 
-func WorkdayRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *WorkdayRepositoryImpl {
+func WorkdayRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *WorkdayRepositoryImpl {
 	return &WorkdayRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
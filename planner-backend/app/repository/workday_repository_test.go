@@ -228,10 +228,9 @@ func TestWorkdayRepositorySave(t *testing.T) {
 			timeslotCreatorThree.Create(db, ctx)
 
 			s := SynchronizeRepositoryImpl{
-				ctx: ctx,
-				db:  db,
+				db: db,
 			}
-			if err := s.Synchronize(2); err != nil {
+			if _, err := s.Synchronize(ctx, 2); err != nil {
 				t.Errorf("Error synchronizing database: %v", err)
 			}
 
@@ -241,11 +240,10 @@ func TestWorkdayRepositorySave(t *testing.T) {
 
 			// Run the test
 			w := WorkdayRepositoryImpl{
-				db:  db,
-				ctx: ctx,
+				db: db,
 			}
 
-			err = w.Save(test.workday)
+			err = w.Save(ctx, test.workday)
 
 			// Check the result
 			if test.expectedError == nil && err != nil {
@@ -259,7 +257,7 @@ func TestWorkdayRepositorySave(t *testing.T) {
 				return
 			}
 
-			res, err := w.GetWorkday(test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
+			res, err := w.GetWorkday(ctx, test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
 			if err != nil {
 				t.Errorf("Error getting workday: %v", err)
 			}
@@ -657,10 +655,9 @@ func TestAssignOnePersonToWorkday(t *testing.T) {
 			personToCreateThree.Create(db, ctx)
 
 			s := SynchronizeRepositoryImpl{
-				ctx: ctx,
-				db:  db,
+				db: db,
 			}
-			if err := s.Synchronize(2); err != nil {
+			if _, err := s.Synchronize(ctx, 2); err != nil {
 				t.Errorf("Error synchronizing database: %v", err)
 			}
 
@@ -670,12 +667,11 @@ func TestAssignOnePersonToWorkday(t *testing.T) {
 
 			// Run the test
 			w := WorkdayRepositoryImpl{
-				db:  db,
-				ctx: ctx,
+				db: db,
 			}
 
 			// Assigned person on workday should not exist
-			wd, err := w.GetWorkday(test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
+			wd, err := w.GetWorkday(ctx, test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
 			if err != nil && test.shouldGetWorkday {
 				t.Errorf("Error getting workday: %v", err)
 			} else {
@@ -686,7 +682,7 @@ func TestAssignOnePersonToWorkday(t *testing.T) {
 			}
 
 			for _, person := range test.personsToAssign {
-				err = w.AssignPersonToWorkday(person.ID, test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
+				err = w.AssignPersonToWorkday(ctx, person.ID, test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
 				if person.expectError && err == nil {
 					t.Errorf("Expected no error, but got %v", err)
 				}
@@ -700,7 +696,7 @@ func TestAssignOnePersonToWorkday(t *testing.T) {
 			}
 
 			// Check the result
-			res, err := w.GetWorkday(test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
+			res, err := w.GetWorkday(ctx, test.workday.Department.ID, test.workday.Workplace.ID, test.workday.Timeslot.ID, test.workday.Date)
 			if err != nil {
 				t.Errorf("Error getting workday: %v", err)
 			}
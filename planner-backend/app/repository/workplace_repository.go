@@ -11,19 +11,22 @@ import (
 
 type WorkplaceRepository interface {
 	// Function Used by the service
-	FindAllWorkplaces(departmentID string) ([]dao.Workplace, error)
-	FindWorkplaceByID(departmentID string, workplaceID string) (dao.Workplace, error)
-	Save(departmentID string, workplace *dao.Workplace) (dao.Workplace, error)
-	Delete(departmentID string, workplace *dao.Workplace) error
+	FindAllWorkplaces(ctx context.Context, departmentID string) ([]dao.Workplace, error)
+	FindWorkplaceByID(ctx context.Context, departmentID string, workplaceID string) (dao.Workplace, error)
+	Save(ctx context.Context, departmentID string, workplace *dao.Workplace) (dao.Workplace, error)
+	Delete(ctx context.Context, departmentID string, workplace *dao.Workplace) error
 }
 
 type WorkplaceRepositoryImpl struct {
-	db  *neo4j.DriverWithContext
-	ctx context.Context
+	db       *neo4j.DriverWithContext
+	timeouts Timeouts
 }
 
-func (w WorkplaceRepositoryImpl) FindAllWorkplaces(departmentID string) ([]dao.Workplace, error) {
+func (w WorkplaceRepositoryImpl) FindAllWorkplaces(ctx context.Context, departmentID string) ([]dao.Workplace, error) {
 	/* Returns all workplaces */
+	ctx, cancel := w.timeouts.read(ctx)
+	defer cancel()
+
 	workplaces := []dao.Workplace{}
 	query := `
     MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(w:Workplace)
@@ -33,13 +36,7 @@ func (w WorkplaceRepositoryImpl) FindAllWorkplaces(departmentID string) ([]dao.W
 		"departmentID": departmentID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, w.db, "WorkplaceRepository", "FindAllWorkplaces", query, params)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +53,10 @@ func (w WorkplaceRepositoryImpl) FindAllWorkplaces(departmentID string) ([]dao.W
 	return workplaces, nil
 }
 
-func (w WorkplaceRepositoryImpl) FindWorkplaceByID(departmentID string, workplaceID string) (dao.Workplace, error) {
+func (w WorkplaceRepositoryImpl) FindWorkplaceByID(ctx context.Context, departmentID string, workplaceID string) (dao.Workplace, error) {
+	ctx, cancel := w.timeouts.read(ctx)
+	defer cancel()
+
 	workplace := dao.Workplace{}
 	query := `
 	MATCH (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(w:Workplace {id: $workplaceID})
@@ -67,13 +67,7 @@ func (w WorkplaceRepositoryImpl) FindWorkplaceByID(departmentID string, workplac
 		"workplaceID":  workplaceID,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeRead(ctx, w.db, "WorkplaceRepository", "FindWorkplaceByID", query, params)
 	if err != nil {
 		return dao.Workplace{}, err
 	}
@@ -89,8 +83,11 @@ func (w WorkplaceRepositoryImpl) FindWorkplaceByID(departmentID string, workplac
 	return workplace, nil
 }
 
-func (w WorkplaceRepositoryImpl) Save(departmentID string, workplace *dao.Workplace) (dao.Workplace, error) {
+func (w WorkplaceRepositoryImpl) Save(ctx context.Context, departmentID string, workplace *dao.Workplace) (dao.Workplace, error) {
 	/* Saves a workplace */
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
     MATCH (d:Department {id: $departmentID})
 	WHERE d.deleted_at IS NULL
@@ -111,13 +108,7 @@ func (w WorkplaceRepositoryImpl) Save(departmentID string, workplace *dao.Workpl
 		"workplaceName": workplace.Name,
 	}
 
-	result, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	result, err := executeWrite(ctx, w.db, "WorkplaceRepository", "Save", query, params)
 	if err != nil {
 		return dao.Workplace{}, err
 	}
@@ -133,8 +124,11 @@ func (w WorkplaceRepositoryImpl) Save(departmentID string, workplace *dao.Workpl
 	return *workplace, nil
 }
 
-func (w WorkplaceRepositoryImpl) Delete(departmentID string, workplace *dao.Workplace) error {
+func (w WorkplaceRepositoryImpl) Delete(ctx context.Context, departmentID string, workplace *dao.Workplace) error {
 	/* Deletes a department */
+	ctx, cancel := w.timeouts.write(ctx)
+	defer cancel()
+
 	query := `
 	MATCH  (d:Department {id: $departmentID})-[:HAS_WORKPLACE]->(w:Workplace {id: $workplaceID})
 	SET w.deleted_at = datetime()`
@@ -143,13 +137,7 @@ func (w WorkplaceRepositoryImpl) Delete(departmentID string, workplace *dao.Work
 		"workplaceID":  workplace.ID,
 	}
 
-	_, err := neo4j.ExecuteQuery(
-		w.ctx,
-		*w.db,
-		query,
-		params,
-		neo4j.EagerResultTransformer,
-	)
+	_, err := executeWrite(ctx, w.db, "WorkplaceRepository", "Delete", query, params)
 	if err != nil {
 		return err
 	}
@@ -157,10 +145,10 @@ func (w WorkplaceRepositoryImpl) Delete(departmentID string, workplace *dao.Work
 	return nil
 }
 
-func WorkplaceRepositoryInit(db *neo4j.DriverWithContext, ctx context.Context) *WorkplaceRepositoryImpl {
+func WorkplaceRepositoryInit(db *neo4j.DriverWithContext, timeouts Timeouts) *WorkplaceRepositoryImpl {
 	return &WorkplaceRepositoryImpl{
-		db:  db,
-		ctx: ctx,
+		db:       db,
+		timeouts: timeouts,
 	}
 }
 
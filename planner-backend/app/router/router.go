@@ -1,7 +1,9 @@
 package router
 
 import (
-	"os"
+	"planner-backend/app/domain/dco"
+	"planner-backend/app/metrics"
+	"planner-backend/app/middleware"
 	"planner-backend/config"
 
 	"github.com/gin-gonic/gin"
@@ -9,7 +11,7 @@ import (
 
 func Init(init *config.Injector) *gin.Engine {
 	// set gin to release mode
-	if os.Getenv("MODE") == "production" {
+	if init.Config.Production() {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
@@ -17,10 +19,27 @@ func Init(init *config.Injector) *gin.Engine {
 
 	// gin Middlewares
 	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.Metrics())
+	// failed requests are answered by this middleware, it also recovers from panics
+	router.Use(middleware.ErrorHandler())
 
 	// insert custom middlewares here
-	// router.Use()
+	router.Use(middleware.Identity(init.TokenKeys))
+
+	// scraped by Prometheus, protected by PLANNER_METRICS_TOKEN if it is set
+	router.GET("/metrics", middleware.RequiredMetricsToken(init.Config.MetricsToken), metrics.Handler())
+
+	// the api-gateway synchronizes its registry of the departments with these routes
+	internal := router.Group("/internal", middleware.RequiredService(dco.GatewayServiceName))
+	{
+		internal.GET("/department", init.DepartmentCtrl.GetAll)
+		internal.PUT("/department/:departmentID", init.DepartmentCtrl.Sync)
+		internal.DELETE("/department/:departmentID", init.DepartmentCtrl.Remove)
+
+		// leave requests are filed on behalf of the employee linked to the person
+		internal.GET("/person/:personID/leave-request", init.LeaveRequestCtrl.GetAllForPerson)
+		internal.POST("/person/:personID/leave-request", init.LeaveRequestCtrl.Create)
+	}
 
 	plannerAPI := router.Group("/api/v1/planner")
 	{
@@ -44,7 +63,7 @@ func Init(init *config.Injector) *gin.Engine {
 		}
 		// secured routes
 		departmentSecured := plannerAPI.Group("/department")
-		//departmentSecured.Use(middleware.RequiredAuth())
+		departmentSecured.Use(middleware.RequiredAuth())
 		{
 
 			departmentSecured.POST("/", init.DepartmentCtrl.Create)
@@ -66,6 +85,12 @@ func Init(init *config.Injector) *gin.Engine {
 			weekdaySecured.PUT("/", init.WeekdayCtrl.UpdateWeekdayForTimeslot)
 			weekdaySecured.DELETE("/", init.WeekdayCtrl.RemoveWeekdayFromTimeslot)
 			weekdaySecured.POST("/bulk", init.WeekdayCtrl.BulkUpdateWeekdaysForTimeslot)
+
+			// the leave requests of a department are only visible to its members
+			leaveRequestSecured := departmentSecured.Group("/:departmentID/leave-request", middleware.RequiredDepartmentMember("departmentID"))
+			leaveRequestSecured.GET("/", init.LeaveRequestCtrl.GetAllForDepartment)        // ?status=pending|approved|rejected|all
+			leaveRequestSecured.POST("/:requestID/approve", init.LeaveRequestCtrl.Approve) // ?force=true
+			leaveRequestSecured.POST("/:requestID/reject", init.LeaveRequestCtrl.Reject)
 		}
 
 		person := plannerAPI.Group("/person")
@@ -80,7 +105,7 @@ func Init(init *config.Injector) *gin.Engine {
 		}
 		// secured routes
 		personSecured := plannerAPI.Group("/person")
-		//personSecured.Use(middleware.RequiredAuth())
+		personSecured.Use(middleware.RequiredAuth())
 		{
 			personSecured.POST("/", init.PersonCtrl.Create)
 			personSecured.PUT("/:personID", init.PersonCtrl.Update)
@@ -88,6 +113,10 @@ func Init(init *config.Injector) *gin.Engine {
 
 			personRelSecured := personSecured.Group("/:personID")
 			{
+				// the plan of a person is only visible to the members of the departments of the person
+				personRelSecured.GET("/workday", init.WorkdayCtrl.GetWorkdaysForPerson) // ?start_date=...&end_date=...
+				personRelSecured.GET("/hours", init.WorkdayCtrl.GetHoursForPerson)      // ?start_date=...&end_date=...
+
 				personRelSecured.POST("/absency", init.PersonRelCtrl.AddAbsency)
 				personRelSecured.DELETE("/absency/:date", init.PersonRelCtrl.RemoveAbsency)
 
@@ -111,7 +140,7 @@ func Init(init *config.Injector) *gin.Engine {
 
 		// secured routes
 		workdaySecured := plannerAPI.Group("/workday")
-		//workdaySecured.Use(middleware.RequiredAuth())
+		workdaySecured.Use(middleware.RequiredAuth())
 		{
 			workdaySecured.PUT("/", init.WorkdayCtrl.UpdateWorkday)
 			workdaySecured.POST("/assign", init.WorkdayCtrl.AssignPersonToWorkday)
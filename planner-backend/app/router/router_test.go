@@ -18,7 +18,7 @@ import (
 )
 
 var token string
-var authErrorString = "{\"response_key\":\"Unauthorized\",\"response_message\":\"Unauthorized\",\"data\":null}"
+var authErrorString = "{\"response_key\":\"Unauthorized\",\"response_message\":\"Unauthorized: Please check your credentials\",\"code\":\"unauthorized\",\"data\":null,\"errors\":[]}"
 
 func TestMain(m *testing.M) {
 	// Generate a mock token
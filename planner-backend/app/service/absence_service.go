@@ -26,23 +26,25 @@ func (a AbsenceServiceImpl) GetAllAbsencies(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all absencies")
 
 	departmentID := c.Param("departmentID")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	date := c.Query("date")
 	if date == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	if _, err := time.Parse("2006-01-02", date); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := a.AbsenceRepository.FindAllAbsencies(departmentID, date)
+	rawData, err := a.AbsenceRepository.FindAllAbsencies(c.Request.Context(), departmentID, date)
 	switch err {
 	case nil:
 		break
@@ -51,7 +53,8 @@ func (a AbsenceServiceImpl) GetAllAbsencies(c *gin.Context) {
 		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapAbsenciesToAbsenciesResponse(rawData)
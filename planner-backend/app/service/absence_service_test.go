@@ -74,8 +74,7 @@ func TestGetAllAbsencies(t *testing.T) {
 				t.Errorf("Error while creating test context: %v", err)
 			}
 
-			absenceService.GetAllAbsencies(c)
-
+			serve(c, absenceService.GetAllAbsencies)
 			if w.Code != testStep.expectedStatusCode {
 				t.Errorf("Expected status code: %d, got: %d", testStep.expectedStatusCode, w.Code)
 			}
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"planner-backend/app/constant"
 	"planner-backend/app/domain/dao"
 	"planner-backend/app/domain/dco"
+	"planner-backend/app/gateway"
 	"planner-backend/app/pkg"
 	"planner-backend/app/repository"
 
@@ -20,10 +22,18 @@ type DepartmentService interface {
 	AddDepartment(c *gin.Context)
 	UpdateDepartment(c *gin.Context)
 	DeleteDepartment(c *gin.Context)
+
+	// Used by the api-gateway to synchronize its registry, the changes are not reported back
+	SyncDepartment(c *gin.Context)
+	RemoveDepartment(c *gin.Context)
 }
 
 type DepartmentServiceImpl struct {
 	DepartmentRepository repository.DepartmentRepository
+	// Reports the changes to the api-gateway, nil if it is not configured
+	Gateway gateway.Client
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
 }
 
 func (d DepartmentServiceImpl) GetAllDepartments(c *gin.Context) {
@@ -32,13 +42,13 @@ func (d DepartmentServiceImpl) GetAllDepartments(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all departments")
 
-	rawData, err := d.DepartmentRepository.FindAllDepartments()
+	rawData, err := d.DepartmentRepository.FindAllDepartments(c.Request.Context())
 	if err != nil {
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapDepartmentListToDepartmentResponseList(rawData)
@@ -52,19 +62,20 @@ func (d DepartmentServiceImpl) GetDepartmentByID(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get department by name")
 
 	id := c.Param("departmentID")
-	rawData, err := d.DepartmentRepository.FindDepartmentByID(id)
+	rawData, err := d.DepartmentRepository.FindDepartmentByID(c.Request.Context(), id)
 	switch err {
 	case nil:
 		break
 	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapDepartmentToDepartmentResponse(rawData)
@@ -78,33 +89,42 @@ func (d DepartmentServiceImpl) AddDepartment(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add department")
 
 	var departmentRequest dco.DepartmentRequest
 	if err := c.ShouldBindJSON(&departmentRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
 	department := mapDepartmentRequestToDepartment(departmentRequest)
 
-	_, err := d.DepartmentRepository.FindDepartmentByID(department.ID)
-	switch err {
-	case nil:
-		pkg.PanicException(constant.Conflict)
-	case pkg.ErrNoRows:
-		break
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	rawData, err := d.DepartmentRepository.Save(&department)
-	if err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Department
+	if err := d.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		_, err := d.DepartmentRepository.FindDepartmentByID(ctx, department.ID)
+		switch err {
+		case nil:
+			return pkg.NewError(constant.Conflict)
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		rawData, err = d.DepartmentRepository.Save(ctx, &department)
+		if err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
+	d.reportSave(rawData)
 
 	data := mapDepartmentToDepartmentResponse(rawData)
 
@@ -116,33 +136,42 @@ func (d DepartmentServiceImpl) UpdateDepartment(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update department")
 
 	id := c.Param("departmentID")
-	department, err := d.DepartmentRepository.FindDepartmentByID(id)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
 	var departmentRequest dco.DepartmentRequest
 	if err := c.ShouldBindJSON(&departmentRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	department.Name = departmentRequest.Name
-	rawData, err := d.DepartmentRepository.Save(&department)
-	if err != nil {
-		slog.Error("Error when updating data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Department
+	if err := d.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		department, err := d.DepartmentRepository.FindDepartmentByID(ctx, id)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		department.Name = departmentRequest.Name
+		rawData, err = d.DepartmentRepository.Save(ctx, &department)
+		if err != nil {
+			slog.Error("Error when updating data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
+	d.reportSave(rawData)
 
 	data := mapDepartmentToDepartmentResponse(rawData)
 
@@ -155,35 +184,104 @@ func (d DepartmentServiceImpl) DeleteDepartment(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete department")
 
 	name := c.Param("departmentID")
-	department, err := d.DepartmentRepository.FindDepartmentByID(name)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var department dao.Department
+	if err := d.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		var err error
+		department, err = d.DepartmentRepository.FindDepartmentByID(ctx, name)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		err = d.DepartmentRepository.Delete(ctx, &department)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when updating data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	if d.Gateway != nil {
+		if err := d.Gateway.DeleteDepartment(department.ID); err != nil {
+			slog.Error("Error happened: when report department to api-gateway", "department", department.ID, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
+}
+
+func (d DepartmentServiceImpl) SyncDepartment(c *gin.Context) {
+	/* SyncDepartment creates, renames or restores the department with the id of the path
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program sync department")
+
+	var request dco.DepartmentSyncRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error when binding json", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+
+	department := dao.Department{ID: c.Param("departmentID"), Name: request.Name}
+	rawData, err := d.DepartmentRepository.Save(c.Request.Context(), &department)
+	if err != nil {
+		slog.Error("Error when saving data to database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
-	err = d.DepartmentRepository.Delete(&department)
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapDepartmentToDepartmentResponse(rawData)))
+}
+
+func (d DepartmentServiceImpl) RemoveDepartment(c *gin.Context) {
+	/* RemoveDepartment deletes the department with the id of the path, unknown departments are ignored
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program remove department")
+
+	err := d.DepartmentRepository.Delete(c.Request.Context(), &dao.Department{ID: c.Param("departmentID")})
 	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
+	case nil, pkg.ErrNoRows:
 		break
 	default:
 		slog.Error("Error when updating data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
 }
 
+func (d DepartmentServiceImpl) reportSave(department dao.Department) {
+	/* Reports a created or renamed department to the api-gateway, failures are repaired by its reconciliation */
+	if d.Gateway == nil {
+		return
+	}
+	if err := d.Gateway.SaveDepartment(gateway.Department{ID: department.ID, Name: department.Name}); err != nil {
+		slog.Error("Error happened: when report department to api-gateway", "department", department.ID, "error", err)
+	}
+}
+
 func mapDepartmentToDepartmentResponse(department dao.Department) dco.DepartmentResponse {
 	/* mapDepartmentToDepartmentResponse is a function to map department to department response
 	 * @param department is a department
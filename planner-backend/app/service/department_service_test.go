@@ -17,6 +17,7 @@ func TestDeleteDepartment(t *testing.T) {
 	departmentMockRepo := mock.NewDepartmentRepositoryMock()
 	departmentService := DepartmentServiceImpl{
 		DepartmentRepository: departmentMockRepo,
+		UnitOfWork:           &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestDELETE{
@@ -52,7 +53,7 @@ func TestDeleteDepartment(t *testing.T) {
 			t.Errorf("Test Step %d: Error while building context: %s", i, err)
 		}
 
-		departmentService.DeleteDepartment(c)
+		serve(c, departmentService.DeleteDepartment)
 		response := w.Result()
 
 		if response.StatusCode != testStep.expectedStatusCode {
@@ -66,6 +67,7 @@ func TestUpdateDepartment(t *testing.T) {
 	departmentMockRepo := mock.NewDepartmentRepositoryMock()
 	departmentService := DepartmentServiceImpl{
 		DepartmentRepository: departmentMockRepo,
+		UnitOfWork:           &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPUT{
@@ -130,7 +132,7 @@ func TestUpdateDepartment(t *testing.T) {
 			t.Errorf("Test Step %d: Error while building context: %s", i, err)
 		}
 
-		departmentService.UpdateDepartment(c)
+		serve(c, departmentService.UpdateDepartment)
 		response := w.Result()
 
 		if response.StatusCode != testStep.expectedStatusCode {
@@ -156,6 +158,7 @@ func TestAddDepartment(t *testing.T) {
 	departmentMockRepo := mock.NewDepartmentRepositoryMock()
 	departmentService := DepartmentServiceImpl{
 		DepartmentRepository: departmentMockRepo,
+		UnitOfWork:           &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -229,7 +232,7 @@ func TestAddDepartment(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			departmentService.AddDepartment(c)
+			serve(c, departmentService.AddDepartment)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -256,6 +259,7 @@ func TestGetAllDepartments(t *testing.T) {
 	departmentMockRepo := mock.NewDepartmentRepositoryMock()
 	departmentService := DepartmentServiceImpl{
 		DepartmentRepository: departmentMockRepo,
+		UnitOfWork:           &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestGET{
@@ -299,7 +303,7 @@ func TestGetAllDepartments(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			departmentService.GetAllDepartments(c)
+			serve(c, departmentService.GetAllDepartments)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -328,6 +332,7 @@ func TestGetDepartmentByID(t *testing.T) {
 	departmentMockRepo := mock.NewDepartmentRepositoryMock()
 	departmentService := DepartmentServiceImpl{
 		DepartmentRepository: departmentMockRepo,
+		UnitOfWork:           &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestGET{
@@ -367,7 +372,7 @@ func TestGetDepartmentByID(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			departmentService.GetDepartmentByID(c)
+			serve(c, departmentService.GetDepartmentByID)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -389,3 +394,121 @@ func TestGetDepartmentByID(t *testing.T) {
 		})
 	}
 }
+
+func TestDepartmentChangesAreReported(t *testing.T) {
+	/* Created, renamed and deleted departments are reported to the api-gateway, failures do not fail the request */
+	department := dao.Department{ID: "test", Name: "test"}
+
+	for _, gatewayError := range []error{nil, errors.New("gateway unavailable")} {
+		departmentMockRepo := mock.NewDepartmentRepositoryMock()
+		gatewayMock := &mock.GatewayClientMock{Error: gatewayError}
+		departmentService := DepartmentServiceImpl{
+			DepartmentRepository: departmentMockRepo,
+			Gateway:              gatewayMock,
+			UnitOfWork:           &mock.UnitOfWorkMock{},
+		}
+
+		departmentMockRepo.On("FindDepartmentByID").Return(nil, pkg.ErrNoRows)
+		departmentMockRepo.On("Save").Return(department, nil)
+		w := httptest.NewRecorder()
+		c, _ := mock.NewTestContextBuilder(w).WithMethod("POST").WithBody(map[string]interface{}{"id": "test", "name": "test"}).Build()
+		serve(c, departmentService.AddDepartment)
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected status code %d, got %d", http.StatusCreated, w.Code)
+		}
+
+		departmentMockRepo.On("FindDepartmentByID").Return(department, nil)
+		departmentMockRepo.On("Delete").Return(nil, nil)
+		w = httptest.NewRecorder()
+		c, _ = mock.NewTestContextBuilder(w).WithMethod("DELETE").WithMapParams(map[string]string{"departmentID": "test"}).Build()
+		serve(c, departmentService.DeleteDepartment)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+
+		if len(gatewayMock.Saved) != 1 || gatewayMock.Saved[0].ID != "test" || len(gatewayMock.Deleted) != 1 || gatewayMock.Deleted[0] != "test" {
+			t.Errorf("Expected the changes to be reported, got %v and %v", gatewayMock.Saved, gatewayMock.Deleted)
+		}
+	}
+}
+
+func TestSyncDepartment(t *testing.T) {
+	/* The api-gateway creates or renames departments, the changes are not reported back */
+	testSteps := []ServiceTestPUT{
+		{
+			mockRequestData:    map[string]interface{}{"name": "renamed"},
+			saveValue:          dao.Department{ID: "test", Name: "renamed"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			mockRequestData:    map[string]interface{}{},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			mockRequestData:    map[string]interface{}{"name": "renamed"},
+			saveError:          errors.New("Save error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for i, testStep := range testSteps {
+		departmentMockRepo := mock.NewDepartmentRepositoryMock()
+		gatewayMock := &mock.GatewayClientMock{}
+		departmentService := DepartmentServiceImpl{
+			DepartmentRepository: departmentMockRepo,
+			Gateway:              gatewayMock,
+			UnitOfWork:           &mock.UnitOfWorkMock{},
+		}
+		departmentMockRepo.On("Save").Return(testStep.saveValue, testStep.saveError)
+
+		w := httptest.NewRecorder()
+		c, err := mock.NewTestContextBuilder(w).
+			WithMethod("PUT").WithMapParams(map[string]string{"departmentID": "test"}).WithBody(testStep.mockRequestData).Build()
+		if err != nil {
+			t.Errorf("Test Step %d: Error while building context: %s", i, err)
+		}
+
+		serve(c, departmentService.SyncDepartment)
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Test Step %d: Expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+		if len(gatewayMock.Saved) != 0 {
+			t.Errorf("Test Step %d: Expected the change not to be reported back, got %v", i, gatewayMock.Saved)
+		}
+	}
+}
+
+func TestRemoveDepartment(t *testing.T) {
+	/* Unknown departments are ignored, so the api-gateway can retry */
+	testSteps := []ServiceTestDELETE{
+		{mockError: nil, expectedStatusCode: http.StatusOK},
+		{mockError: pkg.ErrNoRows, expectedStatusCode: http.StatusOK},
+		{mockError: errors.New("Delete error"), expectedStatusCode: http.StatusInternalServerError},
+	}
+
+	for i, testStep := range testSteps {
+		departmentMockRepo := mock.NewDepartmentRepositoryMock()
+		gatewayMock := &mock.GatewayClientMock{}
+		departmentService := DepartmentServiceImpl{
+			DepartmentRepository: departmentMockRepo,
+			Gateway:              gatewayMock,
+			UnitOfWork:           &mock.UnitOfWorkMock{},
+		}
+		departmentMockRepo.On("Delete").Return(nil, testStep.mockError)
+
+		w := httptest.NewRecorder()
+		c, err := mock.NewTestContextBuilder(w).
+			WithMethod("DELETE").WithMapParams(map[string]string{"departmentID": "test"}).Build()
+		if err != nil {
+			t.Errorf("Test Step %d: Error while building context: %s", i, err)
+		}
+
+		serve(c, departmentService.RemoveDepartment)
+		if w.Code != testStep.expectedStatusCode {
+			t.Errorf("Test Step %d: Expected status code %d, got %d", i, testStep.expectedStatusCode, w.Code)
+		}
+		if len(gatewayMock.Deleted) != 0 {
+			t.Errorf("Test Step %d: Expected the change not to be reported back, got %v", i, gatewayMock.Deleted)
+		}
+	}
+}
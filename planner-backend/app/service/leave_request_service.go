@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"planner-backend/app/constant"
+	"planner-backend/app/domain/dao"
+	"planner-backend/app/domain/dco"
+	"planner-backend/app/middleware"
+	"planner-backend/app/notification"
+	"planner-backend/app/pkg"
+	"planner-backend/app/repository"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+)
+
+type LeaveRequestService interface {
+	// Used by the api-gateway on behalf of the employee linked to the person
+	CreateLeaveRequest(c *gin.Context)
+	GetLeaveRequestsForPerson(c *gin.Context)
+
+	// The approval queue of the planners of a department
+	GetLeaveRequestsForDepartment(c *gin.Context)
+	ApproveLeaveRequest(c *gin.Context)
+	RejectLeaveRequest(c *gin.Context)
+}
+
+// Refuses an approval with conflicting assignments, which are answered with the decision
+var errAssignedDuringLeave = errors.New("the person is assigned during the leave")
+
+type LeaveRequestServiceImpl struct {
+	LeaveRequestRepository repository.LeaveRequestRepository
+	PersonRepository       repository.PersonRepository
+	WorkdayRepository      repository.WorkdayRepository
+	// Notified about every decision on a leave request
+	Notifier notification.Notifier
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
+}
+
+func (l LeaveRequestServiceImpl) CreateLeaveRequest(c *gin.Context) {
+	/* CreateLeaveRequest files a pending leave request for a person
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program create leave request")
+
+	personID := c.Param("personID")
+	if personID == "" {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	var request dco.LeaveRequestRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		slog.Error("Error when binding json", "error", err)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
+	}
+	if err := request.Validate(); err != nil {
+		slog.Error("Error when validating leave request", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	var rawData dao.LeaveRequest
+	if err := l.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		_, err := l.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		leaveRequest := dao.LeaveRequest{
+			PersonID:    personID,
+			StartDate:   request.StartDate,
+			EndDate:     request.EndDate,
+			RequestedBy: request.RequestedBy,
+		}
+		if request.Reason != nil {
+			leaveRequest.Reason = *request.Reason
+		}
+
+		rawData, err = l.LeaveRequestRepository.Save(ctx, &leaveRequest)
+		if err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	data := mapLeaveRequestToLeaveRequestResponse(rawData)
+
+	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, data))
+}
+
+func (l LeaveRequestServiceImpl) GetLeaveRequestsForPerson(c *gin.Context) {
+	/* GetLeaveRequestsForPerson gets all leave requests of a person, the newest first
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program get leave requests for person")
+
+	personID := c.Param("personID")
+	if personID == "" {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	rawData, err := l.LeaveRequestRepository.FindLeaveRequestsForPerson(c.Request.Context(), personID)
+	if err != nil {
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	data := mapLeaveRequestListToLeaveRequestResponseList(rawData)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (l LeaveRequestServiceImpl) GetLeaveRequestsForDepartment(c *gin.Context) {
+	/* GetLeaveRequestsForDepartment gets the leave requests of the persons working at a department
+	 * ?status=pending|approved|rejected|all, the pending requests by default
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program get leave requests for department")
+
+	departmentID := c.Param("departmentID")
+	if departmentID == "" {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	status := c.DefaultQuery("status", dao.LeaveRequestPending)
+	switch status {
+	case dao.LeaveRequestPending, dao.LeaveRequestApproved, dao.LeaveRequestRejected:
+		break
+	case "all":
+		status = ""
+	default:
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+
+	rawData, err := l.LeaveRequestRepository.FindLeaveRequestsForDepartment(c.Request.Context(), departmentID, status)
+	if err != nil {
+		slog.Error("Error when fetching data from database", "error", err)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
+	}
+
+	data := mapLeaveRequestListToLeaveRequestResponseList(rawData)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (l LeaveRequestServiceImpl) ApproveLeaveRequest(c *gin.Context) {
+	/* ApproveLeaveRequest marks the person absent during the leave
+	 * The approval is refused with the conflicting assignments of the person,
+	 * with ?force=true the assignments are released
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program approve leave request")
+
+	request, err := bindDecision(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	// the request is decided in the state it was checked in
+	var data dco.LeaveRequestDecisionResponse
+	err = l.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		leaveRequest, err := l.findLeaveRequest(ctx, c)
+		if err != nil {
+			return err
+		}
+		if leaveRequest.Status == dao.LeaveRequestApproved {
+			return pkg.NewError(constant.Conflict).WithMessage("The leave request is already approved")
+		}
+
+		conflicts, err := l.WorkdayRepository.GetWorkdaysForPerson(ctx, leaveRequest.PersonID, leaveRequest.StartDate, leaveRequest.EndDate)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		data = dco.LeaveRequestDecisionResponse{
+			LeaveRequest: mapLeaveRequestToLeaveRequestResponse(leaveRequest),
+			Conflicts:    mapWorkdayListToWorkdayResponseList(conflicts),
+		}
+		if len(conflicts) > 0 && c.Query("force") != "true" {
+			return errAssignedDuringLeave
+		}
+
+		data.LeaveRequest, err = l.decide(ctx, c, leaveRequest, dao.LeaveRequestApproved, request)
+		return err
+	})
+	switch {
+	case err == errAssignedDuringLeave:
+		c.JSON(http.StatusConflict, pkg.BuildResponse_(
+			constant.Conflict.GetResponseStatus(),
+			"The person is assigned during the leave, approve with ?force=true to release the assignments",
+			data,
+		))
+		return
+	case err != nil:
+		pkg.Abort(c, err)
+		return
+	}
+	l.notify(notification.LeaveRequestApproved, data)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (l LeaveRequestServiceImpl) RejectLeaveRequest(c *gin.Context) {
+	/* RejectLeaveRequest rejects a leave request, the absences of an earlier approval are removed
+	 * @param c is gin context
+	 * @return void
+	 */
+	slog.Info("start to execute program reject leave request")
+
+	request, err := bindDecision(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	var decided dco.LeaveRequestResponse
+	if err := l.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		leaveRequest, err := l.findLeaveRequest(ctx, c)
+		if err != nil {
+			return err
+		}
+		if leaveRequest.Status == dao.LeaveRequestRejected {
+			return pkg.NewError(constant.Conflict).WithMessage("The leave request is already rejected")
+		}
+
+		decided, err = l.decide(ctx, c, leaveRequest, dao.LeaveRequestRejected, request)
+		return err
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	data := dco.LeaveRequestDecisionResponse{
+		LeaveRequest: decided,
+		Conflicts:    []dco.WorkdayResponse{},
+	}
+	l.notify(notification.LeaveRequestRejected, data)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (l LeaveRequestServiceImpl) findLeaveRequest(ctx context.Context, c *gin.Context) (dao.LeaveRequest, error) {
+	/* Helper to find the leave request of the route in its department */
+	departmentID := c.Param("departmentID")
+	requestID := c.Param("requestID")
+	if departmentID == "" || requestID == "" {
+		return dao.LeaveRequest{}, pkg.NewError(constant.InvalidRequest)
+	}
+
+	leaveRequest, err := l.LeaveRequestRepository.FindLeaveRequestByID(ctx, departmentID, requestID)
+	switch err {
+	case nil:
+		break
+	case pkg.ErrNoRows:
+		return leaveRequest, pkg.NewError(constant.DataNotFound)
+	default:
+		slog.Error("Error when fetching data from database", "error", err)
+		return leaveRequest, pkg.NewError(constant.UnknownError).Wrap(err)
+	}
+
+	return leaveRequest, nil
+}
+
+func bindDecision(c *gin.Context) (dco.LeaveRequestDecisionRequest, error) {
+	/* Helper to bind the optional comment of a decision, the body is read once before the transaction */
+	var request dco.LeaveRequestDecisionRequest
+	if err := c.ShouldBindJSON(&request); err != nil && err != io.EOF {
+		slog.Error("Error when binding json", "error", err)
+		return request, pkg.ValidationError(err)
+	}
+	return request, nil
+}
+
+func (l LeaveRequestServiceImpl) decide(ctx context.Context, c *gin.Context, leaveRequest dao.LeaveRequest, status string, request dco.LeaveRequestDecisionRequest) (dco.LeaveRequestResponse, error) {
+	/* Helper to store a decision with the optional comment of the body */
+	now := time.Now()
+	leaveRequest.Status = status
+	leaveRequest.DecidedAt = &now
+	leaveRequest.Comment = ""
+	if request.Comment != nil {
+		leaveRequest.Comment = *request.Comment
+	}
+	if identity, ok := middleware.GetIdentity(c); ok {
+		leaveRequest.DecidedBy = identity.Username
+	}
+
+	if err := l.LeaveRequestRepository.Decide(ctx, leaveRequest); err != nil {
+		slog.Error("Error when saving data to database", "error", err)
+		return dco.LeaveRequestResponse{}, pkg.NewError(constant.UnknownError).Wrap(err)
+	}
+
+	return mapLeaveRequestToLeaveRequestResponse(leaveRequest), nil
+}
+
+func (l LeaveRequestServiceImpl) notify(eventType string, data dco.LeaveRequestDecisionResponse) {
+	/* Notifies about a decision, failures do not revert the decision */
+	if l.Notifier == nil {
+		return
+	}
+	event := notification.Event{Type: eventType, OccurredAt: time.Now(), Data: data}
+	if err := l.Notifier.Notify(event); err != nil {
+		slog.Error("Error happened: when notify about leave request", "leave_request", data.LeaveRequest.ID, "error", err)
+	}
+}
+
+func mapLeaveRequestToLeaveRequestResponse(leaveRequest dao.LeaveRequest) dco.LeaveRequestResponse {
+	/* Maps a LeaveRequest to a LeaveRequestResponse */
+	return dco.LeaveRequestResponse{
+		ID:          leaveRequest.ID,
+		PersonID:    leaveRequest.PersonID,
+		StartDate:   leaveRequest.StartDate,
+		EndDate:     leaveRequest.EndDate,
+		Reason:      leaveRequest.Reason,
+		Status:      leaveRequest.Status,
+		RequestedBy: leaveRequest.RequestedBy,
+		DecidedBy:   leaveRequest.DecidedBy,
+		DecidedAt:   leaveRequest.DecidedAt,
+		Comment:     leaveRequest.Comment,
+		CreatedAt:   leaveRequest.CreatedAt,
+	}
+}
+
+func mapLeaveRequestListToLeaveRequestResponseList(leaveRequests []dao.LeaveRequest) []dco.LeaveRequestResponse {
+	/* Maps a LeaveRequest list to a LeaveRequestResponse list */
+	leaveRequestResponseList := []dco.LeaveRequestResponse{}
+	for _, leaveRequest := range leaveRequests {
+		leaveRequestResponseList = append(leaveRequestResponseList, mapLeaveRequestToLeaveRequestResponse(leaveRequest))
+	}
+
+	return leaveRequestResponseList
+}
+
+var leaveRequestServiceSet = wire.NewSet(
+	wire.Struct(new(LeaveRequestServiceImpl), "*"),
+	wire.Bind(new(LeaveRequestService), new(*LeaveRequestServiceImpl)),
+)
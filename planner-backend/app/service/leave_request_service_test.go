@@ -0,0 +1,262 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"planner-backend/app/domain/dao"
+	"planner-backend/app/domain/dco"
+	"planner-backend/app/domain/dto"
+	"planner-backend/app/mock"
+	"planner-backend/app/notification"
+	"planner-backend/app/pkg"
+	"testing"
+)
+
+func TestCreateLeaveRequest(t *testing.T) {
+	leaveRequestRepository := mock.NewLeaveRequestRepositoryMock()
+	personRepository := mock.NewPersonRepositoryMock()
+	leaveRequestService := LeaveRequestServiceImpl{
+		LeaveRequestRepository: leaveRequestRepository,
+		PersonRepository:       personRepository,
+		UnitOfWork:             &mock.UnitOfWorkMock{},
+	}
+
+	type createLeaveRequestTest struct {
+		body               interface{}
+		personError        error
+		expectedStatusCode int
+	}
+
+	testSteps := []createLeaveRequestTest{
+		{
+			body:               dco.LeaveRequestRequest{StartDate: "2024-01-01", EndDate: "2024-01-05", RequestedBy: "john"},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{
+			// end date before start date
+			body:               dco.LeaveRequestRequest{StartDate: "2024-01-05", EndDate: "2024-01-01", RequestedBy: "john"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// too long
+			body:               dco.LeaveRequestRequest{StartDate: "2024-01-01", EndDate: "2025-06-01", RequestedBy: "john"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// missing requested_by
+			body:               map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-05"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// unknown person
+			body:               dco.LeaveRequestRequest{StartDate: "2024-01-01", EndDate: "2024-01-05", RequestedBy: "john"},
+			personError:        pkg.ErrNoRows,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run("Test Create Leave Request", func(t *testing.T) {
+			personRepository.On("FindPersonByID").Return(dao.Person{ID: "person1"}, testStep.personError)
+			leaveRequestRepository.On("Save").Return(dao.LeaveRequest{ID: "1", PersonID: "person1", Status: dao.LeaveRequestPending}, nil)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, err := mock.NewTestContextBuilder(w).
+				WithMethod("POST").
+				WithMapParams(map[string]string{"personID": "person1"}).
+				WithBody(testStep.body).Build()
+			if err != nil {
+				t.Errorf("Error while building context: %s", err)
+			}
+
+			serve(c, leaveRequestService.CreateLeaveRequest)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestGetLeaveRequestsForDepartment(t *testing.T) {
+	leaveRequestRepository := mock.NewLeaveRequestRepositoryMock()
+	leaveRequestService := LeaveRequestServiceImpl{
+		LeaveRequestRepository: leaveRequestRepository,
+		UnitOfWork:             &mock.UnitOfWorkMock{},
+	}
+	leaveRequestRepository.On("FindLeaveRequestsForDepartment").Return([]dao.LeaveRequest{{ID: "1", Status: dao.LeaveRequestPending}}, nil)
+
+	testSteps := []struct {
+		status             string
+		expectedStatusCode int
+	}{
+		{status: "", expectedStatusCode: http.StatusOK},
+		{status: "all", expectedStatusCode: http.StatusOK},
+		{status: "approved", expectedStatusCode: http.StatusOK},
+		{status: "unknown", expectedStatusCode: http.StatusBadRequest},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run("Test Get Leave Requests For Department", func(t *testing.T) {
+			queries := map[string]string{}
+			if testStep.status != "" {
+				queries["status"] = testStep.status
+			}
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, err := mock.NewTestContextBuilder(w).
+				WithMethod("GET").
+				WithMapParams(map[string]string{"departmentID": "dept1"}).
+				WithQueries(queries).Build()
+			if err != nil {
+				t.Errorf("Error while building context: %s", err)
+			}
+
+			serve(c, leaveRequestService.GetLeaveRequestsForDepartment)
+			if w.Code != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestDecideLeaveRequest(t *testing.T) {
+	pending := dao.LeaveRequest{ID: "1", PersonID: "person1", StartDate: "2024-01-01", EndDate: "2024-01-05", Status: dao.LeaveRequestPending}
+	approved := pending
+	approved.Status = dao.LeaveRequestApproved
+
+	type decideLeaveRequestTest struct {
+		name               string
+		reject             bool
+		leaveRequest       dao.LeaveRequest
+		findError          error
+		conflicts          []dao.Workday
+		force              bool
+		expectedStatusCode int
+		expectedDecision   string
+		expectedEvent      string
+		expectedConflicts  int
+	}
+
+	testSteps := []decideLeaveRequestTest{
+		{
+			name:               "approve without conflicts",
+			leaveRequest:       pending,
+			expectedStatusCode: http.StatusOK,
+			expectedDecision:   dao.LeaveRequestApproved,
+			expectedEvent:      notification.LeaveRequestApproved,
+		},
+		{
+			name:               "approve with conflicts is refused",
+			leaveRequest:       pending,
+			conflicts:          []dao.Workday{{Date: "2024-01-02"}},
+			expectedStatusCode: http.StatusConflict,
+			expectedConflicts:  1,
+		},
+		{
+			name:               "approve with conflicts and force",
+			leaveRequest:       pending,
+			conflicts:          []dao.Workday{{Date: "2024-01-02"}},
+			force:              true,
+			expectedStatusCode: http.StatusOK,
+			expectedDecision:   dao.LeaveRequestApproved,
+			expectedEvent:      notification.LeaveRequestApproved,
+			expectedConflicts:  1,
+		},
+		{
+			name:               "approve an approved request",
+			leaveRequest:       approved,
+			expectedStatusCode: http.StatusConflict,
+		},
+		{
+			name:               "reject an approved request",
+			reject:             true,
+			leaveRequest:       approved,
+			expectedStatusCode: http.StatusOK,
+			expectedDecision:   dao.LeaveRequestRejected,
+			expectedEvent:      notification.LeaveRequestRejected,
+		},
+		{
+			name:               "unknown request",
+			findError:          pkg.ErrNoRows,
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run(testStep.name, func(t *testing.T) {
+			leaveRequestRepository := mock.NewLeaveRequestRepositoryMock()
+			workdayRepository := mock.NewWorkdayRepositoryMock()
+			// a failing notifier must not revert the decision
+			notifier := &mock.NotifierMock{Error: errors.New("webhook is down")}
+			unitOfWork := &mock.UnitOfWorkMock{}
+			leaveRequestService := LeaveRequestServiceImpl{
+				LeaveRequestRepository: leaveRequestRepository,
+				WorkdayRepository:      workdayRepository,
+				Notifier:               notifier,
+				UnitOfWork:             unitOfWork,
+			}
+			leaveRequestRepository.On("FindLeaveRequestByID").Return(testStep.leaveRequest, testStep.findError)
+			workdayRepository.On("GetWorkdaysForPerson").Return(testStep.conflicts, nil)
+
+			queries := map[string]string{}
+			if testStep.force {
+				queries["force"] = "true"
+			}
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, err := mock.NewTestContextBuilder(w).
+				WithMethod("POST").
+				WithMapParams(map[string]string{"departmentID": "dept1", "requestID": "1"}).
+				WithQueries(queries).
+				WithBody(map[string]string{"comment": "Enjoy"}).Build()
+			if err != nil {
+				t.Errorf("Error while building context: %s", err)
+			}
+			c.Set("identity", &dco.Identity{Username: "planner"})
+
+			if testStep.reject {
+				serve(c, leaveRequestService.RejectLeaveRequest)
+			} else {
+				serve(c, leaveRequestService.ApproveLeaveRequest)
+			}
+
+			if w.Code != testStep.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", testStep.expectedStatusCode, w.Code)
+			}
+			// the request is checked and decided in one transaction
+			if unitOfWork.Writes != 1 {
+				t.Errorf("Expected one write transaction, got %d", unitOfWork.Writes)
+			}
+
+			if testStep.expectedDecision == "" {
+				if len(leaveRequestRepository.Decided) != 0 {
+					t.Errorf("Expected no decision, got %+v", leaveRequestRepository.Decided)
+				}
+			} else {
+				if len(leaveRequestRepository.Decided) != 1 {
+					t.Fatalf("Expected one decision, got %+v", leaveRequestRepository.Decided)
+				}
+				decided := leaveRequestRepository.Decided[0]
+				if decided.Status != testStep.expectedDecision || decided.DecidedBy != "planner" || decided.Comment != "Enjoy" {
+					t.Errorf("Expected the decision %s by planner, got %+v", testStep.expectedDecision, decided)
+				}
+				if len(notifier.Events) != 1 || notifier.Events[0].Type != testStep.expectedEvent {
+					t.Errorf("Expected the event %s, got %+v", testStep.expectedEvent, notifier.Events)
+				}
+			}
+
+			var body dto.APIResponse[dco.LeaveRequestDecisionResponse]
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatalf("Error while decoding response: %s", err)
+			}
+			if len(body.Data.Conflicts) != testStep.expectedConflicts {
+				t.Errorf("Expected %d conflicts, got %d", testStep.expectedConflicts, len(body.Data.Conflicts))
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"planner-backend/app/constant"
@@ -37,6 +38,8 @@ type PersonRelServiceImpl struct {
 	PersonRepository     repository.PersonRepository
 	DepartmentRepository repository.DepartmentRepository
 	WorkplaceRepository  repository.WorkplaceRepository
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
 }
 
 /** Absency */
@@ -45,35 +48,43 @@ func (p PersonRelServiceImpl) AddAbsencyToPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add absency to person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var request dco.AbsenceRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	absence := mapAbsenceRequestToAbsence(request)
-
-	if err := p.PersonRelRepository.AddAbsencyToPerson(person, absence); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		absence := mapAbsenceRequestToAbsence(request)
+
+		if err := p.PersonRelRepository.AddAbsencyToPerson(ctx, person, absence); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -84,48 +95,57 @@ func (p PersonRelServiceImpl) RemoveAbsencyFromPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program remove absency from person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	date := c.Param("date")
 	if date == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	if _, err := time.Parse("2006-01-02", date); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	absence, err := p.PersonRelRepository.FindAbsencyForPerson(personID, date)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	if err := p.PersonRelRepository.RemoveAbsencyFromPerson(person, absence); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		absence, err := p.PersonRelRepository.FindAbsencyForPerson(ctx, personID, date)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		if err := p.PersonRelRepository.RemoveAbsencyFromPerson(ctx, person, absence); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -136,24 +156,26 @@ func (p PersonRelServiceImpl) FindAbsencyForPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program find all absencies by person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	date := c.Query("date")
 	if date == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	if _, err := time.Parse("2006-01-02", date); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := p.PersonRelRepository.FindAbsencyForPerson(personID, date)
+	rawData, err := p.PersonRelRepository.FindAbsencyForPerson(c.Request.Context(), personID, date)
 	switch err {
 	case nil:
 		break
@@ -163,7 +185,8 @@ func (p PersonRelServiceImpl) FindAbsencyForPerson(c *gin.Context) {
 		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapAbsenceToAbsenceResponse(rawData)
@@ -177,33 +200,37 @@ func (p PersonRelServiceImpl) FindAbsencyForPersonInRange(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program find all absencies by person in range")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	startDate := c.Query("start_date")
 	if startDate == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	_, err := time.Parse("2006-01-02", startDate)
 	if err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	endDate := c.Query("end_date")
 	if endDate == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	_, err = time.Parse("2006-01-02", endDate)
 	if err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := p.PersonRelRepository.FindAbsencyForPersonInRange(personID, startDate, endDate)
+	rawData, err := p.PersonRelRepository.FindAbsencyForPersonInRange(c.Request.Context(), personID, startDate, endDate)
 	switch err {
 	case nil:
 		break
@@ -213,7 +240,8 @@ func (p PersonRelServiceImpl) FindAbsencyForPersonInRange(c *gin.Context) {
 		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapAbsenciesToAbsenciesResponse(rawData)
@@ -266,43 +294,52 @@ func (p PersonRelServiceImpl) AddDepartmentToPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add department to person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var request dco.RelAddDepartmentRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-	_, err = p.DepartmentRepository.FindDepartmentByID(request.DepartmentID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	if err := p.PersonRelRepository.AddDepartmentToPerson(person, request.DepartmentID); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		_, err = p.DepartmentRepository.FindDepartmentByID(ctx, request.DepartmentID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		if err := p.PersonRelRepository.AddDepartmentToPerson(ctx, person, request.DepartmentID); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -313,33 +350,41 @@ func (p PersonRelServiceImpl) RemoveDepartmentFromPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program remove department from person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	departmentID := c.Param("departmentID")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	if err := p.PersonRelRepository.RemoveDepartmentFromPerson(person, departmentID); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		if err := p.PersonRelRepository.RemoveDepartmentFromPerson(ctx, person, departmentID); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
 }
@@ -350,44 +395,52 @@ func (p PersonRelServiceImpl) AddWorkplaceToPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add workplace to person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var request dco.RelAddWorkplaceRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	_, err = p.WorkplaceRepository.FindWorkplaceByID(request.DepartmentID, request.WorkplaceID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	if err := p.PersonRelRepository.AddWorkplaceToPerson(person, request.DepartmentID, request.WorkplaceID); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		_, err = p.WorkplaceRepository.FindWorkplaceByID(ctx, request.DepartmentID, request.WorkplaceID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		if err := p.PersonRelRepository.AddWorkplaceToPerson(ctx, person, request.DepartmentID, request.WorkplaceID); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -398,33 +451,41 @@ func (p PersonRelServiceImpl) RemoveWorkplaceFromPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program remove workplace from person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	request := dco.RelRemoveWorkplaceRequest{}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	if err := p.PersonRelRepository.RemoveWorkplaceFromPerson(person, request.DepartmentID, request.WorkplaceID); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		if err := p.PersonRelRepository.RemoveWorkplaceFromPerson(ctx, person, request.DepartmentID, request.WorkplaceID); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -436,36 +497,43 @@ func (p PersonRelServiceImpl) AddWeekdayToPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add weekday to person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	request := dco.RelAddWeekdayRequest{}
-	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-	if err := request.Validate(); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	if err := p.PersonRelRepository.AddWeekdayToPerson(person, request.WeekdayID); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		request := dco.RelAddWeekdayRequest{}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			return pkg.ValidationError(err)
+		}
+		if err := request.Validate(); err != nil {
+			return pkg.NewError(constant.InvalidRequest)
+		}
+
+		if err := p.PersonRelRepository.AddWeekdayToPerson(ctx, person, request.WeekdayID); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -476,40 +544,50 @@ func (p PersonRelServiceImpl) RemoveWeekdayFromPerson(c *gin.Context) {
 	 * @param c is gin context
 	 * @return void
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program remove weekday from person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	weekdayIDRaw := c.Param("weekdayID")
 	if weekdayIDRaw == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	weekdayID, err := strconv.ParseInt(weekdayIDRaw, 10, 64)
 	if err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	if weekdayID != 1 && weekdayID != 2 && weekdayID != 3 && weekdayID != 4 && weekdayID != 5 && weekdayID != 6 && weekdayID != 7 {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	if err := p.PersonRelRepository.RemoveWeekdayFromPerson(person, weekdayID); err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		if err := p.PersonRelRepository.RemoveWeekdayFromPerson(ctx, person, weekdayID); err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -34,6 +34,7 @@ func TestAddAbsencyToPerson(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -126,7 +127,7 @@ func TestAddAbsencyToPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.AddAbsencyToPerson(c)
+			serve(c, personRelService.AddAbsencyToPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -142,6 +143,7 @@ func TestRemoveAbsencyFromPerson(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -226,7 +228,7 @@ func TestRemoveAbsencyFromPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.RemoveAbsencyFromPerson(c)
+			serve(c, personRelService.RemoveAbsencyFromPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -242,6 +244,7 @@ func TestFindAbsencyForPerson(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -302,7 +305,7 @@ func TestFindAbsencyForPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.FindAbsencyForPerson(c)
+			serve(c, personRelService.FindAbsencyForPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -318,6 +321,7 @@ func TestFindAbsencyForPersonInRange(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    personRepository,
 		PersonRelRepository: personRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -408,7 +412,7 @@ func TestFindAbsencyForPersonInRange(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.FindAbsencyForPersonInRange(c)
+			serve(c, personRelService.FindAbsencyForPersonInRange)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -426,6 +430,7 @@ func TestAddDepartmentToPerson(t *testing.T) {
 		PersonRepository:     PersonRepository,
 		PersonRelRepository:  PersonRelRepository,
 		DepartmentRepository: DepartmentRepository,
+		UnitOfWork:           &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -539,7 +544,7 @@ func TestAddDepartmentToPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.AddDepartmentToPerson(c)
+			serve(c, personRelService.AddDepartmentToPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -555,6 +560,7 @@ func TestRemoveDepartmentFromPerson(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -635,7 +641,7 @@ func TestRemoveDepartmentFromPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.RemoveDepartmentFromPerson(c)
+			serve(c, personRelService.RemoveDepartmentFromPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -653,6 +659,7 @@ func TestAddWorkplaceToPerson(t *testing.T) {
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
 		WorkplaceRepository: WorkplaceRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -767,7 +774,7 @@ func TestAddWorkplaceToPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.AddWorkplaceToPerson(c)
+			serve(c, personRelService.AddWorkplaceToPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -783,6 +790,7 @@ func TestRemoveWorkplaceFromPerson(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -876,7 +884,7 @@ func TestRemoveWorkplaceFromPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.RemoveWorkplaceFromPerson(c)
+			serve(c, personRelService.RemoveWorkplaceFromPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -892,6 +900,7 @@ func TestAddWeekdayToPerson(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -992,7 +1001,7 @@ func TestAddWeekdayToPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.AddWeekdayToPerson(c)
+			serve(c, personRelService.AddWeekdayToPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -1008,6 +1017,7 @@ func TestRemoveWeekdayFromPerson(t *testing.T) {
 	personRelService := PersonRelServiceImpl{
 		PersonRepository:    PersonRepository,
 		PersonRelRepository: PersonRelRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []serviceTestPersonRel{
@@ -1097,7 +1107,7 @@ func TestRemoveWeekdayFromPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			personRelService.RemoveWeekdayFromPerson(c)
+			serve(c, personRelService.RemoveWeekdayFromPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"planner-backend/app/constant"
@@ -24,6 +25,8 @@ type PersonService interface {
 
 type PersonServiceImpl struct {
 	PersonRepository repository.PersonRepository
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
 }
 
 func (p PersonServiceImpl) GetAllPersons(c *gin.Context) {
@@ -32,15 +35,15 @@ func (p PersonServiceImpl) GetAllPersons(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all persons")
 
 	departmentID := c.Query("department")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := p.PersonRepository.FindAllPersons(departmentID)
+	rawData, err := p.PersonRepository.FindAllPersons(c.Request.Context(), departmentID)
 	switch err {
 	case nil:
 		break
@@ -48,7 +51,8 @@ func (p PersonServiceImpl) GetAllPersons(c *gin.Context) {
 		break
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapPersonListToPersonResponseList(rawData)
@@ -62,23 +66,25 @@ func (p PersonServiceImpl) GetPersonByID(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get person by id")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := p.PersonRepository.FindPersonByID(personID)
+	rawData, err := p.PersonRepository.FindPersonByID(c.Request.Context(), personID)
 	switch err {
 	case nil:
 		break
 	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapPersonToPersonResponse(rawData)
@@ -92,31 +98,39 @@ func (p PersonServiceImpl) AddPerson(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add person")
 
 	var personRequest dco.PersonRequest
 	if err := c.ShouldBindJSON(&personRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	_, err := p.PersonRepository.FindPersonByID(personRequest.ID)
-	switch err {
-	case nil:
-		pkg.PanicException(constant.Conflict)
-	case pkg.ErrNoRows:
-		break
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	person := mapPersonRequestToPerson(personRequest)
-	rawData, err := p.PersonRepository.Save(&person)
-	if err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Person
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		_, err := p.PersonRepository.FindPersonByID(ctx, personRequest.ID)
+		switch err {
+		case nil:
+			return pkg.NewError(constant.Conflict)
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		person := mapPersonRequestToPerson(personRequest)
+		rawData, err = p.PersonRepository.Save(ctx, &person)
+		if err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapPersonToPersonResponse(rawData)
@@ -130,40 +144,49 @@ func (p PersonServiceImpl) UpdatePerson(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var personRequest dco.PersonRequest
 	if err := c.ShouldBindJSON(&personRequest); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	person.FirstName = personRequest.FirstName
-	person.LastName = personRequest.LastName
-	person.Email = personRequest.Email
-	person.Active = *personRequest.Active
-	person.WorkingHours = personRequest.WorkingHours
-
-	rawData, err := p.PersonRepository.Save(&person)
-	if err != nil {
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Person
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		person.FirstName = personRequest.FirstName
+		person.LastName = personRequest.LastName
+		person.Email = personRequest.Email
+		person.Active = *personRequest.Active
+		person.WorkingHours = personRequest.WorkingHours
+
+		rawData, err = p.PersonRepository.Save(ctx, &person)
+		if err != nil {
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapPersonToPersonResponse(rawData)
@@ -177,29 +200,36 @@ func (p PersonServiceImpl) DeletePerson(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete person")
 
 	personID := c.Param("personID")
 	if personID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	person, err := p.PersonRepository.FindPersonByID(personID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	err = p.PersonRepository.Delete(&person)
-	if err != nil {
-		slog.Error("Error when deleting data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := p.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		person, err := p.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		err = p.PersonRepository.Delete(ctx, &person)
+		if err != nil {
+			slog.Error("Error when deleting data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -17,6 +17,7 @@ func TestDeletePerson(t *testing.T) {
 	PersonRepository := mock.NewPersonRepositoryMock()
 	personService := PersonServiceImpl{
 		PersonRepository: PersonRepository,
+		UnitOfWork:       &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestDELETE{
@@ -62,7 +63,7 @@ func TestDeletePerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			personService.DeletePerson(c)
+			serve(c, personService.DeletePerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -76,6 +77,7 @@ func TestUpdatePerson(t *testing.T) {
 	PersonRepository := mock.NewPersonRepositoryMock()
 	personService := PersonServiceImpl{
 		PersonRepository: PersonRepository,
+		UnitOfWork:       &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPUT{
@@ -190,7 +192,7 @@ func TestUpdatePerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			personService.UpdatePerson(c)
+			serve(c, personService.UpdatePerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -233,6 +235,7 @@ func TestAddPerson(t *testing.T) {
 	PersonRepository := mock.NewPersonRepositoryMock()
 	personService := PersonServiceImpl{
 		PersonRepository: PersonRepository,
+		UnitOfWork:       &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -274,7 +277,7 @@ func TestAddPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			personService.AddPerson(c)
+			serve(c, personService.AddPerson)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -317,6 +320,7 @@ func TestGetAllPersons(t *testing.T) {
 	PersonRepository := mock.NewPersonRepositoryMock()
 	personService := PersonServiceImpl{
 		PersonRepository: PersonRepository,
+		UnitOfWork:       &mock.UnitOfWorkMock{},
 	}
 
 	var trueValue = true
@@ -416,7 +420,7 @@ func TestGetAllPersons(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			personService.GetAllPersons(c)
+			serve(c, personService.GetAllPersons)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -461,6 +465,7 @@ func TestGetPerson(t *testing.T) {
 	PersonRepository := mock.NewPersonRepositoryMock()
 	personService := PersonServiceImpl{
 		PersonRepository: PersonRepository,
+		UnitOfWork:       &mock.UnitOfWorkMock{},
 	}
 
 	var trueValue = true
@@ -542,7 +547,7 @@ func TestGetPerson(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			personService.GetPersonByID(c)
+			serve(c, personService.GetPersonByID)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
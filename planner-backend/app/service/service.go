@@ -11,4 +11,5 @@ var ServiceSet = wire.NewSet(
 	personRelServiceSet,
 	workDayServiceSet,
 	absencyServiceSet,
+	leaveRequestServiceSet,
 )
@@ -1,6 +1,16 @@
 package service
 
-import "github.com/gin-gonic/gin"
+import (
+	"planner-backend/app/pkg"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serve(c *gin.Context, handler gin.HandlerFunc) {
+	/* Calls the handler like the router, its recorded error is rendered as by the ErrorHandler middleware */
+	handler(c)
+	pkg.RenderErrors(c)
+}
 
 /**
  * Struct to be used for testing service
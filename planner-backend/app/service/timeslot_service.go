@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"planner-backend/app/constant"
@@ -23,6 +24,8 @@ type TimeslotService interface {
 
 type TimeslotServiceImpl struct {
 	TimeslotRepository repository.TimeslotRepository
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
 }
 
 func (t TimeslotServiceImpl) GetAllTimeslots(c *gin.Context) {
@@ -31,19 +34,20 @@ func (t TimeslotServiceImpl) GetAllTimeslots(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all timeslots")
 
 	departmentID := c.Param("departmentID")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	workplaceID := c.Param("workplaceID")
 	if workplaceID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := t.TimeslotRepository.FindAllTimeslots(departmentID, workplaceID)
+	rawData, err := t.TimeslotRepository.FindAllTimeslots(c.Request.Context(), departmentID, workplaceID)
 	switch err {
 	case nil:
 		break
@@ -51,7 +55,8 @@ func (t TimeslotServiceImpl) GetAllTimeslots(c *gin.Context) {
 		break
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapTimeslotListToTimeslotResponseList(rawData)
@@ -65,25 +70,27 @@ func (t TimeslotServiceImpl) GetTimeslotByID(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get timeslot by id")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	timeslotID := c.Param("timeslotID")
 	if departmentID == "" || workplaceID == "" || timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := t.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotID)
+	rawData, err := t.TimeslotRepository.FindTimeslotByID(c.Request.Context(), departmentID, workplaceID, timeslotID)
 	switch err {
 	case nil:
 		break
 	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapTimeslotToTimeslotResponse(rawData)
@@ -97,40 +104,49 @@ func (t TimeslotServiceImpl) AddTimeslot(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add timeslot")
 
 	timeslotRequest := dco.TimeslotRequest{}
 	if err := c.ShouldBindJSON(&timeslotRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	if departmentID == "" || workplaceID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
-	_, err := t.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotRequest.Name)
-	switch err {
-	case nil:
-		pkg.PanicException(constant.Conflict)
-	case pkg.ErrNoRows:
-		break
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	timeslot := mapTimeslotRequestToTimeslot(timeslotRequest)
-	rawData, err := t.TimeslotRepository.Save(departmentID, workplaceID, &timeslot)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Timeslot
+	if err := t.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		_, err := t.TimeslotRepository.FindTimeslotByID(ctx, departmentID, workplaceID, timeslotRequest.Name)
+		switch err {
+		case nil:
+			return pkg.NewError(constant.Conflict)
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		timeslot := mapTimeslotRequestToTimeslot(timeslotRequest)
+		rawData, err = t.TimeslotRepository.Save(ctx, departmentID, workplaceID, &timeslot)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapTimeslotToTimeslotResponse(rawData)
@@ -144,44 +160,53 @@ func (t TimeslotServiceImpl) UpdateTimeslot(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update timeslot")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	timeslotID := c.Param("timeslotID")
 	if timeslotID == "" || departmentID == "" || workplaceID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	timeslot, err := t.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var timeslotRequest dco.TimeslotRequest
 	if err := c.ShouldBindJSON(&timeslotRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	timeslot.Name = timeslotRequest.Name
-
-	rawData, err := t.TimeslotRepository.Save(departmentID, workplaceID, &timeslot)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Timeslot
+	if err := t.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		timeslot, err := t.TimeslotRepository.FindTimeslotByID(ctx, departmentID, workplaceID, timeslotID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		timeslot.Name = timeslotRequest.Name
+
+		rawData, err = t.TimeslotRepository.Save(ctx, departmentID, workplaceID, &timeslot)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapTimeslotToTimeslotResponse(rawData)
@@ -195,30 +220,37 @@ func (t TimeslotServiceImpl) DeleteTimeslot(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete timeslot")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	timeslotID := c.Param("timeslotID")
 	if departmentID == "" || workplaceID == "" || timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	timeslot, err := t.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	if err := t.TimeslotRepository.Delete(departmentID, workplaceID, &timeslot); err != nil {
-		slog.Error("Error when deleting data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := t.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		timeslot, err := t.TimeslotRepository.FindTimeslotByID(ctx, departmentID, workplaceID, timeslotID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		if err := t.TimeslotRepository.Delete(ctx, departmentID, workplaceID, &timeslot); err != nil {
+			slog.Error("Error when deleting data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
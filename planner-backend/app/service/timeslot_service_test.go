@@ -17,6 +17,7 @@ func TestDeleteTimeslot(t *testing.T) {
 	TimeslotRepository := mock.NewTimeslotRepositoryMock()
 	timeslotService := TimeslotServiceImpl{
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestDELETE{
@@ -66,7 +67,7 @@ func TestDeleteTimeslot(t *testing.T) {
 			t.Errorf("Test Step %d: Error when building context", i)
 		}
 
-		timeslotService.DeleteTimeslot(c)
+		serve(c, timeslotService.DeleteTimeslot)
 		response := w.Result()
 
 		if response.StatusCode != testStep.expectedStatusCode {
@@ -79,6 +80,7 @@ func TestUpdateTimeslot(t *testing.T) {
 	TimeslotRepository := mock.NewTimeslotRepositoryMock()
 	timeslotService := TimeslotServiceImpl{
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPUT{
@@ -193,7 +195,7 @@ func TestUpdateTimeslot(t *testing.T) {
 			t.Errorf("Test Step %d: Error when building context", i)
 		}
 
-		timeslotService.UpdateTimeslot(c)
+		serve(c, timeslotService.UpdateTimeslot)
 		response := w.Result()
 
 		if response.StatusCode != testStep.expectedStatusCode {
@@ -219,6 +221,7 @@ func TestAddTimeslot(t *testing.T) {
 	TimeslotRepository := mock.NewTimeslotRepositoryMock()
 	timeslotService := TimeslotServiceImpl{
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -331,7 +334,7 @@ func TestAddTimeslot(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			timeslotService.AddTimeslot(c)
+			serve(c, timeslotService.AddTimeslot)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -358,6 +361,7 @@ func TestGetAllTimeslots(t *testing.T) {
 	TimeslotRepository := mock.NewTimeslotRepositoryMock()
 	timeslotService := TimeslotServiceImpl{
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestGET{
@@ -408,7 +412,7 @@ func TestGetAllTimeslots(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			timeslotService.GetAllTimeslots(c)
+			serve(c, timeslotService.GetAllTimeslots)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -437,6 +441,7 @@ func TestGetTimeslotByID(t *testing.T) {
 	TimeslotRepository := mock.NewTimeslotRepositoryMock()
 	timeslotService := TimeslotServiceImpl{
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestGET{
@@ -516,7 +521,7 @@ func TestGetTimeslotByID(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			timeslotService.GetTimeslotByID(c)
+			serve(c, timeslotService.GetTimeslotByID)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
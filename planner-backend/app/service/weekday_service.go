@@ -7,6 +7,7 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"planner-backend/app/constant"
@@ -31,51 +32,64 @@ type WeekdayService interface {
 type WeekdayServiceImpl struct {
 	WeekdayRepository  repository.WeekdayRepository
 	TimeslotRepository repository.TimeslotRepository
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
 }
 
 func (w WeekdayServiceImpl) BulkUpdateWeekdaysForTimeslot(c *gin.Context) {
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program bulk update weekdays for timeslot")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	timeslotID := c.Param("timeslotID")
 	if departmentID == "" || workplaceID == "" || timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var weekdaysRequest dco.WeekdaysRequest
 	if err := c.ShouldBindJSON(&weekdaysRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	timeslot, err := w.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
+	var timeslot dao.Timeslot
+	var weekdays []dao.OnWeekday
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		var err error
+		timeslot, err = w.TimeslotRepository.FindTimeslotByID(ctx, departmentID, workplaceID, timeslotID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
 
-	if err := w.WeekdayRepository.DeleteAllWeekdaysFromTimeslot(&timeslot); err != nil {
-		slog.Error("Error when deleting data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
+		if err := w.WeekdayRepository.DeleteAllWeekdaysFromTimeslot(ctx, &timeslot); err != nil {
+			slog.Error("Error when deleting data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
 
-	weekdaysToBeAdded, err := mapWeekdaysRequestToWeekdayList(weekdaysRequest)
-	if err != nil {
-		slog.Error("Error when mapping weekdays request to weekday list", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
-	}
+		weekdaysToBeAdded, err := mapWeekdaysRequestToWeekdayList(weekdaysRequest)
+		if err != nil {
+			slog.Error("Error when mapping weekdays request to weekday list", "error", err)
+			return pkg.NewError(constant.InvalidRequest)
+		}
 
-	weekdays, err := w.WeekdayRepository.AddWeekdaysToTimeslot(&timeslot, weekdaysToBeAdded)
-	if err != nil {
-		slog.Error("Error when adding weekdays to timeslot", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		weekdays, err = w.WeekdayRepository.AddWeekdaysToTimeslot(ctx, &timeslot, weekdaysToBeAdded)
+		if err != nil {
+			slog.Error("Error when adding weekdays to timeslot", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapOnWeekdayListToWeekdayResponseList(weekdays)
@@ -84,48 +98,58 @@ func (w WeekdayServiceImpl) BulkUpdateWeekdaysForTimeslot(c *gin.Context) {
 }
 
 func (w WeekdayServiceImpl) AddWeekdayToTimeslot(c *gin.Context) {
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add weekday to timeslot")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	timeslotID := c.Param("timeslotID")
 	if departmentID == "" || workplaceID == "" || timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var weekdayRequest dco.WeekdayRequest
 	if err := c.ShouldBindJSON(&weekdayRequest); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	if err := weekdayRequest.Validate(); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	timeslot, err := w.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
+	var weekdays []dao.OnWeekday
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		timeslot, err := w.TimeslotRepository.FindTimeslotByID(ctx, departmentID, workplaceID, timeslotID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
 
-	weekday, err := mapWeekdayRequestToWeekday(weekdayRequest)
-	if err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-	weekdays, err := w.WeekdayRepository.AddWeekdayToTimeslot(&timeslot, weekday)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		weekday, err := mapWeekdayRequestToWeekday(weekdayRequest)
+		if err != nil {
+			return pkg.NewError(constant.InvalidRequest)
+		}
+		weekdays, err = w.WeekdayRepository.AddWeekdayToTimeslot(ctx, &timeslot, weekday)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapOnWeekdayListToWeekdayResponseList(weekdays)
@@ -134,85 +158,103 @@ func (w WeekdayServiceImpl) AddWeekdayToTimeslot(c *gin.Context) {
 }
 
 func (w WeekdayServiceImpl) DeleteWeekdayFromTimeslot(c *gin.Context) {
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete weekday from timeslot")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	timeslotID := c.Param("timeslotID")
 	if departmentID == "" || workplaceID == "" || timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var weekdayRequest dco.WeekdayRequest
 	if err := c.ShouldBindJSON(&weekdayRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	if err := weekdayRequest.Validate(); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	timeslot, err := w.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		timeslot, err := w.TimeslotRepository.FindTimeslotByID(ctx, departmentID, workplaceID, timeslotID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
 
-	weekday, err := mapWeekdayRequestToWeekday(weekdayRequest)
-	if err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-	err = w.WeekdayRepository.DeleteWeekdayFromTimeslot(&timeslot, weekday)
-	if err != nil {
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		weekday, err := mapWeekdayRequestToWeekday(weekdayRequest)
+		if err != nil {
+			return pkg.NewError(constant.InvalidRequest)
+		}
+		err = w.WeekdayRepository.DeleteWeekdayFromTimeslot(ctx, &timeslot, weekday)
+		if err != nil {
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
 }
 
 func (w WeekdayServiceImpl) UpdateWeekdayForTimeslot(c *gin.Context) {
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update weekday for timeslot")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	timeslotID := c.Param("timeslotID")
 	if departmentID == "" || workplaceID == "" || timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var weekdayRequest dco.WeekdayRequest
 	if err := c.ShouldBindJSON(&weekdayRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	timeslot, err := w.TimeslotRepository.FindTimeslotByID(departmentID, workplaceID, timeslotID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.InvalidRequest)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
+	var weekdays []dao.OnWeekday
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		timeslot, err := w.TimeslotRepository.FindTimeslotByID(ctx, departmentID, workplaceID, timeslotID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.InvalidRequest)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
 
-	weekday, err := mapWeekdayRequestToWeekday(weekdayRequest)
-	if err != nil {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-	weekdays, err := w.WeekdayRepository.UpdateWeekdayForTimeslot(&timeslot, weekday)
-	if err != nil {
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		weekday, err := mapWeekdayRequestToWeekday(weekdayRequest)
+		if err != nil {
+			return pkg.NewError(constant.InvalidRequest)
+		}
+		weekdays, err = w.WeekdayRepository.UpdateWeekdayForTimeslot(ctx, &timeslot, weekday)
+		if err != nil {
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapOnWeekdayListToWeekdayResponseList(weekdays)
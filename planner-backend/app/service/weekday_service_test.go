@@ -18,6 +18,7 @@ func TestBulkUpdateWeekdaysForTimeslot(t *testing.T) {
 	weekdayService := WeekdayServiceImpl{
 		WeekdayRepository:  WeekdayRepository,
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -91,7 +92,7 @@ func TestBulkUpdateWeekdaysForTimeslot(t *testing.T) {
 				t.Errorf("Test Step %d: Error when getting GIN context", i)
 			}
 
-			weekdayService.BulkUpdateWeekdaysForTimeslot(c)
+			serve(c, weekdayService.BulkUpdateWeekdaysForTimeslot)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -122,6 +123,7 @@ func TestUpdateWeekdayForTimeslot(t *testing.T) {
 	weekdayService := WeekdayServiceImpl{
 		WeekdayRepository:  WeekdayRepository,
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPUT{
@@ -233,7 +235,7 @@ func TestUpdateWeekdayForTimeslot(t *testing.T) {
 				t.Errorf("Test Step %d: Error when getting GIN context", i)
 			}
 
-			weekdayService.UpdateWeekdayForTimeslot(c)
+			serve(c, weekdayService.UpdateWeekdayForTimeslot)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -264,6 +266,7 @@ func TestAddWeekdayToTimeslot(t *testing.T) {
 	weekdayService := WeekdayServiceImpl{
 		WeekdayRepository:  WeekdayRepository,
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -358,7 +361,7 @@ func TestAddWeekdayToTimeslot(t *testing.T) {
 				t.Errorf("Test Step %d: Error when getting GIN context", i)
 			}
 
-			weekdayService.AddWeekdayToTimeslot(c)
+			serve(c, weekdayService.AddWeekdayToTimeslot)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -389,6 +392,7 @@ func TestDeleteWeekdayFromTimeslot(t *testing.T) {
 	weekdayService := WeekdayServiceImpl{
 		WeekdayRepository:  WeekdayRepository,
 		TimeslotRepository: TimeslotRepository,
+		UnitOfWork:         &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -444,7 +448,7 @@ func TestDeleteWeekdayFromTimeslot(t *testing.T) {
 				t.Errorf("Test Step %d: Error when getting GIN context", i)
 			}
 
-			weekdayService.DeleteWeekdayFromTimeslot(c)
+			serve(c, weekdayService.DeleteWeekdayFromTimeslot)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
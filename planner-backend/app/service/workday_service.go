@@ -1,13 +1,18 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"planner-backend/app/constant"
 	"planner-backend/app/domain/dao"
 	"planner-backend/app/domain/dco"
+	"planner-backend/app/domain/dto"
+	"planner-backend/app/middleware"
 	"planner-backend/app/pkg"
 	"planner-backend/app/repository"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
@@ -24,28 +29,42 @@ type WorkdayService interface {
 
 	AssignPersonToWorkday(c *gin.Context)
 	UnassignPersonFromWorkday(c *gin.Context)
+
+	/*
+	 * Gets the Workdays a person is assigned to and the planned hours
+	 * for a range of dates
+	 */
+	GetWorkdaysForPerson(c *gin.Context)
+	GetHoursForPerson(c *gin.Context)
 }
 
 type WorkdayServiceImpl struct {
 	WorkdayRepository repository.WorkdayRepository
+	PersonRepository  repository.PersonRepository
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
 }
 
+// Longest range of dates that can be requested for a person
+const maxPersonRangeDays = 366
+
 func (w WorkdayServiceImpl) GetWorkdaysForDepartmentAndDate(c *gin.Context) {
 	/*
 	 * Gets all Workdays along with the (if present) assigned user
 	 * for a given date
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all workdays")
 
 	departmentID := c.Query("department")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	date := c.Query("date")
 	if date == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var fetchIsActive bool
@@ -54,7 +73,7 @@ func (w WorkdayServiceImpl) GetWorkdaysForDepartmentAndDate(c *gin.Context) {
 		fetchIsActive = true
 	}
 
-	rawData, err := w.WorkdayRepository.GetWorkdaysForDepartmentAndDate(departmentID, date, fetchIsActive)
+	rawData, err := w.WorkdayRepository.GetWorkdaysForDepartmentAndDate(c.Request.Context(), departmentID, date, fetchIsActive)
 	switch err {
 	case nil:
 		break
@@ -62,7 +81,8 @@ func (w WorkdayServiceImpl) GetWorkdaysForDepartmentAndDate(c *gin.Context) {
 		break
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapWorkdayListToWorkdayResponseList(rawData)
@@ -75,35 +95,40 @@ func (w WorkdayServiceImpl) GetWorkday(c *gin.Context) {
 	 * Gets all Workdays along with the (if present) assigned user
 	 * for a given date
 	 **/
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all workdays")
 
 	departmentID := c.Query("department")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	workplaceID := c.Query("workplace")
 	if workplaceID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	timeslotID := c.Query("timeslot")
 	if timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	date := c.Query("date")
 	if date == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := w.WorkdayRepository.GetWorkday(departmentID, workplaceID, timeslotID, date)
+	rawData, err := w.WorkdayRepository.GetWorkday(c.Request.Context(), departmentID, workplaceID, timeslotID, date)
 	switch err {
 	case nil:
 		break
 	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapWorkdayToWorkdayResponse(rawData)
@@ -116,25 +141,28 @@ func (w WorkdayServiceImpl) UpdateWorkday(c *gin.Context) {
 	 * Updates a workday
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update workday")
 
 	// get params from request
 	departmentID := c.Query("departmentID")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	workplaceID := c.Query("workplaceID")
 	if workplaceID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	timeslotID := c.Query("timeslotID")
 	if timeslotID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 	date := c.Query("date")
 	if date == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	// validate params
@@ -142,36 +170,47 @@ func (w WorkdayServiceImpl) UpdateWorkday(c *gin.Context) {
 	// get request body
 	var request dco.UpdateWorkdayRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	if err := request.Validate(); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	// get workday
-	workday, err := w.WorkdayRepository.GetWorkday(departmentID, workplaceID, timeslotID, date)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	// map request to workday
-	workday.StartTime = request.StartTime
-	workday.EndTime = request.EndTime
-	workday.Active = *request.Active
-	if request.Comment != nil {
-		workday.Comment = *request.Comment
-	}
-
-	// save workday
-	if err := w.WorkdayRepository.Save(&workday); err != nil {
-		slog.Error("Error when saving workday", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var workday dao.Workday
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		// get workday
+		var err error
+		workday, err = w.WorkdayRepository.GetWorkday(ctx, departmentID, workplaceID, timeslotID, date)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		// map request to workday
+		workday.StartTime = request.StartTime
+		workday.EndTime = request.EndTime
+		workday.Active = *request.Active
+		if request.Comment != nil {
+			workday.Comment = *request.Comment
+		}
+
+		// save workday
+		if err := w.WorkdayRepository.Save(ctx, &workday); err != nil {
+			slog.Error("Error when saving workday", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, mapWorkdayToWorkdayResponse(workday)))
@@ -181,19 +220,20 @@ func (w WorkdayServiceImpl) AssignPersonToWorkday(c *gin.Context) {
 	/*
 	 * Assigns a person to a workday
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program assign person to workday")
 
 	var request dco.AssignPersonToWorkdayRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
 	if err := request.Validate(); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	if err := w.WorkdayRepository.AssignPersonToWorkday(
+	if err := w.WorkdayRepository.AssignPersonToWorkday(c.Request.Context(),
 		request.PersonID,
 		request.DepartmentID,
 		request.WorkplaceID,
@@ -201,7 +241,8 @@ func (w WorkdayServiceImpl) AssignPersonToWorkday(c *gin.Context) {
 		request.Date,
 	); err != nil {
 		slog.Error("Error when assigning person to workday", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	c.JSON(http.StatusCreated, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -211,19 +252,20 @@ func (w WorkdayServiceImpl) UnassignPersonFromWorkday(c *gin.Context) {
 	/*
 	 * Unassigns a person from a workday
 	 */
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program unassign person from workday")
 
 	var request dco.UnassignPersonFromWorkdayRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
 	if err := request.Validate(); err != nil {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	if err := w.WorkdayRepository.UnassignPersonFromWorkday(
+	if err := w.WorkdayRepository.UnassignPersonFromWorkday(c.Request.Context(),
 		request.PersonID,
 		request.DepartmentID,
 		request.WorkplaceID,
@@ -231,12 +273,191 @@ func (w WorkdayServiceImpl) UnassignPersonFromWorkday(c *gin.Context) {
 		request.Date,
 	); err != nil {
 		slog.Error("Error when unassigning person from workday", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
 }
 
+func (w WorkdayServiceImpl) GetWorkdaysForPerson(c *gin.Context) {
+	/*
+	 * Gets the active Workdays a person is assigned to
+	 * for a range of dates, ?start_date=...&end_date=...
+	 */
+	slog.Info("start to execute program get workdays for person")
+
+	personID := c.Param("personID")
+	if personID == "" {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+	startDate, endDate, err := parsePersonRange(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	var rawData []dao.Workday
+	if err := w.UnitOfWork.Read(c.Request.Context(), func(ctx context.Context) error {
+		person, err := w.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+		if err := authorizePersonRead(c, person); err != nil {
+			return err
+		}
+
+		rawData, err = w.WorkdayRepository.GetWorkdaysForPerson(ctx, personID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	data := mapWorkdayListToWorkdayResponseList(rawData)
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func (w WorkdayServiceImpl) GetHoursForPerson(c *gin.Context) {
+	/*
+	 * Sums up the planned hours of a person for a range of dates
+	 * and compares them to the working hours of the person
+	 */
+	slog.Info("start to execute program get hours for person")
+
+	personID := c.Param("personID")
+	if personID == "" {
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
+	}
+	startDate, endDate, err := parsePersonRange(c)
+	if err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	var person dao.Person
+	var workdays []dao.Workday
+	if err := w.UnitOfWork.Read(c.Request.Context(), func(ctx context.Context) error {
+		var err error
+		person, err = w.PersonRepository.FindPersonByID(ctx, personID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+		if err := authorizePersonRead(c, person); err != nil {
+			return err
+		}
+
+		workdays, err = w.WorkdayRepository.GetWorkdaysForPerson(ctx, personID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
+	}
+
+	var plannedMinutes int64
+	for _, workday := range workdays {
+		plannedMinutes += workday.DurationInMinutes
+	}
+	days := endDate.Sub(startDate).Hours()/24 + 1
+
+	data := dco.PersonHoursResponse{
+		PersonID:        person.ID,
+		StartDate:       startDate.Format("2006-01-02"),
+		EndDate:         endDate.Format("2006-01-02"),
+		Assignments:     len(workdays),
+		PlannedMinutes:  plannedMinutes,
+		PlannedHours:    float64(plannedMinutes) / 60,
+		ContractedHours: person.WorkingHours * days / 7,
+	}
+
+	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, data))
+}
+
+func authorizePersonRead(c *gin.Context, person dao.Person) error {
+	/* Helper to check that the identity belongs to one of the departments of the person, admins see every person */
+	identity, exists := middleware.GetIdentity(c)
+	if !exists {
+		return pkg.NewError(constant.Unauthorized)
+	}
+	if identity.IsAdmin {
+		return nil
+	}
+	for _, department := range person.Departments {
+		if department.ID == identity.Department {
+			return nil
+		}
+	}
+
+	slog.Error("Error happened: when check department of person", "username", identity.Username, "person", person.ID, "department", identity.Department)
+	return pkg.NewError(constant.Forbidden)
+}
+
+func parsePersonRange(c *gin.Context) (time.Time, time.Time, error) {
+	/* Helper to parse the range of dates of a person, ?start_date=...&end_date=... */
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		return time.Time{}, time.Time{}, invalidDate("start_date", err)
+	}
+
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		return time.Time{}, time.Time{}, invalidDate("end_date", err)
+	}
+
+	if endDate.Before(startDate) || endDate.Sub(startDate) >= maxPersonRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, pkg.NewError(constant.InvalidRequest).WithCode("validation_failed").WithDetails(dto.ErrorDetail{
+			Field:   "end_date",
+			Code:    "range",
+			Message: fmt.Sprintf("must be on or after start_date and within %d days", maxPersonRangeDays),
+		})
+	}
+
+	return startDate, endDate, nil
+}
+
+func invalidDate(field string, err error) *pkg.Error {
+	return pkg.NewError(constant.InvalidRequest).WithCode("validation_failed").Wrap(err).WithDetails(dto.ErrorDetail{
+		Field:   field,
+		Code:    "date",
+		Message: "must be a date like 2006-01-02",
+	})
+}
+
 func mapWorkdayPersonToWorkdayPersonResponse(person []dao.Person) []dco.PersonResponse {
 	/*
 	 * Maps a WorkdayPerson to a WorkdayPersonResponse
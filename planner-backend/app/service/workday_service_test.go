@@ -17,6 +17,7 @@ func TestGetWorkdaysForDepartmentAndDate(t *testing.T) {
 	workdayRepository := mock.NewWorkdayRepositoryMock()
 	workdayService := WorkdayServiceImpl{
 		WorkdayRepository: workdayRepository,
+		UnitOfWork:        &mock.UnitOfWorkMock{},
 	}
 
 	mockPerson := dao.Person{
@@ -124,7 +125,7 @@ func TestGetWorkdaysForDepartmentAndDate(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			workdayService.GetWorkdaysForDepartmentAndDate(c)
+			serve(c, workdayService.GetWorkdaysForDepartmentAndDate)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -152,6 +153,7 @@ func TestGetWorkday(t *testing.T) {
 	workdayRepository := mock.NewWorkdayRepositoryMock()
 	workdayService := WorkdayServiceImpl{
 		WorkdayRepository: workdayRepository,
+		UnitOfWork:        &mock.UnitOfWorkMock{},
 	}
 
 	mockPerson := dao.Person{
@@ -274,7 +276,7 @@ func TestGetWorkday(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			workdayService.GetWorkday(c)
+			serve(c, workdayService.GetWorkday)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -299,6 +301,7 @@ func TestUpdateWorkday(t *testing.T) {
 	workdayRepository := mock.NewWorkdayRepositoryMock()
 	workdayService := WorkdayServiceImpl{
 		WorkdayRepository: workdayRepository,
+		UnitOfWork:        &mock.UnitOfWorkMock{},
 	}
 
 	falseValue := false
@@ -552,7 +555,7 @@ func TestUpdateWorkday(t *testing.T) {
 				t.Errorf("Test Step %d: Error while building context: %s", i, err)
 			}
 
-			workdayService.UpdateWorkday(c)
+			serve(c, workdayService.UpdateWorkday)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -566,6 +569,7 @@ func TestAssignPersonToWorkday(t *testing.T) {
 	workdayRepository := mock.NewWorkdayRepositoryMock()
 	workdayService := WorkdayServiceImpl{
 		WorkdayRepository: workdayRepository,
+		UnitOfWork:        &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -641,7 +645,7 @@ func TestAssignPersonToWorkday(t *testing.T) {
 				t.Errorf("Error while building context: %s", err)
 			}
 
-			workdayService.AssignPersonToWorkday(c)
+			serve(c, workdayService.AssignPersonToWorkday)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -655,6 +659,7 @@ func TestUnassignPersonFromWorkday(t *testing.T) {
 	workdayRepository := mock.NewWorkdayRepositoryMock()
 	workdayService := WorkdayServiceImpl{
 		WorkdayRepository: workdayRepository,
+		UnitOfWork:        &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -730,7 +735,7 @@ func TestUnassignPersonFromWorkday(t *testing.T) {
 				t.Errorf("Error while building context: %s", err)
 			}
 
-			workdayService.UnassignPersonFromWorkday(c)
+			serve(c, workdayService.UnassignPersonFromWorkday)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -739,3 +744,210 @@ func TestUnassignPersonFromWorkday(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWorkdaysForPerson(t *testing.T) {
+	workdayRepository := mock.NewWorkdayRepositoryMock()
+	personRepository := mock.NewPersonRepositoryMock()
+	workdayService := WorkdayServiceImpl{
+		WorkdayRepository: workdayRepository,
+		PersonRepository:  personRepository,
+		UnitOfWork:        &mock.UnitOfWorkMock{},
+	}
+
+	type getWorkdaysForPersonTest struct {
+		queries     map[string]string
+		mockValue   []dao.Workday
+		mockError   error
+		personError error
+		// a member of the department of the person if not set
+		identity           *dco.Identity
+		expectedStatusCode int
+		expectedWorkdays   int
+	}
+
+	testSteps := []getWorkdaysForPersonTest{
+		{
+			// valid request
+			queries:            map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31"},
+			mockValue:          []dao.Workday{{Date: "2024-01-02"}, {Date: "2024-01-03"}},
+			expectedStatusCode: http.StatusOK,
+			expectedWorkdays:   2,
+		},
+		{
+			// no workdays
+			queries:            map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-01"},
+			mockError:          pkg.ErrNoRows,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			// missing end_date
+			queries:            map[string]string{"start_date": "2024-01-01"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// end before start
+			queries:            map[string]string{"start_date": "2024-01-02", "end_date": "2024-01-01"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// range too long
+			queries:            map[string]string{"start_date": "2024-01-01", "end_date": "2025-01-01"},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			// repository error
+			queries:            map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31"},
+			mockError:          errors.New("repository error"),
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{
+			// unknown person
+			queries:            map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31"},
+			personError:        pkg.ErrNoRows,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			// the identity does not belong to a department of the person
+			queries:            map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31"},
+			identity:           &dco.Identity{Username: "planner", Department: "department2"},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			queries:            map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-31"},
+			mockValue:          []dao.Workday{{Date: "2024-01-02"}},
+			identity:           &dco.Identity{Username: "admin", Department: "department2", IsAdmin: true},
+			expectedStatusCode: http.StatusOK,
+			expectedWorkdays:   1,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run("Test Get Workdays For Person", func(t *testing.T) {
+			identity := testStep.identity
+			if identity == nil {
+				identity = &dco.Identity{Username: "planner", Department: "department1"}
+			}
+
+			personRepository.On("FindPersonByID").Return(dao.Person{ID: "person1", Departments: []dao.DepartmentInPerson{{ID: "department1"}}}, testStep.personError)
+			workdayRepository.On("GetWorkdaysForPerson").Return(testStep.mockValue, testStep.mockError)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, err := mock.NewTestContextBuilder(w).
+				WithMethod("GET").
+				WithMapParams(map[string]string{"personID": "person1"}).
+				WithQueries(testStep.queries).Build()
+			if err != nil {
+				t.Errorf("Error while building context: %s", err)
+			}
+			c.Set("identity", identity)
+
+			serve(c, workdayService.GetWorkdaysForPerson)
+			response := w.Result()
+
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, response.StatusCode)
+			}
+			if response.StatusCode != http.StatusOK {
+				return
+			}
+
+			var body dto.APIResponse[[]dco.WorkdayResponse]
+			if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+				t.Fatalf("Error while decoding response: %s", err)
+			}
+			if len(body.Data) != testStep.expectedWorkdays {
+				t.Errorf("Expected %d workdays, got %d", testStep.expectedWorkdays, len(body.Data))
+			}
+		})
+	}
+}
+
+func TestGetHoursForPerson(t *testing.T) {
+	workdayRepository := mock.NewWorkdayRepositoryMock()
+	personRepository := mock.NewPersonRepositoryMock()
+	workdayService := WorkdayServiceImpl{
+		WorkdayRepository: workdayRepository,
+		PersonRepository:  personRepository,
+		UnitOfWork:        &mock.UnitOfWorkMock{},
+	}
+
+	type getHoursForPersonTest struct {
+		person      dao.Person
+		personError error
+		// a member of the department of the person if not set
+		identity           *dco.Identity
+		workdays           []dao.Workday
+		expectedStatusCode int
+		expectedResponse   dco.PersonHoursResponse
+	}
+
+	testSteps := []getHoursForPersonTest{
+		{
+			person:             dao.Person{ID: "person1", WorkingHours: 40, Departments: []dao.DepartmentInPerson{{ID: "department1"}}},
+			workdays:           []dao.Workday{{DurationInMinutes: 480}, {DurationInMinutes: 270}},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: dco.PersonHoursResponse{
+				PersonID:        "person1",
+				StartDate:       "2024-01-01",
+				EndDate:         "2024-01-14",
+				Assignments:     2,
+				PlannedMinutes:  750,
+				PlannedHours:    12.5,
+				ContractedHours: 80,
+			},
+		},
+		{
+			// unknown person
+			personError:        pkg.ErrNoRows,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			// the identity does not belong to a department of the person
+			person:             dao.Person{ID: "person1", WorkingHours: 40, Departments: []dao.DepartmentInPerson{{ID: "department1"}}},
+			identity:           &dco.Identity{Username: "planner", Department: "department2"},
+			expectedStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, testStep := range testSteps {
+		t.Run("Test Get Hours For Person", func(t *testing.T) {
+			identity := testStep.identity
+			if identity == nil {
+				identity = &dco.Identity{Username: "planner", Department: "department1"}
+			}
+
+			personRepository.On("FindPersonByID").Return(testStep.person, testStep.personError)
+			workdayRepository.On("GetWorkdaysForPerson").Return(testStep.workdays, nil)
+
+			// get GIN context
+			w := httptest.NewRecorder()
+			c, err := mock.NewTestContextBuilder(w).
+				WithMethod("GET").
+				WithMapParams(map[string]string{"personID": "person1"}).
+				WithQueries(map[string]string{"start_date": "2024-01-01", "end_date": "2024-01-14"}).Build()
+			if err != nil {
+				t.Errorf("Error while building context: %s", err)
+			}
+			c.Set("identity", identity)
+
+			serve(c, workdayService.GetHoursForPerson)
+			response := w.Result()
+
+			if response.StatusCode != testStep.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", testStep.expectedStatusCode, response.StatusCode)
+			}
+			if response.StatusCode != http.StatusOK {
+				return
+			}
+
+			var body dto.APIResponse[dco.PersonHoursResponse]
+			if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+				t.Fatalf("Error while decoding response: %s", err)
+			}
+			if body.Data != testStep.expectedResponse {
+				t.Errorf("Expected %+v, got %+v", testStep.expectedResponse, body.Data)
+			}
+		})
+	}
+}
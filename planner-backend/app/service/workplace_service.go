@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"planner-backend/app/constant"
@@ -24,6 +25,8 @@ type WorkplaceService interface {
 
 type WorkplaceServiceImpl struct {
 	WorkplaceRepository repository.WorkplaceRepository
+	// Runs the operations of several repositories in one transaction
+	UnitOfWork repository.UnitOfWork
 }
 
 func (w WorkplaceServiceImpl) GetAllWorkplaces(c *gin.Context) {
@@ -32,15 +35,15 @@ func (w WorkplaceServiceImpl) GetAllWorkplaces(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get all workplaces")
 
 	departmentID := c.Param("departmentID")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := w.WorkplaceRepository.FindAllWorkplaces(departmentID)
+	rawData, err := w.WorkplaceRepository.FindAllWorkplaces(c.Request.Context(), departmentID)
 	switch err {
 	case nil:
 		break
@@ -48,7 +51,8 @@ func (w WorkplaceServiceImpl) GetAllWorkplaces(c *gin.Context) {
 		break
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapWorkplaceListToWorkplaceResponseList(rawData)
@@ -62,24 +66,26 @@ func (w WorkplaceServiceImpl) GetWorkplaceByName(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program get workplace by name")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	if departmentID == "" || workplaceID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	rawData, err := w.WorkplaceRepository.FindWorkplaceByID(departmentID, workplaceID)
+	rawData, err := w.WorkplaceRepository.FindWorkplaceByID(c.Request.Context(), departmentID, workplaceID)
 	switch err {
 	case nil:
 		break
 	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
+		pkg.Abort(c, pkg.NewError(constant.DataNotFound))
+		return
 	default:
 		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.UnknownError))
+		return
 	}
 
 	data := mapWorkplaceToWorkplaceResponse(rawData)
@@ -93,39 +99,48 @@ func (w WorkplaceServiceImpl) AddWorkplace(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program add workplace")
 
 	workplaceRequest := dco.WorkplaceRequest{}
 	if err := c.ShouldBindJSON(&workplaceRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 	departmentID := c.Param("departmentID")
 	if departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
-	_, err := w.WorkplaceRepository.FindWorkplaceByID(departmentID, workplaceRequest.Name)
-	switch err {
-	case nil:
-		pkg.PanicException(constant.Conflict)
-	case pkg.ErrNoRows:
-		break
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-
-	workplace := mapWorkplaceRequestToWorkplace(workplaceRequest)
-	rawData, err := w.WorkplaceRepository.Save(departmentID, &workplace)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Workplace
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		_, err := w.WorkplaceRepository.FindWorkplaceByID(ctx, departmentID, workplaceRequest.Name)
+		switch err {
+		case nil:
+			return pkg.NewError(constant.Conflict)
+		case pkg.ErrNoRows:
+			break
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		workplace := mapWorkplaceRequestToWorkplace(workplaceRequest)
+		rawData, err = w.WorkplaceRepository.Save(ctx, departmentID, &workplace)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapWorkplaceToWorkplaceResponse(rawData)
@@ -139,42 +154,51 @@ func (w WorkplaceServiceImpl) UpdateWorkplace(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program update workplace")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	if workplaceID == "" || departmentID == "" {
-		pkg.PanicException(constant.InvalidRequest)
-	}
-
-	workplace, err := w.WorkplaceRepository.FindWorkplaceByID(departmentID, workplaceID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
 	var workplaceRequest dco.WorkplaceRequest
 	if err := c.ShouldBindJSON(&workplaceRequest); err != nil {
 		slog.Error("Error when binding json", "error", err)
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.ValidationError(err))
+		return
 	}
 
-	workplace.Name = workplaceRequest.Name
-	rawData, err := w.WorkplaceRepository.Save(departmentID, &workplace)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when saving data to database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	var rawData dao.Workplace
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		workplace, err := w.WorkplaceRepository.FindWorkplaceByID(ctx, departmentID, workplaceID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		workplace.Name = workplaceRequest.Name
+		rawData, err = w.WorkplaceRepository.Save(ctx, departmentID, &workplace)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when saving data to database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	data := mapWorkplaceToWorkplaceResponse(rawData)
@@ -188,28 +212,35 @@ func (w WorkplaceServiceImpl) DeleteWorkplace(c *gin.Context) {
 	 * @return void
 	 */
 
-	defer pkg.PanicHandler(c)
 	slog.Info("start to execute program delete workplace")
 
 	departmentID := c.Param("departmentID")
 	workplaceID := c.Param("workplaceID")
 	if departmentID == "" || workplaceID == "" {
-		pkg.PanicException(constant.InvalidRequest)
+		pkg.Abort(c, pkg.NewError(constant.InvalidRequest))
+		return
 	}
 
-	workplace, err := w.WorkplaceRepository.FindWorkplaceByID(departmentID, workplaceID)
-	switch err {
-	case nil:
-		break
-	case pkg.ErrNoRows:
-		pkg.PanicException(constant.DataNotFound)
-	default:
-		slog.Error("Error when fetching data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
-	}
-	if err := w.WorkplaceRepository.Delete(departmentID, &workplace); err != nil {
-		slog.Error("Error when deleting data from database", "error", err)
-		pkg.PanicException(constant.UnknownError)
+	if err := w.UnitOfWork.Write(c.Request.Context(), func(ctx context.Context) error {
+		workplace, err := w.WorkplaceRepository.FindWorkplaceByID(ctx, departmentID, workplaceID)
+		switch err {
+		case nil:
+			break
+		case pkg.ErrNoRows:
+			return pkg.NewError(constant.DataNotFound)
+		default:
+			slog.Error("Error when fetching data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+		if err := w.WorkplaceRepository.Delete(ctx, departmentID, &workplace); err != nil {
+			slog.Error("Error when deleting data from database", "error", err)
+			return pkg.NewError(constant.UnknownError).Wrap(err)
+		}
+
+		return nil
+	}); err != nil {
+		pkg.Abort(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, pkg.BuildResponse(constant.Success, pkg.Null()))
@@ -17,6 +17,7 @@ func TestDeleteWorkplace(t *testing.T) {
 	WorkplaceRepository := mock.NewWorkplaceRepositoryMock()
 	workplaceService := WorkplaceServiceImpl{
 		WorkplaceRepository: WorkplaceRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestDELETE{
@@ -63,7 +64,7 @@ func TestDeleteWorkplace(t *testing.T) {
 			t.Errorf("Test Step %d: Error when building context", i)
 		}
 
-		workplaceService.DeleteWorkplace(c)
+		serve(c, workplaceService.DeleteWorkplace)
 		response := w.Result()
 
 		if response.StatusCode != testStep.expectedStatusCode {
@@ -77,6 +78,7 @@ func TestUpdateWorkplace(t *testing.T) {
 	WorkplaceRepository := mock.NewWorkplaceRepositoryMock()
 	workplaceService := WorkplaceServiceImpl{
 		WorkplaceRepository: WorkplaceRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPUT{
@@ -146,7 +148,7 @@ func TestUpdateWorkplace(t *testing.T) {
 			t.Errorf("Test Step %d: Error when building context", i)
 		}
 
-		workplaceService.UpdateWorkplace(c)
+		serve(c, workplaceService.UpdateWorkplace)
 		response := w.Result()
 
 		if response.StatusCode != testStep.expectedStatusCode {
@@ -172,6 +174,7 @@ func TestAddWorkplace(t *testing.T) {
 	WorkplaceRepository := mock.NewWorkplaceRepositoryMock()
 	workplaceService := WorkplaceServiceImpl{
 		WorkplaceRepository: WorkplaceRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestPOST{
@@ -267,7 +270,7 @@ func TestAddWorkplace(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			workplaceService.AddWorkplace(c)
+			serve(c, workplaceService.AddWorkplace)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -294,6 +297,7 @@ func TestGetAllWorkplaces(t *testing.T) {
 	WorkplaceRepository := mock.NewWorkplaceRepositoryMock()
 	workplaceService := WorkplaceServiceImpl{
 		WorkplaceRepository: WorkplaceRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestGET{
@@ -342,7 +346,7 @@ func TestGetAllWorkplaces(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			workplaceService.GetAllWorkplaces(c)
+			serve(c, workplaceService.GetAllWorkplaces)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -371,6 +375,7 @@ func TestGetWorkplaceByID(t *testing.T) {
 	WorkplaceRepository := mock.NewWorkplaceRepositoryMock()
 	workplaceService := WorkplaceServiceImpl{
 		WorkplaceRepository: WorkplaceRepository,
+		UnitOfWork:          &mock.UnitOfWorkMock{},
 	}
 
 	testSteps := []ServiceTestGET{
@@ -448,7 +453,7 @@ func TestGetWorkplaceByID(t *testing.T) {
 				t.Errorf("Test Step %d: Error when building context", i)
 			}
 
-			workplaceService.GetWorkplaceByName(c)
+			serve(c, workplaceService.GetWorkplaceByName)
 			response := w.Result()
 
 			if response.StatusCode != testStep.expectedStatusCode {
@@ -2,29 +2,47 @@
 package app
 
 import (
+	"context"
 	"log/slog"
+	"planner-backend/app/metrics"
 	"planner-backend/config"
 	"time"
 )
 
-func InitalizeSynchronization(injector *config.Injector) {
-	// every day
+func InitalizeSynchronization(ctx context.Context, injector *config.Injector) {
+	// every day, until the context of the application is cancelled
 	interval := 24 * time.Hour
 	slog.Info("Initializing synchronization")
 
 	weeksInAdvance := 4
-	if err := injector.SynchronizeRepo.Synchronize(weeksInAdvance); err != nil {
-		slog.Error("Error synchronizing", "error", err)
-	}
+	synchronize(ctx, injector, weeksInAdvance)
 
 	// Create a ticker that ticks every 24 hours
 	go func() {
 		ticker := time.NewTicker(interval)
-		for range ticker.C {
-			slog.Info("Synchronizing")
-			if err := injector.SynchronizeRepo.Synchronize(weeksInAdvance); err != nil {
-				slog.Error("Error synchronizing", "error", err)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				slog.Info("Synchronizing")
+				synchronize(ctx, injector, weeksInAdvance)
 			}
 		}
 	}()
 }
+
+func synchronize(ctx context.Context, injector *config.Injector, weeksInAdvance int) {
+	/* Creates the workdays of the next weeks, the duration and the created workdays are reported to /metrics */
+	start := time.Now()
+	created, err := injector.SynchronizeRepo.Synchronize(ctx, weeksInAdvance)
+	metrics.ObserveSyncJob(metrics.JobWorkdays, start, err)
+	if err != nil {
+		slog.Error("Error synchronizing", "error", err)
+		return
+	}
+
+	metrics.SyncWorkdaysCreated.Add(float64(created))
+	slog.Info("Synchronized workdays", "created", created)
+}
@@ -15,12 +15,24 @@ import (
 
 var db = wire.NewSet(config.ConnectToDB)
 
+var tokenKeys = wire.NewSet(newTokenKeySet)
+
+var gatewayClient = wire.NewSet(newGatewayClient)
+
+var notifier = wire.NewSet(newNotifier)
+
+var repositoryTimeouts = wire.NewSet(newRepositoryTimeouts)
+
 var InjectorSet = wire.NewSet(
 	wire.Struct(new(config.Injector), "*"))
 
-func BuildInjector(ctx context.Context) (*config.Injector, func(), error) {
+func BuildInjector(ctx context.Context, cfg config.Config) (*config.Injector, func(), error) {
 	wire.Build(
 		db,
+		tokenKeys,
+		gatewayClient,
+		notifier,
+		repositoryTimeouts,
 		repository.RepositorySet,
 		service.ServiceSet,
 		controller.ControllerSet,
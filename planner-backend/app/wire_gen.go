@@ -17,82 +17,110 @@ import (
 
 // Injectors from wire.go:
 
-func BuildInjector(ctx context.Context) (*config.Injector, func(), error) {
-	driverWithContext := config.ConnectToDB(ctx)
+func BuildInjector(ctx context.Context, cfg config.Config) (*config.Injector, func(), error) {
+	driverWithContext := config.ConnectToDB(ctx, cfg)
 	systemControllerImpl := &controller.SystemControllerImpl{}
-	departmentRepositoryImpl := repository.DepartmentRepositoryInit(driverWithContext, ctx)
+	timeouts := newRepositoryTimeouts(cfg)
+	departmentRepositoryImpl := repository.DepartmentRepositoryInit(driverWithContext, timeouts)
+	client := newGatewayClient(cfg)
+	unitOfWorkImpl := repository.UnitOfWorkInit(driverWithContext, timeouts)
 	departmentServiceImpl := &service.DepartmentServiceImpl{
 		DepartmentRepository: departmentRepositoryImpl,
+		Gateway:              client,
+		UnitOfWork:           unitOfWorkImpl,
 	}
 	departmentControllerImpl := &controller.DepartmentControllerImpl{
 		DepartmentService: departmentServiceImpl,
 	}
-	workplaceRepositoryImpl := repository.WorkplaceRepositoryInit(driverWithContext, ctx)
+	workplaceRepositoryImpl := repository.WorkplaceRepositoryInit(driverWithContext, timeouts)
 	workplaceServiceImpl := &service.WorkplaceServiceImpl{
 		WorkplaceRepository: workplaceRepositoryImpl,
+		UnitOfWork:          unitOfWorkImpl,
 	}
 	workplaceControllerImpl := &controller.WorkplaceControllerImpl{
 		WorkplaceService: workplaceServiceImpl,
 	}
-	timeslotRepositoryImpl := repository.TimeslotRepositoryInit(driverWithContext, ctx)
+	timeslotRepositoryImpl := repository.TimeslotRepositoryInit(driverWithContext, timeouts)
 	timeslotServiceImpl := &service.TimeslotServiceImpl{
 		TimeslotRepository: timeslotRepositoryImpl,
+		UnitOfWork:         unitOfWorkImpl,
 	}
 	timeslotControllerImpl := &controller.TimeslotControllerImpl{
 		TimeslotService: timeslotServiceImpl,
 	}
-	weekdayRepositoryImpl := repository.WeekdayRepositoryInit(driverWithContext, ctx)
+	weekdayRepositoryImpl := repository.WeekdayRepositoryInit(driverWithContext, timeouts)
 	weekdayServiceImpl := &service.WeekdayServiceImpl{
 		WeekdayRepository:  weekdayRepositoryImpl,
 		TimeslotRepository: timeslotRepositoryImpl,
+		UnitOfWork:         unitOfWorkImpl,
 	}
 	weekdayControllerImpl := &controller.WeekdayControllerImpl{
 		WeekdayService: weekdayServiceImpl,
 	}
-	personRepositoryImpl := repository.PersonRepositoryInit(driverWithContext, ctx)
+	personRepositoryImpl := repository.PersonRepositoryInit(driverWithContext, timeouts)
 	personServiceImpl := &service.PersonServiceImpl{
 		PersonRepository: personRepositoryImpl,
+		UnitOfWork:       unitOfWorkImpl,
 	}
 	personControllerImpl := &controller.PersonControllerImpl{
 		PersonService: personServiceImpl,
 	}
-	personRelRepositoryImpl := repository.PersonRelRepositoryInit(driverWithContext, ctx)
+	personRelRepositoryImpl := repository.PersonRelRepositoryInit(driverWithContext, timeouts)
 	personRelServiceImpl := service.PersonRelServiceImpl{
 		PersonRelRepository:  personRelRepositoryImpl,
 		PersonRepository:     personRepositoryImpl,
 		DepartmentRepository: departmentRepositoryImpl,
 		WorkplaceRepository:  workplaceRepositoryImpl,
+		UnitOfWork:           unitOfWorkImpl,
 	}
 	personRelControllerImpl := &controller.PersonRelControllerImpl{
 		PersonRelService: personRelServiceImpl,
 	}
-	workdayRepositoryImpl := repository.WorkdayRepositoryInit(driverWithContext, ctx)
+	workdayRepositoryImpl := repository.WorkdayRepositoryInit(driverWithContext, timeouts)
 	workdayServiceImpl := &service.WorkdayServiceImpl{
 		WorkdayRepository: workdayRepositoryImpl,
+		PersonRepository:  personRepositoryImpl,
+		UnitOfWork:        unitOfWorkImpl,
 	}
 	workdayControllerImpl := &controller.WorkdayControllerImpl{
 		WorkdayService: workdayServiceImpl,
 	}
-	absenceRepositoryImpl := repository.AbsenceRepositoryInit(driverWithContext, ctx)
+	absenceRepositoryImpl := repository.AbsenceRepositoryInit(driverWithContext, timeouts)
 	absenceServiceImpl := &service.AbsenceServiceImpl{
 		AbsenceRepository: absenceRepositoryImpl,
 	}
 	absenceControllerImpl := &controller.AbsenceControllerImpl{
 		AbsencyService: absenceServiceImpl,
 	}
-	synchronizeRepositoryImpl := repository.SynchronizeRepositoryInit(driverWithContext, ctx)
+	leaveRequestRepositoryImpl := repository.LeaveRequestRepositoryInit(driverWithContext, timeouts)
+	notificationNotifier := newNotifier(cfg)
+	leaveRequestServiceImpl := &service.LeaveRequestServiceImpl{
+		LeaveRequestRepository: leaveRequestRepositoryImpl,
+		PersonRepository:       personRepositoryImpl,
+		WorkdayRepository:      workdayRepositoryImpl,
+		Notifier:               notificationNotifier,
+		UnitOfWork:             unitOfWorkImpl,
+	}
+	leaveRequestControllerImpl := &controller.LeaveRequestControllerImpl{
+		LeaveRequestService: leaveRequestServiceImpl,
+	}
+	synchronizeRepositoryImpl := repository.SynchronizeRepositoryInit(driverWithContext, timeouts)
+	keySet := newTokenKeySet(cfg)
 	injector := &config.Injector{
-		DB:              driverWithContext,
-		SystemCtrl:      systemControllerImpl,
-		DepartmentCtrl:  departmentControllerImpl,
-		WorkplaceCtrl:   workplaceControllerImpl,
-		TimeslotCtrl:    timeslotControllerImpl,
-		WeekdayCtrl:     weekdayControllerImpl,
-		PersonCtrl:      personControllerImpl,
-		PersonRelCtrl:   personRelControllerImpl,
-		WorkdayCtrl:     workdayControllerImpl,
-		AbsenceCtrl:     absenceControllerImpl,
-		SynchronizeRepo: synchronizeRepositoryImpl,
+		Config:           cfg,
+		DB:               driverWithContext,
+		SystemCtrl:       systemControllerImpl,
+		DepartmentCtrl:   departmentControllerImpl,
+		WorkplaceCtrl:    workplaceControllerImpl,
+		TimeslotCtrl:     timeslotControllerImpl,
+		WeekdayCtrl:      weekdayControllerImpl,
+		PersonCtrl:       personControllerImpl,
+		PersonRelCtrl:    personRelControllerImpl,
+		WorkdayCtrl:      workdayControllerImpl,
+		AbsenceCtrl:      absenceControllerImpl,
+		LeaveRequestCtrl: leaveRequestControllerImpl,
+		SynchronizeRepo:  synchronizeRepositoryImpl,
+		TokenKeys:        keySet,
 	}
 	return injector, func() {
 	}, nil
@@ -102,4 +130,12 @@ func BuildInjector(ctx context.Context) (*config.Injector, func(), error) {
 
 var db = wire.NewSet(config.ConnectToDB)
 
+var tokenKeys = wire.NewSet(newTokenKeySet)
+
+var gatewayClient = wire.NewSet(newGatewayClient)
+
+var notifier = wire.NewSet(newNotifier)
+
+var repositoryTimeouts = wire.NewSet(newRepositoryTimeouts)
+
 var InjectorSet = wire.NewSet(wire.Struct(new(config.Injector), "*"))
@@ -2,32 +2,56 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
 	"planner-backend/app"
+	"planner-backend/app/domain/dco"
 	"planner-backend/app/repository"
 	"planner-backend/app/router"
 	"planner-backend/config"
+	"strings"
 )
 
 func main() {
+	// `config print` shows the effective config with the secrets redacted
+	if len(os.Args) > 2 && os.Args[1] == "config" && os.Args[2] == "print" {
+		cfg, err := config.Load(os.Args[3:])
+		cfg.Print(os.Stdout)
+		exitOnInvalidConfig(err)
+		return
+	}
+
+	// every invalid or missing setting is reported at once, before anything is started
+	cfg, err := config.Load(os.Args[1:])
+	exitOnInvalidConfig(err)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	port := os.Getenv("PLANNER_BACKEND_PORT")
-	if port == "" {
-		port = "8081"
-	}
+	config.InitLogger(cfg.LogLevel)
 
-	config.InitLogger()
+	dco.IdentitySigningKey = []byte(cfg.IdentitySigningKey)
 
-	init, _, _ := app.BuildInjector(ctx)
+	init, _, _ := app.BuildInjector(ctx, cfg)
 
 	// I dont know where else to fit this in
 	repository.Migrate(ctx, init.DB)
 
 	router := router.Init(init)
 
-	app.InitalizeSynchronization(init)
+	app.InitalizeSynchronization(ctx, init)
 
-	router.Run(":" + port)
+	router.Run(":" + cfg.Port)
+}
+
+func exitOnInvalidConfig(err error) {
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n  - %s\n", strings.ReplaceAll(err.Error(), "\n", "\n  - "))
+		os.Exit(2)
+	}
 }
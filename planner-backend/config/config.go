@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ModeDevelopment = "development"
+	ModeProduction  = "production"
+)
+
+// Config holds the settings of the planner-backend, see Load for their sources
+type Config struct {
+	// development or production, which enables the release mode of gin
+	Mode string `json:"mode" env:"MODE" flag:"mode" usage:"development or production"`
+	// DEBUG, INFO, WARN or ERROR
+	LogLevel string `json:"log_level" env:"LOG_LEVEL" flag:"log-level" usage:"DEBUG, INFO, WARN or ERROR"`
+	Port     string `json:"port" env:"PLANNER_BACKEND_PORT" flag:"port" usage:"port of the HTTP server"`
+
+	Database Database `json:"database"`
+
+	// Shared with the api-gateway to verify the forwarded identity
+	IdentitySigningKey string `json:"identity_signing_key" env:"PLANNER_IDENTITY_SIGNING_KEY" secret:"true"`
+	// Public keys of the api-gateway to verify access tokens, they are not accepted without it
+	JWKSURL string `json:"jwks_url" env:"PLANNER_JWKS_URL"`
+	// The api-gateway holds the registry of the departments, changes are only picked up by its reconciliation without it
+	GatewayTarget string `json:"gateway_target" env:"PLANNER_GATEWAY_TARGET" flag:"gateway-target" usage:"URL of the api-gateway"`
+	// Decisions on leave requests are posted to this webhook, they are only logged without it
+	NotificationWebhookURL string `json:"notification_webhook_url" env:"PLANNER_NOTIFICATION_WEBHOOK_URL"`
+	// Bearer token of the Prometheus scraper, /metrics is public without it
+	MetricsToken string `json:"metrics_token" env:"PLANNER_METRICS_TOKEN" secret:"true"`
+}
+
+type Database struct {
+	// e.g. bolt://planner-db:7687
+	URI      string `json:"uri" env:"PLANNER_DB_URI" flag:"db-uri" usage:"URI of the Neo4j database"`
+	Username string `json:"username" env:"PLANNER_DB_USERNAME"`
+	Password string `json:"password" env:"PLANNER_DB_PASSWORD" secret:"true"`
+	// Limits of every query, in addition to the request which is cancelled when its client disconnects
+	ReadTimeout  time.Duration `json:"read_timeout" env:"PLANNER_DB_READ_TIMEOUT" usage:"timeout of reading queries"`
+	WriteTimeout time.Duration `json:"write_timeout" env:"PLANNER_DB_WRITE_TIMEOUT" usage:"timeout of writing queries"`
+}
+
+func Default() Config {
+	return Config{
+		Mode:     ModeDevelopment,
+		LogLevel: "INFO",
+		Port:     "8081",
+		Database: Database{
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 30 * time.Second,
+		},
+	}
+}
+
+func (c Config) Production() bool {
+	return c.Mode == ModeProduction
+}
+
+func (c Config) Validate() []error {
+	/* Checks all settings, so every problem is reported at once */
+	errs := []error{}
+
+	if c.Mode != ModeDevelopment && c.Mode != ModeProduction {
+		errs = append(errs, fmt.Errorf("MODE: expected %s or %s, got %q", ModeDevelopment, ModeProduction, c.Mode))
+	}
+	switch strings.ToUpper(c.LogLevel) {
+	case "DEBUG", "INFO", "WARN", "ERROR":
+	default:
+		errs = append(errs, fmt.Errorf("LOG_LEVEL: expected DEBUG, INFO, WARN or ERROR, got %q", c.LogLevel))
+	}
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("PLANNER_BACKEND_PORT: invalid port %q", c.Port))
+	}
+
+	if c.Database.URI == "" {
+		errs = append(errs, fmt.Errorf("PLANNER_DB_URI is required"))
+	} else if parsed, err := url.Parse(c.Database.URI); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		errs = append(errs, fmt.Errorf("PLANNER_DB_URI: invalid uri %q, expected e.g. bolt://planner-db:7687", c.Database.URI))
+	}
+	if c.Database.ReadTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("PLANNER_DB_READ_TIMEOUT: expected a positive duration, got %s", c.Database.ReadTimeout))
+	}
+	if c.Database.WriteTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("PLANNER_DB_WRITE_TIMEOUT: expected a positive duration, got %s", c.Database.WriteTimeout))
+	}
+
+	// every request of the api-gateway carries the signed identity
+	if c.IdentitySigningKey == "" {
+		errs = append(errs, fmt.Errorf("PLANNER_IDENTITY_SIGNING_KEY is required"))
+	}
+
+	urls := map[string]string{
+		"PLANNER_JWKS_URL":                 c.JWKSURL,
+		"PLANNER_GATEWAY_TARGET":           c.GatewayTarget,
+		"PLANNER_NOTIFICATION_WEBHOOK_URL": c.NotificationWebhookURL,
+	}
+	for name, value := range urls {
+		if value == "" {
+			continue
+		}
+		if err := validateURL(value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errs
+}
+
+func validateURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid url %q, expected e.g. http://host:8080", value)
+	}
+	return nil
+}
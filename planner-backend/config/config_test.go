@@ -0,0 +1,83 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Error while writing config: %s", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	/* Test the precedence of the sources: defaults, file, environment and flags */
+	path := writeConfig(t, `{
+		"port": "9000",
+		"database": {"uri": "bolt://file:7687", "username": "neo4j"},
+		"identity_signing_key": "secret",
+		"gateway_target": "http://file:8080"
+	}`)
+	t.Setenv("PLANNER_CONFIG_FILE", path)
+	t.Setenv("PLANNER_DB_URI", "bolt://env:7687")
+	t.Setenv("PLANNER_GATEWAY_TARGET", "http://env:8080")
+
+	config, err := Load([]string{"--gateway-target", "http://flag:8080"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	expected := Default()
+	expected.Port = "9000"
+	expected.Database.URI = "bolt://env:7687"
+	expected.Database.Username = "neo4j"
+	expected.IdentitySigningKey = "secret"
+	expected.GatewayTarget = "http://flag:8080"
+	if config != expected {
+		t.Errorf("Expected %+v, got %+v", expected, config)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	/* Test that every invalid or missing setting is reported at once */
+	t.Setenv("PLANNER_CONFIG_FILE", "")
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("PLANNER_JWKS_URL", "api-gateway/.well-known/jwks.json")
+	t.Setenv("PLANNER_DB_READ_TIMEOUT", "0s")
+
+	_, err := Load(nil)
+	if err == nil {
+		t.Fatalf("Expected an invalid config")
+	}
+
+	for _, message := range []string{"LOG_LEVEL", "PLANNER_DB_URI is required", "PLANNER_IDENTITY_SIGNING_KEY is required", "PLANNER_JWKS_URL", "PLANNER_DB_READ_TIMEOUT"} {
+		if !strings.Contains(err.Error(), message) {
+			t.Errorf("Expected an error for %s, got %v", message, err)
+		}
+	}
+}
+
+func TestPrint(t *testing.T) {
+	config := Default()
+	config.Database.Password = "password"
+	config.IdentitySigningKey = "secret"
+
+	var output bytes.Buffer
+	if err := config.Print(&output); err != nil {
+		t.Fatalf("Error while printing the config: %s", err)
+	}
+
+	if strings.Contains(output.String(), `"password": "password"`) || strings.Contains(output.String(), "secret") {
+		t.Errorf("Expected the secrets to be redacted, got %s", output.String())
+	}
+	if !strings.Contains(output.String(), `"password": "[redacted]"`) {
+		t.Errorf("Expected the redacted password in %s", output.String())
+	}
+}
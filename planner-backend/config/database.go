@@ -3,17 +3,12 @@ package config
 import (
 	"context"
 	"log/slog"
-	"os"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
-func ConnectToDB(ctx context.Context) *neo4j.DriverWithContext {
-	uri := os.Getenv("PLANNER_DB_URI")
-	username := os.Getenv("PLANNER_DB_USERNAME")
-	password := os.Getenv("PLANNER_DB_PASSWORD")
-
-	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(username, password, ""))
+func ConnectToDB(ctx context.Context, c Config) *neo4j.DriverWithContext {
+	driver, err := neo4j.NewDriverWithContext(c.Database.URI, neo4j.BasicAuth(c.Database.Username, c.Database.Password, ""))
 	if err != nil {
 		slog.Error("Failed to connect to database", "error", err)
 		panic(err)
@@ -2,21 +2,25 @@ package config
 
 import (
 	"planner-backend/app/controller"
+	"planner-backend/app/jwks"
 	"planner-backend/app/repository"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 type Injector struct {
-	DB              *neo4j.DriverWithContext
-	SystemCtrl      controller.SystemController
-	DepartmentCtrl  controller.DepartmentController
-	WorkplaceCtrl   controller.WorkplaceController
-	TimeslotCtrl    controller.TimeslotController
-	WeekdayCtrl     controller.WeekdayController
-	PersonCtrl      controller.PersonController
-	PersonRelCtrl   controller.PersonRelController
-	WorkdayCtrl     controller.WorkdayController
-	AbsenceCtrl     controller.AbsenceController
-	SynchronizeRepo repository.SynchronizeRepository
+	Config           Config
+	DB               *neo4j.DriverWithContext
+	SystemCtrl       controller.SystemController
+	DepartmentCtrl   controller.DepartmentController
+	WorkplaceCtrl    controller.WorkplaceController
+	TimeslotCtrl     controller.TimeslotController
+	WeekdayCtrl      controller.WeekdayController
+	PersonCtrl       controller.PersonController
+	PersonRelCtrl    controller.PersonRelController
+	WorkdayCtrl      controller.WorkdayController
+	AbsenceCtrl      controller.AbsenceController
+	LeaveRequestCtrl controller.LeaveRequestController
+	SynchronizeRepo  repository.SynchronizeRepository
+	TokenKeys        *jwks.KeySet
 }
@@ -3,11 +3,12 @@ package config
 import (
 	"log/slog"
 	"os"
+	"strings"
 )
 
-func InitLogger() {
+func InitLogger(level string) {
 	opts := &slog.HandlerOptions{
-		Level:     getLogLevel(),
+		Level:     getLogLevel(level),
 		AddSource: true,
 	}
 
@@ -17,9 +18,8 @@ func InitLogger() {
 	slog.Info("Logger initialized")
 }
 
-func getLogLevel() slog.Level {
-	level := os.Getenv("LOG_LEVEL")
-	switch level {
+func getLogLevel(level string) slog.Level {
+	switch strings.ToUpper(level) {
 	case "DEBUG":
 		return slog.LevelDebug
 	case "INFO":